@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 	"time"
 )
 
@@ -14,6 +15,11 @@ type addressStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// AddressSchemaVerification is an interface for verifying the addresses table.
+type AddressSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // AddressCRUDOperations is an interface for managing the addresses table.
 type AddressCRUDOperations interface {
 	Create(ctx context.Context, model *Address, opts ...Option) error
@@ -24,6 +30,10 @@ type AddressCRUDOperations interface {
 // AddressSearchOperations is an interface for searching the addresses table.
 type AddressSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Address, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error)
 }
 
@@ -33,6 +43,23 @@ type AddressSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) AddressStorage
 }
 
+// AddressAggregateOperations is an interface for the aggregate queries.
+type AddressAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error)
+	SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+}
+
 // AddressRelationLoading is an interface for loading relations.
 type AddressRelationLoading interface {
 	LoadUser(ctx context.Context, model *Address, builders ...*QueryBuilder) error
@@ -49,8 +76,10 @@ type AddressRawQueryOperations interface {
 
 // AddressStorage is a struct for the "addresses" table.
 type AddressStorage interface {
+	AddressSchemaVerification
 	AddressCRUDOperations
 	AddressSearchOperations
+	AddressAggregateOperations
 	AddressRelationLoading
 	AddressRawQueryOperations
 	AddressSettings
@@ -112,6 +141,44 @@ func (t *addressStorage) SetQueryBuilder(builder sq.StatementBuilderType) Addres
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *addressStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *addressStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "UUID", NotNull: true, Statement: ""},
+		{Name: "street", Type: "String", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"street\" String;"},
+		{Name: "city", Type: "String", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"city\" String;"},
+		{Name: "state", Type: "Int32", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"state\" Int32;"},
+		{Name: "zip", Type: "Int64", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"zip\" Int64;"},
+		{Name: "user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID;"},
+		{Name: "created_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"created_at\" DateTime64(3) DEFAULT now();"},
+		{Name: "updated_at", Type: "Nullable(DateTime64(3))", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"updated_at\" Nullable(DateTime64(3));"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *addressStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "addresses_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "ALTER TABLE \"addresses\" ADD INDEX IF NOT EXISTS \"addresses_user_id_unique_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "addresses_city_idx", Columns: []string{"city"}, Unique: false, Statement: "ALTER TABLE \"addresses\" ADD INDEX IF NOT EXISTS \"addresses_city_idx\" (\"city\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "addresses_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "ALTER TABLE \"addresses\" ADD INDEX IF NOT EXISTS \"addresses_user_id_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *addressStorage) LoadUser(ctx context.Context, model *Address, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -171,6 +238,29 @@ func (t *addressStorage) LoadBatchUser(ctx context.Context, items []*Address, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *addressStorage) preload(ctx context.Context, items []*Address, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Address, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Address", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Address is a struct for the "addresses" table.
 type Address struct {
 	Id        string
@@ -203,10 +293,110 @@ func (t *Address) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Address, the other fields are left empty.
+func (t *Address) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Address) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "street":
+			dest = append(dest, &t.Street)
+		case "city":
+			dest = append(dest, &t.City)
+		case "state":
+			dest = append(dest, &t.State)
+		case "zip":
+			dest = append(dest, &t.Zip)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Address columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	AddressColumnId        Column = "id"
+	AddressColumnStreet    Column = "street"
+	AddressColumnCity      Column = "city"
+	AddressColumnState     Column = "state"
+	AddressColumnZip       Column = "zip"
+	AddressColumnUserId    Column = "user_id"
+	AddressColumnCreatedAt Column = "created_at"
+	AddressColumnUpdatedAt Column = "updated_at"
+)
+
 // AddressFilters is a struct that holds filters for Address.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type AddressFilters struct {
-	Id     *string
-	UserId *string
+	Id       *string
+	IdIn     []string
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *AddressFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseAddressFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseAddressFilters(values url.Values) (*AddressFilters, error) {
+	f := &AddressFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // AddressIdEq returns a condition that checks if the field equals the value.
@@ -339,6 +529,12 @@ func AddressUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// AddressHasUser checks if the Address has the User relation matching the filters.
+func AddressHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(AddressColumnUserId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new Address.
 func (t *addressStorage) AsyncCreate(ctx context.Context, model *Address, opts ...Option) error {
 	if model == nil {
@@ -477,9 +673,131 @@ func (t *addressStorage) BatchCreate(ctx context.Context, models []*Address, opt
 }
 
 // FindMany finds multiple Address based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Address, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Address
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Address")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Address rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *addressStorage) FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Address")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *addressStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Address", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *addressStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Address", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *addressStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *addressStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -529,6 +847,218 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Address based on the provided options.
+func (t *addressStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Address")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// AddressAggregateRow is a row of the Address Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type AddressAggregateRow struct {
+	Address
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *AddressAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// SumState returns the sum of the state values, it is set by Sum(AddressColumnState).
+func (r *AddressAggregateRow) SumState() int64 {
+	if v, ok := r.values[Sum(AddressColumnState)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgState returns the average of the state values, it is set by Avg(AddressColumnState).
+func (r *AddressAggregateRow) AvgState() *float64 {
+	if v, ok := r.values[Avg(AddressColumnState)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinState returns the smallest state value, it is set by Min(AddressColumnState).
+func (r *AddressAggregateRow) MinState() *int32 {
+	if v, ok := r.values[Min(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxState returns the largest state value, it is set by Max(AddressColumnState).
+func (r *AddressAggregateRow) MaxState() *int32 {
+	if v, ok := r.values[Max(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// SumZip returns the sum of the zip values, it is set by Sum(AddressColumnZip).
+func (r *AddressAggregateRow) SumZip() int64 {
+	if v, ok := r.values[Sum(AddressColumnZip)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgZip returns the average of the zip values, it is set by Avg(AddressColumnZip).
+func (r *AddressAggregateRow) AvgZip() *float64 {
+	if v, ok := r.values[Avg(AddressColumnZip)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinZip returns the smallest zip value, it is set by Min(AddressColumnZip).
+func (r *AddressAggregateRow) MinZip() *int64 {
+	if v, ok := r.values[Min(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxZip returns the largest zip value, it is set by Max(AddressColumnZip).
+func (r *AddressAggregateRow) MaxZip() *int64 {
+	if v, ok := r.values[Max(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(AddressColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(AddressColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(AddressColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(AddressColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Address rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(AddressColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *addressStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Address")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Address", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -539,13 +1069,22 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}()
 
-	var results []*Address
+	var results []*AddressAggregateRow
 	for rows.Next() {
-		model := &Address{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Address")
+		row := &AddressAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Address.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Address aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -555,20 +1094,159 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
-// FindOne finds a single Address based on the provided options.
-func (t *addressStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Address rows found by the builders.
+func (t *addressStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*AddressAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Address")
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &AddressAggregateRow{}, nil
 	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// SumState returns the sum of the state values of the Address rows found by the builders.
+func (t *addressStorage) SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnState), builders)
+	if err != nil {
+		return 0, err
 	}
+	return row.SumState(), nil
+}
 
-	return results[0], nil
+// AvgState returns the average of the state values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgState(), nil
+}
+
+// MinState returns the smallest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinState(), nil
+}
+
+// MaxState returns the largest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxState(), nil
+}
+
+// SumZip returns the sum of the zip values of the Address rows found by the builders.
+func (t *addressStorage) SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnZip), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumZip(), nil
+}
+
+// AvgZip returns the average of the zip values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgZip(), nil
+}
+
+// MinZip returns the smallest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinZip(), nil
+}
+
+// MaxZip returns the largest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxZip(), nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Address rows.
+func (t *addressStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Sum(AddressColumnState):
+		return new(*int64), nil
+	case Avg(AddressColumnState):
+		return new(*float64), nil
+	case Min(AddressColumnState), Max(AddressColumnState):
+		return new(*int32), nil
+	case Sum(AddressColumnZip):
+		return new(*int64), nil
+	case Avg(AddressColumnZip):
+		return new(*float64), nil
+	case Min(AddressColumnZip), Max(AddressColumnZip):
+		return new(*int64), nil
+	case Min(AddressColumnCreatedAt), Max(AddressColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(AddressColumnUpdatedAt), Max(AddressColumnUpdatedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Address", aggregation)
 }
 
 // Select executes a raw query and returns the result.
@@ -1,8 +1,6 @@
 // Code generated by protoc-gen-structify. DO NOT EDIT.
 // source: example/case_click/db/blog.proto
 // provider: clickhouse
-// protoc-gen-structify: (version=v1.1.18, branch=main, revision=a375e72a7dcb4c16ddd98053e19689593ff764bf), build: (go=go1.24.0, date=2025-02-26T17:46:44+0300)
-// protoc: 3.16.0
 package db
 
 import (
@@ -12,7 +10,13 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 //
@@ -59,6 +63,8 @@ type BlogStorages interface {
 	GetSettingStorage() SettingStorage
 	// GetAddressStorage returns the AddressStorage store.
 	GetAddressStorage() AddressStorage
+	// VerifySchema compares the live tables with the models.
+	VerifySchema(ctx context.Context) (*SchemaReport, error)
 }
 
 // NewBlogStorages returns a new BlogStorages.
@@ -166,6 +172,73 @@ func (c *blogStorages) GetAddressStorage() AddressStorage {
 	return c.addressStorage
 }
 
+// VerifySchema compares the live tables of all the stores with the models.
+// The differences are listed in the report, the error is returned only if the schema can not be read.
+// Use report.Err() to fail fast at startup when the tables are behind the models.
+func (c *blogStorages) VerifySchema(ctx context.Context) (*SchemaReport, error) {
+	var err error
+	var drifts []SchemaDrift
+	report := &SchemaReport{}
+
+	// verify the DeviceStorage table.
+	drifts, err = c.deviceStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the PostStorage table.
+	drifts, err = c.postStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the MessageStorage table.
+	drifts, err = c.messageStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the BotStorage table.
+	drifts, err = c.botStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the BotViewStorage table.
+	drifts, err = c.botViewStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the UserStorage table.
+	drifts, err = c.userStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the SettingStorage table.
+	drifts, err = c.settingStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the AddressStorage table.
+	drifts, err = c.addressStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	return report, nil
+}
+
 //
 // Json types.
 //
@@ -604,6 +677,8 @@ type Options struct {
 	relations bool
 	// uniqField is the unique field.
 	uniqField string
+	// preloads are the relations loaded by the Preload option.
+	preloads []preload
 }
 
 // WithRelations sets the relations flag.
@@ -622,8 +697,11 @@ func WithUniqField(field string) Option {
 }
 
 // FilterApplier is a condition filters.
+// The condition is the SQL expression, so the conditions nest with And, Or and Not.
 type FilterApplier interface {
+	sq.Sqlizer
 	Apply(query sq.SelectBuilder) sq.SelectBuilder
+	ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder
 	ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder
 }
 
@@ -649,8 +727,16 @@ type QueryBuilder struct {
 	}
 	// customTableName is the custom table name.
 	customTableName string
+	// columns are the selected columns, all the columns are selected if it is empty.
+	columns []Column
+	// preloads are the relations loaded with the found models.
+	preloads []preload
 }
 
+// Column is the column name of a table.
+// The columns of every model are generated as constants, e.g. UserColumnName.
+type Column string
+
 // NewQueryBuilder returns a new query builder.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{}
@@ -722,6 +808,12 @@ func (b *QueryBuilder) WithPagination(pagination *Pagination) *QueryBuilder {
 	return b
 }
 
+// WithColumns selects only the given columns, the other fields of the found models are left empty.
+func (b *QueryBuilder) WithColumns(columns ...Column) *QueryBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
 // Filter is a helper function to create a new query builder with filter options.
 func FilterBuilder(filterOptions ...FilterApplier) *QueryBuilder {
 	return NewQueryBuilder().WithFilter(filterOptions...)
@@ -751,6 +843,102 @@ func PaginateBuilder(limit, offset uint64) *QueryBuilder {
 	return NewQueryBuilder().WithPagination(NewPagination(limit, offset))
 }
 
+// ColumnsBuilder is a helper function to create a new query builder which selects the given columns.
+func ColumnsBuilder(columns ...Column) *QueryBuilder {
+	return NewQueryBuilder().WithColumns(columns...)
+}
+
+// ColumnQuerier queries the rows of the given columns, it is implemented by every storage.
+type ColumnQuerier interface {
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+}
+
+// FindManyInto selects the columns of the db tags of T and scans the found rows into T.
+// It lets the list queries skip the heavy columns, e.g.
+//
+//	type userItem struct {
+//		Id   string `db:"id"`
+//		Name string `db:"name"`
+//	}
+//
+//	items, err := FindManyInto[userItem](ctx, storages.GetUserStorage(), builder)
+func FindManyInto[T any](ctx context.Context, querier ColumnQuerier, builders ...*QueryBuilder) ([]*T, error) {
+	fields, err := dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+
+	rows, err := querier.QueryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		value := reflect.ValueOf(result).Elem()
+		dest := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			dest = append(dest, value.FieldByIndex(field.index).Addr().Interface())
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// dbField is the struct field which is scanned from the column of its db tag.
+type dbField struct {
+	column Column
+	index  []int
+}
+
+// dbFields returns the fields of the struct with the db tags.
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%s is not a struct", typ)
+	}
+
+	fields := collectDBFields(typ)
+	if len(fields) == 0 {
+		return nil, errors.Errorf("%s has no fields with the db tag", typ)
+	}
+	return fields, nil
+}
+
+// collectDBFields collects the fields with the db tags, the embedded structs without the tag are included.
+func collectDBFields(typ reflect.Type) []dbField {
+	var fields []dbField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, f := range collectDBFields(field.Type) {
+				fields = append(fields, dbField{column: f.column, index: append([]int{i}, f.index...)})
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, dbField{column: Column(tag), index: field.Index})
+	}
+	return fields
+}
+
 // Pagination is the pagination.
 type Pagination struct {
 	// limit is the limit.
@@ -782,6 +970,89 @@ func Offset(offset uint64) *Pagination {
 	}
 }
 
+//
+// Upsert.
+//
+
+// Conflict is the conflict handling of the upsert, e.g. OnConflict(UserColumnId).DoUpdate().
+// The table with the primary key is the ReplacingMergeTree ordered by it, the inserted row replaces
+// the whole row with the same primary key when the parts are merged, so the conflict target is the primary key
+// and all the columns are updated.
+type Conflict struct {
+	// target are the columns of the unique constraint.
+	target []Column
+	// update are the updated columns, all the inserted columns are updated if it is empty.
+	update []Column
+	// nothing keeps the existing row.
+	nothing bool
+}
+
+// OnConflict returns the conflict on the primary key of the table.
+func OnConflict(columns ...Column) *Conflict {
+	return &Conflict{target: columns}
+}
+
+// DoUpdate updates the existing row with the inserted row, the columns must be empty, the whole row is replaced.
+func (c *Conflict) DoUpdate(columns ...Column) *Conflict {
+	c.update = columns
+	c.nothing = false
+	return c
+}
+
+// DoNothing keeps the existing row, it is not supported by the ReplacingMergeTree tables.
+func (c *Conflict) DoNothing() *Conflict {
+	c.update = nil
+	c.nothing = true
+	return c
+}
+
+// isConflictTarget returns true if the columns are the columns of one of the unique constraints in any order.
+func isConflictTarget(targets [][]Column, columns []Column) bool {
+	for _, target := range targets {
+		if len(target) != len(columns) {
+			continue
+		}
+		matched := true
+		for i, column := range columns {
+			if !containsColumn(target, column) || containsColumn(columns[:i], column) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsColumn returns true if the column is one of the columns.
+func containsColumn(columns []Column, column Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// replace checks that the conflict is handled by the replacing of the row with the same primary key.
+func (c *Conflict) replace(table string, targets [][]Column) error {
+	if c == nil {
+		return errors.New("conflict is nil")
+	}
+	if !isConflictTarget(targets, c.target) {
+		return errors.Errorf("the columns %v are not the primary key of the %s table", c.target, table)
+	}
+	if c.nothing {
+		return errors.Errorf("the rows of the %s table are replaced, the existing row can't be kept", table)
+	}
+	if len(c.update) > 0 {
+		return errors.Errorf("the rows of the %s table are replaced, the updated columns can't be chosen", table)
+	}
+	return nil
+}
+
 //
 // Conditions for query builder.
 //
@@ -816,18 +1087,55 @@ func toInterface[T any](s []T) []interface{} {
 	return result
 }
 
+// ToSql returns no WHERE expression, the condition adds the JOIN clause.
+func (c JoinCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
+// Apply applies the condition to the query, the ON expression is rendered by the condition tree.
 func (c JoinCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	onQuery := c.On.Apply(sq.Select("*"))
-	onClause, args, _ := onQuery.ToSql()
-	onClause = strings.TrimPrefix(onClause, "SELECT * WHERE ")
-	joinExpr := fmt.Sprintf("%s JOIN %s ON %s", c.Type, c.Table.TableName(), onClause)
-	return query.JoinClause(sq.Expr(joinExpr, args...))
+	joinExpr := fmt.Sprintf("%s JOIN %s ON ?", c.Type, c.Table.TableName())
+	return query.JoinClause(sq.Expr(joinExpr, c.On))
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JoinCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
 }
 
 func (c JoinCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
 	return query
 }
 
+// applyClauses adds the JOIN clause of the condition nested in And, Or or Not.
+func (c JoinCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clauseApplier is implemented by the conditions which change the clauses of the query other than WHERE,
+// e.g. ORDER BY, and by the combinators which may nest them.
+type clauseApplier interface {
+	applyClauses(query sq.SelectBuilder) sq.SelectBuilder
+}
+
+// applyClauses applies the clauses other than WHERE of the conditions to the query.
+func applyClauses(query sq.SelectBuilder, conditions ...FilterApplier) sq.SelectBuilder {
+	for _, condition := range conditions {
+		if c, ok := condition.(clauseApplier); ok {
+			query = c.applyClauses(query)
+		}
+	}
+	return query
+}
+
+// hasExpr returns true if the condition has the WHERE expression, the combinators of the ORDER BY and JOIN
+// conditions have none and must not be added to the WHERE clause. The conditions failing to render have it,
+// so the error is returned by the query.
+func hasExpr(condition sq.Sqlizer) bool {
+	sql, _, err := condition.ToSql()
+	return err != nil || sql != ""
+}
+
 // And returns a condition that combines the given conditions with AND.
 type AndCondition struct {
 	Where []FilterApplier
@@ -838,20 +1146,43 @@ func And(conditions ...FilterApplier) FilterApplier {
 	return AndCondition{Where: conditions}
 }
 
-// And returns a condition that combines the given conditions with AND.
-func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+// ToSql returns the WHERE expressions of the conditions combined with AND.
+func (c AndCondition) ToSql() (string, []interface{}, error) {
+	and := make(sq.And, 0, len(c.Where))
 	for _, condition := range c.Where {
-		query = condition.Apply(query)
+		and = append(and, condition)
 	}
-	return query
+	return and.ToSql()
 }
 
-// And returns a condition that combines the given conditions with AND.
+// Apply applies the condition to the query.
+func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c AndCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c AndCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	for _, condition := range c.Where {
-		query = condition.ApplyDelete(query)
+	if !hasExpr(c) {
+		return query
 	}
-	return query
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c AndCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Where...)
 }
 
 //
@@ -868,32 +1199,92 @@ func Or(conditions ...FilterApplier) FilterApplier {
 	return OrCondition{Conditions: conditions}
 }
 
-// Apply applies the condition to the query.
-func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	or := sq.Or{}
+// ToSql returns the WHERE expressions of the conditions combined with OR.
+func (c OrCondition) ToSql() (string, []interface{}, error) {
+	or := make(sq.Or, 0, len(c.Conditions))
 	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+		or = append(or, condition)
 	}
-	return query.Where(or)
+	return or.ToSql()
 }
 
 // Apply applies the condition to the query.
+func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c OrCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c OrCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	or := sq.Or{}
-	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c OrCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Conditions...)
+}
+
+// NotCondition negates the condition.
+type NotCondition struct {
+	Condition FilterApplier
+}
+
+// Not returns a condition that checks if the condition is false, e.g. Not(Or(Eq("a", 1), Eq("b", 2))).
+func Not(condition FilterApplier) FilterApplier {
+	return NotCondition{Condition: condition}
+}
+
+// ToSql returns the negated WHERE expression of the condition.
+func (c NotCondition) ToSql() (string, []interface{}, error) {
+	sql, args, err := c.Condition.ToSql()
+	if err != nil || sql == "" {
+		return sql, args, err
 	}
-	return query.Where(or)
+	return "NOT (" + sql + ")", args, nil
+}
+
+// Apply applies the condition to the query.
+func (c NotCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c NotCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested condition.
+func (c NotCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Condition)
 }
 
 // EqualsCondition equals condition.
@@ -902,13 +1293,24 @@ type EqualsCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c EqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c EqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c EqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
+// ApplyDelete applies the condition to the query.
 func (c EqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // Eq returns a condition that checks if the field equals the value.
@@ -923,14 +1325,24 @@ type BetweenCondition struct {
 	Max   interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c BetweenCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max).ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c BetweenCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c BetweenCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c BetweenCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+	return query.Where(c)
 }
 
 // Between returns a condition that checks if the field is between the min and max values.
@@ -944,14 +1356,24 @@ type NotEqualsCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotEqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotEq returns a condition that checks if the field equals the value.
@@ -965,14 +1387,24 @@ type GreaterThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Gt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThan returns a condition that checks if the field equals the value.
@@ -986,14 +1418,24 @@ type LessThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Lt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // LessThan returns a condition that checks if the field equals the value.
@@ -1007,14 +1449,24 @@ type GreaterThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.GtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThanOrEqual returns a condition that checks if the field equals the value.
@@ -1028,14 +1480,24 @@ type LessThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.LtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 func LessThanOrEq(field string, value interface{}) FilterApplier {
@@ -1048,14 +1510,24 @@ type ILikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c ILikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.ILike{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c ILikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.ILike{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c ILikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c ILikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.ILike{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // ILike returns a condition that checks if the field equals the value.
@@ -1069,14 +1541,24 @@ type LikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.Like{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // Like returns a condition that checks if the field equals the value.
@@ -1090,14 +1572,24 @@ type NotLikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotLikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotLike{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotLikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotLikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotLikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotLike returns a condition that checks if the field equals the value.
@@ -1110,14 +1602,24 @@ type IsNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
 }
 
 // IsNull returns a condition that checks if the field is null.
@@ -1130,14 +1632,24 @@ type IsNotNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNotNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NOT NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNotNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNotNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNotNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
 }
 
 // IsNotNull returns a condition that checks if the field is not null.
@@ -1151,14 +1663,24 @@ type InCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c InCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c InCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c InCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // In returns a condition that checks if the field is in the given values.
@@ -1172,14 +1694,24 @@ type NotInCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotInCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotInCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotInCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotInCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // NotIn returns a condition that checks if the field is not in the given values.
@@ -1198,17 +1730,888 @@ func OrderBy(column string, asc bool) FilterApplier {
 	return OrderCondition{Column: column, Asc: asc}
 }
 
+// ToSql returns no WHERE expression, the condition adds the ORDER BY clause.
+func (c OrderCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
 // Apply applies the condition to the query.
 func (c OrderCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.OrderBy(c.clause())
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c OrderCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
+}
+
+// ApplyDelete applies the condition to the query.
+func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query
+}
+
+// applyClauses adds the ORDER BY clause of the condition nested in And, Or or Not.
+func (c OrderCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clause returns the ORDER BY clause of the column.
+func (c OrderCondition) clause() string {
 	if c.Asc {
-		return query.OrderBy(c.Column + " ASC")
+		return c.Column + " ASC"
 	}
 
 	// default to descending.
-	return query.OrderBy(c.Column + " DESC")
+	return c.Column + " DESC"
+}
+
+// InSubqueryCondition checks if the field is in the values selected by the subquery.
+type InSubqueryCondition struct {
+	Field string
+	Query sq.SelectBuilder
+	// Conditions are applied to the subquery when the condition is built.
+	Conditions []FilterApplier
+}
+
+// InSubquery returns a condition that checks if the field is in the values selected by the query,
+// e.g. InSubquery("id", sq.Select("user_id").From("bots").Where(sq.Eq{"published": true})).
+func InSubquery(field string, query sq.SelectBuilder) FilterApplier {
+	return InSubqueryCondition{Field: field, Query: query}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c InSubqueryCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c InSubqueryCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InSubqueryCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
-func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+func (c InSubqueryCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the IN expression of the subquery, the placeholders of the subquery are numbered by the outer query.
+func (c InSubqueryCondition) expr() sq.Sqlizer {
+	query := c.Query
+	for _, condition := range c.Conditions {
+		query = condition.Apply(query)
+	}
+	sql, args, err := query.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		return errorExpr{err: errors.Wrap(err, "failed to build subquery")}
+	}
+	return sq.Expr(c.Field+" IN ("+sql+")", args...)
+}
+
+// errorExpr is the expression which failed to build, ToSql returns the build error.
+type errorExpr struct {
+	err error
+}
+
+// ToSql returns the build error of the expression.
+func (e errorExpr) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
+
+// JSONContainsCondition checks if the JSON field contains the value: the keys and the values of an object
+// or the elements of an array.
+type JSONContainsCondition struct {
+	Field string
+	Value interface{}
+}
+
+// JSONContains returns a condition that checks if the JSON field contains the value,
+// e.g. JSONContains("settings", map[string]interface{}{"theme": "dark"}) or JSONContains("phones", []string{"+1"}).
+func JSONContains(field string, value interface{}) FilterApplier {
+	return JSONContainsCondition{Field: field, Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONContainsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONContainsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONContainsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONContainsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONHasKeyCondition checks if the JSON object has the top-level key.
+type JSONHasKeyCondition struct {
+	Field string
+	Key   string
+}
+
+// JSONHasKey returns a condition that checks if the JSON object has the top-level key.
+func JSONHasKey(field, key string) FilterApplier {
+	return JSONHasKeyCondition{Field: field, Key: key}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONHasKeyCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONHasKeyCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONPathEqualsCondition checks if the value at the path of the JSON object equals the value.
+type JSONPathEqualsCondition struct {
+	Field string
+	Path  []string
+	Value interface{}
+}
+
+// JSONPathEq returns a condition that checks if the value at the dot-separated path of the JSON object
+// equals the value, e.g. JSONPathEq("meta", "device.os", "ios"). The numeric keys index the arrays from 0.
+func JSONPathEq(field, path string, value interface{}) FilterApplier {
+	return JSONPathEqualsCondition{Field: field, Path: strings.Split(path, "."), Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONPathEqualsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONPathEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the JSONExtract expressions of the keys and the elements of the value.
+func (c JSONContainsCondition) expr() sq.Sqlizer {
+	raw, _ := json.Marshal(c.Value)
+	var value interface{}
+	_ = json.Unmarshal(raw, &value)
+	sql, args := jsonContains(c.Field, value, 0)
+	return sq.Expr(sql, args...)
+}
+
+// expr returns the JSONHas expression of the key.
+func (c JSONHasKeyCondition) expr() sq.Sqlizer {
+	return sq.Expr("JSONHas("+c.Field+", ?)", c.Key)
+}
+
+// expr returns the JSONExtract expression of the path, the function is chosen by the type of the value.
+func (c JSONPathEqualsCondition) expr() sq.Sqlizer {
+	args := make([]interface{}, 0, len(c.Path)+1)
+	for _, key := range c.Path {
+		// the arrays are indexed from 1
+		if index, err := strconv.Atoi(key); err == nil {
+			args = append(args, index+1)
+			continue
+		}
+		args = append(args, key)
+	}
+	keys := strings.TrimSuffix(strings.Repeat("?, ", len(c.Path)), ", ")
+
+	switch v := c.Value.(type) {
+	case string:
+		return sq.Expr("JSONExtractString("+c.Field+", "+keys+") = ?", append(args, v)...)
+	case bool:
+		return sq.Expr("JSONExtractBool("+c.Field+", "+keys+") = ?", append(args, v)...)
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		return sq.Expr("JSONExtractFloat("+c.Field+", "+keys+") = ?", append(args, v)...)
+	default:
+		raw, _ := json.Marshal(v)
+		return sq.Expr("JSONExtractRaw("+c.Field+", "+keys+") = ?", append(args, string(raw))...)
+	}
+}
+
+// jsonContains returns the condition that the raw JSON document doc contains the value,
+// the objects are matched by their keys and the arrays by their elements.
+func jsonContains(doc string, value interface{}, depth int) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sql, a := jsonContains("JSONExtractRaw("+doc+", ?)", v[key], depth)
+			parts = append(parts, sql)
+			args = append(args, key)
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "JSONType(" + doc + ") = 'Object'", nil
+		}
+	case []interface{}:
+		alias := fmt.Sprintf("j%d", depth)
+		for _, element := range v {
+			sql, a := jsonContains(alias, element, depth+1)
+			parts = append(parts, "arrayExists("+alias+" -> "+sql+", JSONExtractArrayRaw("+doc+"))")
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "JSONType(" + doc + ") = 'Array'", nil
+		}
+	default:
+		raw, _ := json.Marshal(v)
+		return doc + " = ?", []interface{}{string(raw)}
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", args
+}
+
+//
+// Filters from query strings.
+//
+
+// filterKeys returns the sorted keys of the query string values, so the filters are parsed in the same order.
+func filterKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseFilterValue parses the single query string value of the filter.
+func parseFilterValue[T any](key string, raw []string) (*T, error) {
+	if len(raw) != 1 {
+		return nil, errors.Errorf("filter %q must have a single value", key)
+	}
+	value, err := parseFilterScalar[T](key, raw[0])
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// parseFilterValues parses the list of the query string values of the filter.
+func parseFilterValues[T any](key string, raw []string) ([]T, error) {
+	values := make([]T, 0, len(raw))
+	for _, r := range raw {
+		value, err := parseFilterScalar[T](key, r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseFilterScalar parses the query string value into the type of the filter field, the times are in RFC 3339.
+func parseFilterScalar[T any](key string, raw string) (T, error) {
+	var value T
+	var err error
+	switch v := any(&value).(type) {
+	case *string:
+		*v = raw
+	case *bool:
+		*v, err = strconv.ParseBool(raw)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(raw, 10, 32)
+		*v = int32(n)
+	case *int64:
+		*v, err = strconv.ParseInt(raw, 10, 64)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(raw, 10, 32)
+		*v = uint32(n)
+	case *uint64:
+		*v, err = strconv.ParseUint(raw, 10, 64)
+	case *float32:
+		var n float64
+		n, err = parseFilterFloat(raw, 32)
+		*v = float32(n)
+	case *float64:
+		*v, err = parseFilterFloat(raw, 64)
+	case *time.Time:
+		*v, err = time.Parse(time.RFC3339, raw)
+	default:
+		return value, errors.Errorf("filter %q has an unsupported type %T", key, value)
+	}
+	if err != nil {
+		return value, errors.Errorf("invalid value %q of the filter %q", raw, key)
+	}
+	return value, nil
+}
+
+// parseFilterFloat parses the finite float, NaN and the infinities are not valid filter values.
+func parseFilterFloat(raw string, bitSize int) (float64, error) {
+	n, err := strconv.ParseFloat(raw, bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("the value is not finite")
+	}
+	return n, nil
+}
+
+//
+// Aggregations.
+//
+
+// Aggregation is an aggregate function of the Aggregate query, e.g. Count() or Sum(UserColumnAge).
+type Aggregation struct {
+	function string
+	column   Column
+}
+
+// Count counts the rows.
+func Count() Aggregation {
+	return Aggregation{function: "count"}
+}
+
+// Sum sums the values of the numeric column, it is NULL if there are no rows.
+func Sum(column Column) Aggregation {
+	return Aggregation{function: "sumOrNull", column: column}
+}
+
+// Avg returns the average value of the numeric column, it is NULL if there are no rows.
+func Avg(column Column) Aggregation {
+	return Aggregation{function: "avgOrNull", column: column}
+}
+
+// Min returns the smallest value of the numeric or timestamp column, it is NULL if there are no rows.
+func Min(column Column) Aggregation {
+	return Aggregation{function: "minOrNull", column: column}
+}
+
+// Max returns the largest value of the numeric or timestamp column, it is NULL if there are no rows.
+func Max(column Column) Aggregation {
+	return Aggregation{function: "maxOrNull", column: column}
+}
+
+// Column returns the aggregated column, it is empty for Count.
+func (a Aggregation) Column() Column {
+	return a.column
+}
+
+// String returns the SQL expression of the aggregation.
+func (a Aggregation) String() string {
+	if a.column == "" {
+		return a.function + "()"
+	}
+	return a.function + "(" + string(a.column) + ")"
+}
+
+// Eq returns the Having condition: aggregation = value.
+func (a Aggregation) Eq(value interface{}) sq.Sqlizer {
+	return sq.Eq{a.String(): value}
+}
+
+// NotEq returns the Having condition: aggregation <> value.
+func (a Aggregation) NotEq(value interface{}) sq.Sqlizer {
+	return sq.NotEq{a.String(): value}
+}
+
+// GT returns the Having condition: aggregation > value.
+func (a Aggregation) GT(value interface{}) sq.Sqlizer {
+	return sq.Gt{a.String(): value}
+}
+
+// GTE returns the Having condition: aggregation >= value.
+func (a Aggregation) GTE(value interface{}) sq.Sqlizer {
+	return sq.GtOrEq{a.String(): value}
+}
+
+// LT returns the Having condition: aggregation < value.
+func (a Aggregation) LT(value interface{}) sq.Sqlizer {
+	return sq.Lt{a.String(): value}
+}
+
+// LTE returns the Having condition: aggregation <= value.
+func (a Aggregation) LTE(value interface{}) sq.Sqlizer {
+	return sq.LtOrEq{a.String(): value}
+}
+
+// AggregateOption is an option of the Aggregate query: an Aggregation, GroupBy, Having,
+// or a QueryBuilder which filters the aggregated rows.
+type AggregateOption interface {
+	applyAggregate(query *aggregateQuery)
+}
+
+// aggregateQuery is the Aggregate query built from the options.
+type aggregateQuery struct {
+	groupBy      []Column
+	aggregations []Aggregation
+	having       []sq.Sqlizer
+	builders     []*QueryBuilder
+}
+
+// newAggregateQuery builds the Aggregate query from the options.
+func newAggregateQuery(options []AggregateOption) *aggregateQuery {
+	query := &aggregateQuery{}
+	for _, option := range options {
+		if option != nil {
+			option.applyAggregate(query)
+		}
+	}
 	return query
 }
+
+// applyAggregate adds the aggregation to the Aggregate query.
+func (a Aggregation) applyAggregate(query *aggregateQuery) {
+	query.aggregations = append(query.aggregations, a)
+}
+
+// applyAggregate adds the filters of the builder to the Aggregate query,
+// the sorting and the pagination are applied to the groups.
+func (b *QueryBuilder) applyAggregate(query *aggregateQuery) {
+	query.builders = append(query.builders, b)
+}
+
+// groupBy is the GroupBy option.
+type groupBy []Column
+
+// GroupBy groups the aggregated rows by the columns, the rows of the Aggregate query hold the values of the columns.
+func GroupBy(columns ...Column) AggregateOption {
+	return groupBy(columns)
+}
+
+// applyAggregate adds the grouped columns to the Aggregate query.
+func (g groupBy) applyAggregate(query *aggregateQuery) {
+	query.groupBy = append(query.groupBy, g...)
+}
+
+// having is the Having option.
+type having []sq.Sqlizer
+
+// Having filters the groups by the conditions of the aggregations, e.g. Having(Count().GT(1)).
+func Having(conditions ...sq.Sqlizer) AggregateOption {
+	return having(conditions)
+}
+
+// applyAggregate adds the conditions to the Aggregate query.
+func (h having) applyAggregate(query *aggregateQuery) {
+	query.having = append(query.having, h...)
+}
+
+// aggregateOptions returns the options of the single aggregation of the rows found by the builders.
+func aggregateOptions(aggregation Aggregation, builders []*QueryBuilder) []AggregateOption {
+	options := make([]AggregateOption, 0, len(builders)+1)
+	options = append(options, aggregation)
+	for _, builder := range builders {
+		options = append(options, builder)
+	}
+	return options
+}
+
+//
+// Eager loading.
+//
+
+// preload is a relation path of WithPreload, e.g. "Posts.Author", with the builders of the relation.
+type preload struct {
+	path     string
+	builders []*QueryBuilder
+}
+
+// WithPreload loads the relations of the found models with the generated LoadBatch functions.
+// The nested relations are separated by dots, e.g. WithPreload("Posts", "Settings", "Posts.Author").
+func (b *QueryBuilder) WithPreload(paths ...string) *QueryBuilder {
+	for _, path := range paths {
+		b.preloads = append(b.preloads, preload{path: path})
+	}
+	return b
+}
+
+// WithPreloadBuilders loads the relation of the path like WithPreload, the builders filter and sort the loaded rows,
+// e.g. WithPreloadBuilders("Posts", SortBuilder(PostIdOrderBy(false))).
+func (b *QueryBuilder) WithPreloadBuilders(path string, builders ...*QueryBuilder) *QueryBuilder {
+	b.preloads = append(b.preloads, preload{path: path, builders: builders})
+	return b
+}
+
+// PreloadBuilder is a helper function to create a new query builder which loads the relations.
+func PreloadBuilder(paths ...string) *QueryBuilder {
+	return NewQueryBuilder().WithPreload(paths...)
+}
+
+// Preload is the option which loads the relations like QueryBuilder.WithPreload, e.g. in FindById.
+func Preload(paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.preloads = append(o.preloads, preload{path: path})
+		}
+	}
+}
+
+// preloadRelations groups the preload paths of the builders by their first relation in the order of the paths.
+// The nested paths are preloaded by the builders of the relation.
+func preloadRelations(builders []*QueryBuilder) ([]string, map[string][]*QueryBuilder) {
+	var preloads []preload
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		preloads = append(preloads, builder.preloads...)
+
+		options := &Options{}
+		for _, o := range builder.options {
+			o(options)
+		}
+		preloads = append(preloads, options.preloads...)
+	}
+
+	var relations []string
+	grouped := make(map[string][]*QueryBuilder)
+	for _, p := range preloads {
+		relation, nested, ok := strings.Cut(p.path, ".")
+		if _, found := grouped[relation]; !found {
+			relations = append(relations, relation)
+			grouped[relation] = []*QueryBuilder{}
+		}
+		if ok {
+			grouped[relation] = append(grouped[relation], NewQueryBuilder().WithPreloadBuilders(nested, p.builders...))
+		} else {
+			grouped[relation] = append(grouped[relation], p.builders...)
+		}
+	}
+	return relations, grouped
+}
+
+//
+// Table upgrades.
+//
+
+// SchemaDriftKind is the kind of the difference between the live table and the model.
+type SchemaDriftKind string
+
+const (
+	// SchemaDriftMissingTable is a table of the model which does not exist in the database.
+	SchemaDriftMissingTable SchemaDriftKind = "missing_table"
+	// SchemaDriftMissingColumn is a column of the model which does not exist in the table.
+	SchemaDriftMissingColumn SchemaDriftKind = "missing_column"
+	// SchemaDriftExtraColumn is a column of the table which is not declared in the model.
+	SchemaDriftExtraColumn SchemaDriftKind = "extra_column"
+	// SchemaDriftTypeMismatch is a column which type differs from the model.
+	SchemaDriftTypeMismatch SchemaDriftKind = "type_mismatch"
+	// SchemaDriftNullabilityMismatch is a column which nullability differs from the model.
+	SchemaDriftNullabilityMismatch SchemaDriftKind = "nullability_mismatch"
+	// SchemaDriftMissingIndex is an index of the model which does not exist in the table.
+	SchemaDriftMissingIndex SchemaDriftKind = "missing_index"
+)
+
+// SchemaDrift is a difference between the live table and the model which is not applied by the upgrade.
+type SchemaDrift struct {
+	// Table is the name of the table.
+	Table string
+	// Column is the name of the column or the index, it is empty for the missing table.
+	Column string
+	// Kind is the kind of the difference.
+	Kind SchemaDriftKind
+	// Expected is the definition declared by the model.
+	Expected string
+	// Actual is the definition of the live table.
+	Actual string
+}
+
+// String returns the description of the drift.
+func (d SchemaDrift) String() string {
+	name := d.Table
+	if d.Column != "" {
+		name += "." + d.Column
+	}
+	switch {
+	case d.Expected == "" && d.Actual == "":
+		return fmt.Sprintf("%s: %s", name, d.Kind)
+	case d.Actual == "":
+		return fmt.Sprintf("%s: %s (expected %q)", name, d.Kind, d.Expected)
+	case d.Expected == "":
+		return fmt.Sprintf("%s: %s (actual %q)", name, d.Kind, d.Actual)
+	}
+	return fmt.Sprintf("%s: %s (expected %q, actual %q)", name, d.Kind, d.Expected, d.Actual)
+}
+
+// Breaking reports whether the drift breaks the queries of the store.
+// The extra columns and the missing indexes do not break the queries.
+func (d SchemaDrift) Breaking() bool {
+	return d.Kind != SchemaDriftExtraColumn && d.Kind != SchemaDriftMissingIndex
+}
+
+// SchemaDriftError is returned by the upgrade when the live schema has the destructive differences.
+type SchemaDriftError struct {
+	Drifts []SchemaDrift
+}
+
+// Error returns the error message.
+func (e *SchemaDriftError) Error() string {
+	drifts := make([]string, 0, len(e.Drifts))
+	for _, drift := range e.Drifts {
+		drifts = append(drifts, drift.String())
+	}
+	return "schema drift: " + strings.Join(drifts, "; ")
+}
+
+// SchemaReport is the result of the schema verification.
+type SchemaReport struct {
+	// Drifts are the differences between the live tables and the models.
+	Drifts []SchemaDrift
+}
+
+// OK reports whether the live tables match the models.
+func (r *SchemaReport) OK() bool {
+	return len(r.Drifts) == 0
+}
+
+// Err returns the *SchemaDriftError of the breaking drifts, nil if the stores can query the tables.
+func (r *SchemaReport) Err() error {
+	var drifts []SchemaDrift
+	for _, drift := range r.Drifts {
+		if drift.Breaking() {
+			drifts = append(drifts, drift)
+		}
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{Drifts: drifts}
+}
+
+// String returns the readable report, one drift per line.
+func (r *SchemaReport) String() string {
+	if r.OK() {
+		return "schema is up to date"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema has %d drift(s):", len(r.Drifts))
+	for _, drift := range r.Drifts {
+		b.WriteString("\n  ")
+		b.WriteString(drift.String())
+	}
+	return b.String()
+}
+
+// tableColumn is the column of the model which the upgrade compares with the live table.
+type tableColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+	// Statement adds the missing column, it is empty if the column can not be added safely.
+	Statement string
+}
+
+// tableIndex is the index of the model which the upgrade creates.
+type tableIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Statement creates the index if it does not exist.
+	Statement string
+}
+
+// liveColumn is the column of the live table.
+type liveColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+}
+
+// liveIndex is the index of the live table.
+type liveIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// introspectColumns returns the columns of the live table of the current database from system.columns.
+// The nullability is the part of the type in clickhouse.
+// No columns are returned if the table does not exist.
+func introspectColumns(ctx context.Context, db QueryExecer, table string) ([]liveColumn, error) {
+	rows, err := db.Query(ctx, `
+		SELECT name, type
+		FROM system.columns
+		WHERE database = currentDatabase() AND table = ?
+		ORDER BY position
+	`, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query columns")
+	}
+	defer rows.Close()
+
+	var columns []liveColumn
+	for rows.Next() {
+		var column liveColumn
+		if err := rows.Scan(&column.Name, &column.Type); err != nil {
+			return nil, errors.Wrap(err, "failed to scan column")
+		}
+		column.NotNull = !strings.HasPrefix(column.Type, "Nullable(")
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate columns")
+	}
+
+	return columns, nil
+}
+
+// introspectIndexes returns the data skipping indexes of the live table of the current database.
+// The indexes are matched by the name, the expressions are not parsed into the columns.
+func introspectIndexes(ctx context.Context, db QueryExecer, table string) ([]liveIndex, error) {
+	rows, err := db.Query(ctx, `
+		SELECT name
+		FROM system.data_skipping_indices
+		WHERE database = currentDatabase() AND table = ?
+	`, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query indexes")
+	}
+	defer rows.Close()
+
+	var indexes []liveIndex
+	for rows.Next() {
+		var index liveIndex
+		if err := rows.Scan(&index.Name); err != nil {
+			return nil, errors.Wrap(err, "failed to scan index")
+		}
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate indexes")
+	}
+
+	return indexes, nil
+}
+
+// compareColumns returns the columns of the model which are missing in the live table and can be added,
+// and the differences which can not be applied safely.
+func compareColumns(table string, columns []tableColumn, live []liveColumn) ([]tableColumn, []SchemaDrift) {
+	actual := make(map[string]liveColumn, len(live))
+	for _, column := range live {
+		actual[column.Name] = column
+	}
+
+	var missing []tableColumn
+	var drifts []SchemaDrift
+	declared := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		declared[column.Name] = true
+
+		current, ok := actual[column.Name]
+		switch {
+		case !ok && column.Statement != "":
+			missing = append(missing, column)
+		case !ok:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+		case !strings.EqualFold(current.Type, column.Type):
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftTypeMismatch, Expected: column.Type, Actual: current.Type})
+		case current.NotNull != column.NotNull:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftNullabilityMismatch, Expected: nullability(column.NotNull), Actual: nullability(current.NotNull)})
+		}
+	}
+
+	for _, column := range live {
+		if !declared[column.Name] {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftExtraColumn, Actual: column.Type})
+		}
+	}
+
+	return missing, drifts
+}
+
+// indexStatements returns the statements of the indexes which columns exist in the table.
+func indexStatements(indexes []tableIndex, live []liveColumn, added []tableColumn) []string {
+	existing := make(map[string]bool, len(live)+len(added))
+	for _, column := range live {
+		existing[column.Name] = true
+	}
+	for _, column := range added {
+		existing[column.Name] = true
+	}
+
+	var statements []string
+	for _, index := range indexes {
+		complete := true
+		for _, column := range index.Columns {
+			complete = complete && existing[column]
+		}
+		if complete {
+			statements = append(statements, index.Statement)
+		}
+	}
+	return statements
+}
+
+// verifyTable returns the differences between the live table and the model.
+// Unlike the upgrade, the columns which can be added are reported as missing too.
+func verifyTable(table string, columns []tableColumn, indexes []tableIndex, live []liveColumn, liveIndexes []liveIndex) []SchemaDrift {
+	if len(live) == 0 {
+		return []SchemaDrift{
+			{Table: table, Kind: SchemaDriftMissingTable},
+		}
+	}
+
+	missing, drifts := compareColumns(table, columns, live)
+	for _, column := range missing {
+		drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+	}
+	for _, index := range indexes {
+		if !hasIndex(index, liveIndexes) {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: index.Name, Kind: SchemaDriftMissingIndex, Expected: strings.Join(index.Columns, ", ")})
+		}
+	}
+	return drifts
+}
+
+// hasIndex reports whether the live table has the index with the same name or on the same columns.
+// The unique index is satisfied only by the live unique index.
+func hasIndex(index tableIndex, live []liveIndex) bool {
+	for _, current := range live {
+		if current.Name == index.Name {
+			return true
+		}
+		if len(current.Columns) > 0 && strings.Join(current.Columns, ",") == strings.Join(index.Columns, ",") && (current.Unique || !index.Unique) {
+			return true
+		}
+	}
+	return false
+}
+
+// nullability returns the nullability of the column in the SQL notation.
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 	"time"
 )
 
@@ -14,6 +15,11 @@ type botStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// BotSchemaVerification is an interface for verifying the bots table.
+type BotSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // BotCRUDOperations is an interface for managing the bots table.
 type BotCRUDOperations interface {
 	Create(ctx context.Context, model *Bot, opts ...Option) error
@@ -24,6 +30,10 @@ type BotCRUDOperations interface {
 // BotSearchOperations is an interface for searching the bots table.
 type BotSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Bot, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Bot) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Bot, error)
 }
 
@@ -33,6 +43,17 @@ type BotSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) BotStorage
 }
 
+// BotAggregateOperations is an interface for the aggregate queries.
+type BotAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotAggregateRow, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+}
+
 // BotRelationLoading is an interface for loading relations.
 type BotRelationLoading interface {
 	LoadUser(ctx context.Context, model *Bot, builders ...*QueryBuilder) error
@@ -49,8 +70,10 @@ type BotRawQueryOperations interface {
 
 // BotStorage is a struct for the "bots" table.
 type BotStorage interface {
+	BotSchemaVerification
 	BotCRUDOperations
 	BotSearchOperations
+	BotAggregateOperations
 	BotRelationLoading
 	BotRawQueryOperations
 	BotSettings
@@ -112,6 +135,42 @@ func (t *botStorage) SetQueryBuilder(builder sq.StatementBuilderType) BotStorage
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *botStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *botStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "UUID", NotNull: true, Statement: ""},
+		{Name: "user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID;"},
+		{Name: "name", Type: "String", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"name\" String;"},
+		{Name: "token", Type: "String", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"token\" String;"},
+		{Name: "is_publish", Type: "Bool", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"is_publish\" Bool;"},
+		{Name: "created_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"created_at\" DateTime64(3);"},
+		{Name: "updated_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"updated_at\" DateTime64(3);"},
+		{Name: "deleted_at", Type: "Nullable(DateTime64(3))", NotNull: false, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"deleted_at\" Nullable(DateTime64(3));"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *botStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "bots_token_unique_idx", Columns: []string{"token"}, Unique: true, Statement: "ALTER TABLE \"bots\" ADD INDEX IF NOT EXISTS \"bots_token_unique_idx\" (\"token\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *botStorage) LoadUser(ctx context.Context, model *Bot, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -171,6 +230,29 @@ func (t *botStorage) LoadBatchUser(ctx context.Context, items []*Bot, builders .
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *botStorage) preload(ctx context.Context, items []*Bot, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Bot, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Bot", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Bot is a struct for the "bots" table.
 type Bot struct {
 	Id        string
@@ -203,11 +285,134 @@ func (t *Bot) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Bot, the other fields are left empty.
+func (t *Bot) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Bot) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "token":
+			dest = append(dest, &t.Token)
+		case "is_publish":
+			dest = append(dest, &t.IsPublish)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		case "deleted_at":
+			dest = append(dest, &t.DeletedAt)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Bot columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	BotColumnId        Column = "id"
+	BotColumnUserId    Column = "user_id"
+	BotColumnName      Column = "name"
+	BotColumnToken     Column = "token"
+	BotColumnIsPublish Column = "is_publish"
+	BotColumnCreatedAt Column = "created_at"
+	BotColumnUpdatedAt Column = "updated_at"
+	BotColumnDeletedAt Column = "deleted_at"
+)
+
 // BotFilters is a struct that holds filters for Bot.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type BotFilters struct {
-	Id        *string
-	UserId    *string
-	CreatedAt *time.Time
+	Id            *string
+	IdIn          []string
+	UserId        *string
+	UserIdIn      []string
+	CreatedAt     *time.Time
+	CreatedAtFrom *time.Time
+	CreatedAtTo   *time.Time
+	CreatedAtIn   []time.Time
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *BotFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	if f.CreatedAt != nil {
+		filters = append(filters, EqualsCondition{Field: "created_at", Value: *f.CreatedAt})
+	}
+	if f.CreatedAtFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtFrom})
+	}
+	if f.CreatedAtTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtTo})
+	}
+	if f.CreatedAtIn != nil {
+		filters = append(filters, InCondition{Field: "created_at", Values: toInterface(f.CreatedAtIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseBotFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseBotFilters(values url.Values) (*BotFilters, error) {
+	f := &BotFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		case "created_at":
+			f.CreatedAt, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_from":
+			f.CreatedAtFrom, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_to":
+			f.CreatedAtTo, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_in":
+			f.CreatedAtIn, err = parseFilterValues[time.Time](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // BotIdEq returns a condition that checks if the field equals the value.
@@ -390,6 +595,12 @@ func BotCreatedAtOrderBy(asc bool) FilterApplier {
 	return OrderBy("created_at", asc)
 }
 
+// BotHasUser checks if the Bot has the User relation matching the filters.
+func BotHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(BotColumnUserId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new Bot.
 func (t *botStorage) AsyncCreate(ctx context.Context, model *Bot, opts ...Option) error {
 	if model == nil {
@@ -528,9 +739,131 @@ func (t *botStorage) BatchCreate(ctx context.Context, models []*Bot, opts ...Opt
 }
 
 // FindMany finds multiple Bot based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Bot, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Bot
+	for rows.Next() {
+		model := &Bot{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Bot")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Bot rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *botStorage) FindEach(ctx context.Context, fn func(*Bot) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Bot{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Bot")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *botStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Bot", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *botStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Bot", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *botStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *botStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -580,6 +913,170 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Bot based on the provided options.
+func (t *botStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Bot, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Bot")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// BotAggregateRow is a row of the Bot Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type BotAggregateRow struct {
+	Bot
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *BotAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(BotColumnCreatedAt).
+func (r *BotAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(BotColumnCreatedAt).
+func (r *BotAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(BotColumnUpdatedAt).
+func (r *BotAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(BotColumnUpdatedAt).
+func (r *BotAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value, it is set by Min(BotColumnDeletedAt).
+func (r *BotAggregateRow) MinDeletedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value, it is set by Max(BotColumnDeletedAt).
+func (r *BotAggregateRow) MaxDeletedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Bot rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(BotColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *botStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Bot")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Bot", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -590,13 +1087,22 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 		}
 	}()
 
-	var results []*Bot
+	var results []*BotAggregateRow
 	for rows.Next() {
-		model := &Bot{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Bot")
+		row := &BotAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Bot.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Bot aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -606,20 +1112,91 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 	return results, nil
 }
 
-// FindOne finds a single Bot based on the provided options.
-func (t *botStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Bot, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Bot rows found by the builders.
+func (t *botStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*BotAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Bot")
+		return nil, err
 	}
+	if len(rows) == 0 {
+		return &BotAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// MinCreatedAt returns the smallest created_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
 	}
+	return row.MinCreatedAt(), nil
+}
 
-	return results[0], nil
+// MaxCreatedAt returns the largest created_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinDeletedAt(), nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxDeletedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Bot rows.
+func (t *botStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Min(BotColumnCreatedAt), Max(BotColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(BotColumnUpdatedAt), Max(BotColumnUpdatedAt):
+		return new(*time.Time), nil
+	case Min(BotColumnDeletedAt), Max(BotColumnDeletedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Bot", aggregation)
 }
 
 // Select executes a raw query and returns the result.
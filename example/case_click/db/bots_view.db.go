@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 	"time"
 )
 
@@ -14,6 +15,11 @@ type botViewStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// BotViewSchemaVerification is an interface for verifying the bots_view table.
+type BotViewSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // BotViewCRUDOperations is an interface for managing the bots_view table.
 type BotViewCRUDOperations interface {
 	Create(ctx context.Context, model *BotView, opts ...Option) error
@@ -24,6 +30,10 @@ type BotViewCRUDOperations interface {
 // BotViewSearchOperations is an interface for searching the bots_view table.
 type BotViewSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*BotView, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*BotView) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*BotView, error)
 }
 
@@ -33,6 +43,17 @@ type BotViewSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) BotViewStorage
 }
 
+// BotViewAggregateOperations is an interface for the aggregate queries.
+type BotViewAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotViewAggregateRow, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+}
+
 // BotViewRelationLoading is an interface for loading relations.
 type BotViewRelationLoading interface {
 	LoadUser(ctx context.Context, model *BotView, builders ...*QueryBuilder) error
@@ -49,8 +70,10 @@ type BotViewRawQueryOperations interface {
 
 // BotViewStorage is a struct for the "bots_view" table.
 type BotViewStorage interface {
+	BotViewSchemaVerification
 	BotViewCRUDOperations
 	BotViewSearchOperations
+	BotViewAggregateOperations
 	BotViewRelationLoading
 	BotViewRawQueryOperations
 	BotViewSettings
@@ -112,6 +135,42 @@ func (t *botViewStorage) SetQueryBuilder(builder sq.StatementBuilderType) BotVie
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *botViewStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *botViewStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "UUID", NotNull: true, Statement: ""},
+		{Name: "user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID;"},
+		{Name: "name", Type: "String", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"name\" String;"},
+		{Name: "token", Type: "String", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"token\" String;"},
+		{Name: "is_publish", Type: "Bool", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"is_publish\" Bool;"},
+		{Name: "created_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"created_at\" DateTime64(3);"},
+		{Name: "updated_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"updated_at\" DateTime64(3);"},
+		{Name: "deleted_at", Type: "Nullable(DateTime64(3))", NotNull: false, Statement: "ALTER TABLE \"bots_view\" ADD COLUMN IF NOT EXISTS \"deleted_at\" Nullable(DateTime64(3));"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *botViewStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "bots_view_token_unique_idx", Columns: []string{"token"}, Unique: true, Statement: "ALTER TABLE \"bots_view\" ADD INDEX IF NOT EXISTS \"bots_view_token_unique_idx\" (\"token\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *botViewStorage) LoadUser(ctx context.Context, model *BotView, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -171,6 +230,29 @@ func (t *botViewStorage) LoadBatchUser(ctx context.Context, items []*BotView, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *botViewStorage) preload(ctx context.Context, items []*BotView, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*BotView, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of BotView", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // BotView is a struct for the "bots_view" table.
 type BotView struct {
 	Id        string
@@ -203,11 +285,134 @@ func (t *BotView) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the BotView, the other fields are left empty.
+func (t *BotView) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *BotView) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "token":
+			dest = append(dest, &t.Token)
+		case "is_publish":
+			dest = append(dest, &t.IsPublish)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		case "deleted_at":
+			dest = append(dest, &t.DeletedAt)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// BotView columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	BotViewColumnId        Column = "id"
+	BotViewColumnUserId    Column = "user_id"
+	BotViewColumnName      Column = "name"
+	BotViewColumnToken     Column = "token"
+	BotViewColumnIsPublish Column = "is_publish"
+	BotViewColumnCreatedAt Column = "created_at"
+	BotViewColumnUpdatedAt Column = "updated_at"
+	BotViewColumnDeletedAt Column = "deleted_at"
+)
+
 // BotViewFilters is a struct that holds filters for BotView.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type BotViewFilters struct {
-	Id        *string
-	UserId    *string
-	CreatedAt *time.Time
+	Id            *string
+	IdIn          []string
+	UserId        *string
+	UserIdIn      []string
+	CreatedAt     *time.Time
+	CreatedAtFrom *time.Time
+	CreatedAtTo   *time.Time
+	CreatedAtIn   []time.Time
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *BotViewFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	if f.CreatedAt != nil {
+		filters = append(filters, EqualsCondition{Field: "created_at", Value: *f.CreatedAt})
+	}
+	if f.CreatedAtFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtFrom})
+	}
+	if f.CreatedAtTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtTo})
+	}
+	if f.CreatedAtIn != nil {
+		filters = append(filters, InCondition{Field: "created_at", Values: toInterface(f.CreatedAtIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseBotViewFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseBotViewFilters(values url.Values) (*BotViewFilters, error) {
+	f := &BotViewFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		case "created_at":
+			f.CreatedAt, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_from":
+			f.CreatedAtFrom, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_to":
+			f.CreatedAtTo, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_in":
+			f.CreatedAtIn, err = parseFilterValues[time.Time](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // BotViewIdEq returns a condition that checks if the field equals the value.
@@ -390,6 +595,12 @@ func BotViewCreatedAtOrderBy(asc bool) FilterApplier {
 	return OrderBy("created_at", asc)
 }
 
+// BotViewHasUser checks if the BotView has the User relation matching the filters.
+func BotViewHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(BotViewColumnUserId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new BotView.
 func (t *botViewStorage) AsyncCreate(ctx context.Context, model *BotView, opts ...Option) error {
 	if model == nil {
@@ -528,9 +739,131 @@ func (t *botViewStorage) BatchCreate(ctx context.Context, models []*BotView, opt
 }
 
 // FindMany finds multiple BotView based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *botViewStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*BotView, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*BotView
+	for rows.Next() {
+		model := &BotView{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan BotView")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the BotView rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *botViewStorage) FindEach(ctx context.Context, fn func(*BotView) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &BotView{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan BotView")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *botViewStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of BotView", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *botViewStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of BotView", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *botViewStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *botViewStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -580,6 +913,170 @@ func (t *botViewStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single BotView based on the provided options.
+func (t *botViewStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*BotView, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne BotView")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// BotViewAggregateRow is a row of the BotView Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type BotViewAggregateRow struct {
+	BotView
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *BotViewAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(BotViewColumnCreatedAt).
+func (r *BotViewAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(BotViewColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(BotViewColumnCreatedAt).
+func (r *BotViewAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(BotViewColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(BotViewColumnUpdatedAt).
+func (r *BotViewAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(BotViewColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(BotViewColumnUpdatedAt).
+func (r *BotViewAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(BotViewColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value, it is set by Min(BotViewColumnDeletedAt).
+func (r *BotViewAggregateRow) MinDeletedAt() *time.Time {
+	if v, ok := r.values[Min(BotViewColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value, it is set by Max(BotViewColumnDeletedAt).
+func (r *BotViewAggregateRow) MaxDeletedAt() *time.Time {
+	if v, ok := r.values[Max(BotViewColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the BotView rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(BotViewColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *botViewStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotViewAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of BotView")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of BotView", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -590,13 +1087,22 @@ func (t *botViewStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}()
 
-	var results []*BotView
+	var results []*BotViewAggregateRow
 	for rows.Next() {
-		model := &BotView{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan BotView")
+		row := &BotViewAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.BotView.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan BotView aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -606,20 +1112,91 @@ func (t *botViewStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
-// FindOne finds a single BotView based on the provided options.
-func (t *botViewStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*BotView, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the BotView rows found by the builders.
+func (t *botViewStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*BotViewAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne BotView")
+		return nil, err
 	}
+	if len(rows) == 0 {
+		return &BotViewAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// MinCreatedAt returns the smallest created_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotViewColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
 	}
+	return row.MinCreatedAt(), nil
+}
 
-	return results[0], nil
+// MaxCreatedAt returns the largest created_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotViewColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotViewColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotViewColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotViewColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinDeletedAt(), nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value of the BotView rows found by the builders,
+// it is nil if there are no rows.
+func (t *botViewStorage) MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotViewColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxDeletedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the BotView rows.
+func (t *botViewStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Min(BotViewColumnCreatedAt), Max(BotViewColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(BotViewColumnUpdatedAt), Max(BotViewColumnUpdatedAt):
+		return new(*time.Time), nil
+	case Min(BotViewColumnDeletedAt), Max(BotViewColumnDeletedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of BotView", aggregation)
 }
 
 // Select executes a raw query and returns the result.
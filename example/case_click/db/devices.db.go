@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 )
 
 // deviceStorage is a struct for the "devices" table.
@@ -13,6 +14,11 @@ type deviceStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// DeviceSchemaVerification is an interface for verifying the devices table.
+type DeviceSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // DeviceCRUDOperations is an interface for managing the devices table.
 type DeviceCRUDOperations interface {
 	Create(ctx context.Context, model *Device, opts ...Option) error
@@ -23,6 +29,10 @@ type DeviceCRUDOperations interface {
 // DeviceSearchOperations is an interface for searching the devices table.
 type DeviceSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Device, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Device) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Device, error)
 }
 
@@ -32,6 +42,11 @@ type DeviceSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) DeviceStorage
 }
 
+// DeviceAggregateOperations is an interface for the aggregate queries.
+type DeviceAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*DeviceAggregateRow, error)
+}
+
 // DeviceRelationLoading is an interface for loading relations.
 type DeviceRelationLoading interface {
 }
@@ -46,8 +61,10 @@ type DeviceRawQueryOperations interface {
 
 // DeviceStorage is a struct for the "devices" table.
 type DeviceStorage interface {
+	DeviceSchemaVerification
 	DeviceCRUDOperations
 	DeviceSearchOperations
+	DeviceAggregateOperations
 	DeviceRelationLoading
 	DeviceRawQueryOperations
 	DeviceSettings
@@ -109,6 +126,47 @@ func (t *deviceStorage) SetQueryBuilder(builder sq.StatementBuilderType) DeviceS
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *deviceStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *deviceStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "name", Type: "String", NotNull: true, Statement: "ALTER TABLE \"devices\" ADD COLUMN IF NOT EXISTS \"name\" String;"},
+		{Name: "value", Type: "String", NotNull: true, Statement: "ALTER TABLE \"devices\" ADD COLUMN IF NOT EXISTS \"value\" String;"},
+		{Name: "user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"devices\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID;"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *deviceStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "devices_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "ALTER TABLE \"devices\" ADD INDEX IF NOT EXISTS \"devices_user_id_unique_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "devices_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "ALTER TABLE \"devices\" ADD INDEX IF NOT EXISTS \"devices_user_id_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *deviceStorage) preload(ctx context.Context, items []*Device, builders ...*QueryBuilder) error {
+	if relations, _ := preloadRelations(builders); len(relations) > 0 {
+		return errors.Errorf("unknown relation %s of Device", relations[0])
+	}
+
+	return nil
+}
+
 // Device is a struct for the "devices" table.
 type Device struct {
 	Name   string
@@ -130,9 +188,83 @@ func (t *Device) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Device, the other fields are left empty.
+func (t *Device) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Device) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "name":
+			dest = append(dest, &t.Name)
+		case "value":
+			dest = append(dest, &t.Value)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Device columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	DeviceColumnName   Column = "name"
+	DeviceColumnValue  Column = "value"
+	DeviceColumnUserId Column = "user_id"
+)
+
 // DeviceFilters is a struct that holds filters for Device.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type DeviceFilters struct {
-	UserId *string
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *DeviceFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseDeviceFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseDeviceFilters(values url.Values) (*DeviceFilters, error) {
+	f := &DeviceFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // DeviceUserIdEq returns a condition that checks if the field equals the value.
@@ -318,9 +450,131 @@ func (t *deviceStorage) BatchCreate(ctx context.Context, models []*Device, opts
 }
 
 // FindMany finds multiple Device based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Device, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Device
+	for rows.Next() {
+		model := &Device{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Device")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Device rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *deviceStorage) FindEach(ctx context.Context, fn func(*Device) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Device{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Device")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *deviceStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Device", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *deviceStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Device", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *deviceStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *deviceStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -370,6 +624,122 @@ func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder)
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Device based on the provided options.
+func (t *deviceStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Device, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Device")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// DeviceAggregateRow is a row of the Device Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type DeviceAggregateRow struct {
+	Device
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *DeviceAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// Aggregate groups the Device rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(DeviceColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *deviceStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*DeviceAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Device")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Device", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -380,13 +750,22 @@ func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder)
 		}
 	}()
 
-	var results []*Device
+	var results []*DeviceAggregateRow
 	for rows.Next() {
-		model := &Device{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Device")
+		row := &DeviceAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Device.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Device aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -396,20 +775,25 @@ func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder)
 	return results, nil
 }
 
-// FindOne finds a single Device based on the provided options.
-func (t *deviceStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Device, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Device rows found by the builders.
+func (t *deviceStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*DeviceAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Device")
+		return nil, err
 	}
-
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+	if len(rows) == 0 {
+		return &DeviceAggregateRow{}, nil
 	}
+	return rows[0], nil
+}
 
-	return results[0], nil
+// aggregateDest returns the scan destination of the aggregation of the Device rows.
+func (t *deviceStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Device", aggregation)
 }
 
 // Select executes a raw query and returns the result.
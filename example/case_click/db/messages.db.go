@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 )
 
 // messageStorage is a struct for the "messages" table.
@@ -13,6 +14,11 @@ type messageStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// MessageSchemaVerification is an interface for verifying the messages table.
+type MessageSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // MessageCRUDOperations is an interface for managing the messages table.
 type MessageCRUDOperations interface {
 	Create(ctx context.Context, model *Message, opts ...Option) error
@@ -23,6 +29,10 @@ type MessageCRUDOperations interface {
 // MessageSearchOperations is an interface for searching the messages table.
 type MessageSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Message, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Message) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Message, error)
 }
 
@@ -32,6 +42,11 @@ type MessageSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) MessageStorage
 }
 
+// MessageAggregateOperations is an interface for the aggregate queries.
+type MessageAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*MessageAggregateRow, error)
+}
+
 // MessageRelationLoading is an interface for loading relations.
 type MessageRelationLoading interface {
 	LoadBot(ctx context.Context, model *Message, builders ...*QueryBuilder) error
@@ -52,8 +67,10 @@ type MessageRawQueryOperations interface {
 
 // MessageStorage is a struct for the "messages" table.
 type MessageStorage interface {
+	MessageSchemaVerification
 	MessageCRUDOperations
 	MessageSearchOperations
+	MessageAggregateOperations
 	MessageRelationLoading
 	MessageRawQueryOperations
 	MessageSettings
@@ -115,6 +132,41 @@ func (t *messageStorage) SetQueryBuilder(builder sq.StatementBuilderType) Messag
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *messageStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *messageStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "UUID", NotNull: true, Statement: ""},
+		{Name: "from_user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"from_user_id\" UUID;"},
+		{Name: "to_user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"to_user_id\" UUID;"},
+		{Name: "bot_id", Type: "Nullable(UUID)", NotNull: false, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"bot_id\" Nullable(UUID);"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *messageStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "messages_from_user_id_unique_idx", Columns: []string{"from_user_id"}, Unique: true, Statement: "ALTER TABLE \"messages\" ADD INDEX IF NOT EXISTS \"messages_from_user_id_unique_idx\" (\"from_user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "messages_to_user_id_unique_idx", Columns: []string{"to_user_id"}, Unique: true, Statement: "ALTER TABLE \"messages\" ADD INDEX IF NOT EXISTS \"messages_to_user_id_unique_idx\" (\"to_user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "messages_from_user_id_idx", Columns: []string{"from_user_id"}, Unique: false, Statement: "ALTER TABLE \"messages\" ADD INDEX IF NOT EXISTS \"messages_from_user_id_idx\" (\"from_user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "messages_to_user_id_idx", Columns: []string{"to_user_id"}, Unique: false, Statement: "ALTER TABLE \"messages\" ADD INDEX IF NOT EXISTS \"messages_to_user_id_idx\" (\"to_user_id\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadBot loads the Bot relation.
 func (t *messageStorage) LoadBot(ctx context.Context, model *Message, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -309,6 +361,33 @@ func (t *messageStorage) LoadBatchToUser(ctx context.Context, items []*Message,
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *messageStorage) preload(ctx context.Context, items []*Message, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Message, ...*QueryBuilder) error
+		switch relation {
+		case "Bot":
+			load = t.LoadBatchBot
+		case "FromUser":
+			load = t.LoadBatchFromUser
+		case "ToUser":
+			load = t.LoadBatchToUser
+		default:
+			return errors.Errorf("unknown relation %s of Message", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Message is a struct for the "messages" table.
 type Message struct {
 	Id         string
@@ -335,11 +414,110 @@ func (t *Message) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Message, the other fields are left empty.
+func (t *Message) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Message) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "from_user_id":
+			dest = append(dest, &t.FromUserId)
+		case "to_user_id":
+			dest = append(dest, &t.ToUserId)
+		case "bot_id":
+			dest = append(dest, &t.BotId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Message columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	MessageColumnId         Column = "id"
+	MessageColumnFromUserId Column = "from_user_id"
+	MessageColumnToUserId   Column = "to_user_id"
+	MessageColumnBotId      Column = "bot_id"
+)
+
 // MessageFilters is a struct that holds filters for Message.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type MessageFilters struct {
-	Id       *string
-	ToUserId *string
-	BotId    *string
+	Id         *string
+	IdIn       []string
+	ToUserId   *string
+	ToUserIdIn []string
+	BotId      *string
+	BotIdIn    []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *MessageFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.ToUserId != nil {
+		filters = append(filters, EqualsCondition{Field: "to_user_id", Value: *f.ToUserId})
+	}
+	if f.ToUserIdIn != nil {
+		filters = append(filters, InCondition{Field: "to_user_id", Values: toInterface(f.ToUserIdIn)})
+	}
+	if f.BotId != nil {
+		filters = append(filters, EqualsCondition{Field: "bot_id", Value: *f.BotId})
+	}
+	if f.BotIdIn != nil {
+		filters = append(filters, InCondition{Field: "bot_id", Values: toInterface(f.BotIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseMessageFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseMessageFilters(values url.Values) (*MessageFilters, error) {
+	f := &MessageFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "to_user_id":
+			f.ToUserId, err = parseFilterValue[string](key, values[key])
+		case "to_user_id_in":
+			f.ToUserIdIn, err = parseFilterValues[string](key, values[key])
+		case "bot_id":
+			f.BotId, err = parseFilterValue[string](key, values[key])
+		case "bot_id_in":
+			f.BotIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // MessageIdEq returns a condition that checks if the field equals the value.
@@ -547,6 +725,24 @@ func MessageBotIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("bot_id", asc)
 }
 
+// MessageHasBot checks if the Message has the Bot relation matching the filters.
+func MessageHasBot(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(BotColumnId)).From((&Bot{}).TableName())
+	return InSubqueryCondition{Field: string(MessageColumnBotId), Query: query, Conditions: filters}
+}
+
+// MessageHasFromUser checks if the Message has the FromUser relation matching the filters.
+func MessageHasFromUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(MessageColumnFromUserId), Query: query, Conditions: filters}
+}
+
+// MessageHasToUser checks if the Message has the ToUser relation matching the filters.
+func MessageHasToUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(MessageColumnToUserId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new Message.
 func (t *messageStorage) AsyncCreate(ctx context.Context, model *Message, opts ...Option) error {
 	if model == nil {
@@ -665,9 +861,131 @@ func (t *messageStorage) BatchCreate(ctx context.Context, models []*Message, opt
 }
 
 // FindMany finds multiple Message based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Message, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Message
+	for rows.Next() {
+		model := &Message{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Message")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Message rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *messageStorage) FindEach(ctx context.Context, fn func(*Message) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Message{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Message")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *messageStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Message", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *messageStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Message", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *messageStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *messageStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -717,6 +1035,122 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Message based on the provided options.
+func (t *messageStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Message, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Message")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// MessageAggregateRow is a row of the Message Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type MessageAggregateRow struct {
+	Message
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *MessageAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// Aggregate groups the Message rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(MessageColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *messageStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*MessageAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Message")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Message", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -727,13 +1161,22 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}()
 
-	var results []*Message
+	var results []*MessageAggregateRow
 	for rows.Next() {
-		model := &Message{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Message")
+		row := &MessageAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Message.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Message aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -743,20 +1186,25 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
-// FindOne finds a single Message based on the provided options.
-func (t *messageStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Message, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Message rows found by the builders.
+func (t *messageStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*MessageAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Message")
+		return nil, err
 	}
-
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+	if len(rows) == 0 {
+		return &MessageAggregateRow{}, nil
 	}
+	return rows[0], nil
+}
 
-	return results[0], nil
+// aggregateDest returns the scan destination of the aggregation of the Message rows.
+func (t *messageStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Message", aggregation)
 }
 
 // Select executes a raw query and returns the result.
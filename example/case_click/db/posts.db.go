@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 )
 
 // postStorage is a struct for the "posts" table.
@@ -13,6 +14,11 @@ type postStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// PostSchemaVerification is an interface for verifying the posts table.
+type PostSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // PostCRUDOperations is an interface for managing the posts table.
 type PostCRUDOperations interface {
 	Create(ctx context.Context, model *Post, opts ...Option) error
@@ -23,6 +29,10 @@ type PostCRUDOperations interface {
 // PostSearchOperations is an interface for searching the posts table.
 type PostSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Post, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Post) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Post, error)
 }
 
@@ -32,6 +42,15 @@ type PostSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) PostStorage
 }
 
+// PostAggregateOperations is an interface for the aggregate queries.
+type PostAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*PostAggregateRow, error)
+	SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+}
+
 // PostRelationLoading is an interface for loading relations.
 type PostRelationLoading interface {
 	LoadAuthor(ctx context.Context, model *Post, builders ...*QueryBuilder) error
@@ -48,8 +67,10 @@ type PostRawQueryOperations interface {
 
 // PostStorage is a struct for the "posts" table.
 type PostStorage interface {
+	PostSchemaVerification
 	PostCRUDOperations
 	PostSearchOperations
+	PostAggregateOperations
 	PostRelationLoading
 	PostRawQueryOperations
 	PostSettings
@@ -111,6 +132,40 @@ func (t *postStorage) SetQueryBuilder(builder sq.StatementBuilderType) PostStora
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *postStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *postStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "Int32", NotNull: true, Statement: ""},
+		{Name: "title", Type: "String", NotNull: true, Statement: "ALTER TABLE \"posts\" ADD COLUMN IF NOT EXISTS \"title\" String;"},
+		{Name: "body", Type: "String", NotNull: true, Statement: "ALTER TABLE \"posts\" ADD COLUMN IF NOT EXISTS \"body\" String;"},
+		{Name: "author_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"posts\" ADD COLUMN IF NOT EXISTS \"author_id\" UUID;"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *postStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "posts_author_id_unique_idx", Columns: []string{"author_id"}, Unique: true, Statement: "ALTER TABLE \"posts\" ADD INDEX IF NOT EXISTS \"posts_author_id_unique_idx\" (\"author_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "posts_title_idx", Columns: []string{"title"}, Unique: false, Statement: "ALTER TABLE \"posts\" ADD INDEX IF NOT EXISTS \"posts_title_idx\" (\"title\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "posts_author_id_idx", Columns: []string{"author_id"}, Unique: false, Statement: "ALTER TABLE \"posts\" ADD INDEX IF NOT EXISTS \"posts_author_id_idx\" (\"author_id\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadAuthor loads the Author relation.
 func (t *postStorage) LoadAuthor(ctx context.Context, model *Post, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -170,6 +225,29 @@ func (t *postStorage) LoadBatchAuthor(ctx context.Context, items []*Post, builde
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *postStorage) preload(ctx context.Context, items []*Post, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Post, ...*QueryBuilder) error
+		switch relation {
+		case "Author":
+			load = t.LoadBatchAuthor
+		default:
+			return errors.Errorf("unknown relation %s of Post", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Post is a struct for the "posts" table.
 type Post struct {
 	Id       int32
@@ -194,10 +272,110 @@ func (t *Post) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Post, the other fields are left empty.
+func (t *Post) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Post) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "title":
+			dest = append(dest, &t.Title)
+		case "body":
+			dest = append(dest, &t.Body)
+		case "author_id":
+			dest = append(dest, &t.AuthorId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Post columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	PostColumnId       Column = "id"
+	PostColumnTitle    Column = "title"
+	PostColumnBody     Column = "body"
+	PostColumnAuthorId Column = "author_id"
+)
+
 // PostFilters is a struct that holds filters for Post.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type PostFilters struct {
-	Id       *int32
-	AuthorId *string
+	Id         *int32
+	IdFrom     *int32
+	IdTo       *int32
+	IdIn       []int32
+	AuthorId   *string
+	AuthorIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *PostFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "id", Value: *f.IdFrom})
+	}
+	if f.IdTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "id", Value: *f.IdTo})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.AuthorId != nil {
+		filters = append(filters, EqualsCondition{Field: "author_id", Value: *f.AuthorId})
+	}
+	if f.AuthorIdIn != nil {
+		filters = append(filters, InCondition{Field: "author_id", Values: toInterface(f.AuthorIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParsePostFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParsePostFilters(values url.Values) (*PostFilters, error) {
+	f := &PostFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[int32](key, values[key])
+		case "id_from":
+			f.IdFrom, err = parseFilterValue[int32](key, values[key])
+		case "id_to":
+			f.IdTo, err = parseFilterValue[int32](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[int32](key, values[key])
+		case "author_id":
+			f.AuthorId, err = parseFilterValue[string](key, values[key])
+		case "author_id_in":
+			f.AuthorIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // PostIdEq returns a condition that checks if the field equals the value.
@@ -315,6 +493,12 @@ func PostAuthorIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("author_id", asc)
 }
 
+// PostHasAuthor checks if the Post has the Author relation matching the filters.
+func PostHasAuthor(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(PostColumnAuthorId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new Post.
 func (t *postStorage) AsyncCreate(ctx context.Context, model *Post, opts ...Option) error {
 	if model == nil {
@@ -433,9 +617,131 @@ func (t *postStorage) BatchCreate(ctx context.Context, models []*Post, opts ...O
 }
 
 // FindMany finds multiple Post based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Post, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Post
+	for rows.Next() {
+		model := &Post{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Post")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Post rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *postStorage) FindEach(ctx context.Context, fn func(*Post) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Post{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Post")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *postStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Post", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *postStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Post", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *postStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *postStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -485,6 +791,154 @@ func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Post based on the provided options.
+func (t *postStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Post, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Post")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// PostAggregateRow is a row of the Post Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type PostAggregateRow struct {
+	Post
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *PostAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// SumId returns the sum of the id values, it is set by Sum(PostColumnId).
+func (r *PostAggregateRow) SumId() int64 {
+	if v, ok := r.values[Sum(PostColumnId)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgId returns the average of the id values, it is set by Avg(PostColumnId).
+func (r *PostAggregateRow) AvgId() *float64 {
+	if v, ok := r.values[Avg(PostColumnId)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinId returns the smallest id value, it is set by Min(PostColumnId).
+func (r *PostAggregateRow) MinId() *int32 {
+	if v, ok := r.values[Min(PostColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxId returns the largest id value, it is set by Max(PostColumnId).
+func (r *PostAggregateRow) MaxId() *int32 {
+	if v, ok := r.values[Max(PostColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Post rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(PostColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *postStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*PostAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Post")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Post", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -495,13 +949,22 @@ func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 		}
 	}()
 
-	var results []*Post
+	var results []*PostAggregateRow
 	for rows.Next() {
-		model := &Post{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Post")
+		row := &PostAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Post.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Post aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -511,20 +974,70 @@ func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	return results, nil
 }
 
-// FindOne finds a single Post based on the provided options.
-func (t *postStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Post, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Post rows found by the builders.
+func (t *postStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*PostAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Post")
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &PostAggregateRow{}, nil
 	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// SumId returns the sum of the id values of the Post rows found by the builders.
+func (t *postStorage) SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(PostColumnId), builders)
+	if err != nil {
+		return 0, err
 	}
+	return row.SumId(), nil
+}
 
-	return results[0], nil
+// AvgId returns the average of the id values of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgId(), nil
+}
+
+// MinId returns the smallest id value of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinId(), nil
+}
+
+// MaxId returns the largest id value of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxId(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Post rows.
+func (t *postStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Sum(PostColumnId):
+		return new(*int64), nil
+	case Avg(PostColumnId):
+		return new(*float64), nil
+	case Min(PostColumnId), Max(PostColumnId):
+		return new(*int32), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Post", aggregation)
 }
 
 // Select executes a raw query and returns the result.
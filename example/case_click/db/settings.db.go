@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 )
 
 // settingStorage is a struct for the "settings" table.
@@ -13,6 +14,11 @@ type settingStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// SettingSchemaVerification is an interface for verifying the settings table.
+type SettingSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // SettingCRUDOperations is an interface for managing the settings table.
 type SettingCRUDOperations interface {
 	Create(ctx context.Context, model *Setting, opts ...Option) error
@@ -23,6 +29,10 @@ type SettingCRUDOperations interface {
 // SettingSearchOperations is an interface for searching the settings table.
 type SettingSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Setting, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error)
 }
 
@@ -32,6 +42,15 @@ type SettingSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) SettingStorage
 }
 
+// SettingAggregateOperations is an interface for the aggregate queries.
+type SettingAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error)
+	SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+}
+
 // SettingRelationLoading is an interface for loading relations.
 type SettingRelationLoading interface {
 	LoadUser(ctx context.Context, model *Setting, builders ...*QueryBuilder) error
@@ -48,8 +67,10 @@ type SettingRawQueryOperations interface {
 
 // SettingStorage is a struct for the "settings" table.
 type SettingStorage interface {
+	SettingSchemaVerification
 	SettingCRUDOperations
 	SettingSearchOperations
+	SettingAggregateOperations
 	SettingRelationLoading
 	SettingRawQueryOperations
 	SettingSettings
@@ -111,6 +132,40 @@ func (t *settingStorage) SetQueryBuilder(builder sq.StatementBuilderType) Settin
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *settingStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *settingStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "Int32", NotNull: true, Statement: ""},
+		{Name: "name", Type: "String", NotNull: true, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"name\" String;"},
+		{Name: "value", Type: "String", NotNull: true, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"value\" String;"},
+		{Name: "user_id", Type: "UUID", NotNull: true, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID;"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *settingStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "settings_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "ALTER TABLE \"settings\" ADD INDEX IF NOT EXISTS \"settings_user_id_unique_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "settings_name_idx", Columns: []string{"name"}, Unique: false, Statement: "ALTER TABLE \"settings\" ADD INDEX IF NOT EXISTS \"settings_name_idx\" (\"name\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "settings_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "ALTER TABLE \"settings\" ADD INDEX IF NOT EXISTS \"settings_user_id_idx\" (\"user_id\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *settingStorage) LoadUser(ctx context.Context, model *Setting, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -170,6 +225,29 @@ func (t *settingStorage) LoadBatchUser(ctx context.Context, items []*Setting, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *settingStorage) preload(ctx context.Context, items []*Setting, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Setting, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Setting", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Setting is a struct for the "settings" table.
 type Setting struct {
 	Id     int32
@@ -194,10 +272,110 @@ func (t *Setting) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Setting, the other fields are left empty.
+func (t *Setting) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Setting) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "value":
+			dest = append(dest, &t.Value)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Setting columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	SettingColumnId     Column = "id"
+	SettingColumnName   Column = "name"
+	SettingColumnValue  Column = "value"
+	SettingColumnUserId Column = "user_id"
+)
+
 // SettingFilters is a struct that holds filters for Setting.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type SettingFilters struct {
-	Id     *int32
-	UserId *string
+	Id       *int32
+	IdFrom   *int32
+	IdTo     *int32
+	IdIn     []int32
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *SettingFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "id", Value: *f.IdFrom})
+	}
+	if f.IdTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "id", Value: *f.IdTo})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseSettingFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseSettingFilters(values url.Values) (*SettingFilters, error) {
+	f := &SettingFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[int32](key, values[key])
+		case "id_from":
+			f.IdFrom, err = parseFilterValue[int32](key, values[key])
+		case "id_to":
+			f.IdTo, err = parseFilterValue[int32](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[int32](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // SettingIdEq returns a condition that checks if the field equals the value.
@@ -315,6 +493,12 @@ func SettingUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// SettingHasUser checks if the Setting has the User relation matching the filters.
+func SettingHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(SettingColumnUserId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new Setting.
 func (t *settingStorage) AsyncCreate(ctx context.Context, model *Setting, opts ...Option) error {
 	if model == nil {
@@ -433,9 +617,131 @@ func (t *settingStorage) BatchCreate(ctx context.Context, models []*Setting, opt
 }
 
 // FindMany finds multiple Setting based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Setting, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Setting
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Setting")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Setting rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *settingStorage) FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan Setting")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *settingStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Setting", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *settingStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Setting", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *settingStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *settingStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -485,6 +791,154 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single Setting based on the provided options.
+func (t *settingStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne Setting")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// SettingAggregateRow is a row of the Setting Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type SettingAggregateRow struct {
+	Setting
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *SettingAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// SumId returns the sum of the id values, it is set by Sum(SettingColumnId).
+func (r *SettingAggregateRow) SumId() int64 {
+	if v, ok := r.values[Sum(SettingColumnId)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgId returns the average of the id values, it is set by Avg(SettingColumnId).
+func (r *SettingAggregateRow) AvgId() *float64 {
+	if v, ok := r.values[Avg(SettingColumnId)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinId returns the smallest id value, it is set by Min(SettingColumnId).
+func (r *SettingAggregateRow) MinId() *int32 {
+	if v, ok := r.values[Min(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxId returns the largest id value, it is set by Max(SettingColumnId).
+func (r *SettingAggregateRow) MaxId() *int32 {
+	if v, ok := r.values[Max(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Setting rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(SettingColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *settingStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Setting")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Setting", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -495,13 +949,22 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}()
 
-	var results []*Setting
+	var results []*SettingAggregateRow
 	for rows.Next() {
-		model := &Setting{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan Setting")
+		row := &SettingAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Setting.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Setting aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -511,20 +974,70 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
-// FindOne finds a single Setting based on the provided options.
-func (t *settingStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the Setting rows found by the builders.
+func (t *settingStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*SettingAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne Setting")
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &SettingAggregateRow{}, nil
 	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// SumId returns the sum of the id values of the Setting rows found by the builders.
+func (t *settingStorage) SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(SettingColumnId), builders)
+	if err != nil {
+		return 0, err
 	}
+	return row.SumId(), nil
+}
 
-	return results[0], nil
+// AvgId returns the average of the id values of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgId(), nil
+}
+
+// MinId returns the smallest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinId(), nil
+}
+
+// MaxId returns the largest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxId(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Setting rows.
+func (t *settingStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Sum(SettingColumnId):
+		return new(*int64), nil
+	case Avg(SettingColumnId):
+		return new(*float64), nil
+	case Min(SettingColumnId), Max(SettingColumnId):
+		return new(*int32), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Setting", aggregation)
 }
 
 // Select executes a raw query and returns the result.
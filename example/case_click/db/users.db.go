@@ -5,6 +5,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	"net/url"
 	"time"
 )
 
@@ -14,6 +15,11 @@ type userStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// UserSchemaVerification is an interface for verifying the users table.
+type UserSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // UserCRUDOperations is an interface for managing the users table.
 type UserCRUDOperations interface {
 	Create(ctx context.Context, model *User, opts ...Option) error
@@ -24,6 +30,10 @@ type UserCRUDOperations interface {
 // UserSearchOperations is an interface for searching the users table.
 type UserSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*User, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*User) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*User, error)
 }
 
@@ -33,6 +43,19 @@ type UserSettings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) UserStorage
 }
 
+// UserAggregateOperations is an interface for the aggregate queries.
+type UserAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*UserAggregateRow, error)
+	SumAge(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgAge(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+}
+
 // UserRelationLoading is an interface for loading relations.
 type UserRelationLoading interface {
 	LoadDevice(ctx context.Context, model *User, builders ...*QueryBuilder) error
@@ -55,8 +78,10 @@ type UserRawQueryOperations interface {
 
 // UserStorage is a struct for the "users" table.
 type UserStorage interface {
+	UserSchemaVerification
 	UserCRUDOperations
 	UserSearchOperations
+	UserAggregateOperations
 	UserRelationLoading
 	UserRawQueryOperations
 	UserSettings
@@ -118,6 +143,48 @@ func (t *userStorage) SetQueryBuilder(builder sq.StatementBuilderType) UserStora
 	return t
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *userStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *userStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "UUID", NotNull: true, Statement: ""},
+		{Name: "name", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"name\" String;"},
+		{Name: "age", Type: "Int32", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"age\" Int32;"},
+		{Name: "email", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"email\" String;"},
+		{Name: "last_name", Type: "Nullable(String)", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"last_name\" Nullable(String);"},
+		{Name: "created_at", Type: "DateTime64(3)", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"created_at\" DateTime64(3) DEFAULT now();"},
+		{Name: "updated_at", Type: "Nullable(DateTime64(3))", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"updated_at\" Nullable(DateTime64(3));"},
+		{Name: "notification_settings", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"notification_settings\" String;"},
+		{Name: "phones", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"phones\" String;"},
+		{Name: "balls", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"balls\" String;"},
+		{Name: "numrs", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"numrs\" String;"},
+		{Name: "comments", Type: "String", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"comments\" String;"},
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *userStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "users_email_unique_idx", Columns: []string{"email"}, Unique: true, Statement: "ALTER TABLE \"users\" ADD INDEX IF NOT EXISTS \"users_email_unique_idx\" (\"email\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "users_unique_idx_name_email", Columns: []string{"name", "email"}, Unique: true, Statement: "ALTER TABLE \"users\" ADD INDEX IF NOT EXISTS \"users_unique_idx_name_email\" (\"name\", \"email\") TYPE bloom_filter GRANULARITY 1;"},
+		{Name: "users_name_idx", Columns: []string{"name"}, Unique: false, Statement: "ALTER TABLE \"users\" ADD INDEX IF NOT EXISTS \"users_name_idx\" (\"name\") TYPE bloom_filter GRANULARITY 1;"},
+	}
+}
+
 // LoadDevice loads the Device relation.
 func (t *userStorage) LoadDevice(ctx context.Context, model *User, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -354,6 +421,35 @@ func (t *userStorage) LoadBatchPosts(ctx context.Context, items []*User, builder
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *userStorage) preload(ctx context.Context, items []*User, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*User, ...*QueryBuilder) error
+		switch relation {
+		case "Device":
+			load = t.LoadBatchDevice
+		case "Settings":
+			load = t.LoadBatchSettings
+		case "Addresses":
+			load = t.LoadBatchAddresses
+		case "Posts":
+			load = t.LoadBatchPosts
+		default:
+			return errors.Errorf("unknown relation %s of User", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // User is a struct for the "users" table.
 type User struct {
 	Id                   string
@@ -397,12 +493,158 @@ func (t *User) ScanRow(row driver.Row) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the User, the other fields are left empty.
+func (t *User) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *User) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "age":
+			dest = append(dest, &t.Age)
+		case "email":
+			dest = append(dest, &t.Email)
+		case "last_name":
+			dest = append(dest, &t.LastName)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		case "notification_settings":
+			dest = append(dest, &t.NotificationSettings)
+		case "phones":
+			dest = append(dest, &t.Phones)
+		case "balls":
+			dest = append(dest, &t.Balls)
+		case "numrs":
+			dest = append(dest, &t.Numrs)
+		case "comments":
+			dest = append(dest, &t.Comments)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// User columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	UserColumnId                   Column = "id"
+	UserColumnName                 Column = "name"
+	UserColumnAge                  Column = "age"
+	UserColumnEmail                Column = "email"
+	UserColumnLastName             Column = "last_name"
+	UserColumnCreatedAt            Column = "created_at"
+	UserColumnUpdatedAt            Column = "updated_at"
+	UserColumnNotificationSettings Column = "notification_settings"
+	UserColumnPhones               Column = "phones"
+	UserColumnBalls                Column = "balls"
+	UserColumnNumrs                Column = "numrs"
+	UserColumnComments             Column = "comments"
+)
+
 // UserFilters is a struct that holds filters for User.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type UserFilters struct {
-	Id    *string
-	Name  *string
-	Age   *int32
-	Email *string
+	Id      *string
+	IdIn    []string
+	Name    *string
+	NameIn  []string
+	Age     *int32
+	AgeFrom *int32
+	AgeTo   *int32
+	AgeIn   []int32
+	Email   *string
+	EmailIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *UserFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.Name != nil {
+		filters = append(filters, EqualsCondition{Field: "name", Value: *f.Name})
+	}
+	if f.NameIn != nil {
+		filters = append(filters, InCondition{Field: "name", Values: toInterface(f.NameIn)})
+	}
+	if f.Age != nil {
+		filters = append(filters, EqualsCondition{Field: "age", Value: *f.Age})
+	}
+	if f.AgeFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "age", Value: *f.AgeFrom})
+	}
+	if f.AgeTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "age", Value: *f.AgeTo})
+	}
+	if f.AgeIn != nil {
+		filters = append(filters, InCondition{Field: "age", Values: toInterface(f.AgeIn)})
+	}
+	if f.Email != nil {
+		filters = append(filters, EqualsCondition{Field: "email", Value: *f.Email})
+	}
+	if f.EmailIn != nil {
+		filters = append(filters, InCondition{Field: "email", Values: toInterface(f.EmailIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseUserFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseUserFilters(values url.Values) (*UserFilters, error) {
+	f := &UserFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "name":
+			f.Name, err = parseFilterValue[string](key, values[key])
+		case "name_in":
+			f.NameIn, err = parseFilterValues[string](key, values[key])
+		case "age":
+			f.Age, err = parseFilterValue[int32](key, values[key])
+		case "age_from":
+			f.AgeFrom, err = parseFilterValue[int32](key, values[key])
+		case "age_to":
+			f.AgeTo, err = parseFilterValue[int32](key, values[key])
+		case "age_in":
+			f.AgeIn, err = parseFilterValues[int32](key, values[key])
+		case "email":
+			f.Email, err = parseFilterValue[string](key, values[key])
+		case "email_in":
+			f.EmailIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // UserIdEq returns a condition that checks if the field equals the value.
@@ -650,6 +892,65 @@ func UserEmailOrderBy(asc bool) FilterApplier {
 	return OrderBy("email", asc)
 }
 
+// UserNotificationSettingsContains returns a condition that checks if the JSON object contains the value.
+func UserNotificationSettingsContains(value interface{}) FilterApplier {
+	return JSONContains("notification_settings", value)
+}
+
+// UserNotificationSettingsHasKey returns a condition that checks if the JSON object has the key.
+func UserNotificationSettingsHasKey(key string) FilterApplier {
+	return JSONHasKey("notification_settings", key)
+}
+
+// UserNotificationSettingsPathEq returns a condition that checks if the value at the dot-separated path of the JSON object equals the value.
+func UserNotificationSettingsPathEq(path string, value interface{}) FilterApplier {
+	return JSONPathEq("notification_settings", path, value)
+}
+
+// UserPhonesContains returns a condition that checks if the JSON array contains the value.
+func UserPhonesContains(value string) FilterApplier {
+	return JSONContains("phones", []string{value})
+}
+
+// UserBallsContains returns a condition that checks if the JSON array contains the value.
+func UserBallsContains(value int32) FilterApplier {
+	return JSONContains("balls", []int32{value})
+}
+
+// UserNumrsContains returns a condition that checks if the JSON array contains the value.
+func UserNumrsContains(value interface{}) FilterApplier {
+	return JSONContains("numrs", []interface{}{value})
+}
+
+// UserCommentsContains returns a condition that checks if the JSON array contains the value.
+func UserCommentsContains(value interface{}) FilterApplier {
+	return JSONContains("comments", []interface{}{value})
+}
+
+// UserHasDevice checks if the User has the Device relation matching the filters.
+func UserHasDevice(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(DeviceColumnUserId)).From((&Device{}).TableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasSettings checks if the User has the Settings relation matching the filters.
+func UserHasSettings(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(SettingColumnUserId)).From((&Setting{}).TableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasAddresses checks if the User has the Addresses relation matching the filters.
+func UserHasAddresses(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(AddressColumnUserId)).From((&Address{}).TableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasPosts checks if the User has the Posts relation matching the filters.
+func UserHasPosts(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(PostColumnAuthorId)).From((&Post{}).TableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
 // AsyncCreate asynchronously inserts a new User.
 func (t *userStorage) AsyncCreate(ctx context.Context, model *User, opts ...Option) error {
 	if model == nil {
@@ -938,9 +1239,131 @@ func (t *userStorage) BatchCreate(ctx context.Context, models []*User, opts ...O
 }
 
 // FindMany finds multiple User based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*User, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*User
+	for rows.Next() {
+		model := &User{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan User")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the User rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *userStorage) FindEach(ctx context.Context, fn func(*User) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &User{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan User")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *userStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of User", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *userStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of User", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *userStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *userStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -990,6 +1413,186 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// FindOne finds a single User based on the provided options.
+func (t *userStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*User, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne User")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+
+// UserAggregateRow is a row of the User Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type UserAggregateRow struct {
+	User
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *UserAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+
+// SumAge returns the sum of the age values, it is set by Sum(UserColumnAge).
+func (r *UserAggregateRow) SumAge() int64 {
+	if v, ok := r.values[Sum(UserColumnAge)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgAge returns the average of the age values, it is set by Avg(UserColumnAge).
+func (r *UserAggregateRow) AvgAge() *float64 {
+	if v, ok := r.values[Avg(UserColumnAge)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinAge returns the smallest age value, it is set by Min(UserColumnAge).
+func (r *UserAggregateRow) MinAge() *int32 {
+	if v, ok := r.values[Min(UserColumnAge)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxAge returns the largest age value, it is set by Max(UserColumnAge).
+func (r *UserAggregateRow) MaxAge() *int32 {
+	if v, ok := r.values[Max(UserColumnAge)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(UserColumnCreatedAt).
+func (r *UserAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(UserColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(UserColumnCreatedAt).
+func (r *UserAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(UserColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(UserColumnUpdatedAt).
+func (r *UserAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(UserColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(UserColumnUpdatedAt).
+func (r *UserAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(UserColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the User rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(UserColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *userStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*UserAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of User")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of User", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
 	rows, err := t.DB().Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -1000,13 +1603,22 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 		}
 	}()
 
-	var results []*User
+	var results []*UserAggregateRow
 	for rows.Next() {
-		model := &User{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan User")
+		row := &UserAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.User.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan User aggregate")
+		}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -1016,20 +1628,114 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	return results, nil
 }
 
-// FindOne finds a single User based on the provided options.
-func (t *userStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*User, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
+// aggregate returns the single aggregation of the User rows found by the builders.
+func (t *userStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*UserAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne User")
+		return nil, err
 	}
+	if len(rows) == 0 {
+		return &UserAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// SumAge returns the sum of the age values of the User rows found by the builders.
+func (t *userStorage) SumAge(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(UserColumnAge), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumAge(), nil
+}
+
+// AvgAge returns the average of the age values of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) AvgAge(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
 	}
+	return row.AvgAge(), nil
+}
 
-	return results[0], nil
+// MinAge returns the smallest age value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinAge(), nil
+}
+
+// MaxAge returns the largest age value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxAge(), nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the User rows.
+func (t *userStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	case Sum(UserColumnAge):
+		return new(*int64), nil
+	case Avg(UserColumnAge):
+		return new(*float64), nil
+	case Min(UserColumnAge), Max(UserColumnAge):
+		return new(*int32), nil
+	case Min(UserColumnCreatedAt), Max(UserColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(UserColumnUpdatedAt), Max(UserColumnUpdatedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of User", aggregation)
 }
 
 // Select executes a raw query and returns the result.
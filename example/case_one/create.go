@@ -39,7 +39,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	store := db.NewBlogStorages(connection)
+	store, err := db.NewBlogStorages(&db.Config{DB: &db.DB{DBRead: connection, DBWrite: connection}})
+	if err != nil {
+		panic(err)
+	}
 	userStorage := store.GetUserStorage()
 	userService := newUserService(userStorage)
 	{
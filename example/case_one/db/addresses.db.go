@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v4"
 	"math"
+	"net/url"
 	"time"
 )
 
@@ -17,18 +18,39 @@ type addressStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// AddressTableManager is an interface for managing the addresses table.
+type AddressTableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+
+// AddressSchemaVerification is an interface for verifying the addresses table.
+type AddressSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // AddressCRUDOperations is an interface for managing the addresses table.
 type AddressCRUDOperations interface {
 	Create(ctx context.Context, model *Address, opts ...Option) (*string, error)
 	BatchCreate(ctx context.Context, models []*Address, opts ...Option) ([]string, error)
-	Update(ctx context.Context, id string, updateData *AddressUpdate) error
-	DeleteById(ctx context.Context, id string, opts ...Option) error
+	Upsert(ctx context.Context, model *Address, conflict *Conflict, opts ...Option) (*string, error)
+	BatchUpsert(ctx context.Context, models []*Address, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id string, updateData *AddressUpdate, opts ...Option) (int64, error)
+	UpdateReturning(ctx context.Context, id string, updateData *AddressUpdate, opts ...Option) (*Address, error)
+	UpdateMany(ctx context.Context, updateData *AddressUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id string, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id string, opts ...Option) (*Address, error)
 }
 
 // AddressSearchOperations is an interface for searching the addresses table.
 type AddressSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Address, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Address, error)
@@ -37,6 +59,24 @@ type AddressSearchOperations interface {
 // AddressPaginationOperations is an interface for pagination operations.
 type AddressPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Address, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Address, *CursorPaginator, error)
+}
+
+// AddressAggregateOperations is an interface for the aggregate queries.
+type AddressAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error)
+	SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
 }
 
 // AddressRelationLoading is an interface for loading relations.
@@ -47,7 +87,7 @@ type AddressRelationLoading interface {
 
 // AddressAdvancedDeletion is an interface for advanced deletion operations.
 type AddressAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // AddressRawQueryOperations is an interface for executing raw queries.
@@ -59,9 +99,13 @@ type AddressRawQueryOperations interface {
 
 // AddressStorage is a struct for the "addresses" table.
 type AddressStorage interface {
+	AddressTableManager
+
+	AddressSchemaVerification
 	AddressCRUDOperations
 	AddressSearchOperations
 	AddressPaginationOperations
+	AddressAggregateOperations
 	AddressRelationLoading
 	AddressAdvancedDeletion
 	AddressRawQueryOperations
@@ -107,6 +151,16 @@ func (t *addressStorage) TableName() string {
 	return "addresses"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *addressStorage) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *addressStorage) QualifiedTableName() string {
+	return "\"addresses\""
+}
+
 // Columns returns the columns for the table.
 func (t *addressStorage) Columns() []string {
 	return []string{
@@ -139,6 +193,127 @@ func (t *addressStorage) DB(ctx context.Context, isWrite bool) QueryExecer {
 	return db
 }
 
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
+func (t *addressStorage) CreateTable(ctx context.Context) error {
+	sqlQuery := `
+		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+		-- Table: "addresses"
+		CREATE TABLE IF NOT EXISTS "addresses" (
+		id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+		street TEXT,
+		city TEXT NOT NULL,
+		state INTEGER,
+		zip BIGINT,
+		user_id UUID NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT now(),
+		updated_at TIMESTAMP);
+		-- Other entities
+		CREATE UNIQUE INDEX IF NOT EXISTS "addresses_user_id_unique_idx" ON "addresses" USING btree (user_id);
+		CREATE INDEX IF NOT EXISTS "addresses_city_idx" ON "addresses" USING btree (city);
+		CREATE INDEX IF NOT EXISTS "addresses_user_id_idx" ON "addresses" USING btree (user_id);
+		-- Foreign keys
+		DO $$ BEGIN ALTER TABLE "addresses" ADD CONSTRAINT "addresses_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id"); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// DropTable drops the table.
+func (t *addressStorage) DropTable(ctx context.Context) error {
+	sqlQuery := `
+		DROP TABLE IF EXISTS "addresses";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// TruncateTable truncates the table.
+func (t *addressStorage) TruncateTable(ctx context.Context) error {
+	sqlQuery := `
+		TRUNCATE TABLE "addresses";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *addressStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *addressStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *addressStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "uuid", NotNull: true, Statement: ""},
+		{Name: "street", Type: "text", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"street\" TEXT;"},
+		{Name: "city", Type: "text", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"city\" TEXT NOT NULL;"},
+		{Name: "state", Type: "integer", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"state\" INTEGER;"},
+		{Name: "zip", Type: "bigint", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"zip\" BIGINT;"},
+		{Name: "user_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID NOT NULL;"},
+		{Name: "created_at", Type: "timestamp without time zone", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"created_at\" TIMESTAMP NOT NULL DEFAULT now();"},
+		{Name: "updated_at", Type: "timestamp without time zone", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN IF NOT EXISTS \"updated_at\" TIMESTAMP;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *addressStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "addresses_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"addresses_user_id_unique_idx\" ON \"addresses\" USING btree (\"user_id\");"},
+		{Name: "addresses_city_idx", Columns: []string{"city"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"addresses_city_idx\" ON \"addresses\" USING btree (\"city\");"},
+		{Name: "addresses_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"addresses_user_id_idx\" ON \"addresses\" USING btree (\"user_id\");"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *addressStorage) LoadUser(ctx context.Context, model *Address, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -198,6 +373,29 @@ func (t *addressStorage) LoadBatchUser(ctx context.Context, items []*Address, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *addressStorage) preload(ctx context.Context, items []*Address, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Address, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Address", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Address is a struct for the "addresses" table.
 type Address struct {
 	Id        string `db:"id"`
@@ -216,6 +414,16 @@ func (t *Address) TableName() string {
 	return "addresses"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *Address) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *Address) QualifiedTableName() string {
+	return "\"addresses\""
+}
+
 // ScanRow scans a row into a Address.
 func (t *Address) ScanRow(r *sql.Row) error {
 	return r.Scan(&t.Id, &t.Street, &t.City, &t.State, &t.Zip, &t.UserId, &t.CreatedAt, &t.UpdatedAt)
@@ -235,10 +443,118 @@ func (t *Address) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Address, the other fields are left empty.
+func (t *Address) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Address) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "street":
+			dest = append(dest, &t.Street)
+		case "city":
+			dest = append(dest, &t.City)
+		case "state":
+			dest = append(dest, &t.State)
+		case "zip":
+			dest = append(dest, &t.Zip)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Address columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	AddressColumnId        Column = "id"
+	AddressColumnStreet    Column = "street"
+	AddressColumnCity      Column = "city"
+	AddressColumnState     Column = "state"
+	AddressColumnZip       Column = "zip"
+	AddressColumnUserId    Column = "user_id"
+	AddressColumnCreatedAt Column = "created_at"
+	AddressColumnUpdatedAt Column = "updated_at"
+)
+
+// Validate validates the Address fields.
+// The fields generated by the database are not validated.
+func (t *Address) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
 // AddressFilters is a struct that holds filters for Address.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type AddressFilters struct {
-	Id     *string
-	UserId *string
+	Id       *string
+	IdIn     []string
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *AddressFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseAddressFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseAddressFilters(values url.Values) (*AddressFilters, error) {
+	f := &AddressFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // AddressIdEq returns a condition that checks if the field equals the value.
@@ -371,6 +687,12 @@ func AddressUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// AddressHasUser checks if the Address has the User relation matching the filters.
+func AddressHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(AddressColumnUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Address.
 func (t *addressStorage) Create(ctx context.Context, model *Address, opts ...Option) (*string, error) {
 	if model == nil {
@@ -383,7 +705,13 @@ func (t *addressStorage) Create(ctx context.Context, model *Address, opts ...Opt
 		o(options)
 	}
 
-	query := t.queryBuilder.Insert("addresses").
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			return nil, errors.Wrap(err, "failed to validate Address")
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"street",
 			"city",
@@ -440,7 +768,18 @@ func (t *addressStorage) BatchCreate(ctx context.Context, models []*Address, opt
 		return nil, errors.New("relations are not supported in batch create")
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "failed to validate Address at index %d", i)
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"street",
 			"city",
@@ -507,6 +846,144 @@ func (t *addressStorage) BatchCreate(ctx context.Context, models []*Address, opt
 	return returnIDs, nil
 }
 
+// addressConflictTargets are the unique constraints of the addresses table which the upsert can conflict on.
+var addressConflictTargets = [][]Column{
+	{AddressColumnUserId},
+}
+
+// addressUpsertColumns are the columns inserted by the upsert.
+var addressUpsertColumns = []upsertColumn{
+	{column: AddressColumnStreet, written: []string{"street"}, index: "street"},
+	{column: AddressColumnCity, written: []string{"city"}, index: "city"},
+	{column: AddressColumnState, written: []string{"state"}, index: "state"},
+	{column: AddressColumnZip, written: []string{"zip"}, index: "zip"},
+	{column: AddressColumnUserId, written: []string{"user_id"}, index: "user_id"},
+	{column: AddressColumnCreatedAt, written: []string{"created_at"}, index: "created_at"},
+	{column: AddressColumnUpdatedAt, written: []string{"updated_at"}, index: "updated_at"},
+}
+
+// upsertValues returns the values of the model in the order of the addressUpsertColumns.
+func (t *addressStorage) upsertValues(model *Address) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(addressUpsertColumns))
+	values = append(values, model.Street)
+	values = append(values, model.City)
+	values = append(values, model.State)
+	values = append(values, model.Zip)
+	values = append(values, model.UserId)
+	values = append(values, model.CreatedAt)
+	values = append(values, nullValue(model.UpdatedAt))
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *addressStorage) upsertQuery(models []*Address, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix(t.TableName(), addressConflictTargets, addressUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames(addressUpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate Address at index %d", i)
+			}
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build query")
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Address or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(AddressColumnUserId).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *addressStorage) Upsert(ctx context.Context, model *Address, conflict *Conflict, opts ...Option) (*string, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Address{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	var id string
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to upsert Address")
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Address records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+// It returns the primary keys of the created or the updated rows.
+func (t *addressStorage) BatchUpsert(ctx context.Context, models []*Address, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+}
+
 // AddressUpdate is used to update an existing Address.
 type AddressUpdate struct {
 	// Use regular pointer types for non-optional fields
@@ -523,93 +1000,112 @@ type AddressUpdate struct {
 	CreatedAt *time.Time
 	// Use null types for optional fields
 	UpdatedAt null.Time
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Address based on non-nil fields.
-func (t *addressStorage) Update(ctx context.Context, id string, updateData *AddressUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
-	}
+// Validate validates the fields of the AddressUpdate which are set.
+func (t *AddressUpdate) Validate() error {
+	v := &validator{}
 
-	query := t.queryBuilder.Update("addresses")
-	// Handle fields that are not optional using a nil check
-	if updateData.Street != nil {
-		query = query.Set("street", *updateData.Street) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.City != nil {
-		query = query.Set("city", *updateData.City) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.State != nil {
-		query = query.Set("state", *updateData.State) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Zip != nil {
-		query = query.Set("zip", *updateData.Zip) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.UserId != nil {
-		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.CreatedAt != nil {
-		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
-	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.UpdatedAt.Valid {
-		// Handle null.Time specifically
-		if updateData.UpdatedAt.Time.IsZero() {
-			query = query.Set("updated_at", nil) // Explicitly set NULL if time is zero
-		} else {
-			query = query.Set("updated_at", updateData.UpdatedAt.Time)
-		}
+	return v.err()
+}
+
+// Update updates an existing Address based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) Update(ctx context.Context, id string, updateData *AddressUpdate, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
 	}
 
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 	query = query.Where("id = ?", id)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to update Address")
+		return 0, errors.Wrap(err, "failed to update Address")
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteById - deletes a Address by its id.
-func (t *addressStorage) DeleteById(ctx context.Context, id string, opts ...Option) error {
+// UpdateReturning updates an existing Address based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) UpdateReturning(ctx context.Context, id string, updateData *AddressUpdate, opts ...Option) (*Address, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
 		o(options)
 	}
 
-	query := t.queryBuilder.Delete("addresses").Where("id = ?", id)
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete Address")
+		return nil, errors.Wrap(err, "failed to update Address")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update Address")
+		}
+		return nil, ErrRowNotFound
 	}
 
-	return nil
+	model := &Address{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan Address")
+	}
+
+	return model, nil
 }
 
-// DeleteMany removes entries from the addresses table using the provided filters
-func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("addresses")
+// UpdateMany updates the addresses rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *addressStorage) UpdateMany(ctx context.Context, updateData *AddressUpdate, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -619,27 +1115,146 @@ func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuild
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
+			query = option.ApplyUpdate(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for update operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete addresses")
+		return 0, errors.Wrap(err, "failed to update addresses")
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *addressStorage) updateQuery(updateData *AddressUpdate, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
+
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate Address")
+		}
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Street != nil {
+		query = query.Set("street", *updateData.Street) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.City != nil {
+		query = query.Set("city", *updateData.City) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.State != nil {
+		query = query.Set("state", *updateData.State) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Zip != nil {
+		query = query.Set("zip", *updateData.Zip) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.UserId != nil {
+		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.CreatedAt != nil {
+		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.UpdatedAt.Valid {
+		// Handle null.Time specifically
+		if updateData.UpdatedAt.Time.IsZero() {
+			query = query.Set("updated_at", nil) // Explicitly set NULL if time is zero
+		} else {
+			query = query.Set("updated_at", updateData.UpdatedAt.Time)
+		}
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
+}
+
+// DeleteById - deletes a Address by its id.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) DeleteById(ctx context.Context, id string, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete Address")
+	}
+
+	return rowsAffected(result)
+}
+
+// DeleteMany removes entries from the addresses table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete addresses")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
 }
 
 // FindById retrieves a Address by its id.
@@ -660,9 +1275,230 @@ func (t *addressStorage) FindById(ctx context.Context, id string, opts ...Option
 }
 
 // FindMany finds multiple Address based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. AddressJoin<Relation>, are filled in the same query.
 func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Address, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Address
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Address")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Address rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *addressStorage) FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *addressStorage) findEachWithCursor(ctx context.Context, fn func(*Address) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *addressStorage) scanEach(rows *sql.Rows, columns []string, fn func(*Address) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan Address")
+		}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *addressStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Address", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *addressStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Address", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *addressStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *addressStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *addressStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
 
 	// set default options
 	options := &Options{}
@@ -702,8 +1538,46 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// AddressJoinUser joins the User relation filtered by the filters,
+// FindMany fills Address.User with the joined rows in the same query.
+// The Address rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func AddressJoinUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "addresses",
+		relation: "User",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Address rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Address rows are merged.
+func (t *addressStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Address, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
 	}
@@ -720,12 +1594,43 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}()
 
 	var results []*Address
+	found := make(map[int64]*Address)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Address{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan Address")
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Address")
+		}
+
+		// the Address row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -735,6 +1640,53 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *addressStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Address relation.
+func (t *addressStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with Address", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "User":
+		s := &userStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(AddressColumnUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Address)
+					p.User = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown relation %s of Address", join.relation)
+}
+
 // FindOne finds a single Address based on the provided options.
 func (t *addressStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
 	// Use findMany but limit the results to 1
@@ -754,7 +1706,7 @@ func (t *addressStorage) FindOne(ctx context.Context, builders ...*QueryBuilder)
 // Count counts Address based on the provided options.
 func (t *addressStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -787,6 +1739,381 @@ func (t *addressStorage) Count(ctx context.Context, builders ...*QueryBuilder) (
 	return count, nil
 }
 
+// AddressAggregateRow is a row of the Address Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type AddressAggregateRow struct {
+	Address
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *AddressAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumState returns the sum of the state values, it is set by Sum(AddressColumnState).
+func (r *AddressAggregateRow) SumState() int64 {
+	if v, ok := r.values[Sum(AddressColumnState)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgState returns the average of the state values, it is set by Avg(AddressColumnState).
+func (r *AddressAggregateRow) AvgState() *float64 {
+	if v, ok := r.values[Avg(AddressColumnState)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinState returns the smallest state value, it is set by Min(AddressColumnState).
+func (r *AddressAggregateRow) MinState() *int32 {
+	if v, ok := r.values[Min(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxState returns the largest state value, it is set by Max(AddressColumnState).
+func (r *AddressAggregateRow) MaxState() *int32 {
+	if v, ok := r.values[Max(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// SumZip returns the sum of the zip values, it is set by Sum(AddressColumnZip).
+func (r *AddressAggregateRow) SumZip() int64 {
+	if v, ok := r.values[Sum(AddressColumnZip)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgZip returns the average of the zip values, it is set by Avg(AddressColumnZip).
+func (r *AddressAggregateRow) AvgZip() *float64 {
+	if v, ok := r.values[Avg(AddressColumnZip)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinZip returns the smallest zip value, it is set by Min(AddressColumnZip).
+func (r *AddressAggregateRow) MinZip() *int64 {
+	if v, ok := r.values[Min(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxZip returns the largest zip value, it is set by Max(AddressColumnZip).
+func (r *AddressAggregateRow) MaxZip() *int64 {
+	if v, ok := r.values[Max(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(AddressColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(AddressColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(AddressColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(AddressColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Address rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(AddressColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *addressStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Address")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Address", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*AddressAggregateRow
+	for rows.Next() {
+		row := &AddressAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Address.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Address aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Address rows found by the builders.
+func (t *addressStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*AddressAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &AddressAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumState returns the sum of the state values of the Address rows found by the builders.
+func (t *addressStorage) SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnState), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumState(), nil
+}
+
+// AvgState returns the average of the state values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgState(), nil
+}
+
+// MinState returns the smallest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinState(), nil
+}
+
+// MaxState returns the largest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxState(), nil
+}
+
+// SumZip returns the sum of the zip values of the Address rows found by the builders.
+func (t *addressStorage) SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnZip), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumZip(), nil
+}
+
+// AvgZip returns the average of the zip values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgZip(), nil
+}
+
+// MinZip returns the smallest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinZip(), nil
+}
+
+// MaxZip returns the largest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxZip(), nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Address rows.
+func (t *addressStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(AddressColumnState):
+		return new(*int64), nil
+	case Avg(AddressColumnState):
+		return new(*float64), nil
+	case Min(AddressColumnState), Max(AddressColumnState):
+		return new(*int32), nil
+	case Sum(AddressColumnZip):
+		return new(*int64), nil
+	case Avg(AddressColumnZip):
+		return new(*float64), nil
+	case Min(AddressColumnZip), Max(AddressColumnZip):
+		return new(*int64), nil
+	case Min(AddressColumnCreatedAt), Max(AddressColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(AddressColumnUpdatedAt), Max(AddressColumnUpdatedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Address", aggregation)
+}
+
 // FindManyWithPagination finds multiple Address with pagination support.
 func (t *addressStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Address, *Paginator, error) {
 	// Count the total number of records
@@ -818,9 +2145,151 @@ func (t *addressStorage) FindManyWithPagination(ctx context.Context, limit int,
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Address after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *addressStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Address, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Address{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find Address")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Address row.
+func (t *addressStorage) cursor(model *Address, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *addressStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of Address can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *addressStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "street":
+		return true
+	case "city":
+		return true
+	case "state":
+		return true
+	case "zip":
+		return true
+	case "user_id":
+		return true
+	case "created_at":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Address for the given ID.
 func (t *addressStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v4"
 	"math"
+	"net/url"
 	"time"
 )
 
@@ -17,18 +18,39 @@ type botStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// BotTableManager is an interface for managing the bots table.
+type BotTableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+
+// BotSchemaVerification is an interface for verifying the bots table.
+type BotSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // BotCRUDOperations is an interface for managing the bots table.
 type BotCRUDOperations interface {
 	Create(ctx context.Context, model *Bot, opts ...Option) (*string, error)
 	BatchCreate(ctx context.Context, models []*Bot, opts ...Option) ([]string, error)
-	Update(ctx context.Context, id string, updateData *BotUpdate) error
-	DeleteById(ctx context.Context, id string, opts ...Option) error
+	Upsert(ctx context.Context, model *Bot, conflict *Conflict, opts ...Option) (*string, error)
+	BatchUpsert(ctx context.Context, models []*Bot, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id string, updateData *BotUpdate, opts ...Option) (int64, error)
+	UpdateReturning(ctx context.Context, id string, updateData *BotUpdate, opts ...Option) (*Bot, error)
+	UpdateMany(ctx context.Context, updateData *BotUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id string, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id string, opts ...Option) (*Bot, error)
 }
 
 // BotSearchOperations is an interface for searching the bots table.
 type BotSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Bot, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Bot) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Bot, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Bot, error)
@@ -37,6 +59,18 @@ type BotSearchOperations interface {
 // BotPaginationOperations is an interface for pagination operations.
 type BotPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Bot, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Bot, *CursorPaginator, error)
+}
+
+// BotAggregateOperations is an interface for the aggregate queries.
+type BotAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotAggregateRow, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
 }
 
 // BotRelationLoading is an interface for loading relations.
@@ -47,7 +81,7 @@ type BotRelationLoading interface {
 
 // BotAdvancedDeletion is an interface for advanced deletion operations.
 type BotAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // BotRawQueryOperations is an interface for executing raw queries.
@@ -59,9 +93,13 @@ type BotRawQueryOperations interface {
 
 // BotStorage is a struct for the "bots" table.
 type BotStorage interface {
+	BotTableManager
+
+	BotSchemaVerification
 	BotCRUDOperations
 	BotSearchOperations
 	BotPaginationOperations
+	BotAggregateOperations
 	BotRelationLoading
 	BotAdvancedDeletion
 	BotRawQueryOperations
@@ -107,6 +145,16 @@ func (t *botStorage) TableName() string {
 	return "bots"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *botStorage) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *botStorage) QualifiedTableName() string {
+	return "\"bots\""
+}
+
 // Columns returns the columns for the table.
 func (t *botStorage) Columns() []string {
 	return []string{
@@ -139,6 +187,123 @@ func (t *botStorage) DB(ctx context.Context, isWrite bool) QueryExecer {
 	return db
 }
 
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
+func (t *botStorage) CreateTable(ctx context.Context) error {
+	sqlQuery := `
+		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+		-- Table: "bots"
+		CREATE TABLE IF NOT EXISTS "bots" (
+		id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+		user_id UUID NOT NULL,
+		name TEXT,
+		token TEXT NOT NULL,
+		is_publish BOOLEAN,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP,
+		deleted_at TIMESTAMP);
+		-- Other entities
+		CREATE UNIQUE INDEX IF NOT EXISTS "bots_token_unique_idx" ON "bots" USING btree (token);
+		-- Foreign keys
+		DO $$ BEGIN ALTER TABLE "bots" ADD CONSTRAINT "bots_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// DropTable drops the table.
+func (t *botStorage) DropTable(ctx context.Context) error {
+	sqlQuery := `
+		DROP TABLE IF EXISTS "bots";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// TruncateTable truncates the table.
+func (t *botStorage) TruncateTable(ctx context.Context) error {
+	sqlQuery := `
+		TRUNCATE TABLE "bots";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *botStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *botStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *botStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "uuid", NotNull: true, Statement: ""},
+		{Name: "user_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID NOT NULL;"},
+		{Name: "name", Type: "text", NotNull: false, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"name\" TEXT;"},
+		{Name: "token", Type: "text", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"token\" TEXT NOT NULL;"},
+		{Name: "is_publish", Type: "boolean", NotNull: false, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"is_publish\" BOOLEAN;"},
+		{Name: "created_at", Type: "timestamp without time zone", NotNull: true, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"created_at\" TIMESTAMP NOT NULL;"},
+		{Name: "updated_at", Type: "timestamp without time zone", NotNull: false, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"updated_at\" TIMESTAMP;"},
+		{Name: "deleted_at", Type: "timestamp without time zone", NotNull: false, Statement: "ALTER TABLE \"bots\" ADD COLUMN IF NOT EXISTS \"deleted_at\" TIMESTAMP;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *botStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "bots_token_unique_idx", Columns: []string{"token"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"bots_token_unique_idx\" ON \"bots\" USING btree (\"token\");"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *botStorage) LoadUser(ctx context.Context, model *Bot, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -198,6 +363,29 @@ func (t *botStorage) LoadBatchUser(ctx context.Context, items []*Bot, builders .
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *botStorage) preload(ctx context.Context, items []*Bot, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Bot, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Bot", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Bot is a struct for the "bots" table.
 type Bot struct {
 	Id        string     `db:"id"`
@@ -216,6 +404,16 @@ func (t *Bot) TableName() string {
 	return "bots"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *Bot) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *Bot) QualifiedTableName() string {
+	return "\"bots\""
+}
+
 // ScanRow scans a row into a Bot.
 func (t *Bot) ScanRow(r *sql.Row) error {
 	return r.Scan(&t.Id, &t.UserId, &t.Name, &t.Token, &t.IsPublish, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt)
@@ -235,11 +433,142 @@ func (t *Bot) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Bot, the other fields are left empty.
+func (t *Bot) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Bot) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "token":
+			dest = append(dest, &t.Token)
+		case "is_publish":
+			dest = append(dest, &t.IsPublish)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		case "deleted_at":
+			dest = append(dest, &t.DeletedAt)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Bot columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	BotColumnId        Column = "id"
+	BotColumnUserId    Column = "user_id"
+	BotColumnName      Column = "name"
+	BotColumnToken     Column = "token"
+	BotColumnIsPublish Column = "is_publish"
+	BotColumnCreatedAt Column = "created_at"
+	BotColumnUpdatedAt Column = "updated_at"
+	BotColumnDeletedAt Column = "deleted_at"
+)
+
+// Validate validates the Bot fields.
+// The fields generated by the database are not validated.
+func (t *Bot) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
 // BotFilters is a struct that holds filters for Bot.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type BotFilters struct {
-	Id        *string
-	UserId    *string
-	CreatedAt *time.Time
+	Id            *string
+	IdIn          []string
+	UserId        *string
+	UserIdIn      []string
+	CreatedAt     *time.Time
+	CreatedAtFrom *time.Time
+	CreatedAtTo   *time.Time
+	CreatedAtIn   []time.Time
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *BotFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	if f.CreatedAt != nil {
+		filters = append(filters, EqualsCondition{Field: "created_at", Value: *f.CreatedAt})
+	}
+	if f.CreatedAtFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtFrom})
+	}
+	if f.CreatedAtTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "created_at", Value: *f.CreatedAtTo})
+	}
+	if f.CreatedAtIn != nil {
+		filters = append(filters, InCondition{Field: "created_at", Values: toInterface(f.CreatedAtIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseBotFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseBotFilters(values url.Values) (*BotFilters, error) {
+	f := &BotFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		case "created_at":
+			f.CreatedAt, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_from":
+			f.CreatedAtFrom, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_to":
+			f.CreatedAtTo, err = parseFilterValue[time.Time](key, values[key])
+		case "created_at_in":
+			f.CreatedAtIn, err = parseFilterValues[time.Time](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // BotIdEq returns a condition that checks if the field equals the value.
@@ -422,6 +751,12 @@ func BotCreatedAtOrderBy(asc bool) FilterApplier {
 	return OrderBy("created_at", asc)
 }
 
+// BotHasUser checks if the Bot has the User relation matching the filters.
+func BotHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(BotColumnUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Bot.
 func (t *botStorage) Create(ctx context.Context, model *Bot, opts ...Option) (*string, error) {
 	if model == nil {
@@ -434,7 +769,13 @@ func (t *botStorage) Create(ctx context.Context, model *Bot, opts ...Option) (*s
 		o(options)
 	}
 
-	query := t.queryBuilder.Insert("bots").
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			return nil, errors.Wrap(err, "failed to validate Bot")
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"user_id",
 			"name",
@@ -491,7 +832,18 @@ func (t *botStorage) BatchCreate(ctx context.Context, models []*Bot, opts ...Opt
 		return nil, errors.New("relations are not supported in batch create")
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "failed to validate Bot at index %d", i)
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"user_id",
 			"name",
@@ -558,6 +910,144 @@ func (t *botStorage) BatchCreate(ctx context.Context, models []*Bot, opts ...Opt
 	return returnIDs, nil
 }
 
+// botConflictTargets are the unique constraints of the bots table which the upsert can conflict on.
+var botConflictTargets = [][]Column{
+	{BotColumnToken},
+}
+
+// botUpsertColumns are the columns inserted by the upsert.
+var botUpsertColumns = []upsertColumn{
+	{column: BotColumnUserId, written: []string{"user_id"}, index: "user_id"},
+	{column: BotColumnName, written: []string{"name"}, index: "name"},
+	{column: BotColumnToken, written: []string{"token"}, index: "token"},
+	{column: BotColumnIsPublish, written: []string{"is_publish"}, index: "is_publish"},
+	{column: BotColumnCreatedAt, written: []string{"created_at"}, index: "created_at"},
+	{column: BotColumnUpdatedAt, written: []string{"updated_at"}, index: "updated_at"},
+	{column: BotColumnDeletedAt, written: []string{"deleted_at"}, index: "deleted_at"},
+}
+
+// upsertValues returns the values of the model in the order of the botUpsertColumns.
+func (t *botStorage) upsertValues(model *Bot) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(botUpsertColumns))
+	values = append(values, model.UserId)
+	values = append(values, model.Name)
+	values = append(values, model.Token)
+	values = append(values, model.IsPublish)
+	values = append(values, model.CreatedAt)
+	values = append(values, model.UpdatedAt)
+	values = append(values, nullValue(model.DeletedAt))
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *botStorage) upsertQuery(models []*Bot, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix(t.TableName(), botConflictTargets, botUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames(botUpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate Bot at index %d", i)
+			}
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build query")
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Bot or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(BotColumnToken).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *botStorage) Upsert(ctx context.Context, model *Bot, conflict *Conflict, opts ...Option) (*string, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Bot{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	var id string
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to upsert Bot")
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Bot records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+// It returns the primary keys of the created or the updated rows.
+func (t *botStorage) BatchUpsert(ctx context.Context, models []*Bot, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+}
+
 // BotUpdate is used to update an existing Bot.
 type BotUpdate struct {
 	// Use regular pointer types for non-optional fields
@@ -574,93 +1064,112 @@ type BotUpdate struct {
 	UpdatedAt *time.Time
 	// Use null types for optional fields
 	DeletedAt null.Time
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Bot based on non-nil fields.
-func (t *botStorage) Update(ctx context.Context, id string, updateData *BotUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
-	}
+// Validate validates the fields of the BotUpdate which are set.
+func (t *BotUpdate) Validate() error {
+	v := &validator{}
 
-	query := t.queryBuilder.Update("bots")
-	// Handle fields that are not optional using a nil check
-	if updateData.UserId != nil {
-		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Name != nil {
-		query = query.Set("name", *updateData.Name) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Token != nil {
-		query = query.Set("token", *updateData.Token) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.IsPublish != nil {
-		query = query.Set("is_publish", *updateData.IsPublish) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.CreatedAt != nil {
-		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.UpdatedAt != nil {
-		query = query.Set("updated_at", *updateData.UpdatedAt) // Dereference pointer value
-	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.DeletedAt.Valid {
-		// Handle null.Time specifically
-		if updateData.DeletedAt.Time.IsZero() {
-			query = query.Set("deleted_at", nil) // Explicitly set NULL if time is zero
-		} else {
-			query = query.Set("deleted_at", updateData.DeletedAt.Time)
-		}
+	return v.err()
+}
+
+// Update updates an existing Bot based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *botStorage) Update(ctx context.Context, id string, updateData *BotUpdate, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
 	}
 
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 	query = query.Where("id = ?", id)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to update Bot")
+		return 0, errors.Wrap(err, "failed to update Bot")
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteById - deletes a Bot by its id.
-func (t *botStorage) DeleteById(ctx context.Context, id string, opts ...Option) error {
+// UpdateReturning updates an existing Bot based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *botStorage) UpdateReturning(ctx context.Context, id string, updateData *BotUpdate, opts ...Option) (*Bot, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
 		o(options)
 	}
 
-	query := t.queryBuilder.Delete("bots").Where("id = ?", id)
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete Bot")
+		return nil, errors.Wrap(err, "failed to update Bot")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update Bot")
+		}
+		return nil, ErrRowNotFound
 	}
 
-	return nil
+	model := &Bot{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan Bot")
+	}
+
+	return model, nil
 }
 
-// DeleteMany removes entries from the bots table using the provided filters
-func (t *botStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("bots")
+// UpdateMany updates the bots rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *botStorage) UpdateMany(ctx context.Context, updateData *BotUpdate, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -670,50 +1179,390 @@ func (t *botStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder)
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
+			query = option.ApplyUpdate(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for update operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete bots")
+		return 0, errors.Wrap(err, "failed to update bots")
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
 }
 
-// FindById retrieves a Bot by its id.
-func (t *botStorage) FindById(ctx context.Context, id string, opts ...Option) (*Bot, error) {
-	builder := NewQueryBuilder()
-	{
-		builder.WithFilter(BotIdEq(id))
-		builder.WithOptions(opts...)
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *botStorage) updateQuery(updateData *BotUpdate, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
+	if updateData == nil {
+		return query, errors.New("update data is nil")
 	}
 
-	// Use FindOne to get a single result
-	model, err := t.FindOne(ctx, builder)
-	if err != nil {
-		return nil, errors.Wrap(err, "find one Bot: ")
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate Bot")
+		}
 	}
-
-	return model, nil
-}
-
+	// Handle fields that are not optional using a nil check
+	if updateData.UserId != nil {
+		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Name != nil {
+		query = query.Set("name", *updateData.Name) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Token != nil {
+		query = query.Set("token", *updateData.Token) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.IsPublish != nil {
+		query = query.Set("is_publish", *updateData.IsPublish) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.CreatedAt != nil {
+		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.UpdatedAt != nil {
+		query = query.Set("updated_at", *updateData.UpdatedAt) // Dereference pointer value
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.DeletedAt.Valid {
+		// Handle null.Time specifically
+		if updateData.DeletedAt.Time.IsZero() {
+			query = query.Set("deleted_at", nil) // Explicitly set NULL if time is zero
+		} else {
+			query = query.Set("deleted_at", updateData.DeletedAt.Time)
+		}
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
+}
+
+// DeleteById - deletes a Bot by its id.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *botStorage) DeleteById(ctx context.Context, id string, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete Bot")
+	}
+
+	return rowsAffected(result)
+}
+
+// DeleteMany removes entries from the bots table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *botStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete bots")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// FindById retrieves a Bot by its id.
+func (t *botStorage) FindById(ctx context.Context, id string, opts ...Option) (*Bot, error) {
+	builder := NewQueryBuilder()
+	{
+		builder.WithFilter(BotIdEq(id))
+		builder.WithOptions(opts...)
+	}
+
+	// Use FindOne to get a single result
+	model, err := t.FindOne(ctx, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "find one Bot: ")
+	}
+
+	return model, nil
+}
+
 // FindMany finds multiple Bot based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. BotJoin<Relation>, are filled in the same query.
 func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Bot, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Bot
+	for rows.Next() {
+		model := &Bot{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Bot")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Bot rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *botStorage) FindEach(ctx context.Context, fn func(*Bot) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *botStorage) findEachWithCursor(ctx context.Context, fn func(*Bot) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *botStorage) scanEach(rows *sql.Rows, columns []string, fn func(*Bot) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &Bot{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan Bot")
+		}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *botStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Bot", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *botStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Bot", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *botStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *botStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *botStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
 
 	// set default options
 	options := &Options{}
@@ -753,8 +1602,46 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// BotJoinUser joins the User relation filtered by the filters,
+// FindMany fills Bot.User with the joined rows in the same query.
+// The Bot rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func BotJoinUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "bots",
+		relation: "User",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Bot rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Bot rows are merged.
+func (t *botStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Bot, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
 	}
@@ -771,12 +1658,43 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 	}()
 
 	var results []*Bot
+	found := make(map[int64]*Bot)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Bot{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan Bot")
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Bot")
+		}
+
+		// the Bot row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -786,6 +1704,53 @@ func (t *botStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *botStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Bot relation.
+func (t *botStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with Bot", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "User":
+		s := &userStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(BotColumnUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Bot)
+					p.User = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown relation %s of Bot", join.relation)
+}
+
 // FindOne finds a single Bot based on the provided options.
 func (t *botStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Bot, error) {
 	// Use findMany but limit the results to 1
@@ -805,7 +1770,7 @@ func (t *botStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*B
 // Count counts Bot based on the provided options.
 func (t *botStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -838,6 +1803,265 @@ func (t *botStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int6
 	return count, nil
 }
 
+// BotAggregateRow is a row of the Bot Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type BotAggregateRow struct {
+	Bot
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *BotAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(BotColumnCreatedAt).
+func (r *BotAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(BotColumnCreatedAt).
+func (r *BotAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(BotColumnUpdatedAt).
+func (r *BotAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(BotColumnUpdatedAt).
+func (r *BotAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value, it is set by Min(BotColumnDeletedAt).
+func (r *BotAggregateRow) MinDeletedAt() *time.Time {
+	if v, ok := r.values[Min(BotColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value, it is set by Max(BotColumnDeletedAt).
+func (r *BotAggregateRow) MaxDeletedAt() *time.Time {
+	if v, ok := r.values[Max(BotColumnDeletedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Bot rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(BotColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *botStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*BotAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Bot")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Bot", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*BotAggregateRow
+	for rows.Next() {
+		row := &BotAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Bot.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Bot aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Bot rows found by the builders.
+func (t *botStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*BotAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &BotAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// MinDeletedAt returns the smallest deleted_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MinDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(BotColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinDeletedAt(), nil
+}
+
+// MaxDeletedAt returns the largest deleted_at value of the Bot rows found by the builders,
+// it is nil if there are no rows.
+func (t *botStorage) MaxDeletedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(BotColumnDeletedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxDeletedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Bot rows.
+func (t *botStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Min(BotColumnCreatedAt), Max(BotColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(BotColumnUpdatedAt), Max(BotColumnUpdatedAt):
+		return new(*time.Time), nil
+	case Min(BotColumnDeletedAt), Max(BotColumnDeletedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Bot", aggregation)
+}
+
 // FindManyWithPagination finds multiple Bot with pagination support.
 func (t *botStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Bot, *Paginator, error) {
 	// Count the total number of records
@@ -869,9 +2093,151 @@ func (t *botStorage) FindManyWithPagination(ctx context.Context, limit int, page
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Bot after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *botStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Bot, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Bot{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find Bot")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Bot row.
+func (t *botStorage) cursor(model *Bot, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *botStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of Bot can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *botStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "user_id":
+		return true
+	case "name":
+		return true
+	case "token":
+		return true
+	case "is_publish":
+		return true
+	case "created_at":
+		return true
+	case "updated_at":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Bot for the given ID.
 func (t *botStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Bot, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
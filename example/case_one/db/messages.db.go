@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v4"
 	"math"
+	"net/url"
 )
 
 // messageStorage is a struct for the "messages" table.
@@ -16,18 +17,39 @@ type messageStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// MessageTableManager is an interface for managing the messages table.
+type MessageTableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+
+// MessageSchemaVerification is an interface for verifying the messages table.
+type MessageSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // MessageCRUDOperations is an interface for managing the messages table.
 type MessageCRUDOperations interface {
 	Create(ctx context.Context, model *Message, opts ...Option) (*string, error)
 	BatchCreate(ctx context.Context, models []*Message, opts ...Option) ([]string, error)
-	Update(ctx context.Context, id string, updateData *MessageUpdate) error
-	DeleteById(ctx context.Context, id string, opts ...Option) error
+	Upsert(ctx context.Context, model *Message, conflict *Conflict, opts ...Option) (*string, error)
+	BatchUpsert(ctx context.Context, models []*Message, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id string, updateData *MessageUpdate, opts ...Option) (int64, error)
+	UpdateReturning(ctx context.Context, id string, updateData *MessageUpdate, opts ...Option) (*Message, error)
+	UpdateMany(ctx context.Context, updateData *MessageUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id string, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id string, opts ...Option) (*Message, error)
 }
 
 // MessageSearchOperations is an interface for searching the messages table.
 type MessageSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Message, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Message) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Message, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Message, error)
@@ -36,6 +58,12 @@ type MessageSearchOperations interface {
 // MessagePaginationOperations is an interface for pagination operations.
 type MessagePaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Message, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Message, *CursorPaginator, error)
+}
+
+// MessageAggregateOperations is an interface for the aggregate queries.
+type MessageAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*MessageAggregateRow, error)
 }
 
 // MessageRelationLoading is an interface for loading relations.
@@ -50,7 +78,7 @@ type MessageRelationLoading interface {
 
 // MessageAdvancedDeletion is an interface for advanced deletion operations.
 type MessageAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // MessageRawQueryOperations is an interface for executing raw queries.
@@ -62,9 +90,13 @@ type MessageRawQueryOperations interface {
 
 // MessageStorage is a struct for the "messages" table.
 type MessageStorage interface {
+	MessageTableManager
+
+	MessageSchemaVerification
 	MessageCRUDOperations
 	MessageSearchOperations
 	MessagePaginationOperations
+	MessageAggregateOperations
 	MessageRelationLoading
 	MessageAdvancedDeletion
 	MessageRawQueryOperations
@@ -110,6 +142,16 @@ func (t *messageStorage) TableName() string {
 	return "messages"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *messageStorage) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *messageStorage) QualifiedTableName() string {
+	return "\"messages\""
+}
+
 // Columns returns the columns for the table.
 func (t *messageStorage) Columns() []string {
 	return []string{
@@ -142,6 +184,122 @@ func (t *messageStorage) DB(ctx context.Context, isWrite bool) QueryExecer {
 	return db
 }
 
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
+func (t *messageStorage) CreateTable(ctx context.Context) error {
+	sqlQuery := `
+		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+		-- Table: "messages"
+		CREATE TABLE IF NOT EXISTS "messages" (
+		id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+		from_user_id UUID NOT NULL,
+		to_user_id UUID NOT NULL,
+		bot_id UUID NOT NULL);
+		-- Other entities
+		CREATE UNIQUE INDEX IF NOT EXISTS "messages_from_user_id_unique_idx" ON "messages" USING btree (from_user_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS "messages_to_user_id_unique_idx" ON "messages" USING btree (to_user_id);
+		CREATE INDEX IF NOT EXISTS "messages_from_user_id_idx" ON "messages" USING btree (from_user_id);
+		CREATE INDEX IF NOT EXISTS "messages_to_user_id_idx" ON "messages" USING btree (to_user_id);
+		-- Foreign keys
+		DO $$ BEGIN ALTER TABLE "messages" ADD CONSTRAINT "messages_from_user_id_fkey" FOREIGN KEY ("from_user_id") REFERENCES "users" ("id"); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+		DO $$ BEGIN ALTER TABLE "messages" ADD CONSTRAINT "messages_to_user_id_fkey" FOREIGN KEY ("to_user_id") REFERENCES "users" ("id"); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// DropTable drops the table.
+func (t *messageStorage) DropTable(ctx context.Context) error {
+	sqlQuery := `
+		DROP TABLE IF EXISTS "messages";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// TruncateTable truncates the table.
+func (t *messageStorage) TruncateTable(ctx context.Context) error {
+	sqlQuery := `
+		TRUNCATE TABLE "messages";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *messageStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *messageStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *messageStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "uuid", NotNull: true, Statement: ""},
+		{Name: "from_user_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"from_user_id\" UUID NOT NULL;"},
+		{Name: "to_user_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"to_user_id\" UUID NOT NULL;"},
+		{Name: "bot_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"messages\" ADD COLUMN IF NOT EXISTS \"bot_id\" UUID NOT NULL;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *messageStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "messages_from_user_id_unique_idx", Columns: []string{"from_user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"messages_from_user_id_unique_idx\" ON \"messages\" USING btree (\"from_user_id\");"},
+		{Name: "messages_to_user_id_unique_idx", Columns: []string{"to_user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"messages_to_user_id_unique_idx\" ON \"messages\" USING btree (\"to_user_id\");"},
+		{Name: "messages_from_user_id_idx", Columns: []string{"from_user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"messages_from_user_id_idx\" ON \"messages\" USING btree (\"from_user_id\");"},
+		{Name: "messages_to_user_id_idx", Columns: []string{"to_user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"messages_to_user_id_idx\" ON \"messages\" USING btree (\"to_user_id\");"},
+	}
+}
+
 // LoadBot loads the Bot relation.
 func (t *messageStorage) LoadBot(ctx context.Context, model *Message, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -336,6 +494,33 @@ func (t *messageStorage) LoadBatchToUser(ctx context.Context, items []*Message,
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *messageStorage) preload(ctx context.Context, items []*Message, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Message, ...*QueryBuilder) error
+		switch relation {
+		case "Bot":
+			load = t.LoadBatchBot
+		case "FromUser":
+			load = t.LoadBatchFromUser
+		case "ToUser":
+			load = t.LoadBatchToUser
+		default:
+			return errors.Errorf("unknown relation %s of Message", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Message is a struct for the "messages" table.
 type Message struct {
 	Id         string  `db:"id"`
@@ -352,6 +537,16 @@ func (t *Message) TableName() string {
 	return "messages"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *Message) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *Message) QualifiedTableName() string {
+	return "\"messages\""
+}
+
 // ScanRow scans a row into a Message.
 func (t *Message) ScanRow(r *sql.Row) error {
 	return r.Scan(&t.Id, &t.FromUserId, &t.ToUserId, &t.BotId)
@@ -367,11 +562,118 @@ func (t *Message) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Message, the other fields are left empty.
+func (t *Message) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Message) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "from_user_id":
+			dest = append(dest, &t.FromUserId)
+		case "to_user_id":
+			dest = append(dest, &t.ToUserId)
+		case "bot_id":
+			dest = append(dest, &t.BotId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Message columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	MessageColumnId         Column = "id"
+	MessageColumnFromUserId Column = "from_user_id"
+	MessageColumnToUserId   Column = "to_user_id"
+	MessageColumnBotId      Column = "bot_id"
+)
+
+// Validate validates the Message fields.
+// The fields generated by the database are not validated.
+func (t *Message) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
 // MessageFilters is a struct that holds filters for Message.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type MessageFilters struct {
-	Id       *string
-	ToUserId *string
-	BotId    *string
+	Id         *string
+	IdIn       []string
+	ToUserId   *string
+	ToUserIdIn []string
+	BotId      *string
+	BotIdIn    []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *MessageFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.ToUserId != nil {
+		filters = append(filters, EqualsCondition{Field: "to_user_id", Value: *f.ToUserId})
+	}
+	if f.ToUserIdIn != nil {
+		filters = append(filters, InCondition{Field: "to_user_id", Values: toInterface(f.ToUserIdIn)})
+	}
+	if f.BotId != nil {
+		filters = append(filters, EqualsCondition{Field: "bot_id", Value: *f.BotId})
+	}
+	if f.BotIdIn != nil {
+		filters = append(filters, InCondition{Field: "bot_id", Values: toInterface(f.BotIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseMessageFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseMessageFilters(values url.Values) (*MessageFilters, error) {
+	f := &MessageFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "to_user_id":
+			f.ToUserId, err = parseFilterValue[string](key, values[key])
+		case "to_user_id_in":
+			f.ToUserIdIn, err = parseFilterValues[string](key, values[key])
+		case "bot_id":
+			f.BotId, err = parseFilterValue[string](key, values[key])
+		case "bot_id_in":
+			f.BotIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // MessageIdEq returns a condition that checks if the field equals the value.
@@ -579,6 +881,24 @@ func MessageBotIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("bot_id", asc)
 }
 
+// MessageHasBot checks if the Message has the Bot relation matching the filters.
+func MessageHasBot(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(BotColumnId)).From((&Bot{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(MessageColumnBotId), Query: query, Conditions: filters}
+}
+
+// MessageHasFromUser checks if the Message has the FromUser relation matching the filters.
+func MessageHasFromUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(MessageColumnFromUserId), Query: query, Conditions: filters}
+}
+
+// MessageHasToUser checks if the Message has the ToUser relation matching the filters.
+func MessageHasToUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(MessageColumnToUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Message.
 func (t *messageStorage) Create(ctx context.Context, model *Message, opts ...Option) (*string, error) {
 	if model == nil {
@@ -591,7 +911,13 @@ func (t *messageStorage) Create(ctx context.Context, model *Message, opts ...Opt
 		o(options)
 	}
 
-	query := t.queryBuilder.Insert("messages").
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			return nil, errors.Wrap(err, "failed to validate Message")
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"from_user_id",
 			"to_user_id",
@@ -640,7 +966,18 @@ func (t *messageStorage) BatchCreate(ctx context.Context, models []*Message, opt
 		return nil, errors.New("relations are not supported in batch create")
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "failed to validate Message at index %d", i)
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"from_user_id",
 			"to_user_id",
@@ -699,6 +1036,137 @@ func (t *messageStorage) BatchCreate(ctx context.Context, models []*Message, opt
 	return returnIDs, nil
 }
 
+// messageConflictTargets are the unique constraints of the messages table which the upsert can conflict on.
+var messageConflictTargets = [][]Column{
+	{MessageColumnFromUserId},
+	{MessageColumnToUserId},
+}
+
+// messageUpsertColumns are the columns inserted by the upsert.
+var messageUpsertColumns = []upsertColumn{
+	{column: MessageColumnFromUserId, written: []string{"from_user_id"}, index: "from_user_id"},
+	{column: MessageColumnToUserId, written: []string{"to_user_id"}, index: "to_user_id"},
+	{column: MessageColumnBotId, written: []string{"bot_id"}, index: "bot_id"},
+}
+
+// upsertValues returns the values of the model in the order of the messageUpsertColumns.
+func (t *messageStorage) upsertValues(model *Message) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(messageUpsertColumns))
+	values = append(values, model.FromUserId)
+	values = append(values, model.ToUserId)
+	values = append(values, nullValue(model.BotId))
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *messageStorage) upsertQuery(models []*Message, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix(t.TableName(), messageConflictTargets, messageUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames(messageUpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate Message at index %d", i)
+			}
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build query")
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Message or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(MessageColumnFromUserId).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *messageStorage) Upsert(ctx context.Context, model *Message, conflict *Conflict, opts ...Option) (*string, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Message{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	var id string
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to upsert Message")
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Message records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+// It returns the primary keys of the created or the updated rows.
+func (t *messageStorage) BatchUpsert(ctx context.Context, models []*Message, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+}
+
 // MessageUpdate is used to update an existing Message.
 type MessageUpdate struct {
 	// Use regular pointer types for non-optional fields
@@ -707,77 +1175,112 @@ type MessageUpdate struct {
 	ToUserId *string
 	// Use null types for optional fields
 	BotId null.String
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Message based on non-nil fields.
-func (t *messageStorage) Update(ctx context.Context, id string, updateData *MessageUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
-	}
+// Validate validates the fields of the MessageUpdate which are set.
+func (t *MessageUpdate) Validate() error {
+	v := &validator{}
 
-	query := t.queryBuilder.Update("messages")
-	// Handle fields that are not optional using a nil check
-	if updateData.FromUserId != nil {
-		query = query.Set("from_user_id", *updateData.FromUserId) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.ToUserId != nil {
-		query = query.Set("to_user_id", *updateData.ToUserId) // Dereference pointer value
-	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.BotId.Valid {
-		// Handle null.String specifically
-		if updateData.BotId.String == "" {
-			query = query.Set("bot_id", nil) // Explicitly set NULL for empty string
-		} else {
-			query = query.Set("bot_id", updateData.BotId.ValueOrZero())
-		}
+	return v.err()
+}
+
+// Update updates an existing Message based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *messageStorage) Update(ctx context.Context, id string, updateData *MessageUpdate, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
 	}
 
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 	query = query.Where("id = ?", id)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to update Message")
+		return 0, errors.Wrap(err, "failed to update Message")
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteById - deletes a Message by its id.
-func (t *messageStorage) DeleteById(ctx context.Context, id string, opts ...Option) error {
+// UpdateReturning updates an existing Message based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *messageStorage) UpdateReturning(ctx context.Context, id string, updateData *MessageUpdate, opts ...Option) (*Message, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
 		o(options)
 	}
 
-	query := t.queryBuilder.Delete("messages").Where("id = ?", id)
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete Message")
+		return nil, errors.Wrap(err, "failed to update Message")
 	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
 
-	return nil
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update Message")
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &Message{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan Message")
+	}
+
+	return model, nil
 }
 
-// DeleteMany removes entries from the messages table using the provided filters
-func (t *messageStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("messages")
+// UpdateMany updates the messages rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *messageStorage) UpdateMany(ctx context.Context, updateData *MessageUpdate, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -787,62 +1290,386 @@ func (t *messageStorage) DeleteMany(ctx context.Context, builders ...*QueryBuild
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
+			query = option.ApplyUpdate(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for update operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete messages")
-	}
-
-	return nil
-}
-
-// FindById retrieves a Message by its id.
-func (t *messageStorage) FindById(ctx context.Context, id string, opts ...Option) (*Message, error) {
-	builder := NewQueryBuilder()
-	{
-		builder.WithFilter(MessageIdEq(id))
-		builder.WithOptions(opts...)
+		return 0, errors.Wrap(err, "failed to update messages")
 	}
 
-	// Use FindOne to get a single result
-	model, err := t.FindOne(ctx, builder)
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return nil, errors.Wrap(err, "find one Message: ")
+		return 0, errors.Wrap(err, "failed to get rows affected")
 	}
-
-	return model, nil
+	return affected, nil
 }
 
-// FindMany finds multiple Message based on the provided options.
-func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Message, error) {
-	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
-
-	// set default options
-	options := &Options{}
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *messageStorage) updateQuery(updateData *MessageUpdate, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
 
-	// apply options from builder
-	for _, builder := range builders {
-		if builder == nil {
-			continue
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate Message")
 		}
-
-		// apply filter options
-		for _, option := range builder.filterOptions {
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.FromUserId != nil {
+		query = query.Set("from_user_id", *updateData.FromUserId) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.ToUserId != nil {
+		query = query.Set("to_user_id", *updateData.ToUserId) // Dereference pointer value
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.BotId.Valid {
+		// Handle null.String specifically
+		if updateData.BotId.String == "" {
+			query = query.Set("bot_id", nil) // Explicitly set NULL for empty string
+		} else {
+			query = query.Set("bot_id", updateData.BotId.ValueOrZero())
+		}
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
+}
+
+// DeleteById - deletes a Message by its id.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *messageStorage) DeleteById(ctx context.Context, id string, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete Message")
+	}
+
+	return rowsAffected(result)
+}
+
+// DeleteMany removes entries from the messages table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *messageStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete messages")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// FindById retrieves a Message by its id.
+func (t *messageStorage) FindById(ctx context.Context, id string, opts ...Option) (*Message, error) {
+	builder := NewQueryBuilder()
+	{
+		builder.WithFilter(MessageIdEq(id))
+		builder.WithOptions(opts...)
+	}
+
+	// Use FindOne to get a single result
+	model, err := t.FindOne(ctx, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "find one Message: ")
+	}
+
+	return model, nil
+}
+
+// FindMany finds multiple Message based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. MessageJoin<Relation>, are filled in the same query.
+func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Message, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Message
+	for rows.Next() {
+		model := &Message{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Message")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Message rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *messageStorage) FindEach(ctx context.Context, fn func(*Message) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *messageStorage) findEachWithCursor(ctx context.Context, fn func(*Message) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *messageStorage) scanEach(rows *sql.Rows, columns []string, fn func(*Message) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &Message{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan Message")
+		}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *messageStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Message", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *messageStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Message", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *messageStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *messageStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *messageStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
+	// build query
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
+
+	// set default options
+	options := &Options{}
+
+	// apply options from builder
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
 			query = option.Apply(query)
 		}
 
@@ -870,8 +1697,70 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// MessageJoinBot joins the Bot relation filtered by the filters,
+// FindMany fills Message.Bot with the joined rows in the same query.
+// The Message rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func MessageJoinBot(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "messages",
+		relation: "Bot",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// MessageJoinFromUser joins the FromUser relation filtered by the filters,
+// FindMany fills Message.FromUser with the joined rows in the same query.
+// The Message rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func MessageJoinFromUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "messages",
+		relation: "FromUser",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// MessageJoinToUser joins the ToUser relation filtered by the filters,
+// FindMany fills Message.ToUser with the joined rows in the same query.
+// The Message rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func MessageJoinToUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "messages",
+		relation: "ToUser",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Message rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Message rows are merged.
+func (t *messageStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Message, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
 	}
@@ -888,12 +1777,43 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}()
 
 	var results []*Message
+	found := make(map[int64]*Message)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Message{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan Message")
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Message")
+		}
+
+		// the Message row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -903,6 +1823,97 @@ func (t *messageStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *messageStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Message relation.
+func (t *messageStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with Message", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "Bot":
+		s := &botStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(BotColumnId)
+		ref := string(MessageColumnBotId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &Bot{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Message)
+					p.Bot = model
+				}
+			},
+		}, nil
+	case "FromUser":
+		s := &userStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(MessageColumnFromUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Message)
+					p.FromUser = model
+				}
+			},
+		}, nil
+	case "ToUser":
+		s := &userStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(MessageColumnToUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Message)
+					p.ToUser = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown relation %s of Message", join.relation)
+}
+
 // FindOne finds a single Message based on the provided options.
 func (t *messageStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Message, error) {
 	// Use findMany but limit the results to 1
@@ -922,7 +1933,7 @@ func (t *messageStorage) FindOne(ctx context.Context, builders ...*QueryBuilder)
 // Count counts Message based on the provided options.
 func (t *messageStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -955,6 +1966,151 @@ func (t *messageStorage) Count(ctx context.Context, builders ...*QueryBuilder) (
 	return count, nil
 }
 
+// MessageAggregateRow is a row of the Message Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type MessageAggregateRow struct {
+	Message
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *MessageAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// Aggregate groups the Message rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(MessageColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *messageStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*MessageAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Message")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Message", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*MessageAggregateRow
+	for rows.Next() {
+		row := &MessageAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Message.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Message aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Message rows found by the builders.
+func (t *messageStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*MessageAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &MessageAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Message rows.
+func (t *messageStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Message", aggregation)
+}
+
 // FindManyWithPagination finds multiple Message with pagination support.
 func (t *messageStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Message, *Paginator, error) {
 	// Count the total number of records
@@ -986,9 +2142,143 @@ func (t *messageStorage) FindManyWithPagination(ctx context.Context, limit int,
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Message after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *messageStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Message, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Message{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find Message")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Message row.
+func (t *messageStorage) cursor(model *Message, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *messageStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of Message can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *messageStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "from_user_id":
+		return true
+	case "to_user_id":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Message for the given ID.
 func (t *messageStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Message, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -7,6 +7,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	"math"
+	"net/url"
 )
 
 // settingStorage is a struct for the "settings" table.
@@ -15,18 +16,39 @@ type settingStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// SettingTableManager is an interface for managing the settings table.
+type SettingTableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+
+// SettingSchemaVerification is an interface for verifying the settings table.
+type SettingSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // SettingCRUDOperations is an interface for managing the settings table.
 type SettingCRUDOperations interface {
 	Create(ctx context.Context, model *Setting, opts ...Option) (*int32, error)
 	BatchCreate(ctx context.Context, models []*Setting, opts ...Option) ([]string, error)
-	Update(ctx context.Context, id int32, updateData *SettingUpdate) error
-	DeleteById(ctx context.Context, id int32, opts ...Option) error
+	Upsert(ctx context.Context, model *Setting, conflict *Conflict, opts ...Option) (*int32, error)
+	BatchUpsert(ctx context.Context, models []*Setting, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id int32, updateData *SettingUpdate, opts ...Option) (int64, error)
+	UpdateReturning(ctx context.Context, id int32, updateData *SettingUpdate, opts ...Option) (*Setting, error)
+	UpdateMany(ctx context.Context, updateData *SettingUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id int32, opts ...Option) (*Setting, error)
 }
 
 // SettingSearchOperations is an interface for searching the settings table.
 type SettingSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Setting, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Setting, error)
@@ -35,6 +57,16 @@ type SettingSearchOperations interface {
 // SettingPaginationOperations is an interface for pagination operations.
 type SettingPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Setting, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Setting, *CursorPaginator, error)
+}
+
+// SettingAggregateOperations is an interface for the aggregate queries.
+type SettingAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error)
+	SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
 }
 
 // SettingRelationLoading is an interface for loading relations.
@@ -45,7 +77,7 @@ type SettingRelationLoading interface {
 
 // SettingAdvancedDeletion is an interface for advanced deletion operations.
 type SettingAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // SettingRawQueryOperations is an interface for executing raw queries.
@@ -57,9 +89,13 @@ type SettingRawQueryOperations interface {
 
 // SettingStorage is a struct for the "settings" table.
 type SettingStorage interface {
+	SettingTableManager
+
+	SettingSchemaVerification
 	SettingCRUDOperations
 	SettingSearchOperations
 	SettingPaginationOperations
+	SettingAggregateOperations
 	SettingRelationLoading
 	SettingAdvancedDeletion
 	SettingRawQueryOperations
@@ -105,6 +141,16 @@ func (t *settingStorage) TableName() string {
 	return "settings"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *settingStorage) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *settingStorage) QualifiedTableName() string {
+	return "\"settings\""
+}
+
 // Columns returns the columns for the table.
 func (t *settingStorage) Columns() []string {
 	return []string{
@@ -137,6 +183,118 @@ func (t *settingStorage) DB(ctx context.Context, isWrite bool) QueryExecer {
 	return db
 }
 
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
+func (t *settingStorage) CreateTable(ctx context.Context) error {
+	sqlQuery := `
+		-- Table: "settings"
+		CREATE TABLE IF NOT EXISTS "settings" (
+		id  SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		value TEXT,
+		user_id UUID NOT NULL);
+		-- Other entities
+		CREATE UNIQUE INDEX IF NOT EXISTS "settings_user_id_unique_idx" ON "settings" USING btree (user_id);
+		CREATE INDEX IF NOT EXISTS "settings_name_idx" ON "settings" USING btree (name);
+		CREATE INDEX IF NOT EXISTS "settings_user_id_idx" ON "settings" USING btree (user_id);
+		-- Foreign keys
+		DO $$ BEGIN ALTER TABLE "settings" ADD CONSTRAINT "settings_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// DropTable drops the table.
+func (t *settingStorage) DropTable(ctx context.Context) error {
+	sqlQuery := `
+		DROP TABLE IF EXISTS "settings";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// TruncateTable truncates the table.
+func (t *settingStorage) TruncateTable(ctx context.Context) error {
+	sqlQuery := `
+		TRUNCATE TABLE "settings";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *settingStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *settingStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *settingStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "integer", NotNull: true, Statement: ""},
+		{Name: "name", Type: "text", NotNull: true, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"name\" TEXT NOT NULL;"},
+		{Name: "value", Type: "text", NotNull: false, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"value\" TEXT;"},
+		{Name: "user_id", Type: "uuid", NotNull: true, Statement: "ALTER TABLE \"settings\" ADD COLUMN IF NOT EXISTS \"user_id\" UUID NOT NULL;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *settingStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "settings_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"settings_user_id_unique_idx\" ON \"settings\" USING btree (\"user_id\");"},
+		{Name: "settings_name_idx", Columns: []string{"name"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"settings_name_idx\" ON \"settings\" USING btree (\"name\");"},
+		{Name: "settings_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"settings_user_id_idx\" ON \"settings\" USING btree (\"user_id\");"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *settingStorage) LoadUser(ctx context.Context, model *Setting, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -196,6 +354,29 @@ func (t *settingStorage) LoadBatchUser(ctx context.Context, items []*Setting, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *settingStorage) preload(ctx context.Context, items []*Setting, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Setting, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return errors.Errorf("unknown relation %s of Setting", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // Setting is a struct for the "settings" table.
 type Setting struct {
 	Id     int32  `db:"id"`
@@ -210,6 +391,16 @@ func (t *Setting) TableName() string {
 	return "settings"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *Setting) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *Setting) QualifiedTableName() string {
+	return "\"settings\""
+}
+
 // ScanRow scans a row into a Setting.
 func (t *Setting) ScanRow(r *sql.Row) error {
 	return r.Scan(&t.Id, &t.Name, &t.Value, &t.UserId)
@@ -225,10 +416,118 @@ func (t *Setting) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Setting, the other fields are left empty.
+func (t *Setting) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Setting) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "value":
+			dest = append(dest, &t.Value)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Setting columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	SettingColumnId     Column = "id"
+	SettingColumnName   Column = "name"
+	SettingColumnValue  Column = "value"
+	SettingColumnUserId Column = "user_id"
+)
+
+// Validate validates the Setting fields.
+// The fields generated by the database are not validated.
+func (t *Setting) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
 // SettingFilters is a struct that holds filters for Setting.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type SettingFilters struct {
-	Id     *int32
-	UserId *string
+	Id       *int32
+	IdFrom   *int32
+	IdTo     *int32
+	IdIn     []int32
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *SettingFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "id", Value: *f.IdFrom})
+	}
+	if f.IdTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "id", Value: *f.IdTo})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseSettingFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseSettingFilters(values url.Values) (*SettingFilters, error) {
+	f := &SettingFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[int32](key, values[key])
+		case "id_from":
+			f.IdFrom, err = parseFilterValue[int32](key, values[key])
+		case "id_to":
+			f.IdTo, err = parseFilterValue[int32](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[int32](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // SettingIdEq returns a condition that checks if the field equals the value.
@@ -346,6 +645,12 @@ func SettingUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// SettingHasUser checks if the Setting has the User relation matching the filters.
+func SettingHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(SettingColumnUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Setting.
 func (t *settingStorage) Create(ctx context.Context, model *Setting, opts ...Option) (*int32, error) {
 	if model == nil {
@@ -358,7 +663,13 @@ func (t *settingStorage) Create(ctx context.Context, model *Setting, opts ...Opt
 		o(options)
 	}
 
-	query := t.queryBuilder.Insert("settings").
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			return nil, errors.Wrap(err, "failed to validate Setting")
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"name",
 			"value",
@@ -407,7 +718,18 @@ func (t *settingStorage) BatchCreate(ctx context.Context, models []*Setting, opt
 		return nil, errors.New("relations are not supported in batch create")
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "failed to validate Setting at index %d", i)
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"name",
 			"value",
@@ -466,6 +788,136 @@ func (t *settingStorage) BatchCreate(ctx context.Context, models []*Setting, opt
 	return returnIDs, nil
 }
 
+// settingConflictTargets are the unique constraints of the settings table which the upsert can conflict on.
+var settingConflictTargets = [][]Column{
+	{SettingColumnUserId},
+}
+
+// settingUpsertColumns are the columns inserted by the upsert.
+var settingUpsertColumns = []upsertColumn{
+	{column: SettingColumnName, written: []string{"name"}, index: "name"},
+	{column: SettingColumnValue, written: []string{"value"}, index: "value"},
+	{column: SettingColumnUserId, written: []string{"user_id"}, index: "user_id"},
+}
+
+// upsertValues returns the values of the model in the order of the settingUpsertColumns.
+func (t *settingStorage) upsertValues(model *Setting) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(settingUpsertColumns))
+	values = append(values, model.Name)
+	values = append(values, model.Value)
+	values = append(values, model.UserId)
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *settingStorage) upsertQuery(models []*Setting, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix(t.TableName(), settingConflictTargets, settingUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames(settingUpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate Setting at index %d", i)
+			}
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build query")
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Setting or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(SettingColumnUserId).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *settingStorage) Upsert(ctx context.Context, model *Setting, conflict *Conflict, opts ...Option) (*int32, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Setting{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	var id int32
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to upsert Setting")
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Setting records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+// It returns the primary keys of the created or the updated rows.
+func (t *settingStorage) BatchUpsert(ctx context.Context, models []*Setting, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+}
+
 // SettingUpdate is used to update an existing Setting.
 type SettingUpdate struct {
 	// Use regular pointer types for non-optional fields
@@ -474,72 +926,112 @@ type SettingUpdate struct {
 	Value *string
 	// Use regular pointer types for non-optional fields
 	UserId *string
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Setting based on non-nil fields.
-func (t *settingStorage) Update(ctx context.Context, id int32, updateData *SettingUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
-	}
+// Validate validates the fields of the SettingUpdate which are set.
+func (t *SettingUpdate) Validate() error {
+	v := &validator{}
 
-	query := t.queryBuilder.Update("settings")
-	// Handle fields that are not optional using a nil check
-	if updateData.Name != nil {
-		query = query.Set("name", *updateData.Name) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Value != nil {
-		query = query.Set("value", *updateData.Value) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.UserId != nil {
-		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
+	return v.err()
+}
+
+// Update updates an existing Setting based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) Update(ctx context.Context, id int32, updateData *SettingUpdate, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
 	}
 
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 	query = query.Where("id = ?", id)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to update Setting")
+		return 0, errors.Wrap(err, "failed to update Setting")
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteById - deletes a Setting by its id.
-func (t *settingStorage) DeleteById(ctx context.Context, id int32, opts ...Option) error {
+// UpdateReturning updates an existing Setting based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) UpdateReturning(ctx context.Context, id int32, updateData *SettingUpdate, opts ...Option) (*Setting, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
 		o(options)
 	}
 
-	query := t.queryBuilder.Delete("settings").Where("id = ?", id)
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete Setting")
+		return nil, errors.Wrap(err, "failed to update Setting")
 	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
 
-	return nil
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update Setting")
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &Setting{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan Setting")
+	}
+
+	return model, nil
 }
 
-// DeleteMany removes entries from the settings table using the provided filters
-func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("settings")
+// UpdateMany updates the settings rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *settingStorage) UpdateMany(ctx context.Context, updateData *SettingUpdate, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -549,50 +1041,369 @@ func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuild
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
+			query = option.ApplyUpdate(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for update operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete settings")
+		return 0, errors.Wrap(err, "failed to update settings")
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
 }
 
-// FindById retrieves a Setting by its id.
-func (t *settingStorage) FindById(ctx context.Context, id int32, opts ...Option) (*Setting, error) {
-	builder := NewQueryBuilder()
-	{
-		builder.WithFilter(SettingIdEq(id))
-		builder.WithOptions(opts...)
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *settingStorage) updateQuery(updateData *SettingUpdate, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
+	if updateData == nil {
+		return query, errors.New("update data is nil")
 	}
 
-	// Use FindOne to get a single result
-	model, err := t.FindOne(ctx, builder)
-	if err != nil {
-		return nil, errors.Wrap(err, "find one Setting: ")
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate Setting")
+		}
 	}
-
-	return model, nil
+	// Handle fields that are not optional using a nil check
+	if updateData.Name != nil {
+		query = query.Set("name", *updateData.Name) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Value != nil {
+		query = query.Set("value", *updateData.Value) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.UserId != nil {
+		query = query.Set("user_id", *updateData.UserId) // Dereference pointer value
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
+}
+
+// DeleteById - deletes a Setting by its id.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete Setting")
+	}
+
+	return rowsAffected(result)
+}
+
+// DeleteMany removes entries from the settings table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete settings")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// FindById retrieves a Setting by its id.
+func (t *settingStorage) FindById(ctx context.Context, id int32, opts ...Option) (*Setting, error) {
+	builder := NewQueryBuilder()
+	{
+		builder.WithFilter(SettingIdEq(id))
+		builder.WithOptions(opts...)
+	}
+
+	// Use FindOne to get a single result
+	model, err := t.FindOne(ctx, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "find one Setting: ")
+	}
+
+	return model, nil
 }
 
 // FindMany finds multiple Setting based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. SettingJoin<Relation>, are filled in the same query.
 func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Setting, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*Setting
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Setting")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Setting rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *settingStorage) FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *settingStorage) findEachWithCursor(ctx context.Context, fn func(*Setting) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *settingStorage) scanEach(rows *sql.Rows, columns []string, fn func(*Setting) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan Setting")
+		}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *settingStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Setting", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *settingStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of Setting", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *settingStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *settingStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *settingStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
 
 	// set default options
 	options := &Options{}
@@ -632,8 +1443,46 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// SettingJoinUser joins the User relation filtered by the filters,
+// FindMany fills Setting.User with the joined rows in the same query.
+// The Setting rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func SettingJoinUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "settings",
+		relation: "User",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Setting rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Setting rows are merged.
+func (t *settingStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Setting, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
 	}
@@ -650,12 +1499,43 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	}()
 
 	var results []*Setting
+	found := make(map[int64]*Setting)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Setting{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan Setting")
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Setting")
+		}
+
+		// the Setting row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -665,6 +1545,53 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *settingStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Setting relation.
+func (t *settingStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with Setting", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "User":
+		s := &userStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(SettingColumnUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Setting)
+					p.User = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown relation %s of Setting", join.relation)
+}
+
 // FindOne finds a single Setting based on the provided options.
 func (t *settingStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
 	// Use findMany but limit the results to 1
@@ -684,7 +1611,7 @@ func (t *settingStorage) FindOne(ctx context.Context, builders ...*QueryBuilder)
 // Count counts Setting based on the provided options.
 func (t *settingStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -717,6 +1644,228 @@ func (t *settingStorage) Count(ctx context.Context, builders ...*QueryBuilder) (
 	return count, nil
 }
 
+// SettingAggregateRow is a row of the Setting Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type SettingAggregateRow struct {
+	Setting
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *SettingAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumId returns the sum of the id values, it is set by Sum(SettingColumnId).
+func (r *SettingAggregateRow) SumId() int64 {
+	if v, ok := r.values[Sum(SettingColumnId)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgId returns the average of the id values, it is set by Avg(SettingColumnId).
+func (r *SettingAggregateRow) AvgId() *float64 {
+	if v, ok := r.values[Avg(SettingColumnId)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinId returns the smallest id value, it is set by Min(SettingColumnId).
+func (r *SettingAggregateRow) MinId() *int32 {
+	if v, ok := r.values[Min(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxId returns the largest id value, it is set by Max(SettingColumnId).
+func (r *SettingAggregateRow) MaxId() *int32 {
+	if v, ok := r.values[Max(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Setting rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(SettingColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *settingStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of Setting")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of Setting", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*SettingAggregateRow
+	for rows.Next() {
+		row := &SettingAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Setting.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan Setting aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Setting rows found by the builders.
+func (t *settingStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*SettingAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &SettingAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumId returns the sum of the id values of the Setting rows found by the builders.
+func (t *settingStorage) SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(SettingColumnId), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumId(), nil
+}
+
+// AvgId returns the average of the id values of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgId(), nil
+}
+
+// MinId returns the smallest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinId(), nil
+}
+
+// MaxId returns the largest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxId(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Setting rows.
+func (t *settingStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(SettingColumnId):
+		return new(*int64), nil
+	case Avg(SettingColumnId):
+		return new(*float64), nil
+	case Min(SettingColumnId), Max(SettingColumnId):
+		return new(*int32), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of Setting", aggregation)
+}
+
 // FindManyWithPagination finds multiple Setting with pagination support.
 func (t *settingStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Setting, *Paginator, error) {
 	// Count the total number of records
@@ -748,9 +1897,145 @@ func (t *settingStorage) FindManyWithPagination(ctx context.Context, limit int,
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Setting after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *settingStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Setting, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Setting{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find Setting")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Setting row.
+func (t *settingStorage) cursor(model *Setting, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *settingStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of Setting can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *settingStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "name":
+		return true
+	case "value":
+		return true
+	case "user_id":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Setting for the given ID.
 func (t *settingStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
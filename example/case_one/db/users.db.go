@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v4"
 	"math"
+	"net/url"
 	"time"
 )
 
@@ -17,18 +18,39 @@ type userStorage struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+// UserTableManager is an interface for managing the users table.
+type UserTableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+
+// UserSchemaVerification is an interface for verifying the users table.
+type UserSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // UserCRUDOperations is an interface for managing the users table.
 type UserCRUDOperations interface {
 	Create(ctx context.Context, model *User, opts ...Option) (*string, error)
 	BatchCreate(ctx context.Context, models []*User, opts ...Option) ([]string, error)
-	Update(ctx context.Context, id string, updateData *UserUpdate) error
-	DeleteById(ctx context.Context, id string, opts ...Option) error
+	Upsert(ctx context.Context, model *User, conflict *Conflict, opts ...Option) (*string, error)
+	BatchUpsert(ctx context.Context, models []*User, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id string, updateData *UserUpdate, opts ...Option) (int64, error)
+	UpdateReturning(ctx context.Context, id string, updateData *UserUpdate, opts ...Option) (*User, error)
+	UpdateMany(ctx context.Context, updateData *UserUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id string, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id string, opts ...Option) (*User, error)
 }
 
 // UserSearchOperations is an interface for searching the users table.
 type UserSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*User, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*User) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*User, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*User, error)
@@ -37,6 +59,20 @@ type UserSearchOperations interface {
 // UserPaginationOperations is an interface for pagination operations.
 type UserPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*User, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*User, *CursorPaginator, error)
+}
+
+// UserAggregateOperations is an interface for the aggregate queries.
+type UserAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*UserAggregateRow, error)
+	SumAge(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgAge(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error)
 }
 
 // UserRelationLoading is an interface for loading relations.
@@ -53,7 +89,7 @@ type UserRelationLoading interface {
 
 // UserAdvancedDeletion is an interface for advanced deletion operations.
 type UserAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // UserRawQueryOperations is an interface for executing raw queries.
@@ -65,9 +101,13 @@ type UserRawQueryOperations interface {
 
 // UserStorage is a struct for the "users" table.
 type UserStorage interface {
+	UserTableManager
+
+	UserSchemaVerification
 	UserCRUDOperations
 	UserSearchOperations
 	UserPaginationOperations
+	UserAggregateOperations
 	UserRelationLoading
 	UserAdvancedDeletion
 	UserRawQueryOperations
@@ -113,6 +153,16 @@ func (t *userStorage) TableName() string {
 	return "users"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *userStorage) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *userStorage) QualifiedTableName() string {
+	return "\"users\""
+}
+
 // Columns returns the columns for the table.
 func (t *userStorage) Columns() []string {
 	return []string{
@@ -145,6 +195,137 @@ func (t *userStorage) DB(ctx context.Context, isWrite bool) QueryExecer {
 	return db
 }
 
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
+func (t *userStorage) CreateTable(ctx context.Context) error {
+	sqlQuery := `
+		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+		-- Table: "users"
+		CREATE TABLE IF NOT EXISTS "users" (
+		id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+		name TEXT NOT NULL,
+		age INTEGER NOT NULL,
+		email TEXT NOT NULL,
+		last_name TEXT,
+		created_at TIMESTAMP NOT NULL DEFAULT now(),
+		updated_at TIMESTAMP,
+		notification_settings JSONB,
+		phones JSONB,
+		balls JSONB,
+		numrs JSONB,
+		comments JSONB);
+		-- Other entities
+		COMMENT ON TABLE "users" IS 'This is a comment of User';
+		CREATE UNIQUE INDEX IF NOT EXISTS "users_email_unique_idx" ON "users" USING btree (email);
+		CREATE UNIQUE INDEX IF NOT EXISTS "users_unique_idx_name_email" ON "users" USING btree (
+            name, 
+            email
+    	);
+		CREATE INDEX IF NOT EXISTS "users_name_idx" ON "users" USING btree (name);
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// DropTable drops the table.
+func (t *userStorage) DropTable(ctx context.Context) error {
+	sqlQuery := `
+		DROP TABLE IF EXISTS "users";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// TruncateTable truncates the table.
+func (t *userStorage) TruncateTable(ctx context.Context) error {
+	sqlQuery := `
+		TRUNCATE TABLE "users";
+	`
+
+	_, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *userStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *userStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *userStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "uuid", NotNull: true, Statement: ""},
+		{Name: "name", Type: "text", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"name\" TEXT NOT NULL;"},
+		{Name: "age", Type: "integer", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"age\" INTEGER NOT NULL;"},
+		{Name: "email", Type: "text", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"email\" TEXT NOT NULL;"},
+		{Name: "last_name", Type: "text", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"last_name\" TEXT;"},
+		{Name: "created_at", Type: "timestamp without time zone", NotNull: true, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"created_at\" TIMESTAMP NOT NULL DEFAULT now();"},
+		{Name: "updated_at", Type: "timestamp without time zone", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"updated_at\" TIMESTAMP;"},
+		{Name: "notification_settings", Type: "jsonb", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"notification_settings\" JSONB;"},
+		{Name: "phones", Type: "jsonb", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"phones\" JSONB;"},
+		{Name: "balls", Type: "jsonb", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"balls\" JSONB;"},
+		{Name: "numrs", Type: "jsonb", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"numrs\" JSONB;"},
+		{Name: "comments", Type: "jsonb", NotNull: false, Statement: "ALTER TABLE \"users\" ADD COLUMN IF NOT EXISTS \"comments\" JSONB;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *userStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "users_email_unique_idx", Columns: []string{"email"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"users_email_unique_idx\" ON \"users\" USING btree (\"email\");"},
+		{Name: "users_unique_idx_name_email", Columns: []string{"name", "email"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"users_unique_idx_name_email\" ON \"users\" USING btree (\"name\", \"email\");"},
+		{Name: "users_name_idx", Columns: []string{"name"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"users_name_idx\" ON \"users\" USING btree (\"name\");"},
+	}
+}
+
 // LoadDevice loads the Device relation.
 func (t *userStorage) LoadDevice(ctx context.Context, model *User, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -381,6 +562,35 @@ func (t *userStorage) LoadBatchPosts(ctx context.Context, items []*User, builder
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *userStorage) preload(ctx context.Context, items []*User, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*User, ...*QueryBuilder) error
+		switch relation {
+		case "Device":
+			load = t.LoadBatchDevice
+		case "Settings":
+			load = t.LoadBatchSettings
+		case "Addresses":
+			load = t.LoadBatchAddresses
+		case "Posts":
+			load = t.LoadBatchPosts
+		default:
+			return errors.Errorf("unknown relation %s of User", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+
+	return nil
+}
+
 // User is a struct for the "users" table.
 type User struct {
 	Id                   string  `db:"id"`
@@ -406,6 +616,16 @@ func (t *User) TableName() string {
 	return "users"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *User) SchemaName() string {
+	return ""
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *User) QualifiedTableName() string {
+	return "\"users\""
+}
+
 // ScanRow scans a row into a User.
 func (t *User) ScanRow(r *sql.Row) error {
 	return r.Scan(&t.Id, &t.Name, &t.Age, &t.Email, &t.LastName, &t.CreatedAt, &t.UpdatedAt, &t.NotificationSettings, &t.Phones, &t.Balls, &t.Numrs, &t.Comments)
@@ -429,12 +649,166 @@ func (t *User) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the User, the other fields are left empty.
+func (t *User) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *User) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "age":
+			dest = append(dest, &t.Age)
+		case "email":
+			dest = append(dest, &t.Email)
+		case "last_name":
+			dest = append(dest, &t.LastName)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		case "notification_settings":
+			dest = append(dest, &t.NotificationSettings)
+		case "phones":
+			dest = append(dest, &t.Phones)
+		case "balls":
+			dest = append(dest, &t.Balls)
+		case "numrs":
+			dest = append(dest, &t.Numrs)
+		case "comments":
+			dest = append(dest, &t.Comments)
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// User columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	UserColumnId                   Column = "id"
+	UserColumnName                 Column = "name"
+	UserColumnAge                  Column = "age"
+	UserColumnEmail                Column = "email"
+	UserColumnLastName             Column = "last_name"
+	UserColumnCreatedAt            Column = "created_at"
+	UserColumnUpdatedAt            Column = "updated_at"
+	UserColumnNotificationSettings Column = "notification_settings"
+	UserColumnPhones               Column = "phones"
+	UserColumnBalls                Column = "balls"
+	UserColumnNumrs                Column = "numrs"
+	UserColumnComments             Column = "comments"
+)
+
+// Validate validates the User fields.
+// The fields generated by the database are not validated.
+func (t *User) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
 // UserFilters is a struct that holds filters for User.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type UserFilters struct {
-	Id    *string
-	Name  *string
-	Age   *int32
-	Email *string
+	Id      *string
+	IdIn    []string
+	Name    *string
+	NameIn  []string
+	Age     *int32
+	AgeFrom *int32
+	AgeTo   *int32
+	AgeIn   []int32
+	Email   *string
+	EmailIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *UserFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.Name != nil {
+		filters = append(filters, EqualsCondition{Field: "name", Value: *f.Name})
+	}
+	if f.NameIn != nil {
+		filters = append(filters, InCondition{Field: "name", Values: toInterface(f.NameIn)})
+	}
+	if f.Age != nil {
+		filters = append(filters, EqualsCondition{Field: "age", Value: *f.Age})
+	}
+	if f.AgeFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "age", Value: *f.AgeFrom})
+	}
+	if f.AgeTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "age", Value: *f.AgeTo})
+	}
+	if f.AgeIn != nil {
+		filters = append(filters, InCondition{Field: "age", Values: toInterface(f.AgeIn)})
+	}
+	if f.Email != nil {
+		filters = append(filters, EqualsCondition{Field: "email", Value: *f.Email})
+	}
+	if f.EmailIn != nil {
+		filters = append(filters, InCondition{Field: "email", Values: toInterface(f.EmailIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseUserFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseUserFilters(values url.Values) (*UserFilters, error) {
+	f := &UserFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "name":
+			f.Name, err = parseFilterValue[string](key, values[key])
+		case "name_in":
+			f.NameIn, err = parseFilterValues[string](key, values[key])
+		case "age":
+			f.Age, err = parseFilterValue[int32](key, values[key])
+		case "age_from":
+			f.AgeFrom, err = parseFilterValue[int32](key, values[key])
+		case "age_to":
+			f.AgeTo, err = parseFilterValue[int32](key, values[key])
+		case "age_in":
+			f.AgeIn, err = parseFilterValues[int32](key, values[key])
+		case "email":
+			f.Email, err = parseFilterValue[string](key, values[key])
+		case "email_in":
+			f.EmailIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // UserIdEq returns a condition that checks if the field equals the value.
@@ -682,6 +1056,65 @@ func UserEmailOrderBy(asc bool) FilterApplier {
 	return OrderBy("email", asc)
 }
 
+// UserNotificationSettingsContains returns a condition that checks if the JSON object contains the value.
+func UserNotificationSettingsContains(value interface{}) FilterApplier {
+	return JSONContains("notification_settings", value)
+}
+
+// UserNotificationSettingsHasKey returns a condition that checks if the JSON object has the key.
+func UserNotificationSettingsHasKey(key string) FilterApplier {
+	return JSONHasKey("notification_settings", key)
+}
+
+// UserNotificationSettingsPathEq returns a condition that checks if the value at the dot-separated path of the JSON object equals the value.
+func UserNotificationSettingsPathEq(path string, value interface{}) FilterApplier {
+	return JSONPathEq("notification_settings", path, value)
+}
+
+// UserPhonesContains returns a condition that checks if the JSON array contains the value.
+func UserPhonesContains(value string) FilterApplier {
+	return JSONContains("phones", []string{value})
+}
+
+// UserBallsContains returns a condition that checks if the JSON array contains the value.
+func UserBallsContains(value int32) FilterApplier {
+	return JSONContains("balls", []int32{value})
+}
+
+// UserNumrsContains returns a condition that checks if the JSON array contains the value.
+func UserNumrsContains(value interface{}) FilterApplier {
+	return JSONContains("numrs", []interface{}{value})
+}
+
+// UserCommentsContains returns a condition that checks if the JSON array contains the value.
+func UserCommentsContains(value interface{}) FilterApplier {
+	return JSONContains("comments", []interface{}{value})
+}
+
+// UserHasDevice checks if the User has the Device relation matching the filters.
+func UserHasDevice(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(DeviceColumnUserId)).From((&Device{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasSettings checks if the User has the Settings relation matching the filters.
+func UserHasSettings(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(SettingColumnUserId)).From((&Setting{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasAddresses checks if the User has the Addresses relation matching the filters.
+func UserHasAddresses(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(AddressColumnUserId)).From((&Address{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
+// UserHasPosts checks if the User has the Posts relation matching the filters.
+func UserHasPosts(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(PostColumnAuthorId)).From((&Post{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string(UserColumnId), Query: query, Conditions: filters}
+}
+
 // Create creates a new User.
 func (t *userStorage) Create(ctx context.Context, model *User, opts ...Option) (*string, error) {
 	if model == nil {
@@ -693,6 +1126,12 @@ func (t *userStorage) Create(ctx context.Context, model *User, opts ...Option) (
 	for _, o := range opts {
 		o(options)
 	}
+
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			return nil, errors.Wrap(err, "failed to validate User")
+		}
+	}
 	// get value of phones
 	phones, err := model.Phones.Value()
 	if err != nil {
@@ -714,7 +1153,7 @@ func (t *userStorage) Create(ctx context.Context, model *User, opts ...Option) (
 		return nil, errors.Wrap(err, "failed to get value of Comments")
 	}
 
-	query := t.queryBuilder.Insert("users").
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"name",
 			"age",
@@ -818,7 +1257,18 @@ func (t *userStorage) BatchCreate(ctx context.Context, models []*User, opts ...O
 		return nil, errors.New("relations are not supported in batch create")
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "failed to validate User at index %d", i)
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			"name",
 			"age",
@@ -837,6 +1287,26 @@ func (t *userStorage) BatchCreate(ctx context.Context, models []*User, opts ...O
 		if model == nil {
 			return nil, errors.New("one of the models is nil")
 		}
+		// get value of phones
+		phones, err := model.Phones.Value()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value of Phones")
+		}
+		// get value of balls
+		balls, err := model.Balls.Value()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value of Balls")
+		}
+		// get value of numrs
+		numrs, err := model.Numrs.Value()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value of Numrs")
+		}
+		// get value of comments
+		comments, err := model.Comments.Value()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value of Comments")
+		}
 		query = query.Values(
 			model.Name,
 			model.Age,
@@ -893,195 +1363,717 @@ func (t *userStorage) BatchCreate(ctx context.Context, models []*User, opts ...O
 	return returnIDs, nil
 }
 
-// UserUpdate is used to update an existing User.
-type UserUpdate struct {
-	// Use regular pointer types for non-optional fields
-	Name *string
-	// Use regular pointer types for non-optional fields
-	Age *int32
-	// Use regular pointer types for non-optional fields
-	Email *string
-	// Use null types for optional fields
-	LastName null.String
-	// Use regular pointer types for non-optional fields
-	CreatedAt *time.Time
-	// Use null types for optional fields
-	UpdatedAt null.Time
-	// Use null types for optional fields
-	NotificationSettings NullableJSON[*UserNotificationSetting]
-	// Use regular pointer types for non-optional fields
-	Phones *UserPhonesRepeated
-	// Use regular pointer types for non-optional fields
-	Balls *UserBallsRepeated
-	// Use regular pointer types for non-optional fields
-	Numrs *UserNumrsRepeated
-	// Use regular pointer types for non-optional fields
-	Comments *UserCommentsRepeated
+// userConflictTargets are the unique constraints of the users table which the upsert can conflict on.
+var userConflictTargets = [][]Column{
+	{UserColumnEmail},
+	{UserColumnName, UserColumnEmail},
+}
+
+// userUpsertColumns are the columns inserted by the upsert.
+var userUpsertColumns = []upsertColumn{
+	{column: UserColumnName, written: []string{"name"}, index: "name"},
+	{column: UserColumnAge, written: []string{"age"}, index: "age"},
+	{column: UserColumnEmail, written: []string{"email"}, index: "email"},
+	{column: UserColumnLastName, written: []string{"last_name"}, index: "last_name"},
+	{column: UserColumnCreatedAt, written: []string{"created_at"}, index: "created_at"},
+	{column: UserColumnUpdatedAt, written: []string{"updated_at"}, index: "updated_at"},
+	{column: UserColumnNotificationSettings, written: []string{"notification_settings"}, index: "notification_settings"},
+	{column: UserColumnPhones, written: []string{"phones"}, index: "phones"},
+	{column: UserColumnBalls, written: []string{"balls"}, index: "balls"},
+	{column: UserColumnNumrs, written: []string{"numrs"}, index: "numrs"},
+	{column: UserColumnComments, written: []string{"comments"}, index: "comments"},
+}
+
+// upsertValues returns the values of the model in the order of the userUpsertColumns.
+func (t *userStorage) upsertValues(model *User) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(userUpsertColumns))
+	values = append(values, model.Name)
+	values = append(values, model.Age)
+	values = append(values, model.Email)
+	values = append(values, nullValue(model.LastName))
+	values = append(values, model.CreatedAt)
+	values = append(values, nullValue(model.UpdatedAt))
+	values = append(values, nullValue(model.NotificationSettings))
+	phonesValue, err := model.Phones.Value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value of Phones")
+	}
+	values = append(values, phonesValue)
+	ballsValue, err := model.Balls.Value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value of Balls")
+	}
+	values = append(values, ballsValue)
+	numrsValue, err := model.Numrs.Value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value of Numrs")
+	}
+	values = append(values, numrsValue)
+	commentsValue, err := model.Comments.Value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value of Comments")
+	}
+	values = append(values, commentsValue)
+	return values, nil
 }
 
-// Update updates an existing User based on non-nil fields.
-func (t *userStorage) Update(ctx context.Context, id string, updateData *UserUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *userStorage) upsertQuery(models []*User, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
 	}
 
-	query := t.queryBuilder.Update("users")
-	// Handle fields that are not optional using a nil check
-	if updateData.Name != nil {
-		query = query.Set("name", *updateData.Name) // Dereference pointer value
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
 	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Age != nil {
-		query = query.Set("age", *updateData.Age) // Dereference pointer value
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
 	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Email != nil {
-		query = query.Set("email", *updateData.Email) // Dereference pointer value
+
+	suffix, err := conflict.suffix(t.TableName(), userConflictTargets, userUpsertColumns)
+	if err != nil {
+		return "", nil, err
 	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.LastName.Valid {
-		// Handle null.String specifically
-		if updateData.LastName.String == "" {
-			query = query.Set("last_name", nil) // Explicitly set NULL for empty string
-		} else {
-			query = query.Set("last_name", updateData.LastName.ValueOrZero())
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames(userUpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
 		}
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.CreatedAt != nil {
-		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
-	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.UpdatedAt.Valid {
-		// Handle null.Time specifically
-		if updateData.UpdatedAt.Time.IsZero() {
-			query = query.Set("updated_at", nil) // Explicitly set NULL if time is zero
-		} else {
-			query = query.Set("updated_at", updateData.UpdatedAt.Time)
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate User at index %d", i)
+			}
 		}
-	}
-	// Handle fields that are optional and can be explicitly set to NULL
-	if updateData.NotificationSettings.Valid {
-		if updateData.NotificationSettings.Data == nil {
-			query = query.Set("notification_settings", nil) // Explicitly set NULL
-		} else {
-			query = query.Set("notification_settings", updateData.NotificationSettings.Data)
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
 		}
+		query = query.Values(values...)
 	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Phones != nil {
-		query = query.Set("phones", *updateData.Phones) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Balls != nil {
-		query = query.Set("balls", *updateData.Balls) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Numrs != nil {
-		query = query.Set("numrs", *updateData.Numrs) // Dereference pointer value
-	}
-	// Handle fields that are not optional using a nil check
-	if updateData.Comments != nil {
-		query = query.Set("comments", *updateData.Comments) // Dereference pointer value
-	}
-
-	query = query.Where("id = ?", id)
+	query = query.Suffix(suffix + " RETURNING \"id\"")
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
-	}
-	t.logQuery(ctx, sqlQuery, args...)
-
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
-	if err != nil {
-		return errors.Wrap(err, "failed to update User")
+		return "", nil, errors.Wrap(err, "failed to build query")
 	}
-
-	return nil
+	return sqlQuery, args, nil
 }
 
-// DeleteById - deletes a User by its id.
-func (t *userStorage) DeleteById(ctx context.Context, id string, opts ...Option) error {
-	// set default options
-	options := &Options{}
-	for _, o := range opts {
-		o(options)
+// Upsert creates the User or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(UserColumnEmail).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *userStorage) Upsert(ctx context.Context, model *User, conflict *Conflict, opts ...Option) (*string, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
 	}
 
-	query := t.queryBuilder.Delete("users").Where("id = ?", id)
-
-	sqlQuery, args, err := query.ToSql()
+	sqlQuery, args, err := t.upsertQuery([]*User{model}, conflict, opts...)
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, err
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	var id string
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete User")
-	}
-
-	return nil
-}
-
-// DeleteMany removes entries from the users table using the provided filters
-func (t *userStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("users")
-
-	var withFilter bool
-	for _, builder := range builders {
-		if builder == nil {
-			continue
-		}
-
-		// apply filter options
-		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
-			withFilter = true
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
 		}
+		return nil, errors.Wrap(err, "failed to upsert User")
 	}
 
-	if !withFilter {
-		return errors.New("filters are required for delete operation")
-	}
+	return &id, nil
+}
 
-	sqlQuery, args, err := query.ToSql()
+// BatchUpsert creates multiple User records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+// It returns the primary keys of the created or the updated rows.
+func (t *userStorage) BatchUpsert(ctx context.Context, models []*User, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return nil, err
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete users")
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
 	}
-
-	return nil
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+}
+
+// UserUpdate is used to update an existing User.
+type UserUpdate struct {
+	// Use regular pointer types for non-optional fields
+	Name *string
+	// Use regular pointer types for non-optional fields
+	Age *int32
+	// Use regular pointer types for non-optional fields
+	Email *string
+	// Use null types for optional fields
+	LastName null.String
+	// Use regular pointer types for non-optional fields
+	CreatedAt *time.Time
+	// Use null types for optional fields
+	UpdatedAt null.Time
+	// Use null types for optional fields
+	NotificationSettings NullableJSON[*UserNotificationSetting]
+	// Use regular pointer types for non-optional fields
+	Phones *UserPhonesRepeated
+	// Use regular pointer types for non-optional fields
+	Balls *UserBallsRepeated
+	// Use regular pointer types for non-optional fields
+	Numrs *UserNumrsRepeated
+	// Use regular pointer types for non-optional fields
+	Comments *UserCommentsRepeated
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
+}
+
+// Validate validates the fields of the UserUpdate which are set.
+func (t *UserUpdate) Validate() error {
+	v := &validator{}
+
+	return v.err()
+}
+
+// Update updates an existing User based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *userStorage) Update(ctx context.Context, id string, updateData *UserUpdate, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
+	query = query.Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update User")
+	}
+
+	return rowsAffected(result)
+}
+
+// UpdateReturning updates an existing User based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *userStorage) UpdateReturning(ctx context.Context, id string, updateData *UserUpdate, opts ...Option) (*User, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update User")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update User")
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &User{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan User")
+	}
+
+	return model, nil
+}
+
+// UpdateMany updates the users rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *userStorage) UpdateMany(ctx context.Context, updateData *UserUpdate, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update users")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *userStorage) updateQuery(updateData *UserUpdate, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
+
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate User")
+		}
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Name != nil {
+		query = query.Set("name", *updateData.Name) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Age != nil {
+		query = query.Set("age", *updateData.Age) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Email != nil {
+		query = query.Set("email", *updateData.Email) // Dereference pointer value
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.LastName.Valid {
+		// Handle null.String specifically
+		if updateData.LastName.String == "" {
+			query = query.Set("last_name", nil) // Explicitly set NULL for empty string
+		} else {
+			query = query.Set("last_name", updateData.LastName.ValueOrZero())
+		}
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.CreatedAt != nil {
+		query = query.Set("created_at", *updateData.CreatedAt) // Dereference pointer value
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.UpdatedAt.Valid {
+		// Handle null.Time specifically
+		if updateData.UpdatedAt.Time.IsZero() {
+			query = query.Set("updated_at", nil) // Explicitly set NULL if time is zero
+		} else {
+			query = query.Set("updated_at", updateData.UpdatedAt.Time)
+		}
+	}
+	// Handle fields that are optional and can be explicitly set to NULL
+	if updateData.NotificationSettings.Valid {
+		if updateData.NotificationSettings.Data == nil {
+			query = query.Set("notification_settings", nil) // Explicitly set NULL
+		} else {
+			query = query.Set("notification_settings", updateData.NotificationSettings.Data)
+		}
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Phones != nil {
+		query = query.Set("phones", *updateData.Phones) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Balls != nil {
+		query = query.Set("balls", *updateData.Balls) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Numrs != nil {
+		query = query.Set("numrs", *updateData.Numrs) // Dereference pointer value
+	}
+	// Handle fields that are not optional using a nil check
+	if updateData.Comments != nil {
+		query = query.Set("comments", *updateData.Comments) // Dereference pointer value
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
+}
+
+// DeleteById - deletes a User by its id.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *userStorage) DeleteById(ctx context.Context, id string, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete User")
+	}
+
+	return rowsAffected(result)
+}
+
+// DeleteMany removes entries from the users table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *userStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete users")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
+}
+
+// FindById retrieves a User by its id.
+func (t *userStorage) FindById(ctx context.Context, id string, opts ...Option) (*User, error) {
+	builder := NewQueryBuilder()
+	{
+		builder.WithFilter(UserIdEq(id))
+		builder.WithOptions(opts...)
+	}
+
+	// Use FindOne to get a single result
+	model, err := t.FindOne(ctx, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "find one User: ")
+	}
+
+	return model, nil
+}
+
+// FindMany finds multiple User based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. UserJoin<Relation>, are filled in the same query.
+func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*User, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*User
+	for rows.Next() {
+		model := &User{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan User")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the User rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *userStorage) FindEach(ctx context.Context, fn func(*User) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *userStorage) findEachWithCursor(ctx context.Context, fn func(*User) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *userStorage) scanEach(rows *sql.Rows, columns []string, fn func(*User) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &User{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan User")
+		}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *userStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of User", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *userStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of User", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
 }
 
-// FindById retrieves a User by its id.
-func (t *userStorage) FindById(ctx context.Context, id string, opts ...Option) (*User, error) {
-	builder := NewQueryBuilder()
-	{
-		builder.WithFilter(UserIdEq(id))
-		builder.WithOptions(opts...)
+// hasColumn returns true if the column belongs to the table.
+func (t *userStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Use FindOne to get a single result
-	model, err := t.FindOne(ctx, builder)
+// queryColumns executes the query of the builders which selects the columns.
+func (t *userStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
 	if err != nil {
-		return nil, errors.Wrap(err, "find one User: ")
+		return nil, errors.Wrap(err, "failed to build query")
 	}
+	t.logQuery(ctx, sqlQuery, args...)
 
-	return model, nil
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
 }
 
-// FindMany finds multiple User based on the provided options.
-func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*User, error) {
+// selectQuery builds the query of the builders which selects the columns.
+func (t *userStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
 
 	// set default options
 	options := &Options{}
@@ -1121,8 +2113,82 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// UserJoinDevice joins the Device relation filtered by the filters,
+// FindMany fills User.Device with the joined rows in the same query.
+// The User rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func UserJoinDevice(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "users",
+		relation: "Device",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// UserJoinSettings joins the Settings relation filtered by the filters,
+// FindMany fills User.Settings with the joined rows in the same query.
+// The User rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func UserJoinSettings(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "users",
+		relation: "Settings",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// UserJoinAddresses joins the Addresses relation filtered by the filters,
+// FindMany fills User.Addresses with the joined rows in the same query.
+// The User rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func UserJoinAddresses(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "users",
+		relation: "Addresses",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// UserJoinPosts joins the Posts relation filtered by the filters,
+// FindMany fills User.Posts with the joined rows in the same query.
+// The User rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func UserJoinPosts(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "users",
+		relation: "Posts",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the User rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated User rows are merged.
+func (t *userStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*User, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
 	}
@@ -1139,12 +2205,43 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	}()
 
 	var results []*User
+	found := make(map[int64]*User)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &User{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan User")
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan User")
+		}
+
+		// the User row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -1154,6 +2251,119 @@ func (t *userStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *userStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a User relation.
+func (t *userStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with User", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "Device":
+		s := &deviceStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(DeviceColumnUserId)
+		ref := string(UserColumnId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &Device{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*User)
+					p.Device = model
+				}
+			},
+		}, nil
+	case "Settings":
+		s := &settingStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(SettingColumnUserId)
+		ref := string(UserColumnId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &Setting{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*User)
+					p.Settings = model
+				}
+			},
+		}, nil
+	case "Addresses":
+		s := &addressStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(AddressColumnUserId)
+		ref := string(UserColumnId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &Address{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*User)
+					p.Addresses = append(p.Addresses, model)
+				}
+			},
+		}, nil
+	case "Posts":
+		s := &postStorage{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(PostColumnAuthorId)
+		ref := string(UserColumnId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &Post{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*User)
+					p.Posts = append(p.Posts, model)
+				}
+			},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown relation %s of User", join.relation)
+}
+
 // FindOne finds a single User based on the provided options.
 func (t *userStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*User, error) {
 	// Use findMany but limit the results to 1
@@ -1173,7 +2383,7 @@ func (t *userStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*
 // Count counts User based on the provided options.
 func (t *userStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -1206,6 +2416,304 @@ func (t *userStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int
 	return count, nil
 }
 
+// UserAggregateRow is a row of the User Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type UserAggregateRow struct {
+	User
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *UserAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumAge returns the sum of the age values, it is set by Sum(UserColumnAge).
+func (r *UserAggregateRow) SumAge() int64 {
+	if v, ok := r.values[Sum(UserColumnAge)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgAge returns the average of the age values, it is set by Avg(UserColumnAge).
+func (r *UserAggregateRow) AvgAge() *float64 {
+	if v, ok := r.values[Avg(UserColumnAge)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinAge returns the smallest age value, it is set by Min(UserColumnAge).
+func (r *UserAggregateRow) MinAge() *int32 {
+	if v, ok := r.values[Min(UserColumnAge)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxAge returns the largest age value, it is set by Max(UserColumnAge).
+func (r *UserAggregateRow) MaxAge() *int32 {
+	if v, ok := r.values[Max(UserColumnAge)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(UserColumnCreatedAt).
+func (r *UserAggregateRow) MinCreatedAt() *time.Time {
+	if v, ok := r.values[Min(UserColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(UserColumnCreatedAt).
+func (r *UserAggregateRow) MaxCreatedAt() *time.Time {
+	if v, ok := r.values[Max(UserColumnCreatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(UserColumnUpdatedAt).
+func (r *UserAggregateRow) MinUpdatedAt() *time.Time {
+	if v, ok := r.values[Min(UserColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(UserColumnUpdatedAt).
+func (r *UserAggregateRow) MaxUpdatedAt() *time.Time {
+	if v, ok := r.values[Max(UserColumnUpdatedAt)].(**time.Time); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the User rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(UserColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *userStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*UserAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of User")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of User", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*UserAggregateRow
+	for rows.Next() {
+		row := &UserAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.User.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan User aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the User rows found by the builders.
+func (t *userStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*UserAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &UserAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumAge returns the sum of the age values of the User rows found by the builders.
+func (t *userStorage) SumAge(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(UserColumnAge), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumAge(), nil
+}
+
+// AvgAge returns the average of the age values of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) AvgAge(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgAge(), nil
+}
+
+// MinAge returns the smallest age value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinAge(), nil
+}
+
+// MaxAge returns the largest age value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxAge(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnAge), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxAge(), nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Min(UserColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the User rows found by the builders,
+// it is nil if there are no rows.
+func (t *userStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*time.Time, error) {
+	row, err := t.aggregate(ctx, Max(UserColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the User rows.
+func (t *userStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(UserColumnAge):
+		return new(*int64), nil
+	case Avg(UserColumnAge):
+		return new(*float64), nil
+	case Min(UserColumnAge), Max(UserColumnAge):
+		return new(*int32), nil
+	case Min(UserColumnCreatedAt), Max(UserColumnCreatedAt):
+		return new(*time.Time), nil
+	case Min(UserColumnUpdatedAt), Max(UserColumnUpdatedAt):
+		return new(*time.Time), nil
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of User", aggregation)
+}
+
 // FindManyWithPagination finds multiple User with pagination support.
 func (t *userStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*User, *Paginator, error) {
 	// Count the total number of records
@@ -1237,9 +2745,147 @@ func (t *userStorage) FindManyWithPagination(ctx context.Context, limit int, pag
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of User after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *userStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*User, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&User{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find User")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the User row.
+func (t *userStorage) cursor(model *User, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *userStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of User can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *userStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "name":
+		return true
+	case "age":
+		return true
+	case "email":
+		return true
+	case "created_at":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the User for the given ID.
 func (t *userStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*User, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
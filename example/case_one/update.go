@@ -26,7 +26,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	store := db.NewBlogStorages(connection)
+	store, err := db.NewBlogStorages(&db.Config{DB: &db.DB{DBRead: connection, DBWrite: connection}})
+	if err != nil {
+		panic(err)
+	}
 	userStorage := store.GetUserStorage()
 
 	newName := "Ivan"
@@ -34,7 +37,7 @@ func main() {
 	// Create Transaction Manager for store
 	err = store.TxManager().ExecFuncWithTx(ctx, func(ctx context.Context) error {
 		// Update user name
-		err := userStorage.Update(ctx, "d0e628b8-3266-480b-bb65-cfc356121b29", &db.UserUpdate{
+		_, err := userStorage.Update(ctx, "d0e628b8-3266-480b-bb65-cfc356121b29", &db.UserUpdate{
 			Name: &newName,
 		})
 		if err != nil {
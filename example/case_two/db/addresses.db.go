@@ -9,6 +9,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	"math"
+	"net/url"
 )
 
 // addressStorage is a struct for the "addresses" table.
@@ -25,17 +26,28 @@ type AddressTableManager interface {
 	UpgradeTable(ctx context.Context) error
 }
 
+// AddressSchemaVerification is an interface for verifying the addresses table.
+type AddressSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // AddressCRUDOperations is an interface for managing the addresses table.
 type AddressCRUDOperations interface {
 	Create(ctx context.Context, model *Address, opts ...Option) (*string, error)
-	Update(ctx context.Context, id string, updateData *AddressUpdate) error
-	DeleteById(ctx context.Context, id string, opts ...Option) error
+	Update(ctx context.Context, id string, updateData *AddressUpdate) (int64, error)
+	UpdateReturning(ctx context.Context, id string, updateData *AddressUpdate) (*Address, error)
+	UpdateMany(ctx context.Context, updateData *AddressUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id string, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id string, opts ...Option) (*Address, error)
 }
 
 // AddressSearchOperations is an interface for searching the addresses table.
 type AddressSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Address, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Address, error)
@@ -44,6 +56,24 @@ type AddressSearchOperations interface {
 // AddressPaginationOperations is an interface for pagination operations.
 type AddressPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Address, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Address, *CursorPaginator, error)
+}
+
+// AddressAggregateOperations is an interface for the aggregate queries.
+type AddressAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error)
+	SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error)
+	MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error)
+	MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error)
+	MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error)
+	MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error)
 }
 
 // AddressRelationLoading is an interface for loading relations.
@@ -54,7 +84,7 @@ type AddressRelationLoading interface {
 
 // AddressAdvancedDeletion is an interface for advanced deletion operations.
 type AddressAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // AddressRawQueryOperations is an interface for executing raw queries.
@@ -67,9 +97,11 @@ type AddressRawQueryOperations interface {
 // AddressStorage is a struct for the "addresses" table.
 type AddressStorage interface {
 	AddressTableManager
+	AddressSchemaVerification
 	AddressCRUDOperations
 	AddressSearchOperations
 	AddressPaginationOperations
+	AddressAggregateOperations
 	AddressRelationLoading
 	AddressAdvancedDeletion
 	AddressRawQueryOperations
@@ -140,22 +172,89 @@ func (t *addressStorage) DropTable(ctx context.Context) error {
 	return err
 }
 
-// TruncateTable truncates the table.
+// TruncateTable truncates the table, sqlite has no TRUNCATE statement so all the rows are deleted.
 func (t *addressStorage) TruncateTable(ctx context.Context) error {
 	sqlQuery := `
-		TRUNCATE TABLE addresses;
+		DELETE FROM addresses;
 	`
 
 	_, err := t.db.ExecContext(ctx, sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *addressStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return fmt.Errorf("failed to introspect table: %w", err)
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.Name, err)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *addressStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table: %w", err)
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect indexes: %w", err)
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *addressStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "TEXT", NotNull: true, Statement: ""},
+		{Name: "street", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN \"street\" TEXT;"},
+		{Name: "city", Type: "TEXT", NotNull: true, Statement: ""},
+		{Name: "state", Type: "INTEGER", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN \"state\" INTEGER;"},
+		{Name: "zip", Type: "INTEGER", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN \"zip\" INTEGER;"},
+		{Name: "user_id", Type: "TEXT", NotNull: true, Statement: ""},
+		{Name: "created_at", Type: "TEXT", NotNull: true, Statement: "ALTER TABLE \"addresses\" ADD COLUMN \"created_at\" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP;"},
+		{Name: "updated_at", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"addresses\" ADD COLUMN \"updated_at\" TEXT;"},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *addressStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "addresses_city_idx", Columns: []string{"city"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"addresses_city_idx\" ON \"addresses\" (\"city\");"},
+		{Name: "addresses_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"addresses_user_id_idx\" ON \"addresses\" (\"user_id\");"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *addressStorage) LoadUser(ctx context.Context, model *Address, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -164,8 +263,7 @@ func (t *addressStorage) LoadUser(ctx context.Context, model *Address, builders
 
 	// NewUserStorage creates a new UserStorage.
 	s := NewUserStorage(t.db)
-
-	// Add the filter for the relation
+	// Add the filter for the relation without dereferencing
 	builders = append(builders, FilterBuilder(UserIdEq(model.UserId)))
 	relationModel, err := s.FindOne(ctx, builders...)
 	if err != nil {
@@ -178,9 +276,10 @@ func (t *addressStorage) LoadUser(ctx context.Context, model *Address, builders
 
 // LoadBatchUser loads the User relation.
 func (t *addressStorage) LoadBatchUser(ctx context.Context, items []*Address, builders ...*QueryBuilder) error {
-	requestItems := make([]interface{}, len(items))
-	for i, item := range items {
-		requestItems[i] = item.UserId
+	requestItems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		// Append the value directly for non-optional fields
+		requestItems = append(requestItems, item.UserId)
 	}
 
 	// NewUserStorage creates a new UserStorage.
@@ -200,6 +299,7 @@ func (t *addressStorage) LoadBatchUser(ctx context.Context, items []*Address, bu
 
 	// Assign User to items
 	for _, item := range items {
+		// Assign the relation directly for non-optional fields
 		if v, ok := resultMap[item.UserId]; ok {
 			item.User = v
 		}
@@ -208,6 +308,29 @@ func (t *addressStorage) LoadBatchUser(ctx context.Context, items []*Address, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *addressStorage) preload(ctx context.Context, items []*Address, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Address, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return fmt.Errorf("unknown relation %s of Address", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return fmt.Errorf("failed to preload %s: %w", relation, err)
+		}
+	}
+
+	return nil
+}
+
 // Address is a struct for the "addresses" table.
 type Address struct {
 	Id        string `db:"id"`
@@ -245,10 +368,110 @@ func (t *Address) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Address, the other fields are left empty.
+func (t *Address) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Address) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "street":
+			dest = append(dest, &t.Street)
+		case "city":
+			dest = append(dest, &t.City)
+		case "state":
+			dest = append(dest, &t.State)
+		case "zip":
+			dest = append(dest, &t.Zip)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		case "created_at":
+			dest = append(dest, &t.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &t.UpdatedAt)
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Address columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	AddressColumnId        Column = "id"
+	AddressColumnStreet    Column = "street"
+	AddressColumnCity      Column = "city"
+	AddressColumnState     Column = "state"
+	AddressColumnZip       Column = "zip"
+	AddressColumnUserId    Column = "user_id"
+	AddressColumnCreatedAt Column = "created_at"
+	AddressColumnUpdatedAt Column = "updated_at"
+)
+
 // AddressFilters is a struct that holds filters for Address.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type AddressFilters struct {
-	Id     *string
-	UserId *string
+	Id       *string
+	IdIn     []string
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *AddressFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseAddressFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseAddressFilters(values url.Values) (*AddressFilters, error) {
+	f := &AddressFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[string](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[string](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // AddressIdEq returns a condition that checks if the field equals the value.
@@ -371,6 +594,12 @@ func AddressUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// AddressHasUser checks if the Address has the User relation matching the filters.
+func AddressHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(AddressColumnUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Address.
 func (t *addressStorage) Create(ctx context.Context, model *Address, opts ...Option) (*string, error) {
 	if model == nil {
@@ -439,15 +668,117 @@ type AddressUpdate struct {
 	UserId    *string
 	CreatedAt *string
 	UpdatedAt *string
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Address based on non-nil fields.
-func (t *addressStorage) Update(ctx context.Context, id string, updateData *AddressUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
+// Update updates an existing Address based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) Update(ctx context.Context, id string, updateData *AddressUpdate) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
+	query = query.Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update Address: %w", err)
+	}
+
+	return rowsAffected(result)
+}
+
+// UpdateReturning updates an existing Address based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) UpdateReturning(ctx context.Context, id string, updateData *AddressUpdate) (*Address, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Address: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to update Address: %w", err)
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &Address{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, fmt.Errorf("failed to scan Address: %w", err)
+	}
+
+	return model, nil
+}
+
+// UpdateMany updates the addresses rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *addressStorage) UpdateMany(ctx context.Context, updateData *AddressUpdate, builders ...*QueryBuilder) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update addresses: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
+	return affected, nil
+}
 
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *addressStorage) updateQuery(updateData *AddressUpdate) (sq.UpdateBuilder, error) {
 	query := t.queryBuilder.Update("addresses")
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
 	if updateData.Street != nil {
 		query = query.Set("street", updateData.Street)
 	}
@@ -470,23 +801,12 @@ func (t *addressStorage) Update(ctx context.Context, id string, updateData *Addr
 		query = query.Set("updated_at", updateData.UpdatedAt)
 	}
 
-	query = query.Where("id = ?", id)
-
-	sqlQuery, args, err := query.ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
-	}
-
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
-	if err != nil {
-		return fmt.Errorf("failed to update Address: %w", err)
-	}
-
-	return nil
+	return setExpressions(query, updateData.Expressions), nil
 }
 
 // DeleteById - deletes a Address by its id.
-func (t *addressStorage) DeleteById(ctx context.Context, id string, opts ...Option) error {
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *addressStorage) DeleteById(ctx context.Context, id string, opts ...Option) (int64, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
@@ -497,19 +817,20 @@ func (t *addressStorage) DeleteById(ctx context.Context, id string, opts ...Opti
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteMany removes entries from the addresses table using the provided filters
-func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
+// DeleteMany removes entries from the addresses table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
 	query := t.queryBuilder.Delete("addresses")
 
@@ -527,20 +848,24 @@ func (t *addressStorage) DeleteMany(ctx context.Context, builders ...*QueryBuild
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for delete operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
 }
 
 // FindById retrieves a Address by its id.
@@ -561,9 +886,146 @@ func (t *addressStorage) FindById(ctx context.Context, id string, opts ...Option
 }
 
 // FindMany finds multiple Address based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. AddressJoin<Relation>, are filled in the same query.
 func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Address, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Address
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, fmt.Errorf("failed to scan Address: %w", err)
+		}
+		results = append(results, model)
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Address rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it.
+func (t *addressStorage) FindEach(ctx context.Context, fn func(*Address) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		model := &Address{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return fmt.Errorf("failed to scan Address: %w", err)
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *addressStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Address", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *addressStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, fmt.Errorf("unknown column %q of Address", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *addressStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *addressStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Address: %w", err)
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *addressStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -599,8 +1061,46 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// AddressJoinUser joins the User relation filtered by the filters,
+// FindMany fills Address.User with the joined rows in the same query.
+// The Address rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func AddressJoinUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "addresses",
+		relation: "User",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Address rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Address rows are merged.
+func (t *addressStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Address, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
@@ -612,17 +1112,99 @@ func (t *addressStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	defer rows.Close()
 
 	var results []*Address
+	found := make(map[int64]*Address)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Address{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan Address: %w", err)
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Address: %w", err)
+		}
+
+		// the Address row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
 	}
 
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *addressStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Address relation.
+func (t *addressStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, fmt.Errorf("the join of the %s relation of %s can't be used with Address", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, fmt.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "User":
+		s := &userStorage{db: t.db, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(AddressColumnUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.TableName() + "." + column + " = " + t.TableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Address)
+					p.User = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown relation %s of Address", join.relation)
+}
+
 // FindOne finds a single Address based on the provided options.
 func (t *addressStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
 	// Use findMany but limit the results to 1
@@ -671,6 +1253,373 @@ func (t *addressStorage) Count(ctx context.Context, builders ...*QueryBuilder) (
 	return count, nil
 }
 
+// AddressAggregateRow is a row of the Address Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type AddressAggregateRow struct {
+	Address
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *AddressAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumState returns the sum of the state values, it is set by Sum(AddressColumnState).
+func (r *AddressAggregateRow) SumState() int64 {
+	if v, ok := r.values[Sum(AddressColumnState)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgState returns the average of the state values, it is set by Avg(AddressColumnState).
+func (r *AddressAggregateRow) AvgState() *float64 {
+	if v, ok := r.values[Avg(AddressColumnState)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinState returns the smallest state value, it is set by Min(AddressColumnState).
+func (r *AddressAggregateRow) MinState() *int32 {
+	if v, ok := r.values[Min(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxState returns the largest state value, it is set by Max(AddressColumnState).
+func (r *AddressAggregateRow) MaxState() *int32 {
+	if v, ok := r.values[Max(AddressColumnState)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// SumZip returns the sum of the zip values, it is set by Sum(AddressColumnZip).
+func (r *AddressAggregateRow) SumZip() int64 {
+	if v, ok := r.values[Sum(AddressColumnZip)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgZip returns the average of the zip values, it is set by Avg(AddressColumnZip).
+func (r *AddressAggregateRow) AvgZip() *float64 {
+	if v, ok := r.values[Avg(AddressColumnZip)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinZip returns the smallest zip value, it is set by Min(AddressColumnZip).
+func (r *AddressAggregateRow) MinZip() *int64 {
+	if v, ok := r.values[Min(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxZip returns the largest zip value, it is set by Max(AddressColumnZip).
+func (r *AddressAggregateRow) MaxZip() *int64 {
+	if v, ok := r.values[Max(AddressColumnZip)].(**int64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinCreatedAt returns the smallest created_at value, it is set by Min(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MinCreatedAt() *string {
+	if v, ok := r.values[Min(AddressColumnCreatedAt)].(**string); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxCreatedAt returns the largest created_at value, it is set by Max(AddressColumnCreatedAt).
+func (r *AddressAggregateRow) MaxCreatedAt() *string {
+	if v, ok := r.values[Max(AddressColumnCreatedAt)].(**string); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value, it is set by Min(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MinUpdatedAt() *string {
+	if v, ok := r.values[Min(AddressColumnUpdatedAt)].(**string); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value, it is set by Max(AddressColumnUpdatedAt).
+func (r *AddressAggregateRow) MaxUpdatedAt() *string {
+	if v, ok := r.values[Max(AddressColumnUpdatedAt)].(**string); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Address rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(AddressColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *addressStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*AddressAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, fmt.Errorf("no aggregations of Address")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Address", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate Address: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*AddressAggregateRow
+	for rows.Next() {
+		row := &AddressAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Address.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Address aggregate: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Address rows found by the builders.
+func (t *addressStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*AddressAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &AddressAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumState returns the sum of the state values of the Address rows found by the builders.
+func (t *addressStorage) SumState(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnState), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumState(), nil
+}
+
+// AvgState returns the average of the state values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgState(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgState(), nil
+}
+
+// MinState returns the smallest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinState(), nil
+}
+
+// MaxState returns the largest state value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxState(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnState), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxState(), nil
+}
+
+// SumZip returns the sum of the zip values of the Address rows found by the builders.
+func (t *addressStorage) SumZip(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(AddressColumnZip), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumZip(), nil
+}
+
+// AvgZip returns the average of the zip values of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) AvgZip(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgZip(), nil
+}
+
+// MinZip returns the smallest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinZip(), nil
+}
+
+// MaxZip returns the largest zip value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxZip(ctx context.Context, builders ...*QueryBuilder) (*int64, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnZip), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxZip(), nil
+}
+
+// MinCreatedAt returns the smallest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinCreatedAt(), nil
+}
+
+// MaxCreatedAt returns the largest created_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxCreatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnCreatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxCreatedAt(), nil
+}
+
+// MinUpdatedAt returns the smallest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MinUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error) {
+	row, err := t.aggregate(ctx, Min(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinUpdatedAt(), nil
+}
+
+// MaxUpdatedAt returns the largest updated_at value of the Address rows found by the builders,
+// it is nil if there are no rows.
+func (t *addressStorage) MaxUpdatedAt(ctx context.Context, builders ...*QueryBuilder) (*string, error) {
+	row, err := t.aggregate(ctx, Max(AddressColumnUpdatedAt), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxUpdatedAt(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Address rows.
+func (t *addressStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(AddressColumnState):
+		return new(*int64), nil
+	case Avg(AddressColumnState):
+		return new(*float64), nil
+	case Min(AddressColumnState), Max(AddressColumnState):
+		return new(*int32), nil
+	case Sum(AddressColumnZip):
+		return new(*int64), nil
+	case Avg(AddressColumnZip):
+		return new(*float64), nil
+	case Min(AddressColumnZip), Max(AddressColumnZip):
+		return new(*int64), nil
+	case Min(AddressColumnCreatedAt), Max(AddressColumnCreatedAt):
+		return new(*string), nil
+	case Min(AddressColumnUpdatedAt), Max(AddressColumnUpdatedAt):
+		return new(*string), nil
+	}
+	return nil, fmt.Errorf("unsupported aggregation %s of Address", aggregation)
+}
+
 // FindManyWithPagination finds multiple Address with pagination support.
 func (t *addressStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Address, *Paginator, error) {
 	// Count the total number of records
@@ -702,6 +1651,148 @@ func (t *addressStorage) FindManyWithPagination(ctx context.Context, limit int,
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Address after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *addressStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Address, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Address{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find Address: %w", err)
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Address row.
+func (t *addressStorage) cursor(model *Address, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *addressStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, fmt.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, fmt.Errorf("the column %q of Address can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *addressStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "street":
+		return true
+	case "city":
+		return true
+	case "state":
+		return true
+	case "zip":
+		return true
+	case "user_id":
+		return true
+	case "created_at":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Address for the given ID.
 func (t *addressStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Address, error) {
 	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
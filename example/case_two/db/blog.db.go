@@ -1,19 +1,27 @@
 // Code generated by protoc-gen-structify. DO NOT EDIT.
 // source: example/case_two/db/blog.proto
 // provider: sqlite
-// protoc-gen-structify: (version=v1.1.0, branch=main, revision=0176e1872838a373cdc0d8a630d16ecb748ed32d), build: (go=go1.22.6, date=2024-08-14T15:04:24+0300)
-// protoc: 3.15.8
 package db
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -157,6 +165,8 @@ type BlogStorages interface {
 	GetAddressStorage() AddressStorage
 	// TxManager returns the transaction manager.
 	TxManager() *TxManager
+	// VerifySchema compares the live tables with the models.
+	VerifySchema(ctx context.Context) (*SchemaReport, error)
 	// CreateTables creates the tables for all the stores.
 	CreateTables(ctx context.Context) error
 	// DropTables drops the tables for all the stores.
@@ -165,6 +175,10 @@ type BlogStorages interface {
 	TruncateTables(ctx context.Context) error
 	// UpgradeTables upgrades the tables for all the stores.
 	UpgradeTables(ctx context.Context) error
+	// LoadFixtures inserts the rows of the fixture files into the tables.
+	LoadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
+	// ReloadFixtures truncates the tables and loads the fixture files.
+	ReloadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
 }
 
 // NewBlogStorages returns a new BlogStorages.
@@ -211,25 +225,72 @@ func (c *blogStorages) GetAddressStorage() AddressStorage {
 	return c.addressStorage
 }
 
+// VerifySchema compares the live tables of all the stores with the models.
+// The differences are listed in the report, the error is returned only if the schema can not be read.
+// Use report.Err() to fail fast at startup when the tables are behind the models.
+func (c *blogStorages) VerifySchema(ctx context.Context) (*SchemaReport, error) {
+	var err error
+	var drifts []SchemaDrift
+	report := &SchemaReport{}
+
+	// verify the DeviceStorage table.
+	drifts, err = c.deviceStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the PostStorage table.
+	drifts, err = c.postStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the UserStorage table.
+	drifts, err = c.userStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the SettingStorage table.
+	drifts, err = c.settingStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	// verify the AddressStorage table.
+	drifts, err = c.addressStorage.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+
+	return report, nil
+}
+
 // CreateTables creates the tables for all the stores.
+// The referenced tables are created first.
 // This is idempotent and safe to run multiple times.
 func (c *blogStorages) CreateTables(ctx context.Context) error {
 	var err error
 
-	// create the DeviceStorage table.
-	err = c.deviceStorage.CreateTable(ctx)
+	// create the UserStorage table.
+	err = c.userStorage.CreateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
-	// create the PostStorage table.
-	err = c.postStorage.CreateTable(ctx)
+	// create the DeviceStorage table.
+	err = c.deviceStorage.CreateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
-	// create the UserStorage table.
-	err = c.userStorage.CreateTable(ctx)
+	// create the PostStorage table.
+	err = c.postStorage.CreateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
@@ -250,36 +311,37 @@ func (c *blogStorages) CreateTables(ctx context.Context) error {
 }
 
 // DropTables drops the tables for all the stores.
+// The referencing tables are dropped first.
 // This is idempotent and safe to run multiple times.
 func (c *blogStorages) DropTables(ctx context.Context) error {
 	var err error
 
-	// drop the DeviceStorage table.
-	err = c.deviceStorage.DropTable(ctx)
+	// drop the AddressStorage table.
+	err = c.addressStorage.DropTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
 	}
 
-	// drop the PostStorage table.
-	err = c.postStorage.DropTable(ctx)
+	// drop the SettingStorage table.
+	err = c.settingStorage.DropTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
 	}
 
-	// drop the UserStorage table.
-	err = c.userStorage.DropTable(ctx)
+	// drop the PostStorage table.
+	err = c.postStorage.DropTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
 	}
 
-	// drop the SettingStorage table.
-	err = c.settingStorage.DropTable(ctx)
+	// drop the DeviceStorage table.
+	err = c.deviceStorage.DropTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
 	}
 
-	// drop the AddressStorage table.
-	err = c.addressStorage.DropTable(ctx)
+	// drop the UserStorage table.
+	err = c.userStorage.DropTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
 	}
@@ -288,36 +350,37 @@ func (c *blogStorages) DropTables(ctx context.Context) error {
 }
 
 // TruncateTables truncates the tables for all the stores.
+// The referencing tables are truncated first.
 // This is idempotent and safe to run multiple times.
 func (c *blogStorages) TruncateTables(ctx context.Context) error {
 	var err error
 
-	// truncate the DeviceStorage table.
-	err = c.deviceStorage.TruncateTable(ctx)
+	// truncate the AddressStorage table.
+	err = c.addressStorage.TruncateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to truncate table: %w", err)
 	}
 
-	// truncate the PostStorage table.
-	err = c.postStorage.TruncateTable(ctx)
+	// truncate the SettingStorage table.
+	err = c.settingStorage.TruncateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to truncate table: %w", err)
 	}
 
-	// truncate the UserStorage table.
-	err = c.userStorage.TruncateTable(ctx)
+	// truncate the PostStorage table.
+	err = c.postStorage.TruncateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to truncate table: %w", err)
 	}
 
-	// truncate the SettingStorage table.
-	err = c.settingStorage.TruncateTable(ctx)
+	// truncate the DeviceStorage table.
+	err = c.deviceStorage.TruncateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to truncate table: %w", err)
 	}
 
-	// truncate the AddressStorage table.
-	err = c.addressStorage.TruncateTable(ctx)
+	// truncate the UserStorage table.
+	err = c.userStorage.TruncateTable(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to truncate table: %w", err)
 	}
@@ -326,40 +389,66 @@ func (c *blogStorages) TruncateTables(ctx context.Context) error {
 }
 
 // UpgradeTables runs the database upgrades for all the stores.
+// The referenced tables are upgraded first like in CreateTables, so the missing tables are created in the dependency order.
+// The drifts of all the tables are collected into one *SchemaDriftError.
 // This is idempotent and safe to run multiple times.
 func (c *blogStorages) UpgradeTables(ctx context.Context) error {
 	var err error
+	var drifts []SchemaDrift
+
+	// run the UserStorage upgrade.
+	err = c.userStorage.UpgradeTable(ctx)
+	if err != nil {
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
+	}
 
 	// run the DeviceStorage upgrade.
 	err = c.deviceStorage.UpgradeTable(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 
 	// run the PostStorage upgrade.
 	err = c.postStorage.UpgradeTable(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
-	}
-
-	// run the UserStorage upgrade.
-	err = c.userStorage.UpgradeTable(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 
 	// run the SettingStorage upgrade.
 	err = c.settingStorage.UpgradeTable(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 
 	// run the AddressStorage upgrade.
 	err = c.addressStorage.UpgradeTable(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 
@@ -611,8 +700,23 @@ var (
 	ErrRowAlreadyExist = errors.New("row already exist")
 	// ErrModelIsNil is returned when a relation model is nil.
 	ErrModelIsNil = errors.New("model is nil")
+	// ErrInvalidCursor is returned when a cursor can't be decoded or doesn't match the sorting of the query.
+	ErrInvalidCursor = errors.New("invalid cursor")
 )
 
+// rowsAffected returns the number of the rows affected by the statement of the single row,
+// it returns ErrRowNotFound if no row is affected.
+func rowsAffected(result sql.Result) (int64, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return 0, ErrRowNotFound
+	}
+	return affected, nil
+}
+
 //
 // Transaction manager.
 //
@@ -729,6 +833,10 @@ type Option func(*Options)
 type Options struct {
 	// if true, then method was create/update relations
 	relations bool
+	// preloads are the relations loaded by the Preload option.
+	preloads []preload
+	// searchHighlight are the marks of the matches in the snippets of Search.
+	searchHighlight *searchHighlight
 }
 
 // WithRelations sets the relations flag.
@@ -740,8 +848,11 @@ func WithRelations() Option {
 }
 
 // FilterApplier is a condition filters.
+// The condition is the SQL expression, so the conditions nest with And, Or and Not.
 type FilterApplier interface {
+	sq.Sqlizer
 	Apply(query sq.SelectBuilder) sq.SelectBuilder
+	ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder
 	ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder
 }
 
@@ -755,8 +866,18 @@ type QueryBuilder struct {
 	sortOptions []FilterApplier
 	// pagination is the pagination.
 	pagination *Pagination
+	// columns are the selected columns, all the columns are selected if it is empty.
+	columns []Column
+	// joins are the typed joins of the relations.
+	joins []RelationJoin
+	// preloads are the relations loaded with the found models.
+	preloads []preload
 }
 
+// Column is the column name of a table.
+// The columns of every model are generated as constants, e.g. UserColumnName.
+type Column string
+
 // NewQueryBuilder returns a new query builder.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{}
@@ -786,6 +907,12 @@ func (b *QueryBuilder) WithPagination(pagination *Pagination) *QueryBuilder {
 	return b
 }
 
+// WithColumns selects only the given columns, the other fields of the found models are left empty.
+func (b *QueryBuilder) WithColumns(columns ...Column) *QueryBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
 // Filter is a helper function to create a new query builder with filter options.
 func FilterBuilder(filterOptions ...FilterApplier) *QueryBuilder {
 	return NewQueryBuilder().WithFilter(filterOptions...)
@@ -815,6 +942,102 @@ func PaginateBuilder(limit, offset uint64) *QueryBuilder {
 	return NewQueryBuilder().WithPagination(NewPagination(limit, offset))
 }
 
+// ColumnsBuilder is a helper function to create a new query builder which selects the given columns.
+func ColumnsBuilder(columns ...Column) *QueryBuilder {
+	return NewQueryBuilder().WithColumns(columns...)
+}
+
+// ColumnQuerier queries the rows of the given columns, it is implemented by every storage.
+type ColumnQuerier interface {
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+}
+
+// FindManyInto selects the columns of the db tags of T and scans the found rows into T.
+// It lets the list queries skip the heavy columns, e.g.
+//
+//	type userItem struct {
+//		Id   string `db:"id"`
+//		Name string `db:"name"`
+//	}
+//
+//	items, err := FindManyInto[userItem](ctx, storages.GetUserStorage(), builder)
+func FindManyInto[T any](ctx context.Context, querier ColumnQuerier, builders ...*QueryBuilder) ([]*T, error) {
+	fields, err := dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+
+	rows, err := querier.QueryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		value := reflect.ValueOf(result).Elem()
+		dest := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			dest = append(dest, value.FieldByIndex(field.index).Addr().Interface())
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// dbField is the struct field which is scanned from the column of its db tag.
+type dbField struct {
+	column Column
+	index  []int
+}
+
+// dbFields returns the fields of the struct with the db tags.
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", typ)
+	}
+
+	fields := collectDBFields(typ)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no fields with the db tag", typ)
+	}
+	return fields, nil
+}
+
+// collectDBFields collects the fields with the db tags, the embedded structs without the tag are included.
+func collectDBFields(typ reflect.Type) []dbField {
+	var fields []dbField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, f := range collectDBFields(field.Type) {
+				fields = append(fields, dbField{column: f.column, index: append([]int{i}, f.index...)})
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, dbField{column: Column(tag), index: field.Index})
+	}
+	return fields
+}
+
 // Pagination is the pagination.
 type Pagination struct {
 	// limit is the limit.
@@ -846,6 +1069,170 @@ func Offset(offset uint64) *Pagination {
 	}
 }
 
+//
+// Upsert.
+//
+
+// Conflict is the conflict handling of the upsert, e.g. OnConflict(UserColumnEmail).DoUpdate(UserColumnName, UserColumnAge).
+type Conflict struct {
+	// target are the columns of the unique constraint.
+	target []Column
+	// update are the updated columns, all the inserted columns are updated if it is empty.
+	update []Column
+	// nothing keeps the existing row.
+	nothing bool
+}
+
+// OnConflict returns the conflict on the unique constraint of the columns: the unique field or the unique_index
+// group of the table. The existing row is updated with all the inserted columns but the primary key by default.
+func OnConflict(columns ...Column) *Conflict {
+	return &Conflict{target: columns}
+}
+
+// DoUpdate updates the columns of the existing row with the inserted values.
+func (c *Conflict) DoUpdate(columns ...Column) *Conflict {
+	c.update = columns
+	c.nothing = false
+	return c
+}
+
+// DoNothing keeps the existing row, its primary key is still returned by the upsert.
+func (c *Conflict) DoNothing() *Conflict {
+	c.update = nil
+	c.nothing = true
+	return c
+}
+
+// upsertColumn is the column inserted by the upsert.
+type upsertColumn struct {
+	column Column
+	// primaryKey is true for the primary key, it is not updated unless it is one of the updated columns.
+	primaryKey bool
+}
+
+// upsertColumnNames returns the names of the inserted columns.
+func upsertColumnNames(columns []upsertColumn) []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, string(column.column))
+	}
+	return names
+}
+
+// isConflictTarget returns true if the columns are the columns of one of the unique constraints in any order.
+func isConflictTarget(targets [][]Column, columns []Column) bool {
+	for _, target := range targets {
+		if len(target) != len(columns) {
+			continue
+		}
+		matched := true
+		for i, column := range columns {
+			if !containsColumn(target, column) || containsColumn(columns[:i], column) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsColumn returns true if the column is one of the columns.
+func containsColumn(columns []Column, column Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// suffix returns the ON CONFLICT clause of the upsert into the table with the unique constraints targets
+// and the inserted columns.
+func (c *Conflict) suffix(table string, targets [][]Column, columns []upsertColumn) (string, error) {
+	if c == nil {
+		return "", errors.New("conflict is nil")
+	}
+	if !isConflictTarget(targets, c.target) {
+		return "", fmt.Errorf("the columns %v are not a unique constraint of the %s table", c.target, table)
+	}
+
+	inserted := make([]Column, 0, len(columns))
+	for _, column := range columns {
+		inserted = append(inserted, column.column)
+	}
+
+	var set []Column
+	switch {
+	case c.nothing:
+	case len(c.update) == 0:
+		for _, column := range columns {
+			if !column.primaryKey && !containsColumn(c.target, column.column) {
+				set = append(set, column.column)
+			}
+		}
+	default:
+		for _, column := range c.update {
+			if !containsColumn(inserted, column) {
+				return "", fmt.Errorf("the column %q of the %s table is not inserted by the upsert", column, table)
+			}
+			set = append(set, column)
+		}
+	}
+	if len(set) == 0 {
+		// the existing row is not changed, the update of the target returns its primary key.
+		set = c.target[:1]
+	}
+
+	assignments := make([]string, 0, len(set))
+	for _, column := range set {
+		assignments = append(assignments, string(column)+" = excluded."+string(column))
+	}
+	target := make([]string, 0, len(c.target))
+	for _, column := range c.target {
+		target = append(target, string(column))
+	}
+	return "ON CONFLICT (" + strings.Join(target, ", ") + ") DO UPDATE SET " + strings.Join(assignments, ", "), nil
+}
+
+//
+// Update expressions.
+//
+
+// UpdateExpression sets the column to the SQL expression on update, e.g. Increment(UserColumnAge, 1).
+// The expression is evaluated by the database, so the counters are updated atomically.
+type UpdateExpression struct {
+	column Column
+	expr   sq.Sqlizer
+}
+
+// Increment adds the value to the column, the negative value decrements it.
+func Increment(column Column, value interface{}) UpdateExpression {
+	return UpdateExpression{column: column, expr: sq.Expr(string(column)+" + ?", value)}
+}
+
+// SetExpr sets the column to the SQL expression, e.g. SetExpr(UserColumnUpdatedAt, "now()").
+// The expression is not escaped, pass the values as the args of its placeholders.
+func SetExpr(column Column, expr string, args ...interface{}) UpdateExpression {
+	return UpdateExpression{column: column, expr: sq.Expr(expr, args...)}
+}
+
+// setExpressions sets the columns of the update query to the expressions.
+func setExpressions(query sq.UpdateBuilder, expressions []UpdateExpression) sq.UpdateBuilder {
+	for _, expression := range expressions {
+		query = query.Set(string(expression.column), expression.expr)
+	}
+	return query
+}
+
+// returning returns the RETURNING clause which reads the columns of the updated row.
+// The columns are listed instead of *, the columns of the table upgrades are added at the end of the table.
+func returning(columns []string) string {
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+
 //
 // Conditions for query builder.
 //
@@ -880,18 +1267,55 @@ func toInterface[T any](s []T) []interface{} {
 	return result
 }
 
+// ToSql returns no WHERE expression, the condition adds the JOIN clause.
+func (c JoinCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
+// Apply applies the condition to the query, the ON expression is rendered by the condition tree.
 func (c JoinCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	onQuery := c.On.Apply(sq.Select("*"))
-	onClause, args, _ := onQuery.ToSql()
-	onClause = strings.TrimPrefix(onClause, "SELECT * WHERE ")
-	joinExpr := fmt.Sprintf("%s JOIN %s ON %s", c.Type, c.Table.TableName(), onClause)
-	return query.JoinClause(sq.Expr(joinExpr, args...))
+	joinExpr := fmt.Sprintf("%s JOIN %s ON ?", c.Type, c.Table.TableName())
+	return query.JoinClause(sq.Expr(joinExpr, c.On))
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JoinCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
 }
 
 func (c JoinCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
 	return query
 }
 
+// applyClauses adds the JOIN clause of the condition nested in And, Or or Not.
+func (c JoinCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clauseApplier is implemented by the conditions which change the clauses of the query other than WHERE,
+// e.g. ORDER BY, and by the combinators which may nest them.
+type clauseApplier interface {
+	applyClauses(query sq.SelectBuilder) sq.SelectBuilder
+}
+
+// applyClauses applies the clauses other than WHERE of the conditions to the query.
+func applyClauses(query sq.SelectBuilder, conditions ...FilterApplier) sq.SelectBuilder {
+	for _, condition := range conditions {
+		if c, ok := condition.(clauseApplier); ok {
+			query = c.applyClauses(query)
+		}
+	}
+	return query
+}
+
+// hasExpr returns true if the condition has the WHERE expression, the combinators of the ORDER BY and JOIN
+// conditions have none and must not be added to the WHERE clause. The conditions failing to render have it,
+// so the error is returned by the query.
+func hasExpr(condition sq.Sqlizer) bool {
+	sql, _, err := condition.ToSql()
+	return err != nil || sql != ""
+}
+
 // And returns a condition that combines the given conditions with AND.
 type AndCondition struct {
 	Where []FilterApplier
@@ -902,20 +1326,43 @@ func And(conditions ...FilterApplier) FilterApplier {
 	return AndCondition{Where: conditions}
 }
 
-// And returns a condition that combines the given conditions with AND.
-func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+// ToSql returns the WHERE expressions of the conditions combined with AND.
+func (c AndCondition) ToSql() (string, []interface{}, error) {
+	and := make(sq.And, 0, len(c.Where))
 	for _, condition := range c.Where {
-		query = condition.Apply(query)
+		and = append(and, condition)
 	}
-	return query
+	return and.ToSql()
 }
 
-// And returns a condition that combines the given conditions with AND.
+// Apply applies the condition to the query.
+func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c AndCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c AndCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	for _, condition := range c.Where {
-		query = condition.ApplyDelete(query)
+	if !hasExpr(c) {
+		return query
 	}
-	return query
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c AndCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Where...)
 }
 
 //
@@ -932,74 +1379,155 @@ func Or(conditions ...FilterApplier) FilterApplier {
 	return OrCondition{Conditions: conditions}
 }
 
-// Apply applies the condition to the query.
-func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	or := sq.Or{}
+// ToSql returns the WHERE expressions of the conditions combined with OR.
+func (c OrCondition) ToSql() (string, []interface{}, error) {
+	or := make(sq.Or, 0, len(c.Conditions))
 	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+		or = append(or, condition)
 	}
-	return query.Where(or)
+	return or.ToSql()
 }
 
 // Apply applies the condition to the query.
-func (c OrCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	or := sq.Or{}
-	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
 	}
-	return query.Where(or)
+	return query.Where(c)
 }
 
-// EqualsCondition equals condition.
-type EqualsCondition struct {
-	Field string
-	Value interface{}
+// ApplyUpdate applies the condition to the query.
+func (c OrCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
 }
 
-// Apply applies the condition to the query.
-func (c EqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+// ApplyDelete applies the condition to the query.
+func (c OrCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
 }
 
-func (c EqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c OrCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Conditions...)
 }
 
-// Eq returns a condition that checks if the field equals the value.
-func Eq(field string, value interface{}) FilterApplier {
-	return EqualsCondition{Field: field, Value: value}
+// NotCondition negates the condition.
+type NotCondition struct {
+	Condition FilterApplier
 }
 
-// BetweenCondition
-type BetweenCondition struct {
-	Field string
-	Min   interface{}
-	Max   interface{}
+// Not returns a condition that checks if the condition is false, e.g. Not(Or(Eq("a", 1), Eq("b", 2))).
+func Not(condition FilterApplier) FilterApplier {
+	return NotCondition{Condition: condition}
 }
 
-// Apply applies the condition to the query.
-func (c BetweenCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+// ToSql returns the negated WHERE expression of the condition.
+func (c NotCondition) ToSql() (string, []interface{}, error) {
+	sql, args, err := c.Condition.ToSql()
+	if err != nil || sql == "" {
+		return sql, args, err
+	}
+	return "NOT (" + sql + ")", args, nil
 }
 
-// ApplyDelete applies the condition to the query.
-func (c BetweenCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+// Apply applies the condition to the query.
+func (c NotCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
 }
 
-// Between returns a condition that checks if the field is between the min and max values.
-func Between(field string, min, max interface{}) FilterApplier {
-	return BetweenCondition{Field: field, Min: min, Max: max}
+// ApplyUpdate applies the condition to the query.
+func (c NotCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c NotCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested condition.
+func (c NotCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Condition)
+}
+
+// EqualsCondition equals condition.
+type EqualsCondition struct {
+	Field string
+	Value interface{}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c EqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Value}.ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c EqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c EqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c EqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// Eq returns a condition that checks if the field equals the value.
+func Eq(field string, value interface{}) FilterApplier {
+	return EqualsCondition{Field: field, Value: value}
+}
+
+// BetweenCondition
+type BetweenCondition struct {
+	Field string
+	Min   interface{}
+	Max   interface{}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c BetweenCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max).ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c BetweenCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c BetweenCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c BetweenCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// Between returns a condition that checks if the field is between the min and max values.
+func Between(field string, min, max interface{}) FilterApplier {
+	return BetweenCondition{Field: field, Min: min, Max: max}
 }
 
 // NotEqualsCondition not equals condition.
@@ -1008,14 +1536,24 @@ type NotEqualsCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotEqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotEq returns a condition that checks if the field equals the value.
@@ -1029,14 +1567,24 @@ type GreaterThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Gt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThan returns a condition that checks if the field equals the value.
@@ -1050,14 +1598,24 @@ type LessThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Lt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // LessThan returns a condition that checks if the field equals the value.
@@ -1071,14 +1629,24 @@ type GreaterThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.GtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThanOrEqual returns a condition that checks if the field equals the value.
@@ -1092,14 +1660,24 @@ type LessThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.LtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 func LessThanOrEq(field string, value interface{}) FilterApplier {
@@ -1112,14 +1690,24 @@ type LikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.Like{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // Like returns a condition that checks if the field equals the value.
@@ -1133,14 +1721,24 @@ type NotLikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotLikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotLike{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotLikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotLikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotLikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotLike returns a condition that checks if the field equals the value.
@@ -1153,14 +1751,24 @@ type IsNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
 }
 
 // IsNull returns a condition that checks if the field is null.
@@ -1173,14 +1781,24 @@ type IsNotNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNotNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NOT NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNotNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNotNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNotNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
 }
 
 // IsNotNull returns a condition that checks if the field is not null.
@@ -1194,14 +1812,24 @@ type InCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c InCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c InCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c InCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // In returns a condition that checks if the field is in the given values.
@@ -1215,14 +1843,24 @@ type NotInCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotInCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotInCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotInCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotInCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // NotIn returns a condition that checks if the field is not in the given values.
@@ -1241,17 +1879,1733 @@ func OrderBy(column string, asc bool) FilterApplier {
 	return OrderCondition{Column: column, Asc: asc}
 }
 
+// ToSql returns no WHERE expression, the condition adds the ORDER BY clause.
+func (c OrderCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
 // Apply applies the condition to the query.
 func (c OrderCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.OrderBy(c.clause())
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c OrderCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
+}
+
+// ApplyDelete applies the condition to the query.
+func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query
+}
+
+// applyClauses adds the ORDER BY clause of the condition nested in And, Or or Not.
+func (c OrderCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clause returns the ORDER BY clause of the column.
+func (c OrderCondition) clause() string {
 	if c.Asc {
-		return query.OrderBy(c.Column + " ASC")
+		return c.Column + " ASC"
 	}
 
 	// default to descending.
-	return query.OrderBy(c.Column + " DESC")
+	return c.Column + " DESC"
+}
+
+// ExistsCondition checks if the table has rows matching the conditions.
+type ExistsCondition struct {
+	Table      Table
+	Conditions []FilterApplier
+	Not        bool
+}
+
+// Exists returns a condition that checks if the table has rows matching the conditions,
+// e.g. Exists(&Bot{}, EqColumn("bots.user_id", "users.id"), Eq("published", true)).
+func Exists(table Table, conditions ...FilterApplier) FilterApplier {
+	return ExistsCondition{Table: table, Conditions: conditions}
+}
+
+// NotExists returns a condition that checks if the table has no rows matching the conditions.
+func NotExists(table Table, conditions ...FilterApplier) FilterApplier {
+	return ExistsCondition{Table: table, Conditions: conditions, Not: true}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c ExistsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c ExistsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c ExistsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
-func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query
+func (c ExistsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the EXISTS expression of the subquery.
+func (c ExistsCondition) expr() sq.Sqlizer {
+	subQuery := sq.Select("1").From(c.Table.TableName())
+	for _, condition := range c.Conditions {
+		subQuery = condition.Apply(subQuery)
+	}
+	if c.Not {
+		return sq.Expr("NOT EXISTS (?)", subQuery)
+	}
+	return sq.Expr("EXISTS (?)", subQuery)
+}
+
+// ColumnEqualsCondition checks if the field equals the column, e.g. the column of the outer query of Exists.
+type ColumnEqualsCondition struct {
+	Field  string
+	Column string
+}
+
+// EqColumn returns a condition that checks if the field equals the column.
+func EqColumn(field, column string) FilterApplier {
+	return ColumnEqualsCondition{Field: field, Column: column}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c ColumnEqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " = " + c.Column).ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c ColumnEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c ColumnEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c ColumnEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// InSubqueryCondition checks if the field is in the values selected by the subquery.
+type InSubqueryCondition struct {
+	Field string
+	Query sq.SelectBuilder
+	// Conditions are applied to the subquery when the condition is built.
+	Conditions []FilterApplier
+}
+
+// InSubquery returns a condition that checks if the field is in the values selected by the query,
+// e.g. InSubquery("id", sq.Select("user_id").From("bots").Where(sq.Eq{"published": true})).
+func InSubquery(field string, query sq.SelectBuilder) FilterApplier {
+	return InSubqueryCondition{Field: field, Query: query}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c InSubqueryCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c InSubqueryCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InSubqueryCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c InSubqueryCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the IN expression of the subquery, the placeholders of the subquery are numbered by the outer query.
+func (c InSubqueryCondition) expr() sq.Sqlizer {
+	query := c.Query
+	for _, condition := range c.Conditions {
+		query = condition.Apply(query)
+	}
+	sql, args, err := query.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		return errorExpr{err: fmt.Errorf("failed to build subquery: %w", err)}
+	}
+	return sq.Expr(c.Field+" IN ("+sql+")", args...)
+}
+
+// errorExpr is the expression which failed to build, ToSql returns the build error.
+type errorExpr struct {
+	err error
+}
+
+// ToSql returns the build error of the expression.
+func (e errorExpr) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
+
+// JSONContainsCondition checks if the JSON field contains the value: the keys and the values of an object
+// or the elements of an array.
+type JSONContainsCondition struct {
+	Field string
+	Value interface{}
+}
+
+// JSONContains returns a condition that checks if the JSON field contains the value,
+// e.g. JSONContains("settings", map[string]interface{}{"theme": "dark"}) or JSONContains("phones", []string{"+1"}).
+func JSONContains(field string, value interface{}) FilterApplier {
+	return JSONContainsCondition{Field: field, Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONContainsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONContainsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONContainsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONContainsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONHasKeyCondition checks if the JSON object has the top-level key.
+type JSONHasKeyCondition struct {
+	Field string
+	Key   string
+}
+
+// JSONHasKey returns a condition that checks if the JSON object has the top-level key.
+func JSONHasKey(field, key string) FilterApplier {
+	return JSONHasKeyCondition{Field: field, Key: key}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONHasKeyCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONHasKeyCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONPathEqualsCondition checks if the value at the path of the JSON object equals the value.
+type JSONPathEqualsCondition struct {
+	Field string
+	Path  []string
+	Value interface{}
+}
+
+// JSONPathEq returns a condition that checks if the value at the dot-separated path of the JSON object
+// equals the value, e.g. JSONPathEq("meta", "device.os", "ios"). The numeric keys index the arrays from 0.
+func JSONPathEq(field, path string, value interface{}) FilterApplier {
+	return JSONPathEqualsCondition{Field: field, Path: strings.Split(path, "."), Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONPathEqualsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONPathEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the json_extract and json_each expressions of the keys and the elements of the value.
+func (c JSONContainsCondition) expr() sq.Sqlizer {
+	raw, _ := json.Marshal(c.Value)
+	var value interface{}
+	_ = json.Unmarshal(raw, &value)
+	sql, args := jsonContains(jsonColumn(c.Field), value, 0)
+	return sq.Expr(sql, args...)
+}
+
+// expr returns the json_type expression of the key.
+func (c JSONHasKeyCondition) expr() sq.Sqlizer {
+	return sq.Expr("json_type("+jsonColumn(c.Field)+", ?) IS NOT NULL", jsonPath(c.Key))
+}
+
+// expr returns the json_extract expression of the path.
+func (c JSONPathEqualsCondition) expr() sq.Sqlizer {
+	return sq.Expr("json_extract("+jsonColumn(c.Field)+", ?) = ?", jsonPath(c.Path...), c.Value)
+}
+
+// jsonColumn returns the JSON column as text, the JSON values are stored as blobs.
+func jsonColumn(field string) string {
+	return "CAST(" + field + " AS TEXT)"
+}
+
+// jsonPath returns the JSON path of the keys, e.g. $."device"."os", the numeric keys index the arrays.
+func jsonPath(keys ...string) string {
+	path := "$"
+	for _, key := range keys {
+		if _, err := strconv.Atoi(key); err == nil {
+			path += "[" + key + "]"
+			continue
+		}
+		path += ".\"" + strings.ReplaceAll(key, "\"", "") + "\""
+	}
+	return path
+}
+
+// jsonContains returns the condition that the JSON document doc contains the value,
+// the objects are matched by their keys and the arrays by their elements.
+func jsonContains(doc string, value interface{}, depth int) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sql, a := jsonContains("json_extract("+doc+", '"+strings.ReplaceAll(jsonPath(key), "'", "''")+"')", v[key], depth)
+			parts = append(parts, sql)
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "json_type(" + doc + ") = 'object'", nil
+		}
+	case []interface{}:
+		alias := fmt.Sprintf("j%d", depth)
+		for _, element := range v {
+			sql, a := jsonContains(alias+".value", element, depth+1)
+			parts = append(parts, "EXISTS (SELECT 1 FROM json_each("+doc+") AS "+alias+" WHERE "+sql+")")
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "json_type(" + doc + ") = 'array'", nil
+		}
+	case nil:
+		return doc + " IS NULL", nil
+	default:
+		return doc + " = ?", []interface{}{v}
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", args
+}
+
+//
+// Filters from query strings.
+//
+
+// filterKeys returns the sorted keys of the query string values, so the filters are parsed in the same order.
+func filterKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseFilterValue parses the single query string value of the filter.
+func parseFilterValue[T any](key string, raw []string) (*T, error) {
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("filter %q must have a single value", key)
+	}
+	value, err := parseFilterScalar[T](key, raw[0])
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// parseFilterValues parses the list of the query string values of the filter.
+func parseFilterValues[T any](key string, raw []string) ([]T, error) {
+	values := make([]T, 0, len(raw))
+	for _, r := range raw {
+		value, err := parseFilterScalar[T](key, r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseFilterScalar parses the query string value into the type of the filter field.
+func parseFilterScalar[T any](key string, raw string) (T, error) {
+	var value T
+	var err error
+	switch v := any(&value).(type) {
+	case *string:
+		*v = raw
+	case *bool:
+		*v, err = strconv.ParseBool(raw)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(raw, 10, 32)
+		*v = int32(n)
+	case *int64:
+		*v, err = strconv.ParseInt(raw, 10, 64)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(raw, 10, 32)
+		*v = uint32(n)
+	case *uint64:
+		*v, err = strconv.ParseUint(raw, 10, 64)
+	case *float32:
+		var n float64
+		n, err = parseFilterFloat(raw, 32)
+		*v = float32(n)
+	case *float64:
+		*v, err = parseFilterFloat(raw, 64)
+	default:
+		return value, fmt.Errorf("filter %q has an unsupported type %T", key, value)
+	}
+	if err != nil {
+		return value, fmt.Errorf("invalid value %q of the filter %q", raw, key)
+	}
+	return value, nil
+}
+
+// parseFilterFloat parses the finite float, NaN and the infinities are not valid filter values.
+func parseFilterFloat(raw string, bitSize int) (float64, error) {
+	n, err := strconv.ParseFloat(raw, bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("the value is not finite")
+	}
+	return n, nil
+}
+
+//
+// Aggregations.
+//
+
+// Aggregation is an aggregate function of the Aggregate query, e.g. Count() or Sum(UserColumnAge).
+type Aggregation struct {
+	function string
+	column   Column
+}
+
+// Count counts the rows.
+func Count() Aggregation {
+	return Aggregation{function: "COUNT"}
+}
+
+// Sum sums the values of the numeric column.
+func Sum(column Column) Aggregation {
+	return Aggregation{function: "SUM", column: column}
+}
+
+// Avg returns the average value of the numeric column.
+func Avg(column Column) Aggregation {
+	return Aggregation{function: "AVG", column: column}
+}
+
+// Min returns the smallest value of the numeric or timestamp column.
+func Min(column Column) Aggregation {
+	return Aggregation{function: "MIN", column: column}
+}
+
+// Max returns the largest value of the numeric or timestamp column.
+func Max(column Column) Aggregation {
+	return Aggregation{function: "MAX", column: column}
+}
+
+// Column returns the aggregated column, it is empty for Count.
+func (a Aggregation) Column() Column {
+	return a.column
+}
+
+// String returns the SQL expression of the aggregation.
+func (a Aggregation) String() string {
+	if a.column == "" {
+		return a.function + "(*)"
+	}
+	return a.function + "(" + string(a.column) + ")"
+}
+
+// Eq returns the Having condition: aggregation = value.
+func (a Aggregation) Eq(value interface{}) sq.Sqlizer {
+	return sq.Eq{a.String(): value}
+}
+
+// NotEq returns the Having condition: aggregation <> value.
+func (a Aggregation) NotEq(value interface{}) sq.Sqlizer {
+	return sq.NotEq{a.String(): value}
+}
+
+// GT returns the Having condition: aggregation > value.
+func (a Aggregation) GT(value interface{}) sq.Sqlizer {
+	return sq.Gt{a.String(): value}
+}
+
+// GTE returns the Having condition: aggregation >= value.
+func (a Aggregation) GTE(value interface{}) sq.Sqlizer {
+	return sq.GtOrEq{a.String(): value}
+}
+
+// LT returns the Having condition: aggregation < value.
+func (a Aggregation) LT(value interface{}) sq.Sqlizer {
+	return sq.Lt{a.String(): value}
+}
+
+// LTE returns the Having condition: aggregation <= value.
+func (a Aggregation) LTE(value interface{}) sq.Sqlizer {
+	return sq.LtOrEq{a.String(): value}
+}
+
+// AggregateOption is an option of the Aggregate query: an Aggregation, GroupBy, Having,
+// or a QueryBuilder which filters the aggregated rows.
+type AggregateOption interface {
+	applyAggregate(query *aggregateQuery)
+}
+
+// aggregateQuery is the Aggregate query built from the options.
+type aggregateQuery struct {
+	groupBy      []Column
+	aggregations []Aggregation
+	having       []sq.Sqlizer
+	builders     []*QueryBuilder
+}
+
+// newAggregateQuery builds the Aggregate query from the options.
+func newAggregateQuery(options []AggregateOption) *aggregateQuery {
+	query := &aggregateQuery{}
+	for _, option := range options {
+		if option != nil {
+			option.applyAggregate(query)
+		}
+	}
+	return query
+}
+
+// applyAggregate adds the aggregation to the Aggregate query.
+func (a Aggregation) applyAggregate(query *aggregateQuery) {
+	query.aggregations = append(query.aggregations, a)
+}
+
+// applyAggregate adds the filters of the builder to the Aggregate query,
+// the sorting and the pagination are applied to the groups.
+func (b *QueryBuilder) applyAggregate(query *aggregateQuery) {
+	query.builders = append(query.builders, b)
+}
+
+// groupBy is the GroupBy option.
+type groupBy []Column
+
+// GroupBy groups the aggregated rows by the columns, the rows of the Aggregate query hold the values of the columns.
+func GroupBy(columns ...Column) AggregateOption {
+	return groupBy(columns)
+}
+
+// applyAggregate adds the grouped columns to the Aggregate query.
+func (g groupBy) applyAggregate(query *aggregateQuery) {
+	query.groupBy = append(query.groupBy, g...)
+}
+
+// having is the Having option.
+type having []sq.Sqlizer
+
+// Having filters the groups by the conditions of the aggregations, e.g. Having(Count().GT(1)).
+func Having(conditions ...sq.Sqlizer) AggregateOption {
+	return having(conditions)
+}
+
+// applyAggregate adds the conditions to the Aggregate query.
+func (h having) applyAggregate(query *aggregateQuery) {
+	query.having = append(query.having, h...)
+}
+
+// aggregateOptions returns the options of the single aggregation of the rows found by the builders.
+func aggregateOptions(aggregation Aggregation, builders []*QueryBuilder) []AggregateOption {
+	options := make([]AggregateOption, 0, len(builders)+1)
+	options = append(options, aggregation)
+	for _, builder := range builders {
+		options = append(options, builder)
+	}
+	return options
+}
+
+//
+// Keyset pagination.
+//
+
+// Cursor is the position of the keyset pagination returned by FindManyAfter.
+// It holds the values of the sort columns and the primary key of the first or the last row of a page,
+// use String to pass it to the clients as an opaque token and ParseCursor to read it back.
+type Cursor struct {
+	sort     string
+	values   []json.RawMessage
+	backward bool
+}
+
+// cursorData is the encoded form of the Cursor.
+type cursorData struct {
+	Sort     string            `json:"s"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// CursorPaginator holds the cursors of the neighbour pages, a cursor is nil if there is no such page.
+type CursorPaginator struct {
+	Next *Cursor
+	Prev *Cursor
+}
+
+// ParseCursor decodes the cursor returned by Cursor.String.
+func ParseCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var data cursorData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return &Cursor{sort: data.Sort, values: data.Values, backward: data.Backward}, nil
+}
+
+// String encodes the cursor as a base64 string.
+func (c *Cursor) String() string {
+	raw, _ := json.Marshal(cursorData{Sort: c.sort, Values: c.values, Backward: c.backward})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsBackward returns true if the cursor points to the previous page.
+func (c *Cursor) IsBackward() bool {
+	return c.backward
+}
+
+// newCursor returns the cursor of the row, dest are the pointers to the fields of the sort columns.
+func newCursor(orders []OrderCondition, dest []interface{}, backward bool) (*Cursor, error) {
+	values := make([]json.RawMessage, 0, len(dest))
+	for _, value := range dest {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		values = append(values, raw)
+	}
+	return &Cursor{sort: cursorSort(orders), values: values, backward: backward}, nil
+}
+
+// decode decodes the values of the cursor into dest, the pointers to the fields of the sort columns,
+// and returns the values. The cursor must be created with the same sorting.
+func (c *Cursor) decode(orders []OrderCondition, dest []interface{}) ([]interface{}, error) {
+	if c.sort != cursorSort(orders) {
+		return nil, fmt.Errorf("%w: the cursor is sorted by %q, the query is sorted by %q", ErrInvalidCursor, c.sort, cursorSort(orders))
+	}
+	if len(c.values) != len(dest) {
+		return nil, fmt.Errorf("%w: wrong number of values", ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, 0, len(dest))
+	for i, value := range dest {
+		if err := json.Unmarshal(c.values[i], value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		values = append(values, reflect.ValueOf(value).Elem().Interface())
+	}
+	return values, nil
+}
+
+// cursorSort returns the signature of the sorting of the cursor.
+func cursorSort(orders []OrderCondition) string {
+	parts := make([]string, 0, len(orders))
+	for _, order := range orders {
+		parts = append(parts, order.clause())
+	}
+	return strings.Join(parts, ",")
+}
+
+// cursorColumns returns the sort columns.
+func cursorColumns(orders []OrderCondition) []string {
+	columns := make([]string, 0, len(orders))
+	for _, order := range orders {
+		columns = append(columns, order.Column)
+	}
+	return columns
+}
+
+// cursorCondition is the keyset condition of the rows after the cursor in the sorting order,
+// or before the cursor if it is backward.
+type cursorCondition struct {
+	orders   []OrderCondition
+	values   []interface{}
+	backward bool
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c cursorCondition) ToSql() (string, []interface{}, error) {
+	return c.condition().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c cursorCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c cursorCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c cursorCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// condition returns (a > x) OR (a = x AND b > y) ... for the sort columns a, b and the values x, y.
+func (c cursorCondition) condition() sq.Sqlizer {
+	or := sq.Or{}
+	for i, order := range c.orders {
+		and := sq.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{c.orders[j].Column: c.values[j]})
+		}
+		if order.Asc != c.backward {
+			and = append(and, sq.Gt{order.Column: c.values[i]})
+		} else {
+			and = append(and, sq.Lt{order.Column: c.values[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+//
+// Typed joins.
+//
+
+// RelationJoin is the typed join of a relation, FindMany scans the joined rows into the relation field
+// of the found models in the same query. It is created by the generated join builders, e.g. UserJoinPosts.
+type RelationJoin struct {
+	table    string
+	relation string
+	joinType JoinType
+	filters  []FilterApplier
+}
+
+// WithJoins joins the relations, see RelationJoin.
+func (b *QueryBuilder) WithJoins(joins ...RelationJoin) *QueryBuilder {
+	b.joins = append(b.joins, joins...)
+	return b
+}
+
+// joinRowColumn is the column of the row numbers which identify the base and the joined rows.
+const joinRowColumn = "structify_row"
+
+// joinedRelation is the typed join of a relation resolved by the storage.
+type joinedRelation struct {
+	join RelationJoin
+	// query selects the joined rows.
+	query sq.SelectBuilder
+	// exists selects the joined rows of a base row, the base rows of InnerJoin are filtered by it.
+	// It keeps the question placeholders to be nested into the base query.
+	exists sq.SelectBuilder
+	// column is the column of the joined rows which references the column ref of the base rows.
+	column string
+	ref    string
+	// columns are the selected columns of the joined rows.
+	columns []string
+	// newRow returns the joined model, the scan destinations of its columns
+	// and the function which attaches the model to the base model.
+	newRow func() (interface{}, []interface{}, func(parent interface{}))
+}
+
+// joinedRow holds the scanned values of a joined row, the values are NULL if the base row has no joined row.
+type joinedRow struct {
+	model    interface{}
+	dest     []interface{}
+	nullable []interface{}
+	attach   func(parent interface{})
+	row      sql.NullInt64
+}
+
+// newJoinedRow returns the scan destinations of a joined row.
+func (r *joinedRelation) newJoinedRow() *joinedRow {
+	model, dest, attach := r.newRow()
+	nullable := make([]interface{}, 0, len(dest))
+	for _, d := range dest {
+		nullable = append(nullable, reflect.New(reflect.TypeOf(d)).Interface())
+	}
+	return &joinedRow{model: model, dest: dest, nullable: nullable, attach: attach}
+}
+
+// scanDest returns the scan destinations of the joined columns and the row number.
+func (r *joinedRow) scanDest() []interface{} {
+	return append(append([]interface{}{}, r.nullable...), &r.row)
+}
+
+// assign assigns the scanned non-NULL values to the joined model.
+func (r *joinedRow) assign() {
+	for i := range r.dest {
+		value := reflect.ValueOf(r.nullable[i]).Elem()
+		if !value.IsNil() {
+			reflect.ValueOf(r.dest[i]).Elem().Set(value.Elem())
+		}
+	}
+}
+
+// joinBuilders returns the typed joins of the builders.
+func joinBuilders(builders []*QueryBuilder) []RelationJoin {
+	var joins []RelationJoin
+	for _, builder := range builders {
+		if builder != nil {
+			joins = append(joins, builder.joins...)
+		}
+	}
+	return joins
+}
+
+// rowNumberColumn returns the column which numbers the rows in the sorting order of the builders.
+func rowNumberColumn(builders []*QueryBuilder) string {
+	var clauses []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			if order, ok := option.(OrderCondition); ok {
+				clauses = append(clauses, order.clause())
+			}
+		}
+	}
+	if len(clauses) == 0 {
+		return "ROW_NUMBER() OVER () AS " + joinRowColumn
+	}
+	return "ROW_NUMBER() OVER (ORDER BY " + strings.Join(clauses, ", ") + ") AS " + joinRowColumn
+}
+
+// joinQuery selects the base rows of the query base joined with the relations, the base rows are selected
+// as "t0" and the rows of the relations as "t1", "t2"... The joined columns are aliased as "t1_column".
+func joinQuery(builder sq.StatementBuilderType, base sq.SelectBuilder, columns []string, relations []*joinedRelation) sq.SelectBuilder {
+	selects := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		selects = append(selects, "t0."+column)
+	}
+	selects = append(selects, "t0."+joinRowColumn)
+	for i, relation := range relations {
+		alias := fmt.Sprintf("t%d", i+1)
+		for _, column := range relation.columns {
+			selects = append(selects, alias+"."+column+" AS "+alias+"_"+column)
+		}
+		selects = append(selects, alias+"."+joinRowColumn+" AS "+alias+"_"+joinRowColumn)
+	}
+
+	// the subqueries keep the question placeholders, they are numbered by the outer query
+	query := builder.Select(selects...).FromSelect(base.PlaceholderFormat(sq.Question), "t0")
+	for i, relation := range relations {
+		alias := fmt.Sprintf("t%d", i+1)
+		on := alias + "." + relation.column + " = t0." + relation.ref
+		subquery := relation.query.PlaceholderFormat(sq.Question)
+		query = query.JoinClause(sq.Expr(string(relation.join.joinType)+" JOIN (?) AS "+alias+" ON "+on, subquery))
+	}
+	return query.OrderBy("t0." + joinRowColumn)
+}
+
+//
+// Eager loading.
+//
+
+// preload is a relation path of WithPreload, e.g. "Posts.Author", with the builders of the relation.
+type preload struct {
+	path     string
+	builders []*QueryBuilder
+}
+
+// WithPreload loads the relations of the found models with the generated LoadBatch functions.
+// The nested relations are separated by dots, e.g. WithPreload("Posts", "Settings", "Posts.Author").
+func (b *QueryBuilder) WithPreload(paths ...string) *QueryBuilder {
+	for _, path := range paths {
+		b.preloads = append(b.preloads, preload{path: path})
+	}
+	return b
+}
+
+// WithPreloadBuilders loads the relation of the path like WithPreload, the builders filter and sort the loaded rows,
+// e.g. WithPreloadBuilders("Posts", SortBuilder(PostIdOrderBy(false))).
+func (b *QueryBuilder) WithPreloadBuilders(path string, builders ...*QueryBuilder) *QueryBuilder {
+	b.preloads = append(b.preloads, preload{path: path, builders: builders})
+	return b
+}
+
+// PreloadBuilder is a helper function to create a new query builder which loads the relations.
+func PreloadBuilder(paths ...string) *QueryBuilder {
+	return NewQueryBuilder().WithPreload(paths...)
+}
+
+// Preload is the option which loads the relations like QueryBuilder.WithPreload, e.g. in FindById.
+func Preload(paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.preloads = append(o.preloads, preload{path: path})
+		}
+	}
+}
+
+// preloadRelations groups the preload paths of the builders by their first relation in the order of the paths.
+// The nested paths are preloaded by the builders of the relation.
+func preloadRelations(builders []*QueryBuilder) ([]string, map[string][]*QueryBuilder) {
+	var preloads []preload
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		preloads = append(preloads, builder.preloads...)
+
+		options := &Options{}
+		for _, o := range builder.options {
+			o(options)
+		}
+		preloads = append(preloads, options.preloads...)
+	}
+
+	var relations []string
+	grouped := make(map[string][]*QueryBuilder)
+	for _, p := range preloads {
+		relation, nested, ok := strings.Cut(p.path, ".")
+		if _, found := grouped[relation]; !found {
+			relations = append(relations, relation)
+			grouped[relation] = []*QueryBuilder{}
+		}
+		if ok {
+			grouped[relation] = append(grouped[relation], NewQueryBuilder().WithPreloadBuilders(nested, p.builders...))
+		} else {
+			grouped[relation] = append(grouped[relation], p.builders...)
+		}
+	}
+	return relations, grouped
+}
+
+//
+// Full-text search.
+//
+
+// searchRankColumn is the column of the relevance of the rows found by Search.
+const searchRankColumn = "structify_rank"
+
+// searchHighlight are the marks of the matches in the snippets returned by Search.
+type searchHighlight struct {
+	start string
+	stop  string
+}
+
+// WithSearchHighlight is the option which makes Search return the snippets of the searchable fields,
+// the matches are wrapped in the start and the stop marks, e.g. WithSearchHighlight("<b>", "</b>").
+func WithSearchHighlight(start, stop string) Option {
+	return func(o *Options) {
+		o.searchHighlight = &searchHighlight{start: start, stop: stop}
+	}
+}
+
+// builderHighlight returns the highlight marks set by the WithSearchHighlight option of the builders.
+func builderHighlight(builders []*QueryBuilder) *searchHighlight {
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	return options.searchHighlight
+}
+
+// searchMatch returns the FTS5 query which matches the rows containing all the words of the query.
+// The words are quoted, so the FTS5 query syntax is not interpreted.
+func searchMatch(query string) string {
+	words := strings.Fields(query)
+	for i, word := range words {
+		words[i] = "\"" + strings.ReplaceAll(word, "\"", "\"\"") + "\""
+	}
+	return strings.Join(words, " ")
+}
+
+//
+// Table upgrades.
+//
+
+// SchemaDriftKind is the kind of the difference between the live table and the model.
+type SchemaDriftKind string
+
+const (
+	// SchemaDriftMissingTable is a table of the model which does not exist in the database.
+	SchemaDriftMissingTable SchemaDriftKind = "missing_table"
+	// SchemaDriftMissingColumn is a column of the model which does not exist in the table.
+	SchemaDriftMissingColumn SchemaDriftKind = "missing_column"
+	// SchemaDriftExtraColumn is a column of the table which is not declared in the model.
+	SchemaDriftExtraColumn SchemaDriftKind = "extra_column"
+	// SchemaDriftTypeMismatch is a column which type differs from the model.
+	SchemaDriftTypeMismatch SchemaDriftKind = "type_mismatch"
+	// SchemaDriftNullabilityMismatch is a column which nullability differs from the model.
+	SchemaDriftNullabilityMismatch SchemaDriftKind = "nullability_mismatch"
+	// SchemaDriftMissingIndex is an index of the model which does not exist in the table.
+	SchemaDriftMissingIndex SchemaDriftKind = "missing_index"
+)
+
+// SchemaDrift is a difference between the live table and the model which is not applied by the upgrade.
+type SchemaDrift struct {
+	// Table is the name of the table.
+	Table string
+	// Column is the name of the column or the index, it is empty for the missing table.
+	Column string
+	// Kind is the kind of the difference.
+	Kind SchemaDriftKind
+	// Expected is the definition declared by the model.
+	Expected string
+	// Actual is the definition of the live table.
+	Actual string
+}
+
+// String returns the description of the drift.
+func (d SchemaDrift) String() string {
+	name := d.Table
+	if d.Column != "" {
+		name += "." + d.Column
+	}
+	switch {
+	case d.Expected == "" && d.Actual == "":
+		return fmt.Sprintf("%s: %s", name, d.Kind)
+	case d.Actual == "":
+		return fmt.Sprintf("%s: %s (expected %q)", name, d.Kind, d.Expected)
+	case d.Expected == "":
+		return fmt.Sprintf("%s: %s (actual %q)", name, d.Kind, d.Actual)
+	}
+	return fmt.Sprintf("%s: %s (expected %q, actual %q)", name, d.Kind, d.Expected, d.Actual)
+}
+
+// Breaking reports whether the drift breaks the queries of the store.
+// The extra columns and the missing indexes do not break the queries.
+func (d SchemaDrift) Breaking() bool {
+	return d.Kind != SchemaDriftExtraColumn && d.Kind != SchemaDriftMissingIndex
+}
+
+// SchemaDriftError is returned by the upgrade when the live schema has the destructive differences.
+type SchemaDriftError struct {
+	Drifts []SchemaDrift
+}
+
+// Error returns the error message.
+func (e *SchemaDriftError) Error() string {
+	drifts := make([]string, 0, len(e.Drifts))
+	for _, drift := range e.Drifts {
+		drifts = append(drifts, drift.String())
+	}
+	return "schema drift: " + strings.Join(drifts, "; ")
+}
+
+// SchemaReport is the result of the schema verification.
+type SchemaReport struct {
+	// Drifts are the differences between the live tables and the models.
+	Drifts []SchemaDrift
+}
+
+// OK reports whether the live tables match the models.
+func (r *SchemaReport) OK() bool {
+	return len(r.Drifts) == 0
+}
+
+// Err returns the *SchemaDriftError of the breaking drifts, nil if the stores can query the tables.
+func (r *SchemaReport) Err() error {
+	var drifts []SchemaDrift
+	for _, drift := range r.Drifts {
+		if drift.Breaking() {
+			drifts = append(drifts, drift)
+		}
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{Drifts: drifts}
+}
+
+// String returns the readable report, one drift per line.
+func (r *SchemaReport) String() string {
+	if r.OK() {
+		return "schema is up to date"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema has %d drift(s):", len(r.Drifts))
+	for _, drift := range r.Drifts {
+		b.WriteString("\n  ")
+		b.WriteString(drift.String())
+	}
+	return b.String()
+}
+
+// tableColumn is the column of the model which the upgrade compares with the live table.
+type tableColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+	// Statement adds the missing column, it is empty if the column can not be added safely.
+	Statement string
+}
+
+// tableIndex is the index of the model which the upgrade creates.
+type tableIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Statement creates the index if it does not exist.
+	Statement string
+}
+
+// liveColumn is the column of the live table.
+type liveColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+}
+
+// liveIndex is the index of the live table.
+type liveIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// introspectColumns returns the columns of the live table from PRAGMA table_info.
+// No columns are returned if the table does not exist.
+func introspectColumns(ctx context.Context, db QueryExecer, table string) ([]liveColumn, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name, type, \"notnull\" FROM pragma_table_info(?) ORDER BY cid", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []liveColumn
+	for rows.Next() {
+		var column liveColumn
+		if err := rows.Scan(&column.Name, &column.Type, &column.NotNull); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+// introspectIndexes returns the indexes of the live table from PRAGMA index_list.
+func introspectIndexes(ctx context.Context, db QueryExecer, table string) ([]liveIndex, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT il.name, il."unique", COALESCE(ii.name, '')
+		FROM pragma_index_list(?) il, pragma_index_info(il.name) ii
+		ORDER BY il.name, ii.seqno
+	`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []liveIndex
+	for rows.Next() {
+		var name, column string
+		var unique bool
+		if err := rows.Scan(&name, &unique, &column); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, liveIndex{Name: name, Unique: unique})
+		}
+		index := &indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate indexes: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// compareColumns returns the columns of the model which are missing in the live table and can be added,
+// and the differences which can not be applied safely.
+func compareColumns(table string, columns []tableColumn, live []liveColumn) ([]tableColumn, []SchemaDrift) {
+	actual := make(map[string]liveColumn, len(live))
+	for _, column := range live {
+		actual[column.Name] = column
+	}
+
+	var missing []tableColumn
+	var drifts []SchemaDrift
+	declared := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		declared[column.Name] = true
+
+		current, ok := actual[column.Name]
+		switch {
+		case !ok && column.Statement != "":
+			missing = append(missing, column)
+		case !ok:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+		case !strings.EqualFold(current.Type, column.Type):
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftTypeMismatch, Expected: column.Type, Actual: current.Type})
+		case current.NotNull != column.NotNull:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftNullabilityMismatch, Expected: nullability(column.NotNull), Actual: nullability(current.NotNull)})
+		}
+	}
+
+	for _, column := range live {
+		if !declared[column.Name] {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftExtraColumn, Actual: column.Type})
+		}
+	}
+
+	return missing, drifts
+}
+
+// indexStatements returns the statements of the indexes which columns exist in the table.
+func indexStatements(indexes []tableIndex, live []liveColumn, added []tableColumn) []string {
+	existing := make(map[string]bool, len(live)+len(added))
+	for _, column := range live {
+		existing[column.Name] = true
+	}
+	for _, column := range added {
+		existing[column.Name] = true
+	}
+
+	var statements []string
+	for _, index := range indexes {
+		complete := true
+		for _, column := range index.Columns {
+			complete = complete && existing[column]
+		}
+		if complete {
+			statements = append(statements, index.Statement)
+		}
+	}
+	return statements
+}
+
+// verifyTable returns the differences between the live table and the model.
+// Unlike the upgrade, the columns which can be added are reported as missing too.
+func verifyTable(table string, columns []tableColumn, indexes []tableIndex, live []liveColumn, liveIndexes []liveIndex) []SchemaDrift {
+	if len(live) == 0 {
+		return []SchemaDrift{
+			{Table: table, Kind: SchemaDriftMissingTable},
+		}
+	}
+
+	missing, drifts := compareColumns(table, columns, live)
+	for _, column := range missing {
+		drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+	}
+	for _, index := range indexes {
+		if !hasIndex(index, liveIndexes) {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: index.Name, Kind: SchemaDriftMissingIndex, Expected: strings.Join(index.Columns, ", ")})
+		}
+	}
+	return drifts
+}
+
+// hasIndex reports whether the live table has the index with the same name or on the same columns.
+// The unique index is satisfied only by the live unique index.
+func hasIndex(index tableIndex, live []liveIndex) bool {
+	for _, current := range live {
+		if current.Name == index.Name {
+			return true
+		}
+		if len(current.Columns) > 0 && strings.Join(current.Columns, ",") == strings.Join(index.Columns, ",") && (current.Unique || !index.Unique) {
+			return true
+		}
+	}
+	return false
+}
+
+// nullability returns the nullability of the column in the SQL notation.
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+//
+// Fixtures.
+//
+
+// LoadFixtures inserts the rows of the fixture files into the tables in one transaction.
+// The paths are the glob patterns of the YAML or JSON files in fsys, every document maps
+// the table names to the rows. The rows are a list, or a map of the named rows which can be referenced:
+//
+//	users:
+//	  alice:
+//	    name: Alice
+//	posts:
+//	  - title: Hello
+//	    author: $users.alice
+//
+// The string "$table.row" references the named row inserted before, it resolves to the referenced column
+// of the relation field or to the primary key of the row, "$table.row.column" resolves to the given column.
+// A leading "$$" escapes the dollar sign. The tables are filled in the dependency order, the values are inserted as is,
+// the missing uuid primary key is generated like Create does.
+func (c *blogStorages) LoadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error {
+	return c.loadFixtures(ctx, fsys, paths, false)
+}
+
+// ReloadFixtures truncates all the tables and loads the fixtures in one transaction.
+func (c *blogStorages) ReloadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error {
+	return c.loadFixtures(ctx, fsys, paths, true)
+}
+
+// loadFixtures reads the fixture files and inserts the rows, the tables are truncated first if truncate is set.
+func (c *blogStorages) loadFixtures(ctx context.Context, fsys fs.FS, paths []string, truncate bool) error {
+	rows, err := readFixtures(fsys, paths)
+	if err != nil {
+		return err
+	}
+
+	return c.tx.ExecFuncWithTx(ctx, func(ctx context.Context) error {
+		tx, _ := TxFromContext(ctx)
+		// sqlite has no TRUNCATE statement, the referencing tables are cleared first.
+		if truncate {
+			for i := len(fixtureTableOrder) - 1; i >= 0; i-- {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+fixtureTables[fixtureTableOrder[i]].name); err != nil {
+					return fmt.Errorf("failed to truncate tables: %w", err)
+				}
+			}
+		}
+
+		// the named rows are kept to resolve the references.
+		loaded := make(map[string]map[string]interface{})
+		for _, key := range fixtureTableOrder {
+			for _, row := range rows[key] {
+				values, err := insertFixture(ctx, tx, fixtureTables[key], row, loaded)
+				if err != nil {
+					return fmt.Errorf("failed to load fixture %s: %w", row.source, err)
+				}
+				if row.name != "" {
+					loaded[key+"."+row.name] = values
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// fixtureTable is the table which can be filled by the fixtures.
+type fixtureTable struct {
+	name       string                     // name is the quoted table name.
+	primaryKey string                     // primaryKey is the column the references resolve to by default.
+	columns    map[string]bool            // columns are the columns of the table.
+	relations  map[string]fixtureRelation // relations are the relation fields which set the referencing columns.
+	uuids      []string                   // uuids are the uuid primary key columns generated if the row doesn't set them.
+}
+
+// fixtureRelation is the relation field which sets the referencing column.
+type fixtureRelation struct {
+	column    string // column is the referencing column.
+	reference string // reference is the referenced column.
+}
+
+// fixtureRow is the row of the fixtures.
+type fixtureRow struct {
+	name   string        // name is the name of the row, it is empty for the rows of a list.
+	source string        // source is the file and the line of the row.
+	keys   []string      // keys are the columns and the relation fields in the document order.
+	values []interface{} // values are the values of the keys.
+}
+
+// fixtureTableOrder is the dependency order of the tables, the referenced tables go first.
+var fixtureTableOrder = []string{
+	"users",
+	"devices",
+	"posts",
+	"settings",
+	"addresses",
+}
+
+// fixtureTables are the tables by their names.
+var fixtureTables = map[string]fixtureTable{
+	"users": {
+		name:       "\"users\"",
+		primaryKey: "id",
+		columns: map[string]bool{
+			"id":                    true,
+			"name":                  true,
+			"age":                   true,
+			"email":                 true,
+			"last_name":             true,
+			"created_at":            true,
+			"updated_at":            true,
+			"notification_settings": true,
+			"phones":                true,
+			"balls":                 true,
+			"numrs":                 true,
+			"comments":              true,
+		},
+		uuids: []string{
+			"id",
+		},
+	},
+	"devices": {
+		name:       "\"devices\"",
+		primaryKey: "",
+		columns: map[string]bool{
+			"name":    true,
+			"value":   true,
+			"user_id": true,
+		},
+	},
+	"posts": {
+		name:       "\"posts\"",
+		primaryKey: "id",
+		columns: map[string]bool{
+			"id":        true,
+			"title":     true,
+			"body":      true,
+			"author_id": true,
+		},
+		relations: map[string]fixtureRelation{
+			"author": {column: "author_id", reference: "id"},
+		},
+	},
+	"settings": {
+		name:       "\"settings\"",
+		primaryKey: "id",
+		columns: map[string]bool{
+			"id":      true,
+			"name":    true,
+			"value":   true,
+			"user_id": true,
+		},
+		relations: map[string]fixtureRelation{
+			"user": {column: "user_id", reference: "id"},
+		},
+	},
+	"addresses": {
+		name:       "\"addresses\"",
+		primaryKey: "id",
+		columns: map[string]bool{
+			"id":         true,
+			"street":     true,
+			"city":       true,
+			"state":      true,
+			"zip":        true,
+			"user_id":    true,
+			"created_at": true,
+			"updated_at": true,
+		},
+		uuids: []string{
+			"id",
+		},
+		relations: map[string]fixtureRelation{
+			"user": {column: "user_id", reference: "id"},
+		},
+	},
+}
+
+// readFixtures reads the rows of the fixture files matching the patterns by the table names.
+func readFixtures(fsys fs.FS, patterns []string) (map[string][]*fixtureRow, error) {
+	rows := make(map[string][]*fixtureRow)
+	names := make(map[string]bool)
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture pattern %q: %w", pattern, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no fixture files match %q", pattern)
+		}
+
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read fixture %s: %w", file, err)
+			}
+
+			// json is a subset of yaml, so both are read by the yaml decoder.
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			for {
+				var document yaml.Node
+				if err := decoder.Decode(&document); err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return nil, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+				}
+				if err := parseFixtureDocument(file, &document, rows, names); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// parseFixtureDocument parses the rows of the document by the table names.
+func parseFixtureDocument(file string, document *yaml.Node, rows map[string][]*fixtureRow, names map[string]bool) error {
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	root := document.Content[0]
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d: the fixture must map the table names to the rows", file, root.Line)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if _, ok := fixtureTables[key.Value]; !ok {
+			return fmt.Errorf("%s:%d: unknown table %q", file, key.Line, key.Value)
+		}
+
+		switch value.Kind {
+		case yaml.SequenceNode:
+			for _, node := range value.Content {
+				row, err := parseFixtureRow(file, "", node)
+				if err != nil {
+					return err
+				}
+				rows[key.Value] = append(rows[key.Value], row)
+			}
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := value.Content[j].Value
+				if names[key.Value+"."+name] {
+					return fmt.Errorf("%s:%d: duplicate row %s.%s", file, value.Content[j].Line, key.Value, name)
+				}
+				names[key.Value+"."+name] = true
+
+				row, err := parseFixtureRow(file, name, value.Content[j+1])
+				if err != nil {
+					return err
+				}
+				rows[key.Value] = append(rows[key.Value], row)
+			}
+		default:
+			return fmt.Errorf("%s:%d: the rows of %s must be a list or a map", file, value.Line, key.Value)
+		}
+	}
+
+	return nil
+}
+
+// parseFixtureRow parses the values of the row, the nested maps and lists are stored as json.
+func parseFixtureRow(file string, name string, node *yaml.Node) (*fixtureRow, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d: the row must map the columns to the values", file, node.Line)
+	}
+
+	row := &fixtureRow{name: name, source: fmt.Sprintf("%s:%d", file, node.Line)}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value interface{}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return nil, fmt.Errorf("%s:%d: failed to parse value: %w", file, node.Content[i+1].Line, err)
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: failed to marshal value: %w", file, node.Content[i+1].Line, err)
+			}
+			value = string(data)
+		}
+
+		row.keys = append(row.keys, node.Content[i].Value)
+		row.values = append(row.values, value)
+	}
+
+	return row, nil
+}
+
+// insertFixture inserts the row and returns the values of the inserted row.
+func insertFixture(ctx context.Context, db QueryExecer, table fixtureTable, row *fixtureRow, loaded map[string]map[string]interface{}) (map[string]interface{}, error) {
+	var columns []string
+	var values []interface{}
+	for i, key := range row.keys {
+		column, reference := key, ""
+		if relation, ok := table.relations[key]; ok {
+			column, reference = relation.column, relation.reference
+		} else if !table.columns[key] {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+
+		value, err := resolveFixtureValue(row.values[i], reference, loaded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", key, err)
+		}
+		columns = append(columns, quoteFixtureIdent(column))
+		values = append(values, value)
+	}
+
+	// the uuid primary key is generated by Create, not by the database.
+	for _, column := range table.uuids {
+		if containsFixtureKey(row.keys, column) {
+			continue
+		}
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate uuid for %s: %w", column, err)
+		}
+		columns = append(columns, quoteFixtureIdent(column))
+		values = append(values, id.String())
+	}
+
+	sqlQuery, args := "INSERT INTO "+table.name+" DEFAULT VALUES RETURNING *", []interface{}(nil)
+	if len(columns) > 0 {
+		var err error
+		sqlQuery, args, err = sq.Insert(table.name).
+			Columns(columns...).
+			Values(values...).
+			Suffix("RETURNING *").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert row: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFixtureRow(rows)
+}
+
+// containsFixtureKey returns true if the key is one of the keys of the row.
+func containsFixtureKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFixtureRow scans the returned row into the map of the column values.
+func scanFixtureRow(rows *sql.Rows) (map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to insert row: %w", err)
+		}
+		return nil, errors.New("no row is returned")
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		// the text values are returned as bytes by the driver.
+		if b, ok := values[i].([]byte); ok {
+			row[column] = string(b)
+			continue
+		}
+		row[column] = values[i]
+	}
+
+	return row, rows.Err()
+}
+
+// resolveFixtureValue resolves the reference to the row loaded before, the other values are returned as is.
+// The reference resolves to the given column, to the referenced column of the relation or to the primary key.
+func resolveFixtureValue(value interface{}, reference string, loaded map[string]map[string]interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, "$") {
+		return value, nil
+	}
+	if strings.HasPrefix(s, "$$") {
+		return s[1:], nil
+	}
+
+	// the table names may contain the dots, so the longest one wins.
+	var table, rest string
+	for _, key := range fixtureTableOrder {
+		if strings.HasPrefix(s[1:], key+".") && len(key) > len(table) {
+			table, rest = key, s[len(key)+2:]
+		}
+	}
+	if table == "" {
+		return nil, fmt.Errorf("unknown table of the reference %s", s)
+	}
+
+	name, column, _ := strings.Cut(rest, ".")
+	row, ok := loaded[table+"."+name]
+	if !ok {
+		return nil, fmt.Errorf("row %s is not loaded, the referenced rows must be named", s)
+	}
+	if column == "" {
+		column = reference
+	}
+	if column == "" {
+		column = fixtureTables[table].primaryKey
+	}
+	if column == "" {
+		return nil, fmt.Errorf("%s has no primary key, the column must be referenced", table)
+	}
+
+	v, ok := row[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown column of the reference %s", s)
+	}
+	return v, nil
+}
+
+// quoteFixtureIdent returns the quoted column name.
+func quoteFixtureIdent(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
 }
@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	"math"
+	"net/url"
 )
 
 // deviceStorage is a struct for the "devices" table.
@@ -24,15 +25,27 @@ type DeviceTableManager interface {
 	UpgradeTable(ctx context.Context) error
 }
 
+// DeviceSchemaVerification is an interface for verifying the devices table.
+type DeviceSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // DeviceCRUDOperations is an interface for managing the devices table.
 type DeviceCRUDOperations interface {
 	Create(ctx context.Context, model *Device, opts ...Option) error
-	Update(ctx context.Context, id int64, updateData *DeviceUpdate) error
+	Upsert(ctx context.Context, model *Device, conflict *Conflict, opts ...Option) error
+	BatchUpsert(ctx context.Context, models []*Device, conflict *Conflict, opts ...Option) error
+	Update(ctx context.Context, id int64, updateData *DeviceUpdate) (int64, error)
+	UpdateMany(ctx context.Context, updateData *DeviceUpdate, builders ...*QueryBuilder) (int64, error)
 }
 
 // DeviceSearchOperations is an interface for searching the devices table.
 type DeviceSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Device, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Device) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Device, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Device, error)
@@ -43,13 +56,18 @@ type DevicePaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Device, *Paginator, error)
 }
 
+// DeviceAggregateOperations is an interface for the aggregate queries.
+type DeviceAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*DeviceAggregateRow, error)
+}
+
 // DeviceRelationLoading is an interface for loading relations.
 type DeviceRelationLoading interface {
 }
 
 // DeviceAdvancedDeletion is an interface for advanced deletion operations.
 type DeviceAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // DeviceRawQueryOperations is an interface for executing raw queries.
@@ -62,9 +80,11 @@ type DeviceRawQueryOperations interface {
 // DeviceStorage is a struct for the "devices" table.
 type DeviceStorage interface {
 	DeviceTableManager
+	DeviceSchemaVerification
 	DeviceCRUDOperations
 	DeviceSearchOperations
 	DevicePaginationOperations
+	DeviceAggregateOperations
 	DeviceRelationLoading
 	DeviceAdvancedDeletion
 	DeviceRawQueryOperations
@@ -130,19 +150,90 @@ func (t *deviceStorage) DropTable(ctx context.Context) error {
 	return err
 }
 
-// TruncateTable truncates the table.
+// TruncateTable truncates the table, sqlite has no TRUNCATE statement so all the rows are deleted.
 func (t *deviceStorage) TruncateTable(ctx context.Context) error {
 	sqlQuery := `
-		TRUNCATE TABLE devices;
+		DELETE FROM devices;
 	`
 
 	_, err := t.db.ExecContext(ctx, sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *deviceStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return fmt.Errorf("failed to introspect table: %w", err)
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.Name, err)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *deviceStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table: %w", err)
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect indexes: %w", err)
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *deviceStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "name", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"devices\" ADD COLUMN \"name\" TEXT;"},
+		{Name: "value", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"devices\" ADD COLUMN \"value\" TEXT;"},
+		{Name: "user_id", Type: "TEXT", NotNull: true, Statement: ""},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *deviceStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "devices_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"devices_user_id_unique_idx\" ON \"devices\" (\"user_id\");"},
+		{Name: "devices_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"devices_user_id_idx\" ON \"devices\" (\"user_id\");"},
+	}
+}
+
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *deviceStorage) preload(ctx context.Context, items []*Device, builders ...*QueryBuilder) error {
+	if relations, _ := preloadRelations(builders); len(relations) > 0 {
+		return fmt.Errorf("unknown relation %s of Device", relations[0])
+	}
+
 	return nil
 }
 
@@ -172,9 +263,83 @@ func (t *Device) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Device, the other fields are left empty.
+func (t *Device) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Device) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "name":
+			dest = append(dest, &t.Name)
+		case "value":
+			dest = append(dest, &t.Value)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Device columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	DeviceColumnName   Column = "name"
+	DeviceColumnValue  Column = "value"
+	DeviceColumnUserId Column = "user_id"
+)
+
 // DeviceFilters is a struct that holds filters for Device.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type DeviceFilters struct {
-	UserId *string
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *DeviceFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseDeviceFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseDeviceFilters(values url.Values) (*DeviceFilters, error) {
+	f := &DeviceFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // DeviceUserIdEq returns a condition that checks if the field equals the value.
@@ -274,47 +439,197 @@ func (t *deviceStorage) Create(ctx context.Context, model *Device, opts ...Optio
 	return nil
 }
 
+// deviceConflictTargets are the unique constraints of the devices table which the upsert can conflict on.
+var deviceConflictTargets = [][]Column{
+	{DeviceColumnUserId},
+}
+
+// deviceUpsertColumns are the columns inserted by the upsert.
+var deviceUpsertColumns = []upsertColumn{
+	{column: DeviceColumnName},
+	{column: DeviceColumnValue},
+	{column: DeviceColumnUserId},
+}
+
+// upsertValues returns the values of the model in the order of the deviceUpsertColumns.
+func (t *deviceStorage) upsertValues(model *Device) ([]interface{}, error) {
+
+	values := make([]interface{}, 0, len(deviceUpsertColumns))
+	values = append(values, model.Name)
+	values = append(values, model.Value)
+	values = append(values, model.UserId)
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *deviceStorage) upsertQuery(models []*Device, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix("devices", deviceConflictTargets, deviceUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert("devices").Columns(upsertColumnNames(deviceUpsertColumns)...)
+	for _, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Device or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(DeviceColumnUserId).DoUpdate()).
+func (t *deviceStorage) Upsert(ctx context.Context, model *Device, conflict *Conflict, opts ...Option) error {
+	if model == nil {
+		return errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Device{model}, conflict, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to upsert Device: %w", err)
+	}
+
+	return nil
+}
+
+// BatchUpsert creates multiple Device records in a single batch or handles their conflicts on the unique constraint.
+func (t *deviceStorage) BatchUpsert(ctx context.Context, models []*Device, conflict *Conflict, opts ...Option) error {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return fmt.Errorf("failed to execute bulk upsert: %w", err)
+	}
+
+	return nil
+}
+
 // DeviceUpdate is used to update an existing Device.
 type DeviceUpdate struct {
 	Name   *string
 	Value  *string
 	UserId *string
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Device based on non-nil fields.
-func (t *deviceStorage) Update(ctx context.Context, id int64, updateData *DeviceUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
+// Update updates an existing Device based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *deviceStorage) Update(ctx context.Context, id int64, updateData *DeviceUpdate) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
 	}
+	query = query.Where("id = ?", id)
 
-	query := t.queryBuilder.Update("devices")
-	if updateData.Name != nil {
-		query = query.Set("name", updateData.Name)
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
-	if updateData.Value != nil {
-		query = query.Set("value", updateData.Value)
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update Device: %w", err)
 	}
-	if updateData.UserId != nil {
-		query = query.Set("user_id", updateData.UserId)
+
+	return rowsAffected(result)
+}
+
+// UpdateMany updates the devices rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *deviceStorage) UpdateMany(ctx context.Context, updateData *DeviceUpdate, builders ...*QueryBuilder) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
 	}
 
-	query = query.Where("id = ?", id)
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update Device: %w", err)
+		return 0, fmt.Errorf("failed to update devices: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *deviceStorage) updateQuery(updateData *DeviceUpdate) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update("devices")
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
+	if updateData.Name != nil {
+		query = query.Set("name", updateData.Name)
+	}
+	if updateData.Value != nil {
+		query = query.Set("value", updateData.Value)
+	}
+	if updateData.UserId != nil {
+		query = query.Set("user_id", updateData.UserId)
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
 }
 
-// DeleteMany removes entries from the devices table using the provided filters
-func (t *deviceStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
+// DeleteMany removes entries from the devices table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *deviceStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
 	query := t.queryBuilder.Delete("devices")
 
@@ -332,26 +647,167 @@ func (t *deviceStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilde
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for delete operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
 }
 
 // FindMany finds multiple Device based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. DeviceJoin<Relation>, are filled in the same query.
 func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Device, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Device
+	for rows.Next() {
+		model := &Device{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, fmt.Errorf("failed to scan Device: %w", err)
+		}
+		results = append(results, model)
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Device rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it.
+func (t *deviceStorage) FindEach(ctx context.Context, fn func(*Device) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		model := &Device{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return fmt.Errorf("failed to scan Device: %w", err)
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *deviceStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Device", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *deviceStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, fmt.Errorf("unknown column %q of Device", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *deviceStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *deviceStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Device: %w", err)
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *deviceStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -387,8 +843,34 @@ func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder)
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// findManyJoined finds the Device rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Device rows are merged.
+func (t *deviceStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Device, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
@@ -400,17 +882,77 @@ func (t *deviceStorage) FindMany(ctx context.Context, builders ...*QueryBuilder)
 	defer rows.Close()
 
 	var results []*Device
+	found := make(map[int64]*Device)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Device{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan Device: %w", err)
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Device: %w", err)
+		}
+
+		// the Device row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
 	}
 
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *deviceStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Device relation.
+func (t *deviceStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, fmt.Errorf("the join of the %s relation of %s can't be used with Device", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, fmt.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	}
+
+	return nil, fmt.Errorf("unknown relation %s of Device", join.relation)
+}
+
 // FindOne finds a single Device based on the provided options.
 func (t *deviceStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Device, error) {
 	// Use findMany but limit the results to 1
@@ -459,6 +1001,143 @@ func (t *deviceStorage) Count(ctx context.Context, builders ...*QueryBuilder) (i
 	return count, nil
 }
 
+// DeviceAggregateRow is a row of the Device Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type DeviceAggregateRow struct {
+	Device
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *DeviceAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// Aggregate groups the Device rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(DeviceColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *deviceStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*DeviceAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, fmt.Errorf("no aggregations of Device")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Device", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate Device: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*DeviceAggregateRow
+	for rows.Next() {
+		row := &DeviceAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Device.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Device aggregate: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Device rows found by the builders.
+func (t *deviceStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*DeviceAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &DeviceAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Device rows.
+func (t *deviceStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	}
+	return nil, fmt.Errorf("unsupported aggregation %s of Device", aggregation)
+}
+
 // FindManyWithPagination finds multiple Device with pagination support.
 func (t *deviceStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Device, *Paginator, error) {
 	// Count the total number of records
@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	"math"
+	"net/url"
 )
 
 // postStorage is a struct for the "posts" table.
@@ -24,17 +25,30 @@ type PostTableManager interface {
 	UpgradeTable(ctx context.Context) error
 }
 
+// PostSchemaVerification is an interface for verifying the posts table.
+type PostSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // PostCRUDOperations is an interface for managing the posts table.
 type PostCRUDOperations interface {
 	Create(ctx context.Context, model *Post, opts ...Option) (*int32, error)
-	Update(ctx context.Context, id int32, updateData *PostUpdate) error
-	DeleteById(ctx context.Context, id int32, opts ...Option) error
+	Upsert(ctx context.Context, model *Post, conflict *Conflict, opts ...Option) (*int32, error)
+	BatchUpsert(ctx context.Context, models []*Post, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id int32, updateData *PostUpdate) (int64, error)
+	UpdateReturning(ctx context.Context, id int32, updateData *PostUpdate) (*Post, error)
+	UpdateMany(ctx context.Context, updateData *PostUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id int32, opts ...Option) (*Post, error)
 }
 
 // PostSearchOperations is an interface for searching the posts table.
 type PostSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Post, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Post) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Post, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Post, error)
@@ -43,6 +57,16 @@ type PostSearchOperations interface {
 // PostPaginationOperations is an interface for pagination operations.
 type PostPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Post, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Post, *CursorPaginator, error)
+}
+
+// PostAggregateOperations is an interface for the aggregate queries.
+type PostAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*PostAggregateRow, error)
+	SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
 }
 
 // PostRelationLoading is an interface for loading relations.
@@ -53,7 +77,7 @@ type PostRelationLoading interface {
 
 // PostAdvancedDeletion is an interface for advanced deletion operations.
 type PostAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // PostRawQueryOperations is an interface for executing raw queries.
@@ -66,9 +90,11 @@ type PostRawQueryOperations interface {
 // PostStorage is a struct for the "posts" table.
 type PostStorage interface {
 	PostTableManager
+	PostSchemaVerification
 	PostCRUDOperations
 	PostSearchOperations
 	PostPaginationOperations
+	PostAggregateOperations
 	PostRelationLoading
 	PostAdvancedDeletion
 	PostRawQueryOperations
@@ -136,22 +162,86 @@ func (t *postStorage) DropTable(ctx context.Context) error {
 	return err
 }
 
-// TruncateTable truncates the table.
+// TruncateTable truncates the table, sqlite has no TRUNCATE statement so all the rows are deleted.
 func (t *postStorage) TruncateTable(ctx context.Context) error {
 	sqlQuery := `
-		TRUNCATE TABLE posts;
+		DELETE FROM posts;
 	`
 
 	_, err := t.db.ExecContext(ctx, sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *postStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return fmt.Errorf("failed to introspect table: %w", err)
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.Name, err)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *postStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table: %w", err)
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect indexes: %w", err)
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *postStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "INTEGER", NotNull: false, Statement: ""},
+		{Name: "title", Type: "TEXT", NotNull: true, Statement: ""},
+		{Name: "body", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"posts\" ADD COLUMN \"body\" TEXT;"},
+		{Name: "author_id", Type: "TEXT", NotNull: true, Statement: ""},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *postStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "posts_author_id_unique_idx", Columns: []string{"author_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"posts_author_id_unique_idx\" ON \"posts\" (\"author_id\");"},
+		{Name: "posts_title_idx", Columns: []string{"title"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"posts_title_idx\" ON \"posts\" (\"title\");"},
+		{Name: "posts_author_id_idx", Columns: []string{"author_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"posts_author_id_idx\" ON \"posts\" (\"author_id\");"},
+	}
+}
+
 // LoadAuthor loads the Author relation.
 func (t *postStorage) LoadAuthor(ctx context.Context, model *Post, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -160,8 +250,7 @@ func (t *postStorage) LoadAuthor(ctx context.Context, model *Post, builders ...*
 
 	// NewUserStorage creates a new UserStorage.
 	s := NewUserStorage(t.db)
-
-	// Add the filter for the relation
+	// Add the filter for the relation without dereferencing
 	builders = append(builders, FilterBuilder(UserIdEq(model.AuthorId)))
 	relationModel, err := s.FindOne(ctx, builders...)
 	if err != nil {
@@ -174,9 +263,10 @@ func (t *postStorage) LoadAuthor(ctx context.Context, model *Post, builders ...*
 
 // LoadBatchAuthor loads the Author relation.
 func (t *postStorage) LoadBatchAuthor(ctx context.Context, items []*Post, builders ...*QueryBuilder) error {
-	requestItems := make([]interface{}, len(items))
-	for i, item := range items {
-		requestItems[i] = item.AuthorId
+	requestItems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		// Append the value directly for non-optional fields
+		requestItems = append(requestItems, item.AuthorId)
 	}
 
 	// NewUserStorage creates a new UserStorage.
@@ -196,6 +286,7 @@ func (t *postStorage) LoadBatchAuthor(ctx context.Context, items []*Post, builde
 
 	// Assign User to items
 	for _, item := range items {
+		// Assign the relation directly for non-optional fields
 		if v, ok := resultMap[item.AuthorId]; ok {
 			item.Author = v
 		}
@@ -204,6 +295,29 @@ func (t *postStorage) LoadBatchAuthor(ctx context.Context, items []*Post, builde
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *postStorage) preload(ctx context.Context, items []*Post, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Post, ...*QueryBuilder) error
+		switch relation {
+		case "Author":
+			load = t.LoadBatchAuthor
+		default:
+			return fmt.Errorf("unknown relation %s of Post", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return fmt.Errorf("failed to preload %s: %w", relation, err)
+		}
+	}
+
+	return nil
+}
+
 // Post is a struct for the "posts" table.
 type Post struct {
 	Id       int32  `db:"id"`
@@ -233,10 +347,110 @@ func (t *Post) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Post, the other fields are left empty.
+func (t *Post) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Post) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "title":
+			dest = append(dest, &t.Title)
+		case "body":
+			dest = append(dest, &t.Body)
+		case "author_id":
+			dest = append(dest, &t.AuthorId)
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Post columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	PostColumnId       Column = "id"
+	PostColumnTitle    Column = "title"
+	PostColumnBody     Column = "body"
+	PostColumnAuthorId Column = "author_id"
+)
+
 // PostFilters is a struct that holds filters for Post.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type PostFilters struct {
-	Id       *int32
-	AuthorId *string
+	Id         *int32
+	IdFrom     *int32
+	IdTo       *int32
+	IdIn       []int32
+	AuthorId   *string
+	AuthorIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *PostFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "id", Value: *f.IdFrom})
+	}
+	if f.IdTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "id", Value: *f.IdTo})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.AuthorId != nil {
+		filters = append(filters, EqualsCondition{Field: "author_id", Value: *f.AuthorId})
+	}
+	if f.AuthorIdIn != nil {
+		filters = append(filters, InCondition{Field: "author_id", Values: toInterface(f.AuthorIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParsePostFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParsePostFilters(values url.Values) (*PostFilters, error) {
+	f := &PostFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[int32](key, values[key])
+		case "id_from":
+			f.IdFrom, err = parseFilterValue[int32](key, values[key])
+		case "id_to":
+			f.IdTo, err = parseFilterValue[int32](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[int32](key, values[key])
+		case "author_id":
+			f.AuthorId, err = parseFilterValue[string](key, values[key])
+		case "author_id_in":
+			f.AuthorIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // PostIdEq returns a condition that checks if the field equals the value.
@@ -349,6 +563,12 @@ func PostAuthorIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("author_id", asc)
 }
 
+// PostHasAuthor checks if the Post has the Author relation matching the filters.
+func PostHasAuthor(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(PostColumnAuthorId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Post.
 func (t *postStorage) Create(ctx context.Context, model *Post, opts ...Option) (*int32, error) {
 	if model == nil {
@@ -390,47 +610,251 @@ func (t *postStorage) Create(ctx context.Context, model *Post, opts ...Option) (
 	return &id, nil
 }
 
+// postConflictTargets are the unique constraints of the posts table which the upsert can conflict on.
+var postConflictTargets = [][]Column{
+	{PostColumnAuthorId},
+}
+
+// postUpsertColumns are the columns inserted by the upsert.
+var postUpsertColumns = []upsertColumn{
+	{column: PostColumnTitle},
+	{column: PostColumnBody},
+	{column: PostColumnAuthorId},
+}
+
+// upsertValues returns the values of the model in the order of the postUpsertColumns.
+func (t *postStorage) upsertValues(model *Post) ([]interface{}, error) {
+
+	values := make([]interface{}, 0, len(postUpsertColumns))
+	values = append(values, model.Title)
+	values = append(values, model.Body)
+	values = append(values, model.AuthorId)
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *postStorage) upsertQuery(models []*Post, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix("posts", postConflictTargets, postUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert("posts").Columns(upsertColumnNames(postUpsertColumns)...)
+	for _, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Post or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(PostColumnAuthorId).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *postStorage) Upsert(ctx context.Context, model *Post, conflict *Conflict, opts ...Option) (*int32, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Post{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int32
+	err = t.DB(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert Post: %w", err)
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Post records in a single batch or handles their conflicts on the unique constraint.
+// It returns the primary keys of the created or the updated rows.
+func (t *postStorage) BatchUpsert(ctx context.Context, models []*Post, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute bulk upsert: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return ids, nil
+}
+
 // PostUpdate is used to update an existing Post.
 type PostUpdate struct {
 	Title    *string
 	Body     *string
 	AuthorId *string
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Post based on non-nil fields.
-func (t *postStorage) Update(ctx context.Context, id int32, updateData *PostUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
+// Update updates an existing Post based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *postStorage) Update(ctx context.Context, id int32, updateData *PostUpdate) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
 	}
+	query = query.Where("id = ?", id)
 
-	query := t.queryBuilder.Update("posts")
-	if updateData.Title != nil {
-		query = query.Set("title", updateData.Title)
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
-	if updateData.Body != nil {
-		query = query.Set("body", updateData.Body)
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update Post: %w", err)
 	}
-	if updateData.AuthorId != nil {
-		query = query.Set("author_id", updateData.AuthorId)
+
+	return rowsAffected(result)
+}
+
+// UpdateReturning updates an existing Post based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *postStorage) UpdateReturning(ctx context.Context, id int32, updateData *PostUpdate) (*Post, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return nil, err
 	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
-	query = query.Where("id = ?", id)
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Post: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to update Post: %w", err)
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &Post{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, fmt.Errorf("failed to scan Post: %w", err)
+	}
+
+	return model, nil
+}
+
+// UpdateMany updates the posts rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *postStorage) UpdateMany(ctx context.Context, updateData *PostUpdate, builders ...*QueryBuilder) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update Post: %w", err)
+		return 0, fmt.Errorf("failed to update posts: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *postStorage) updateQuery(updateData *PostUpdate) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update("posts")
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
+	if updateData.Title != nil {
+		query = query.Set("title", updateData.Title)
+	}
+	if updateData.Body != nil {
+		query = query.Set("body", updateData.Body)
+	}
+	if updateData.AuthorId != nil {
+		query = query.Set("author_id", updateData.AuthorId)
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
 }
 
 // DeleteById - deletes a Post by its id.
-func (t *postStorage) DeleteById(ctx context.Context, id int32, opts ...Option) error {
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *postStorage) DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
@@ -441,19 +865,20 @@ func (t *postStorage) DeleteById(ctx context.Context, id int32, opts ...Option)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Post: %w", err)
+		return 0, fmt.Errorf("failed to delete Post: %w", err)
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteMany removes entries from the posts table using the provided filters
-func (t *postStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
+// DeleteMany removes entries from the posts table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *postStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
 	query := t.queryBuilder.Delete("posts")
 
@@ -471,20 +896,24 @@ func (t *postStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder)
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for delete operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
 }
 
 // FindById retrieves a Post by its id.
@@ -505,9 +934,146 @@ func (t *postStorage) FindById(ctx context.Context, id int32, opts ...Option) (*
 }
 
 // FindMany finds multiple Post based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. PostJoin<Relation>, are filled in the same query.
 func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Post, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Post
+	for rows.Next() {
+		model := &Post{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, fmt.Errorf("failed to scan Post: %w", err)
+		}
+		results = append(results, model)
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Post rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it.
+func (t *postStorage) FindEach(ctx context.Context, fn func(*Post) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		model := &Post{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return fmt.Errorf("failed to scan Post: %w", err)
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *postStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Post", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *postStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, fmt.Errorf("unknown column %q of Post", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *postStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *postStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Post: %w", err)
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *postStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -543,8 +1109,46 @@ func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// PostJoinAuthor joins the Author relation filtered by the filters,
+// FindMany fills Post.Author with the joined rows in the same query.
+// The Post rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func PostJoinAuthor(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "posts",
+		relation: "Author",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Post rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Post rows are merged.
+func (t *postStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Post, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
@@ -556,17 +1160,99 @@ func (t *postStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) (
 	defer rows.Close()
 
 	var results []*Post
+	found := make(map[int64]*Post)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Post{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan Post: %w", err)
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Post: %w", err)
+		}
+
+		// the Post row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
 	}
 
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *postStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Post relation.
+func (t *postStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, fmt.Errorf("the join of the %s relation of %s can't be used with Post", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, fmt.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "Author":
+		s := &userStorage{db: t.db, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(PostColumnAuthorId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.TableName() + "." + column + " = " + t.TableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Post)
+					p.Author = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown relation %s of Post", join.relation)
+}
+
 // FindOne finds a single Post based on the provided options.
 func (t *postStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Post, error) {
 	// Use findMany but limit the results to 1
@@ -615,6 +1301,220 @@ func (t *postStorage) Count(ctx context.Context, builders ...*QueryBuilder) (int
 	return count, nil
 }
 
+// PostAggregateRow is a row of the Post Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type PostAggregateRow struct {
+	Post
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *PostAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumId returns the sum of the id values, it is set by Sum(PostColumnId).
+func (r *PostAggregateRow) SumId() int64 {
+	if v, ok := r.values[Sum(PostColumnId)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgId returns the average of the id values, it is set by Avg(PostColumnId).
+func (r *PostAggregateRow) AvgId() *float64 {
+	if v, ok := r.values[Avg(PostColumnId)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinId returns the smallest id value, it is set by Min(PostColumnId).
+func (r *PostAggregateRow) MinId() *int32 {
+	if v, ok := r.values[Min(PostColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxId returns the largest id value, it is set by Max(PostColumnId).
+func (r *PostAggregateRow) MaxId() *int32 {
+	if v, ok := r.values[Max(PostColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Post rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(PostColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *postStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*PostAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, fmt.Errorf("no aggregations of Post")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Post", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate Post: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*PostAggregateRow
+	for rows.Next() {
+		row := &PostAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Post.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Post aggregate: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Post rows found by the builders.
+func (t *postStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*PostAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &PostAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumId returns the sum of the id values of the Post rows found by the builders.
+func (t *postStorage) SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(PostColumnId), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumId(), nil
+}
+
+// AvgId returns the average of the id values of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgId(), nil
+}
+
+// MinId returns the smallest id value of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinId(), nil
+}
+
+// MaxId returns the largest id value of the Post rows found by the builders,
+// it is nil if there are no rows.
+func (t *postStorage) MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(PostColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxId(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Post rows.
+func (t *postStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(PostColumnId):
+		return new(*int64), nil
+	case Avg(PostColumnId):
+		return new(*float64), nil
+	case Min(PostColumnId), Max(PostColumnId):
+		return new(*int32), nil
+	}
+	return nil, fmt.Errorf("unsupported aggregation %s of Post", aggregation)
+}
+
 // FindManyWithPagination finds multiple Post with pagination support.
 func (t *postStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Post, *Paginator, error) {
 	// Count the total number of records
@@ -646,6 +1546,142 @@ func (t *postStorage) FindManyWithPagination(ctx context.Context, limit int, pag
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Post after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *postStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Post, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Post{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find Post: %w", err)
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Post row.
+func (t *postStorage) cursor(model *Post, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *postStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, fmt.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, fmt.Errorf("the column %q of Post can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *postStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "title":
+		return true
+	case "body":
+		return true
+	case "author_id":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Post for the given ID.
 func (t *postStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Post, error) {
 	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
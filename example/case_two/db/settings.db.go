@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	"math"
+	"net/url"
 )
 
 // settingStorage is a struct for the "settings" table.
@@ -24,17 +25,30 @@ type SettingTableManager interface {
 	UpgradeTable(ctx context.Context) error
 }
 
+// SettingSchemaVerification is an interface for verifying the settings table.
+type SettingSchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // SettingCRUDOperations is an interface for managing the settings table.
 type SettingCRUDOperations interface {
 	Create(ctx context.Context, model *Setting, opts ...Option) (*int32, error)
-	Update(ctx context.Context, id int32, updateData *SettingUpdate) error
-	DeleteById(ctx context.Context, id int32, opts ...Option) error
+	Upsert(ctx context.Context, model *Setting, conflict *Conflict, opts ...Option) (*int32, error)
+	BatchUpsert(ctx context.Context, models []*Setting, conflict *Conflict, opts ...Option) ([]string, error)
+	Update(ctx context.Context, id int32, updateData *SettingUpdate) (int64, error)
+	UpdateReturning(ctx context.Context, id int32, updateData *SettingUpdate) (*Setting, error)
+	UpdateMany(ctx context.Context, updateData *SettingUpdate, builders ...*QueryBuilder) (int64, error)
+	DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error)
 	FindById(ctx context.Context, id int32, opts ...Option) (*Setting, error)
 }
 
 // SettingSearchOperations is an interface for searching the settings table.
 type SettingSearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*Setting, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Setting, error)
@@ -43,6 +57,16 @@ type SettingSearchOperations interface {
 // SettingPaginationOperations is an interface for pagination operations.
 type SettingPaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Setting, *Paginator, error)
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Setting, *CursorPaginator, error)
+}
+
+// SettingAggregateOperations is an interface for the aggregate queries.
+type SettingAggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error)
+	SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error)
+	AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
+	MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error)
 }
 
 // SettingRelationLoading is an interface for loading relations.
@@ -53,7 +77,7 @@ type SettingRelationLoading interface {
 
 // SettingAdvancedDeletion is an interface for advanced deletion operations.
 type SettingAdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // SettingRawQueryOperations is an interface for executing raw queries.
@@ -66,9 +90,11 @@ type SettingRawQueryOperations interface {
 // SettingStorage is a struct for the "settings" table.
 type SettingStorage interface {
 	SettingTableManager
+	SettingSchemaVerification
 	SettingCRUDOperations
 	SettingSearchOperations
 	SettingPaginationOperations
+	SettingAggregateOperations
 	SettingRelationLoading
 	SettingAdvancedDeletion
 	SettingRawQueryOperations
@@ -136,22 +162,86 @@ func (t *settingStorage) DropTable(ctx context.Context) error {
 	return err
 }
 
-// TruncateTable truncates the table.
+// TruncateTable truncates the table, sqlite has no TRUNCATE statement so all the rows are deleted.
 func (t *settingStorage) TruncateTable(ctx context.Context) error {
 	sqlQuery := `
-		TRUNCATE TABLE settings;
+		DELETE FROM settings;
 	`
 
 	_, err := t.db.ExecContext(ctx, sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *settingStorage) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return fmt.Errorf("failed to introspect table: %w", err)
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.Name, err)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *settingStorage) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table: %w", err)
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect indexes: %w", err)
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *settingStorage) tableColumns() []tableColumn {
+	return []tableColumn{
+		{Name: "id", Type: "INTEGER", NotNull: false, Statement: ""},
+		{Name: "name", Type: "TEXT", NotNull: true, Statement: ""},
+		{Name: "value", Type: "TEXT", NotNull: false, Statement: "ALTER TABLE \"settings\" ADD COLUMN \"value\" TEXT;"},
+		{Name: "user_id", Type: "TEXT", NotNull: true, Statement: ""},
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *settingStorage) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{Name: "settings_user_id_unique_idx", Columns: []string{"user_id"}, Unique: true, Statement: "CREATE UNIQUE INDEX IF NOT EXISTS \"settings_user_id_unique_idx\" ON \"settings\" (\"user_id\");"},
+		{Name: "settings_name_idx", Columns: []string{"name"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"settings_name_idx\" ON \"settings\" (\"name\");"},
+		{Name: "settings_user_id_idx", Columns: []string{"user_id"}, Unique: false, Statement: "CREATE INDEX IF NOT EXISTS \"settings_user_id_idx\" ON \"settings\" (\"user_id\");"},
+	}
+}
+
 // LoadUser loads the User relation.
 func (t *settingStorage) LoadUser(ctx context.Context, model *Setting, builders ...*QueryBuilder) error {
 	if model == nil {
@@ -160,8 +250,7 @@ func (t *settingStorage) LoadUser(ctx context.Context, model *Setting, builders
 
 	// NewUserStorage creates a new UserStorage.
 	s := NewUserStorage(t.db)
-
-	// Add the filter for the relation
+	// Add the filter for the relation without dereferencing
 	builders = append(builders, FilterBuilder(UserIdEq(model.UserId)))
 	relationModel, err := s.FindOne(ctx, builders...)
 	if err != nil {
@@ -174,9 +263,10 @@ func (t *settingStorage) LoadUser(ctx context.Context, model *Setting, builders
 
 // LoadBatchUser loads the User relation.
 func (t *settingStorage) LoadBatchUser(ctx context.Context, items []*Setting, builders ...*QueryBuilder) error {
-	requestItems := make([]interface{}, len(items))
-	for i, item := range items {
-		requestItems[i] = item.UserId
+	requestItems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		// Append the value directly for non-optional fields
+		requestItems = append(requestItems, item.UserId)
 	}
 
 	// NewUserStorage creates a new UserStorage.
@@ -196,6 +286,7 @@ func (t *settingStorage) LoadBatchUser(ctx context.Context, items []*Setting, bu
 
 	// Assign User to items
 	for _, item := range items {
+		// Assign the relation directly for non-optional fields
 		if v, ok := resultMap[item.UserId]; ok {
 			item.User = v
 		}
@@ -204,6 +295,29 @@ func (t *settingStorage) LoadBatchUser(ctx context.Context, items []*Setting, bu
 	return nil
 }
 
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *settingStorage) preload(ctx context.Context, items []*Setting, builders ...*QueryBuilder) error {
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*Setting, ...*QueryBuilder) error
+		switch relation {
+		case "User":
+			load = t.LoadBatchUser
+		default:
+			return fmt.Errorf("unknown relation %s of Setting", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return fmt.Errorf("failed to preload %s: %w", relation, err)
+		}
+	}
+
+	return nil
+}
+
 // Setting is a struct for the "settings" table.
 type Setting struct {
 	Id     int32  `db:"id"`
@@ -233,10 +347,110 @@ func (t *Setting) ScanRows(r *sql.Rows) error {
 	)
 }
 
+// ScanColumns scans the given columns of the row into the Setting, the other fields are left empty.
+func (t *Setting) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *Setting) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &t.Id)
+		case "name":
+			dest = append(dest, &t.Name)
+		case "value":
+			dest = append(dest, &t.Value)
+		case "user_id":
+			dest = append(dest, &t.UserId)
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// Setting columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	SettingColumnId     Column = "id"
+	SettingColumnName   Column = "name"
+	SettingColumnValue  Column = "value"
+	SettingColumnUserId Column = "user_id"
+)
+
 // SettingFilters is a struct that holds filters for Setting.
+// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 type SettingFilters struct {
-	Id     *int32
-	UserId *string
+	Id       *int32
+	IdFrom   *int32
+	IdTo     *int32
+	IdIn     []int32
+	UserId   *string
+	UserIdIn []string
+}
+
+// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+func (f *SettingFilters) Apply() *QueryBuilder {
+	if f == nil {
+		return NewQueryBuilder()
+	}
+	var filters []FilterApplier
+	if f.Id != nil {
+		filters = append(filters, EqualsCondition{Field: "id", Value: *f.Id})
+	}
+	if f.IdFrom != nil {
+		filters = append(filters, GreaterThanOrEqualCondition{Field: "id", Value: *f.IdFrom})
+	}
+	if f.IdTo != nil {
+		filters = append(filters, LessThanOrEqualCondition{Field: "id", Value: *f.IdTo})
+	}
+	if f.IdIn != nil {
+		filters = append(filters, InCondition{Field: "id", Values: toInterface(f.IdIn)})
+	}
+	if f.UserId != nil {
+		filters = append(filters, EqualsCondition{Field: "user_id", Value: *f.UserId})
+	}
+	if f.UserIdIn != nil {
+		filters = append(filters, InCondition{Field: "user_id", Values: toInterface(f.UserIdIn)})
+	}
+	return FilterBuilder(filters...)
+}
+
+// ParseSettingFilters parses the filters from the query string values, e.g. of an HTTP list request.
+// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+// The unknown keys, the repeated keys of the single values and the invalid values are errors.
+func ParseSettingFilters(values url.Values) (*SettingFilters, error) {
+	f := &SettingFilters{}
+	for _, key := range filterKeys(values) {
+		var err error
+		switch key {
+		case "id":
+			f.Id, err = parseFilterValue[int32](key, values[key])
+		case "id_from":
+			f.IdFrom, err = parseFilterValue[int32](key, values[key])
+		case "id_to":
+			f.IdTo, err = parseFilterValue[int32](key, values[key])
+		case "id_in":
+			f.IdIn, err = parseFilterValues[int32](key, values[key])
+		case "user_id":
+			f.UserId, err = parseFilterValue[string](key, values[key])
+		case "user_id_in":
+			f.UserIdIn, err = parseFilterValues[string](key, values[key])
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
 
 // SettingIdEq returns a condition that checks if the field equals the value.
@@ -349,6 +563,12 @@ func SettingUserIdOrderBy(asc bool) FilterApplier {
 	return OrderBy("user_id", asc)
 }
 
+// SettingHasUser checks if the Setting has the User relation matching the filters.
+func SettingHasUser(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string(UserColumnId)).From((&User{}).TableName())
+	return InSubqueryCondition{Field: string(SettingColumnUserId), Query: query, Conditions: filters}
+}
+
 // Create creates a new Setting.
 func (t *settingStorage) Create(ctx context.Context, model *Setting, opts ...Option) (*int32, error) {
 	if model == nil {
@@ -390,47 +610,251 @@ func (t *settingStorage) Create(ctx context.Context, model *Setting, opts ...Opt
 	return &id, nil
 }
 
+// settingConflictTargets are the unique constraints of the settings table which the upsert can conflict on.
+var settingConflictTargets = [][]Column{
+	{SettingColumnUserId},
+}
+
+// settingUpsertColumns are the columns inserted by the upsert.
+var settingUpsertColumns = []upsertColumn{
+	{column: SettingColumnName},
+	{column: SettingColumnValue},
+	{column: SettingColumnUserId},
+}
+
+// upsertValues returns the values of the model in the order of the settingUpsertColumns.
+func (t *settingStorage) upsertValues(model *Setting) ([]interface{}, error) {
+
+	values := make([]interface{}, 0, len(settingUpsertColumns))
+	values = append(values, model.Name)
+	values = append(values, model.Value)
+	values = append(values, model.UserId)
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *settingStorage) upsertQuery(models []*Setting, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix("settings", settingConflictTargets, settingUpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert("settings").Columns(upsertColumnNames(settingUpsertColumns)...)
+	for _, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the Setting or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict(SettingColumnUserId).DoUpdate()).
+// It returns the primary key of the created or the updated row.
+func (t *settingStorage) Upsert(ctx context.Context, model *Setting, conflict *Conflict, opts ...Option) (*int32, error) {
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*Setting{model}, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int32
+	err = t.DB(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert Setting: %w", err)
+	}
+
+	return &id, nil
+}
+
+// BatchUpsert creates multiple Setting records in a single batch or handles their conflicts on the unique constraint.
+// It returns the primary keys of the created or the updated rows.
+func (t *settingStorage) BatchUpsert(ctx context.Context, models []*Setting, conflict *Conflict, opts ...Option) ([]string, error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute bulk upsert: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return ids, nil
+}
+
 // SettingUpdate is used to update an existing Setting.
 type SettingUpdate struct {
 	Name   *string
 	Value  *string
 	UserId *string
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
 }
 
-// Update updates an existing Setting based on non-nil fields.
-func (t *settingStorage) Update(ctx context.Context, id int32, updateData *SettingUpdate) error {
-	if updateData == nil {
-		return errors.New("update data is nil")
+// Update updates an existing Setting based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) Update(ctx context.Context, id int32, updateData *SettingUpdate) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
 	}
+	query = query.Where("id = ?", id)
 
-	query := t.queryBuilder.Update("settings")
-	if updateData.Name != nil {
-		query = query.Set("name", updateData.Name)
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
-	if updateData.Value != nil {
-		query = query.Set("value", updateData.Value)
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update Setting: %w", err)
 	}
-	if updateData.UserId != nil {
-		query = query.Set("user_id", updateData.UserId)
+
+	return rowsAffected(result)
+}
+
+// UpdateReturning updates an existing Setting based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) UpdateReturning(ctx context.Context, id int32, updateData *SettingUpdate) (*Setting, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return nil, err
 	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
-	query = query.Where("id = ?", id)
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Setting: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to update Setting: %w", err)
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &Setting{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, fmt.Errorf("failed to scan Setting: %w", err)
+	}
+
+	return model, nil
+}
+
+// UpdateMany updates the settings rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *settingStorage) UpdateMany(ctx context.Context, updateData *SettingUpdate, builders ...*QueryBuilder) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update Setting: %w", err)
+		return 0, fmt.Errorf("failed to update settings: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *settingStorage) updateQuery(updateData *SettingUpdate) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update("settings")
+	if updateData == nil {
+		return query, errors.New("update data is nil")
+	}
+	if updateData.Name != nil {
+		query = query.Set("name", updateData.Name)
+	}
+	if updateData.Value != nil {
+		query = query.Set("value", updateData.Value)
+	}
+	if updateData.UserId != nil {
+		query = query.Set("user_id", updateData.UserId)
+	}
+
+	return setExpressions(query, updateData.Expressions), nil
 }
 
 // DeleteById - deletes a Setting by its id.
-func (t *settingStorage) DeleteById(ctx context.Context, id int32, opts ...Option) error {
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the id.
+func (t *settingStorage) DeleteById(ctx context.Context, id int32, opts ...Option) (int64, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
@@ -441,19 +865,20 @@ func (t *settingStorage) DeleteById(ctx context.Context, id int32, opts ...Optio
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Setting: %w", err)
+		return 0, fmt.Errorf("failed to delete Setting: %w", err)
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 
-// DeleteMany removes entries from the settings table using the provided filters
-func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
+// DeleteMany removes entries from the settings table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
 	query := t.queryBuilder.Delete("settings")
 
@@ -471,20 +896,24 @@ func (t *settingStorage) DeleteMany(ctx context.Context, builders ...*QueryBuild
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for delete operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
 }
 
 // FindById retrieves a Setting by its id.
@@ -505,9 +934,146 @@ func (t *settingStorage) FindById(ctx context.Context, id int32, opts ...Option)
 }
 
 // FindMany finds multiple Setting based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. SettingJoin<Relation>, are filled in the same query.
 func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*Setting, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Setting
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, fmt.Errorf("failed to scan Setting: %w", err)
+		}
+		results = append(results, model)
+	}
+
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the Setting rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it.
+func (t *settingStorage) FindEach(ctx context.Context, fn func(*Setting) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		model := &Setting{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return fmt.Errorf("failed to scan Setting: %w", err)
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *settingStorage) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Setting", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *settingStorage) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, fmt.Errorf("unknown column %q of Setting", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *settingStorage) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *settingStorage) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Setting: %w", err)
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *settingStorage) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -543,8 +1109,46 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+
+// SettingJoinUser joins the User relation filtered by the filters,
+// FindMany fills Setting.User with the joined rows in the same query.
+// The Setting rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func SettingJoinUser(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    "settings",
+		relation: "User",
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+
+// findManyJoined finds the Setting rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated Setting rows are merged.
+func (t *settingStorage) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*Setting, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
@@ -556,17 +1160,99 @@ func (t *settingStorage) FindMany(ctx context.Context, builders ...*QueryBuilder
 	defer rows.Close()
 
 	var results []*Setting
+	found := make(map[int64]*Setting)
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &Setting{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan Setting: %w", err)
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Setting: %w", err)
+		}
+
+		// the Setting row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
 	}
 
 	return results, nil
 }
 
+// hasSelected returns true if the column is in the selected columns.
+func (t *settingStorage) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a Setting relation.
+func (t *settingStorage) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, fmt.Errorf("the join of the %s relation of %s can't be used with Setting", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, fmt.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	case "User":
+		s := &userStorage{db: t.db, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string(UserColumnId)
+		ref := string(SettingColumnUserId)
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.TableName() + "." + column + " = " + t.TableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &User{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*Setting)
+					p.User = model
+				}
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown relation %s of Setting", join.relation)
+}
+
 // FindOne finds a single Setting based on the provided options.
 func (t *settingStorage) FindOne(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
 	// Use findMany but limit the results to 1
@@ -615,6 +1301,220 @@ func (t *settingStorage) Count(ctx context.Context, builders ...*QueryBuilder) (
 	return count, nil
 }
 
+// SettingAggregateRow is a row of the Setting Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type SettingAggregateRow struct {
+	Setting
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *SettingAggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+
+// SumId returns the sum of the id values, it is set by Sum(SettingColumnId).
+func (r *SettingAggregateRow) SumId() int64 {
+	if v, ok := r.values[Sum(SettingColumnId)].(**int64); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// AvgId returns the average of the id values, it is set by Avg(SettingColumnId).
+func (r *SettingAggregateRow) AvgId() *float64 {
+	if v, ok := r.values[Avg(SettingColumnId)].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+
+// MinId returns the smallest id value, it is set by Min(SettingColumnId).
+func (r *SettingAggregateRow) MinId() *int32 {
+	if v, ok := r.values[Min(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// MaxId returns the largest id value, it is set by Max(SettingColumnId).
+func (r *SettingAggregateRow) MaxId() *int32 {
+	if v, ok := r.values[Max(SettingColumnId)].(**int32); ok {
+		return *v
+	}
+	return nil
+}
+
+// Aggregate groups the Setting rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy(SettingColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *settingStorage) Aggregate(ctx context.Context, options ...AggregateOption) ([]*SettingAggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, fmt.Errorf("no aggregations of Setting")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of Setting", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate Setting: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*SettingAggregateRow
+	for rows.Next() {
+		row := &SettingAggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.Setting.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan Setting aggregate: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the Setting rows found by the builders.
+func (t *settingStorage) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*SettingAggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &SettingAggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+
+// SumId returns the sum of the id values of the Setting rows found by the builders.
+func (t *settingStorage) SumId(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	row, err := t.aggregate(ctx, Sum(SettingColumnId), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.SumId(), nil
+}
+
+// AvgId returns the average of the id values of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) AvgId(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.AvgId(), nil
+}
+
+// MinId returns the smallest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MinId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Min(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MinId(), nil
+}
+
+// MaxId returns the largest id value of the Setting rows found by the builders,
+// it is nil if there are no rows.
+func (t *settingStorage) MaxId(ctx context.Context, builders ...*QueryBuilder) (*int32, error) {
+	row, err := t.aggregate(ctx, Max(SettingColumnId), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.MaxId(), nil
+}
+
+// aggregateDest returns the scan destination of the aggregation of the Setting rows.
+func (t *settingStorage) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	case Sum(SettingColumnId):
+		return new(*int64), nil
+	case Avg(SettingColumnId):
+		return new(*float64), nil
+	case Min(SettingColumnId), Max(SettingColumnId):
+		return new(*int32), nil
+	}
+	return nil, fmt.Errorf("unsupported aggregation %s of Setting", aggregation)
+}
+
 // FindManyWithPagination finds multiple Setting with pagination support.
 func (t *settingStorage) FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*Setting, *Paginator, error) {
 	// Count the total number of records
@@ -646,6 +1546,142 @@ func (t *settingStorage) FindManyWithPagination(ctx context.Context, limit int,
 	return records, paginator, nil
 }
 
+// FindManyAfter finds a page of Setting after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *settingStorage) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*Setting, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&Setting{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find Setting: %w", err)
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the Setting row.
+func (t *settingStorage) cursor(model *Setting, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *settingStorage) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, fmt.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, fmt.Errorf("the column %q of Setting can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == "id" {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: "id", Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *settingStorage) isCursorColumn(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "name":
+		return true
+	case "value":
+		return true
+	case "user_id":
+		return true
+	}
+	return false
+}
+
 // SelectForUpdate lock locks the Setting for the given ID.
 func (t *settingStorage) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*Setting, error) {
 	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
@@ -1,10 +1,23 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/cjp2600/protoc-gen-structify/plugin"
+	"github.com/cjp2600/protoc-gen-structify/plugin/importer"
 )
 
 func main() {
+	// structify import generates the proto definitions from an existing database.
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := importer.Run(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Run the plugin
 	// This will read the request from stdin, and write the response to stdout
 	plugin.NewPlugin().Run()
@@ -2,11 +2,13 @@ package generator
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
 
 	"github.com/cjp2600/protoc-gen-structify/plugin/provider"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 	plugingo "github.com/golang/protobuf/protoc-gen-go/plugin"
 )
@@ -99,9 +101,35 @@ func (c *contentGenerator) Files() ([]*plugingo.CodeGeneratorResponse_File, erro
 		})
 	}
 
+	// append the schema DDL file next to the base file
+	if c.state.EmitSchema {
+		schemaFile, err := c.buildSchemaFile(path.Dir(baseFileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build schema: %w", err)
+		}
+		result = append(result, schemaFile)
+	}
+
 	return result, nil
 }
 
+// buildSchemaFile builds the DDL file of the schema in the dialect of the provider.
+// Example:
+//
+//	example.schema.sql
+func (c *contentGenerator) buildSchemaFile(dir string) (*plugingo.CodeGeneratorResponse_File, error) {
+	dialect, err := schemapkg.NewDialect(provider.ParseFromString(c.state.Provider).String())
+	if err != nil {
+		return nil, err
+	}
+
+	s := schemapkg.Build(c.state, dialect)
+	return &plugingo.CodeGeneratorResponse_File{
+		Name:    proto.String(path.Join(dir, c.request.BaseFileName+schemapkg.SchemaFileSuffix)),
+		Content: proto.String(schemapkg.DDL(dialect, s, c.state.FileToGenerate)),
+	}, nil
+}
+
 // buildTemplater builds the templater.
 func (c *contentGenerator) buildTemplater(temps ...statepkg.Templater) string {
 	var builder strings.Builder
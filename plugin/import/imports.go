@@ -67,6 +67,8 @@ var (
 	ImportLibSqlite3        = Import{"github.com/mattn/go-sqlite3", "_"}
 	ImportLibSqlite3WOAlias = Import{"github.com/mattn/go-sqlite3", ""}
 	ImportStrings           = Import{"strings", ""}
+	ImportRegexp            = Import{"regexp", ""}
+	ImportUTF8              = Import{"unicode/utf8", ""}
 	ImportMath              = Import{"math", ""}
 	ImportSquirrel          = Import{"github.com/Masterminds/squirrel", "sq"}
 	ImportNull              = Import{"gopkg.in/guregu/null.v4", ""}
@@ -75,10 +77,25 @@ var (
 	ImportContext           = Import{"context", ""}
 	ImportStrconv           = Import{"strconv", ""}
 	ImportSync              = Import{"sync", ""}
+	ImportAtomic            = Import{"sync/atomic", ""}
 	ImportTime              = Import{"time", ""}
 	ImportJson              = Import{"encoding/json", ""}
+	ImportBase64            = Import{"encoding/base64", ""}
+	ImportHex               = Import{"encoding/hex", ""}
+	ImportCryptoAES         = Import{"crypto/aes", ""}
+	ImportCryptoCipher      = Import{"crypto/cipher", ""}
+	ImportCryptoHMAC        = Import{"crypto/hmac", ""}
+	ImportCryptoRand        = Import{"crypto/rand", ""}
+	ImportCryptoSHA256      = Import{"crypto/sha256", ""}
 	ImportSQLDriver         = Import{"database/sql/driver", ""}
 	ImportGoogleUUID        = Import{"github.com/google/uuid", ""}
 	ImportClickhouse        = Import{"github.com/ClickHouse/clickhouse-go/v2", ""}
 	ImportClickhouseDriver  = Import{"github.com/ClickHouse/clickhouse-go/v2/lib/driver", ""}
+	ImportBytes             = Import{"bytes", ""}
+	ImportIO                = Import{"io", ""}
+	ImportIOFS              = Import{"io/fs", ""}
+	ImportYAML              = Import{"gopkg.in/yaml.v3", ""}
+	ImportReflect           = Import{"reflect", ""}
+	ImportSort              = Import{"sort", ""}
+	ImportURL               = Import{"net/url", ""}
 )
@@ -0,0 +1,340 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
+)
+
+var (
+	// createTableRegexp matches the CREATE TABLE statement.
+	createTableRegexp = regexp.MustCompile(`(?is)^CREATE\s+(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s*\((.*)\)`)
+	// alterTableRegexp matches the ALTER TABLE statement.
+	alterTableRegexp = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(\S+)\s+(.*)$`)
+	// addConstraintRegexp matches the ADD CONSTRAINT action.
+	addConstraintRegexp = regexp.MustCompile(`(?is)^ADD\s+CONSTRAINT\s+(\S+)\s+(.*)$`)
+	// setDefaultRegexp matches the ALTER COLUMN ... SET DEFAULT action.
+	setDefaultRegexp = regexp.MustCompile(`(?is)^ALTER\s+COLUMN\s+(\S+)\s+SET\s+DEFAULT\s+(.*)$`)
+	// createIndexRegexp matches the CREATE INDEX statement.
+	createIndexRegexp = regexp.MustCompile(`(?is)^CREATE\s+(UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s+ON\s+(?:ONLY\s+)?(\S+)\s+(?:USING\s+\w+\s*)?\((.*)\)\s*$`)
+	// commentRegexp matches the COMMENT ON TABLE statement.
+	commentRegexp = regexp.MustCompile(`(?is)^COMMENT\s+ON\s+TABLE\s+(\S+)\s+IS\s+'(.*)'$`)
+	// primaryKeyRegexp matches the PRIMARY KEY constraint.
+	primaryKeyRegexp = regexp.MustCompile(`(?is)^PRIMARY\s+KEY\s*\((.*)\)`)
+	// uniqueRegexp matches the UNIQUE constraint.
+	uniqueRegexp = regexp.MustCompile(`(?is)^UNIQUE\s*\((.*)\)`)
+	// foreignKeyRegexp matches the FOREIGN KEY constraint.
+	foreignKeyRegexp = regexp.MustCompile(`(?is)^FOREIGN\s+KEY\s*\((.*?)\)\s*REFERENCES\s+([^\s(]+)\s*\((.*?)\)(.*)$`)
+	// castRegexp matches the literal with the type cast.
+	castRegexp = regexp.MustCompile(`^('(?:[^']|'')*')::[\w\s."\[\]]+$`)
+	// onDeleteCascadeRegexp matches the ON DELETE CASCADE action of the foreign key.
+	onDeleteCascadeRegexp = regexp.MustCompile(`(?i)ON\s+DELETE\s+CASCADE`)
+)
+
+// columnKeywords are the keywords which end the column type.
+var columnKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "CHECK": true, "COLLATE": true, "GENERATED": true,
+}
+
+// ParseDump parses the tables of the schema from the output of pg_dump --schema-only.
+func ParseDump(r io.Reader, schema string) (*schemapkg.Schema, error) {
+	if schema == "" {
+		schema = "public"
+	}
+
+	statements, err := splitStatements(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schemapkg.Schema{Provider: "postgres"}
+	table := func(ident string) *schemapkg.Table {
+		tableSchema, name := splitIdent(ident)
+		if tableSchema != "" && tableSchema != schema {
+			return nil
+		}
+		for _, t := range s.Tables {
+			if t.Name == name {
+				return t
+			}
+		}
+		return nil
+	}
+
+	for _, statement := range statements {
+		if matches := createTableRegexp.FindStringSubmatch(statement); matches != nil {
+			tableSchema, name := splitIdent(matches[1])
+			if tableSchema != "" && tableSchema != schema {
+				continue
+			}
+			t := &schemapkg.Table{Name: name}
+			if schema != "public" {
+				t.Schema = schema
+			}
+			if err := parseTableBody(t, matches[2]); err != nil {
+				return nil, fmt.Errorf("failed to parse table %s: %w", name, err)
+			}
+			s.Tables = append(s.Tables, t)
+		}
+	}
+
+	for _, statement := range statements {
+		switch {
+		case alterTableRegexp.MatchString(statement):
+			matches := alterTableRegexp.FindStringSubmatch(statement)
+			if t := table(matches[1]); t != nil {
+				parseAlterTable(t, matches[2])
+			}
+		case createIndexRegexp.MatchString(statement):
+			matches := createIndexRegexp.FindStringSubmatch(statement)
+			if t := table(matches[3]); t != nil {
+				_, name := splitIdent(matches[2])
+				if columns := identList(matches[4]); columns != nil {
+					addIndex(t, name, columns, matches[1] != "", false)
+				}
+			}
+		case commentRegexp.MatchString(statement):
+			matches := commentRegexp.FindStringSubmatch(statement)
+			if t := table(matches[1]); t != nil {
+				t.Comment = strings.ReplaceAll(matches[2], "''", "'")
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// splitStatements splits the dump into the statements without the comments.
+func splitStatements(r io.Reader) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString(" ")
+
+		if strings.HasSuffix(line, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+
+	return statements, nil
+}
+
+// parseTableBody parses the columns and the constraints of the CREATE TABLE statement.
+func parseTableBody(t *schemapkg.Table, body string) error {
+	for _, definition := range splitTopLevel(body) {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		upper := strings.ToUpper(definition)
+		switch {
+		case strings.HasPrefix(upper, "CONSTRAINT "):
+			fields := strings.Fields(definition)
+			if len(fields) < 3 {
+				return fmt.Errorf("invalid constraint %q", definition)
+			}
+			_, name := splitIdent(fields[1])
+			parseConstraint(t, name, strings.Join(fields[2:], " "))
+		case strings.HasPrefix(upper, "PRIMARY KEY"), strings.HasPrefix(upper, "UNIQUE"), strings.HasPrefix(upper, "FOREIGN KEY"):
+			parseConstraint(t, "", definition)
+		case strings.HasPrefix(upper, "CHECK"), strings.HasPrefix(upper, "EXCLUDE"):
+			continue
+		default:
+			parseColumn(t, definition)
+		}
+	}
+	return nil
+}
+
+// parseColumn parses the column definition.
+func parseColumn(t *schemapkg.Table, definition string) {
+	fields := strings.Fields(definition)
+	_, name := splitIdent(fields[0])
+
+	i := 1
+	var typ []string
+	for ; i < len(fields) && !columnKeywords[strings.ToUpper(fields[i])]; i++ {
+		typ = append(typ, fields[i])
+	}
+
+	var notNull, primaryKey, unique bool
+	var def string
+	for ; i < len(fields); i++ {
+		switch strings.ToUpper(fields[i]) {
+		case "NOT":
+			notNull = true
+		case "PRIMARY":
+			primaryKey = true
+		case "UNIQUE":
+			unique = true
+		case "DEFAULT":
+			var expression []string
+			for i++; i < len(fields) && !columnKeywords[strings.ToUpper(fields[i])]; i++ {
+				expression = append(expression, fields[i])
+			}
+			def = strings.Join(expression, " ")
+			i--
+		}
+	}
+
+	c := postgresColumn(name, strings.ToLower(strings.Join(typ, " ")), notNull || primaryKey, def)
+	c.PrimaryKey = primaryKey
+	t.Columns = append(t.Columns, c)
+	if unique {
+		addIndex(t, t.Name+"_"+name+"_key", []string{name}, true, false)
+	}
+}
+
+// parseAlterTable parses the ALTER TABLE actions which pg_dump emits.
+func parseAlterTable(t *schemapkg.Table, action string) {
+	if matches := addConstraintRegexp.FindStringSubmatch(action); matches != nil {
+		_, name := splitIdent(matches[1])
+		parseConstraint(t, name, matches[2])
+		return
+	}
+
+	if matches := setDefaultRegexp.FindStringSubmatch(action); matches != nil {
+		_, name := splitIdent(matches[1])
+		if c := t.Column(name); c != nil {
+			def := postgresColumn(c.Name, c.Type, c.NotNull, matches[2])
+			c.Default, c.AutoIncrement = def.Default, def.AutoIncrement
+		}
+	}
+}
+
+// parseConstraint parses the table constraint.
+func parseConstraint(t *schemapkg.Table, name, constraint string) {
+	constraint = strings.TrimSpace(constraint)
+
+	if matches := primaryKeyRegexp.FindStringSubmatch(constraint); matches != nil {
+		addIndex(t, name, identList(matches[1]), true, true)
+		for _, column := range identList(matches[1]) {
+			if c := t.Column(column); c != nil {
+				c.NotNull = true
+			}
+		}
+		return
+	}
+
+	if matches := uniqueRegexp.FindStringSubmatch(constraint); matches != nil {
+		if name == "" {
+			name = t.Name + "_" + strings.Join(identList(matches[1]), "_") + "_key"
+		}
+		addIndex(t, name, identList(matches[1]), true, false)
+		return
+	}
+
+	if matches := foreignKeyRegexp.FindStringSubmatch(constraint); matches != nil {
+		columns, refColumns := identList(matches[1]), identList(matches[3])
+		if len(columns) != 1 || len(refColumns) != 1 {
+			return
+		}
+		refSchema, refTable := splitIdent(matches[2])
+		if refSchema == "public" {
+			refSchema = ""
+		}
+		if name == "" {
+			name = t.Name + "_" + columns[0] + "_fkey"
+		}
+		t.ForeignKeys = append(t.ForeignKeys, &schemapkg.ForeignKey{
+			Name:      name,
+			Column:    columns[0],
+			RefSchema: refSchema,
+			RefTable:  refTable,
+			RefColumn: refColumns[0],
+			Cascade:   onDeleteCascadeRegexp.MatchString(matches[4]),
+		})
+	}
+}
+
+// postgresDefault returns the default value without the type cast of the literal.
+func postgresDefault(def string) string {
+	def = strings.TrimSpace(def)
+	if matches := castRegexp.FindStringSubmatch(def); matches != nil {
+		return matches[1]
+	}
+	return def
+}
+
+// splitTopLevel splits the list by the commas which are not in the parentheses or the quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	var depth int
+	var quoted bool
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// identList returns the identifiers of the comma separated list,
+// nil is returned if any item is an expression.
+func identList(s string) []string {
+	var idents []string
+	for _, item := range splitTopLevel(s) {
+		fields := strings.Fields(item)
+		if len(fields) == 0 || strings.ContainsAny(fields[0], "()") {
+			return nil
+		}
+		_, name := splitIdent(fields[0])
+		idents = append(idents, name)
+	}
+	return idents
+}
+
+// splitIdent splits the optionally qualified and quoted identifier into the schema and the name.
+func splitIdent(ident string) (string, string) {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	for i := 0; i < len(ident); i++ {
+		switch c := ident[i]; {
+		case c == '"' && quoted && i+1 < len(ident) && ident[i+1] == '"':
+			current.WriteByte('"')
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	parts = append(parts, current.String())
+
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
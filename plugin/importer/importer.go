@@ -0,0 +1,156 @@
+// Package importer reverse-engineers the structify proto definitions from an existing database.
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
+)
+
+// Config is the configuration of the import.
+type Config struct {
+	// DSN is the database url, postgres://... for postgres, sqlite://path or file:path for sqlite.
+	// The sqlite driver requires cgo, so sqlite is imported only by the binary built with CGO_ENABLED=1.
+	DSN string
+	// DumpFile is the output of pg_dump --schema-only, it is read instead of the database.
+	DumpFile string
+	// Schema is the postgres schema to import.
+	Schema string
+	// Tables limits the import to the given tables.
+	Tables []string
+	// Package is the proto package of the generated file.
+	Package string
+	// GoPackage is the go_package option of the generated file.
+	GoPackage string
+	// URLEnv is the environment variable of the database url in the structify.db option.
+	URLEnv string
+}
+
+// Run runs the import command with the command line arguments
+// and writes the proto file to the output.
+func Run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	config := Config{}
+	var tables, output string
+	fs.StringVar(&config.DSN, "dsn", os.Getenv("DATABASE_URL"), "database url, defaults to $DATABASE_URL")
+	fs.StringVar(&config.DumpFile, "dump", "", "pg_dump --schema-only file to read instead of the database")
+	fs.StringVar(&config.Schema, "schema", "public", "postgres schema to import")
+	fs.StringVar(&tables, "tables", "", "comma separated tables to import, all tables by default")
+	fs.StringVar(&config.Package, "package", "db", "proto package")
+	fs.StringVar(&config.GoPackage, "go_package", "", "go_package option")
+	fs.StringVar(&config.URLEnv, "url_env", "DATABASE_URL", "environment variable of the database url")
+	fs.StringVar(&output, "o", "", "output file, stdout by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if tables != "" {
+		config.Tables = strings.Split(tables, ",")
+	}
+
+	s, err := Read(context.Background(), config)
+	if err != nil {
+		return err
+	}
+
+	content, err := Render(s, config)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = stdout.Write(content)
+		return err
+	}
+	return os.WriteFile(output, content, 0o644)
+}
+
+// Read reads the schema from the dump file or the database.
+func Read(ctx context.Context, config Config) (*schemapkg.Schema, error) {
+	var s *schemapkg.Schema
+	switch {
+	case config.DumpFile != "":
+		f, err := os.Open(config.DumpFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dump: %w", err)
+		}
+		defer f.Close()
+
+		s, err = ParseDump(f, config.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dump: %w", err)
+		}
+	case config.DSN != "":
+		driver, dsn := parseDSN(config.DSN)
+		if driver == "sqlite3" && !sqliteSupported {
+			return nil, fmt.Errorf("the sqlite import requires protoc-gen-structify built with cgo, reinstall it with CGO_ENABLED=1")
+		}
+		db, err := sql.Open(driver, dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		defer db.Close()
+
+		if driver == "postgres" {
+			s, err = ReadPostgres(ctx, db, config.Schema)
+		} else {
+			s, err = ReadSQLite(ctx, db)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("either the dsn or the dump file is required")
+	}
+
+	if len(config.Tables) > 0 {
+		s.Tables = filterTables(s.Tables, config.Tables)
+	}
+	if len(s.Tables) == 0 {
+		return nil, fmt.Errorf("no tables found")
+	}
+	return s, nil
+}
+
+// parseDSN returns the sql driver and the data source name of the database url.
+func parseDSN(dsn string) (string, string) {
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return "postgres", dsn
+	case strings.HasPrefix(dsn, "sqlite://"):
+		return "sqlite3", strings.TrimPrefix(dsn, "sqlite://")
+	case strings.HasPrefix(dsn, "sqlite3://"):
+		return "sqlite3", strings.TrimPrefix(dsn, "sqlite3://")
+	case strings.HasPrefix(dsn, "file:"):
+		return "sqlite3", dsn
+	}
+
+	// the key=value connection string of postgres.
+	if strings.Contains(dsn, "=") {
+		return "postgres", dsn
+	}
+	return "sqlite3", dsn
+}
+
+// filterTables returns the tables with the given names.
+func filterTables(tables []*schemapkg.Table, names []string) []*schemapkg.Table {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	var filtered []*schemapkg.Table
+	for _, t := range tables {
+		if wanted[t.Name] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dump = `
+--
+-- PostgreSQL database dump
+--
+CREATE TABLE public.users (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    email text NOT NULL,
+    age integer,
+    tags text[]
+);
+COMMENT ON TABLE public.users IS 'registered users';
+CREATE TABLE public.posts (
+    id bigint NOT NULL,
+    author_id uuid NOT NULL,
+    slug text NOT NULL,
+    status text DEFAULT 'draft'::text NOT NULL
+);
+ALTER TABLE ONLY public.posts ALTER COLUMN id SET DEFAULT nextval('public.posts_id_seq'::regclass);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.posts ADD CONSTRAINT posts_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE ONLY public.posts ADD CONSTRAINT posts_author_slug_key UNIQUE (author_id, slug);
+ALTER TABLE ONLY public.posts
+    ADD CONSTRAINT posts_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.users(id) ON DELETE CASCADE;
+`
+
+func TestParseDump(t *testing.T) {
+	s, err := ParseDump(strings.NewReader(dump), "public")
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+
+	users := s.Table("users")
+	require.NotNil(t, users)
+	assert.Equal(t, "registered users", users.Comment)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey())
+	assert.Equal(t, "gen_random_uuid()", users.Column("id").Default)
+	assert.False(t, users.Column("age").NotNull)
+	assert.Equal(t, "text[]", users.Column("tags").Type)
+
+	posts := s.Table("posts")
+	require.NotNil(t, posts)
+	assert.True(t, posts.Column("id").AutoIncrement)
+	assert.Empty(t, posts.Column("id").Default)
+	assert.Equal(t, "'draft'", posts.Column("status").Default)
+	require.Len(t, posts.Indexes, 1)
+	assert.Equal(t, []string{"author_id", "slug"}, posts.Indexes[0].Columns)
+	require.Len(t, posts.ForeignKeys, 1)
+	assert.Equal(t, "users", posts.ForeignKeys[0].RefTable)
+	assert.True(t, posts.ForeignKeys[0].Cascade)
+}
+
+func TestRender(t *testing.T) {
+	s, err := ParseDump(strings.NewReader(dump), "public")
+	require.NoError(t, err)
+
+	content, err := Render(s, Config{Package: "db", URLEnv: "DATABASE_URL"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "package", expected: "package db;"},
+		{name: "provider", expected: "provider: \"postgres\""},
+		{name: "primary key", expected: "string id = 1 [(structify.field) = {uuid: true, primary_key: true, default: \"gen_random_uuid()\"}];"},
+		{name: "unique", expected: "string email = 2 [(structify.field) = {unique: true}];"},
+		{name: "nullable", expected: "optional int32 age = 3;"},
+		{name: "array", expected: "// column tags of type text[] is imported as string."},
+		{name: "auto increment", expected: "int64 id = 1 [(structify.field) = {primary_key: true, auto_increment: true}];"},
+		{name: "not null", expected: "string slug = 3 [(structify.field) = {nullable: false}];"},
+		{name: "default", expected: "string status = 4 [(structify.field) = {default: \"'draft'\"}];"},
+		{name: "relation", expected: "User author = 5 [(structify.field) = {relation: {field: \"author_id\", reference: \"id\", foreign: {cascade: true}}}];"},
+		{name: "unique index", expected: "unique_index: [{fields: [\"author_id\", \"slug\"]}]"},
+		{name: "comment", expected: "comment: \"registered users\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, string(content), tt.expected)
+		})
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		dsn    string
+		driver string
+		source string
+	}{
+		{dsn: "postgres://user@localhost/db", driver: "postgres", source: "postgres://user@localhost/db"},
+		{dsn: "host=localhost dbname=db", driver: "postgres", source: "host=localhost dbname=db"},
+		{dsn: "sqlite://data.db", driver: "sqlite3", source: "data.db"},
+		{dsn: "file:data.db?cache=shared", driver: "sqlite3", source: "file:data.db?cache=shared"},
+		{dsn: "data.db", driver: "sqlite3", source: "data.db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			driver, source := parseDSN(tt.dsn)
+			assert.Equal(t, tt.driver, driver)
+			assert.Equal(t, tt.source, source)
+		})
+	}
+}
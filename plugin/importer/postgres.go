@@ -0,0 +1,210 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
+)
+
+// ReadPostgres reads the tables of the postgres schema from the catalog.
+func ReadPostgres(ctx context.Context, db *sql.DB, schema string) (*schemapkg.Schema, error) {
+	if schema == "" {
+		schema = "public"
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.relname, COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND n.nspname = $1
+		ORDER BY c.relname
+	`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+
+	s := &schemapkg.Schema{Provider: "postgres"}
+	for rows.Next() {
+		t := &schemapkg.Table{}
+		if err := rows.Scan(&t.Name, &t.Comment); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		if schema != "public" {
+			t.Schema = schema
+		}
+		s.Tables = append(s.Tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tables: %w", err)
+	}
+
+	for _, t := range s.Tables {
+		if err := readPostgresColumns(ctx, db, schema, t); err != nil {
+			return nil, err
+		}
+		if err := readPostgresIndexes(ctx, db, schema, t); err != nil {
+			return nil, err
+		}
+		if err := readPostgresForeignKeys(ctx, db, schema, t); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// readPostgresColumns reads the columns of the table from information_schema.
+func readPostgresColumns(ctx context.Context, db *sql.DB, schema string, t *schemapkg.Table) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name, data_type, udt_name, is_nullable, COALESCE(column_default, '')
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		ORDER BY ordinal_position
+	`, schema, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query columns of %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, dataType, udtName, isNullable, def string
+		if err := rows.Scan(&name, &dataType, &udtName, &isNullable, &def); err != nil {
+			return fmt.Errorf("failed to scan column of %s: %w", t.Name, err)
+		}
+
+		switch dataType {
+		case "ARRAY":
+			dataType = postgresUDTType(strings.TrimPrefix(udtName, "_")) + "[]"
+		case "USER-DEFINED":
+			dataType = udtName
+		}
+		t.Columns = append(t.Columns, postgresColumn(name, dataType, isNullable == "NO", def))
+	}
+	return rows.Err()
+}
+
+// readPostgresIndexes reads the indexes and the primary key of the table.
+func readPostgresIndexes(ctx context.Context, db *sql.DB, schema string, t *schemapkg.Table) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT i.relname, ix.indisunique, ix.indisprimary, array_to_string(array_agg(a.attname ORDER BY k.ord), ',')
+		FROM pg_index ix
+		JOIN pg_class c ON c.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+		WHERE n.nspname = $1 AND c.relname = $2 AND ix.indexprs IS NULL AND ix.indpred IS NULL
+		GROUP BY i.relname, ix.indisunique, ix.indisprimary
+		ORDER BY i.relname
+	`, schema, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes of %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, columns string
+		var unique, primary bool
+		if err := rows.Scan(&name, &unique, &primary, &columns); err != nil {
+			return fmt.Errorf("failed to scan index of %s: %w", t.Name, err)
+		}
+		addIndex(t, name, strings.Split(columns, ","), unique, primary)
+	}
+	return rows.Err()
+}
+
+// readPostgresForeignKeys reads the single column foreign keys of the table.
+func readPostgresForeignKeys(ctx context.Context, db *sql.DB, schema string, t *schemapkg.Table) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT con.conname, a.attname, rn.nspname, rc.relname, ra.attname, con.confdeltype = 'c'
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_class rc ON rc.oid = con.confrelid
+		JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = con.conkey[1]
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = con.confkey[1]
+		WHERE con.contype = 'f' AND array_length(con.conkey, 1) = 1 AND n.nspname = $1 AND c.relname = $2
+		ORDER BY con.conname
+	`, schema, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys of %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fk := &schemapkg.ForeignKey{}
+		if err := rows.Scan(&fk.Name, &fk.Column, &fk.RefSchema, &fk.RefTable, &fk.RefColumn, &fk.Cascade); err != nil {
+			return fmt.Errorf("failed to scan foreign key of %s: %w", t.Name, err)
+		}
+		if fk.RefSchema == "public" {
+			fk.RefSchema = ""
+		}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	}
+	return rows.Err()
+}
+
+// postgresColumn returns the column of the catalog definition.
+// The serial defaults are turned into the auto increment.
+func postgresColumn(name, dataType string, notNull bool, def string) *schemapkg.Column {
+	c := &schemapkg.Column{
+		Name:    name,
+		Type:    dataType,
+		NotNull: notNull,
+		Default: postgresDefault(def),
+	}
+	if strings.HasPrefix(def, "nextval(") {
+		c.AutoIncrement = true
+		c.Default = ""
+	}
+	return c
+}
+
+// postgresUDTType returns the type name of the internal postgres type name.
+func postgresUDTType(udt string) string {
+	switch udt {
+	case "int2":
+		return "smallint"
+	case "int4":
+		return "integer"
+	case "int8":
+		return "bigint"
+	case "float4":
+		return "real"
+	case "float8":
+		return "double precision"
+	case "bool":
+		return "boolean"
+	case "varchar":
+		return "character varying"
+	case "timestamp":
+		return "timestamp without time zone"
+	case "timestamptz":
+		return "timestamp with time zone"
+	}
+	return udt
+}
+
+// addIndex adds the index to the table, the primary key and the single column uniques are marked on the columns.
+func addIndex(t *schemapkg.Table, name string, columns []string, unique, primary bool) {
+	if primary {
+		for _, column := range columns {
+			if c := t.Column(column); c != nil {
+				c.PrimaryKey = true
+			}
+		}
+		return
+	}
+
+	t.Indexes = append(t.Indexes, &schemapkg.Index{
+		Name:    name,
+		Columns: columns,
+		Unique:  unique,
+	})
+}
@@ -0,0 +1,285 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gertd/go-pluralize"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
+)
+
+// typeParamsRegexp matches the parameters of the column type, e.g. (255).
+var typeParamsRegexp = regexp.MustCompile(`\s*\(.*?\)`)
+
+// identRegexp matches the characters which are not allowed in the proto identifiers.
+var identRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// message is the proto message of the table.
+type message struct {
+	Name    string
+	Table   *schemapkg.Table
+	Fields  []string
+	Options []string
+	Notes   []string
+}
+
+// Render renders the proto file of the schema.
+func Render(s *schemapkg.Schema, config Config) ([]byte, error) {
+	if s.Provider != "postgres" && s.Provider != "sqlite" {
+		return nil, fmt.Errorf("unsupported provider: %q", s.Provider)
+	}
+
+	pluralizer := pluralize.NewClient()
+	messages := make(map[string]*message, len(s.Tables))
+	names := make(map[string]bool, len(s.Tables))
+	for _, t := range s.Tables {
+		name := helperpkg.UpperCamelCase(pluralizer.Singular(protoIdent(t.Name)))
+		for names[name] {
+			name += "Table"
+		}
+		names[name] = true
+		messages[t.Name] = &message{Name: name, Table: t}
+	}
+
+	timestamp := false
+	for _, t := range s.Tables {
+		m := messages[t.Name]
+		number := 1
+		fields := make(map[string]bool, len(t.Columns))
+		if pk := t.PrimaryKey(); len(pk) > 1 {
+			m.Notes = append(m.Notes, fmt.Sprintf("primary key (%s) is not supported.", strings.Join(pk, ", ")))
+		}
+
+		for _, c := range t.Columns {
+			typ, options := protoType(s.Provider, c)
+			if strings.HasSuffix(c.Type, "[]") {
+				m.Notes = append(m.Notes, fmt.Sprintf("column %s of type %s is imported as string.", c.Name, c.Type))
+			}
+			timestamp = timestamp || typ == "google.protobuf.Timestamp"
+			options = append(options, columnOptions(t, c)...)
+
+			label := ""
+			switch {
+			case !c.NotNull && !c.PrimaryKey:
+				label = "optional "
+				if len(options) > 0 {
+					options = append(options, "nullable: true")
+				}
+			case len(options) == 0 && !c.AutoIncrement:
+				// the columns without the options are nullable in structify.
+				options = append(options, "nullable: false")
+			}
+
+			name := protoIdent(c.Name)
+			fields[name] = true
+			m.Fields = append(m.Fields, field(label+typ, name, number, options))
+			number++
+		}
+
+		for _, fk := range t.ForeignKeys {
+			ref, ok := messages[fk.RefTable]
+			if !ok || (fk.RefSchema != "" && fk.RefSchema != ref.Table.Schema) {
+				m.Notes = append(m.Notes, fmt.Sprintf("foreign key %s references %s which is not imported.", fk.Name, fk.RefTable))
+				continue
+			}
+			if c := t.Column(fk.Column); c != nil && c.PrimaryKey {
+				m.Notes = append(m.Notes, fmt.Sprintf("foreign key %s on the primary key is not supported.", fk.Name))
+				continue
+			}
+
+			name := relationName(fk, ref.Name, fields)
+			fields[name] = true
+			relation := fmt.Sprintf("relation: {field: %s, reference: %s, foreign: {cascade: %t}}",
+				strconv.Quote(fk.Column), strconv.Quote(fk.RefColumn), fk.Cascade)
+			m.Fields = append(m.Fields, field(ref.Name, name, number, []string{relation}))
+			number++
+		}
+
+		if helperpkg.Plural(m.Name) != t.Name {
+			m.Options = append(m.Options, "table: "+strconv.Quote(t.Name))
+		}
+		if t.Comment != "" {
+			m.Options = append(m.Options, "comment: "+strconv.Quote(t.Comment))
+		}
+		var uniqueIndexes []string
+		for _, i := range t.Indexes {
+			switch {
+			case len(i.Columns) > 1 && i.Unique:
+				uniqueIndexes = append(uniqueIndexes, fmt.Sprintf("{fields: [%s]}", quoteList(i.Columns)))
+			case len(i.Columns) > 1:
+				m.Notes = append(m.Notes, fmt.Sprintf("index %s (%s) is not supported.", i.Name, strings.Join(i.Columns, ", ")))
+			}
+		}
+		if len(uniqueIndexes) > 0 {
+			m.Options = append(m.Options, fmt.Sprintf("unique_index: [%s]", strings.Join(uniqueIndexes, ", ")))
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by structify import.\n\n")
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "import \"github.com/cjp2600/protoc-gen-structify/plugin/options/structify.proto\";\n")
+	if timestamp {
+		fmt.Fprintf(&b, "import \"google/protobuf/timestamp.proto\";\n")
+	}
+	fmt.Fprintf(&b, "\npackage %s;\n\n", config.Package)
+	if config.GoPackage != "" {
+		fmt.Fprintf(&b, "option go_package = %s;\n\n", strconv.Quote(config.GoPackage))
+	}
+
+	fmt.Fprintf(&b, "option (structify.db) = {\n  provider: %s\n", strconv.Quote(s.Provider))
+	if config.URLEnv != "" {
+		fmt.Fprintf(&b, "  url_env: %s\n", strconv.Quote(config.URLEnv))
+	}
+	if s.Provider == "postgres" && config.Schema != "" && config.Schema != "public" {
+		fmt.Fprintf(&b, "  schema: %s\n", strconv.Quote(config.Schema))
+	}
+	fmt.Fprintf(&b, "};\n")
+
+	tables := make([]*schemapkg.Table, len(s.Tables))
+	copy(tables, s.Tables)
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	for _, t := range tables {
+		m := messages[t.Name]
+		fmt.Fprintf(&b, "\n")
+		if t.Comment != "" {
+			fmt.Fprintf(&b, "// %s\n", strings.ReplaceAll(t.Comment, "\n", "\n// "))
+		}
+		fmt.Fprintf(&b, "message %s {\n", m.Name)
+		for _, note := range m.Notes {
+			fmt.Fprintf(&b, "  // %s\n", note)
+		}
+		for _, f := range m.Fields {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+		if len(m.Options) > 0 {
+			fmt.Fprintf(&b, "\n  option (structify.opts) = {\n")
+			for _, option := range m.Options {
+				fmt.Fprintf(&b, "    %s\n", option)
+			}
+			fmt.Fprintf(&b, "  };\n")
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// protoType returns the proto type and the type options of the column.
+// The arrays are imported as string, the repeated fields are stored as json by structify.
+func protoType(provider string, c *schemapkg.Column) (string, []string) {
+	typ := strings.ToLower(strings.TrimSpace(c.Type))
+	if strings.HasSuffix(typ, "[]") {
+		return "string", nil
+	}
+	typ = typeParamsRegexp.ReplaceAllString(typ, "")
+
+	var options []string
+	var protoType string
+	switch typ {
+	case "uuid":
+		protoType = "string"
+		options = append(options, "uuid: true")
+	case "json", "jsonb":
+		protoType = "string"
+		options = append(options, "json: true")
+	case "smallint", "int2", "smallserial", "tinyint", "mediumint":
+		protoType = "int32"
+	case "integer", "int", "int4", "serial":
+		protoType = "int32"
+		if provider == "sqlite" {
+			protoType = "int64"
+		}
+	case "bigint", "int8", "bigserial":
+		protoType = "int64"
+	case "boolean", "bool":
+		protoType = "bool"
+	case "real", "float4", "float":
+		protoType = "float"
+	case "double precision", "double", "float8", "numeric", "decimal", "money":
+		protoType = "double"
+	case "timestamp", "timestamp without time zone", "timestamp with time zone", "timestamptz", "date", "datetime":
+		protoType = "google.protobuf.Timestamp"
+	case "bytea", "blob":
+		protoType = "bytes"
+	default:
+		protoType = "string"
+	}
+
+	return protoType, options
+}
+
+// columnOptions returns the field options of the column constraints.
+func columnOptions(t *schemapkg.Table, c *schemapkg.Column) []string {
+	var options []string
+	if c.PrimaryKey && len(t.PrimaryKey()) == 1 {
+		options = append(options, "primary_key: true")
+	}
+	if c.AutoIncrement {
+		options = append(options, "auto_increment: true")
+	}
+
+	var unique, index bool
+	for _, i := range t.Indexes {
+		if len(i.Columns) == 1 && i.Columns[0] == c.Name {
+			unique = unique || i.Unique
+			index = index || !i.Unique
+		}
+	}
+	if unique {
+		options = append(options, "unique: true")
+	}
+	if index {
+		options = append(options, "index: true")
+	}
+	if c.Default != "" {
+		options = append(options, "default: "+strconv.Quote(c.Default))
+	}
+	return options
+}
+
+// field returns the proto field declaration.
+func field(typ, name string, number int, options []string) string {
+	declaration := fmt.Sprintf("%s %s = %d", typ, name, number)
+	if len(options) > 0 {
+		declaration += fmt.Sprintf(" [(structify.field) = {%s}]", strings.Join(options, ", "))
+	}
+	return declaration + ";"
+}
+
+// relationName returns the unique field name of the foreign key relation,
+// the _id suffix of the column is trimmed, e.g. author_id is the author relation.
+func relationName(fk *schemapkg.ForeignKey, refMessage string, fields map[string]bool) string {
+	name := strings.TrimSuffix(protoIdent(fk.Column), "_id")
+	if name == protoIdent(fk.Column) || fields[name] {
+		name = helperpkg.SnakeCase(refMessage)
+	}
+	for fields[name] {
+		name += "_ref"
+	}
+	return name
+}
+
+// protoIdent returns the identifier which is valid in proto.
+func protoIdent(name string) string {
+	ident := strings.Trim(identRegexp.ReplaceAllString(name, "_"), "_")
+	if ident == "" || (ident[0] >= '0' && ident[0] <= '9') {
+		ident = "f_" + ident
+	}
+	return ident
+}
+
+// quoteList returns the comma separated quoted strings.
+func quoteList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
+)
+
+// ReadSQLite reads the tables of the sqlite database from the catalog.
+func ReadSQLite(ctx context.Context, db *sql.DB) (*schemapkg.Schema, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT name, COALESCE(sql, '')
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+
+	s := &schemapkg.Schema{Provider: "sqlite"}
+	definitions := make(map[string]string)
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		definitions[name] = definition
+		s.Tables = append(s.Tables, &schemapkg.Table{Name: name})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tables: %w", err)
+	}
+
+	for _, t := range s.Tables {
+		autoIncrement := strings.Contains(strings.ToUpper(definitions[t.Name]), "AUTOINCREMENT")
+		if err := readSQLiteColumns(ctx, db, t, autoIncrement); err != nil {
+			return nil, err
+		}
+		if err := readSQLiteIndexes(ctx, db, t); err != nil {
+			return nil, err
+		}
+		if err := readSQLiteForeignKeys(ctx, db, t); err != nil {
+			return nil, err
+		}
+	}
+
+	// the foreign key without the column references the primary key.
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			if ref := s.Table(fk.RefTable); fk.RefColumn == "" && ref != nil && len(ref.PrimaryKey()) == 1 {
+				fk.RefColumn = ref.PrimaryKey()[0]
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// readSQLiteColumns reads the columns of the table from PRAGMA table_info.
+func readSQLiteColumns(ctx context.Context, db *sql.DB, t *schemapkg.Table, autoIncrement bool) error {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", COALESCE(dflt_value, ''), pk FROM pragma_table_info(?) ORDER BY cid`, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query columns of %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	var pk []*schemapkg.Column
+	for rows.Next() {
+		c := &schemapkg.Column{}
+		var position int
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.Default, &position); err != nil {
+			return fmt.Errorf("failed to scan column of %s: %w", t.Name, err)
+		}
+		if position > 0 {
+			c.PrimaryKey = true
+			pk = append(pk, c)
+		}
+		t.Columns = append(t.Columns, c)
+	}
+
+	// only the integer primary key, the alias of the rowid, can be auto incremented.
+	if autoIncrement && len(pk) == 1 && strings.EqualFold(pk[0].Type, "INTEGER") {
+		pk[0].AutoIncrement = true
+	}
+	return rows.Err()
+}
+
+// readSQLiteIndexes reads the indexes of the table, the primary key index is skipped.
+func readSQLiteIndexes(ctx context.Context, db *sql.DB, t *schemapkg.Table) error {
+	rows, err := db.QueryContext(ctx, `SELECT name, "unique", origin FROM pragma_index_list(?) ORDER BY name`, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes of %s: %w", t.Name, err)
+	}
+
+	type index struct {
+		name   string
+		unique bool
+	}
+	var indexes []index
+	for rows.Next() {
+		var i index
+		var origin string
+		if err := rows.Scan(&i.name, &i.unique, &origin); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan index of %s: %w", t.Name, err)
+		}
+		if origin != "pk" {
+			indexes = append(indexes, i)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate indexes of %s: %w", t.Name, err)
+	}
+
+	for _, i := range indexes {
+		columns, err := readSQLiteIndexColumns(ctx, db, i.name)
+		if err != nil {
+			return fmt.Errorf("failed to read index %s: %w", i.name, err)
+		}
+		if len(columns) > 0 {
+			addIndex(t, i.name, columns, i.unique, false)
+		}
+	}
+	return nil
+}
+
+// readSQLiteIndexColumns reads the columns of the index, the expressions are skipped.
+func readSQLiteIndexColumns(ctx context.Context, db *sql.DB, index string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT COALESCE(name, '') FROM pragma_index_info(?) ORDER BY seqno`, index)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, nil
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// readSQLiteForeignKeys reads the single column foreign keys of the table.
+func readSQLiteForeignKeys(ctx context.Context, db *sql.DB, t *schemapkg.Table) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, "from", "table", COALESCE("to", ''), on_delete
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq
+	`, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys of %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[int]int)
+	var keys []*schemapkg.ForeignKey
+	var ids []int
+	for rows.Next() {
+		var id int
+		var onDelete string
+		fk := &schemapkg.ForeignKey{}
+		if err := rows.Scan(&id, &fk.Column, &fk.RefTable, &fk.RefColumn, &onDelete); err != nil {
+			return fmt.Errorf("failed to scan foreign key of %s: %w", t.Name, err)
+		}
+		columns[id]++
+		if columns[id] > 1 {
+			continue
+		}
+		fk.Name = t.Name + "_" + fk.Column + "_fkey"
+		fk.Cascade = strings.EqualFold(onDelete, "CASCADE")
+		keys = append(keys, fk)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// the composite foreign keys can not be declared as the relations.
+	for i, fk := range keys {
+		if columns[ids[i]] == 1 {
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build cgo
+
+package importer
+
+// github.com/mattn/go-sqlite3 is the cgo binding of the sqlite library.
+import _ "github.com/mattn/go-sqlite3"
+
+// sqliteSupported reports whether the sqlite driver is linked into the binary.
+const sqliteSupported = true
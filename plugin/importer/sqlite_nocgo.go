@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package importer
+
+// sqliteSupported reports whether the sqlite driver is linked into the binary,
+// github.com/mattn/go-sqlite3 requires cgo, so the CGO_ENABLED=0 build can't import sqlite.
+const sqliteSupported = false
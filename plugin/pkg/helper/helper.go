@@ -62,6 +62,108 @@ func HasUnique(f *descriptorpb.FieldDescriptorProto) bool {
 	return false
 }
 
+// IsEncrypted returns the encrypted option for a field.
+func IsEncrypted(f *descriptorpb.FieldDescriptorProto) bool {
+	if opts := GetFieldOptions(f); opts != nil {
+		return opts.GetEncrypted()
+	}
+	return false
+}
+
+// HasBlindIndex returns true if the encrypted field keeps a blind index column.
+func HasBlindIndex(f *descriptorpb.FieldDescriptorProto) bool {
+	if opts := GetFieldOptions(f); opts != nil {
+		return opts.GetEncrypted() && opts.GetBlindIndex()
+	}
+	return false
+}
+
+// BlindIndexPostfix is the postfix of the blind index column name.
+const BlindIndexPostfix = "_bidx"
+
+// BlindIndexColumn returns the name of the blind index column for a field.
+func BlindIndexColumn(f *descriptorpb.FieldDescriptorProto) string {
+	return f.GetName() + BlindIndexPostfix
+}
+
+// IsSearchable returns true if the field is a part of the full-text search of the table.
+// Only the single string fields which are not encrypted can be searched.
+func IsSearchable(f *descriptorpb.FieldDescriptorProto) bool {
+	opts := GetFieldOptions(f)
+	if opts == nil || opts.GetSearch() == nil || opts.GetEncrypted() || IsRepeated(f) {
+		return false
+	}
+	return f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_STRING
+}
+
+// SearchWeight returns the rank weight of the searchable field: A, B, C or D, it is D if the weight is not set.
+func SearchWeight(f *descriptorpb.FieldDescriptorProto) string {
+	weight := strings.ToUpper(GetFieldOptions(f).GetSearch().GetWeight())
+	switch weight {
+	case "A", "B", "C", "D":
+		return weight
+	}
+	return "D"
+}
+
+// SearchRank returns the rank multiplier of the weight, they are the default weights of the postgres ts_rank.
+func SearchRank(weight string) float64 {
+	switch weight {
+	case "A":
+		return 1.0
+	case "B":
+		return 0.4
+	case "C":
+		return 0.2
+	}
+	return 0.1
+}
+
+// SearchVectorColumn is the name of the postgres column which keeps the full-text search document.
+const SearchVectorColumn = "search_vector"
+
+// SearchConfig is the postgres text search configuration of the full-text search, it does not stem the words,
+// so the search works the same for any language.
+const SearchConfig = "simple"
+
+// SearchTablePostfix is the postfix of the sqlite FTS5 table name which keeps the full-text search document.
+const SearchTablePostfix = "_search"
+
+// IsScalarFilter returns true if the filter of the field can be parsed from a query string value:
+// the single numbers, strings, booleans, enums and timestamps.
+func IsScalarFilter(f *descriptorpb.FieldDescriptorProto) bool {
+	if IsRepeated(f) {
+		return false
+	}
+	switch f.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		return IsTimestamp(f)
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+	return true
+}
+
+// IsRangeFilter returns true if the field can be filtered by a range of values: the single numbers and timestamps.
+func IsRangeFilter(f *descriptorpb.FieldDescriptorProto) bool {
+	if !IsScalarFilter(f) {
+		return false
+	}
+	switch f.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return false
+	}
+	return true
+}
+
+// IsListFilter returns true if the field can be filtered by a list of the allowed values: the scalar filters
+// except the booleans.
+func IsListFilter(f *descriptorpb.FieldDescriptorProto) bool {
+	return IsScalarFilter(f) && f.GetType() != descriptorpb.FieldDescriptorProto_TYPE_BOOL
+}
+
 // GetMessageOptions returns the custom options for a message.
 func GetMessageOptions(d *descriptorpb.DescriptorProto) *structify.StructifyMessageOptions {
 	opts := d.GetOptions()
@@ -77,6 +179,48 @@ func GetMessageOptions(d *descriptorpb.DescriptorProto) *structify.StructifyMess
 	return nil
 }
 
+// InsertedPrimaryKey returns the primary key fields of the message if their values are inserted,
+// it returns nil if the primary key is generated by the database.
+func InsertedPrimaryKey(d *descriptorpb.DescriptorProto) []*descriptorpb.FieldDescriptorProto {
+	var fields []*descriptorpb.FieldDescriptorProto
+	for _, f := range d.GetField() {
+		opts := GetFieldOptions(f)
+		if !opts.GetPrimaryKey() {
+			continue
+		}
+		if opts.GetAutoIncrement() || strings.Contains(opts.GetDefault(), "uuid_generate") {
+			return nil
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// UniqueConstraints returns the unique constraints of the message other than the primary key:
+// the unique fields and the unique_index groups.
+func UniqueConstraints(d *descriptorpb.DescriptorProto) [][]*descriptorpb.FieldDescriptorProto {
+	var constraints [][]*descriptorpb.FieldDescriptorProto
+	for _, f := range d.GetField() {
+		if HasUnique(f) && !GetFieldOptions(f).GetPrimaryKey() {
+			constraints = append(constraints, []*descriptorpb.FieldDescriptorProto{f})
+		}
+	}
+	for _, index := range GetMessageOptions(d).GetUniqueIndex() {
+		var fields []*descriptorpb.FieldDescriptorProto
+		for _, name := range index.GetFields() {
+			for _, f := range d.GetField() {
+				if f.GetName() == name {
+					fields = append(fields, f)
+				}
+			}
+		}
+		if len(fields) > 0 {
+			constraints = append(constraints, fields)
+		}
+	}
+	return constraints
+}
+
 // GetDBOptions returns the custom options for a file.
 func GetDBOptions(f *descriptorpb.FileDescriptorProto) *structify.StructifyDBOptions {
 	opts := f.GetOptions()
@@ -91,6 +235,48 @@ func GetDBOptions(f *descriptorpb.FileDescriptorProto) *structify.StructifyDBOpt
 	return nil
 }
 
+// GetMethodOptions returns the custom options for a service method.
+func GetMethodOptions(m *descriptorpb.MethodDescriptorProto) *structify.MethodOptions {
+	opts := m.GetOptions()
+	if opts != nil {
+		ext, err := proto.GetExtension(opts, structify.E_Method)
+		if err == nil && ext != nil {
+			if customOpts, ok := ext.(*structify.MethodOptions); ok {
+				return customOpts
+			}
+		}
+	}
+	return nil
+}
+
+// MessageName returns the name of the message from its fully qualified type name.
+// example: ".blog.User" -> "User"
+func MessageName(f *descriptorpb.FileDescriptorProto, typeName string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(typeName, "."+f.GetPackage()+"."), ".")
+}
+
+// IsServiceMessage returns true if the message is the request or the response
+// of an annotated service method and not the object of the method.
+func IsServiceMessage(f *descriptorpb.FileDescriptorProto, m *descriptorpb.DescriptorProto) bool {
+	for _, service := range f.GetService() {
+		for _, method := range service.GetMethod() {
+			opts := GetMethodOptions(method)
+			if opts == nil {
+				continue
+			}
+
+			if MessageName(f, opts.GetObjectType()) == m.GetName() {
+				continue
+			}
+			if MessageName(f, method.GetInputType()) == m.GetName() ||
+				MessageName(f, method.GetOutputType()) == m.GetName() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // exists returns true if the descriptor exists.
 func (d *DescriptorMList) exists(name string) bool {
 	_, ok := (*d)[name]
@@ -159,6 +345,19 @@ func Plural(name string) string {
 	return strings.ToLower(plural)
 }
 
+// QuoteIdent quotes the given SQL identifier.
+// If schema is not empty, the identifier is qualified with the quoted schema name.
+func QuoteIdent(schema, name string) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+
+	if schema == "" {
+		return quote(name)
+	}
+	return quote(schema) + "." + quote(name)
+}
+
 // PostgresType returns the postgres type for the given type.
 func PostgresType(goType string, options *structify.StructifyFieldOptions, isJson bool) string {
 	t := GoTypeToPostgresType(goType)
@@ -247,6 +446,61 @@ func GoTypeToPostgresType(goType string) string {
 	}
 }
 
+// ClickHouseType returns the clickhouse type for the given type.
+// Pointer types are Nullable and slices are Arrays.
+func ClickHouseType(goType string, options *structify.StructifyFieldOptions, isJson bool) string {
+	if isJson || (options != nil && options.Json) {
+		return "String"
+	}
+
+	nullable := strings.HasPrefix(goType, "*")
+	goType = strings.TrimPrefix(goType, "*")
+	array := strings.HasPrefix(goType, "[]") && goType != "[]byte"
+	if array {
+		goType = strings.TrimPrefix(goType, "[]")
+	}
+
+	t := GoTypeToClickHouseType(goType)
+	if options != nil && options.Uuid {
+		t = "UUID"
+	}
+
+	if array {
+		return "Array(" + t + ")"
+	}
+	if nullable {
+		return "Nullable(" + t + ")"
+	}
+	return t
+}
+
+// GoTypeToClickHouseType returns the clickhouse type for the given type.
+func GoTypeToClickHouseType(goType string) string {
+	goType = strings.TrimPrefix(goType, "*")
+	switch goType {
+	case "string", "[]byte":
+		return "String"
+	case "bool":
+		return "Bool"
+	case "int", "int64":
+		return "Int64"
+	case "int32":
+		return "Int32"
+	case "uint32":
+		return "UInt32"
+	case "uint64":
+		return "UInt64"
+	case "float32":
+		return "Float32"
+	case "float64":
+		return "Float64"
+	case "time.Time":
+		return "DateTime64(3)"
+	default:
+		return "String"
+	}
+}
+
 type IncludeTemplate struct {
 	Name string
 	Body string
@@ -430,6 +684,57 @@ func IsRepeated(field *descriptorpb.FieldDescriptorProto) bool {
 	return field.Label != nil && *field.Label == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
 }
 
+// IsTimestamp returns true if the field is a single google.protobuf.Timestamp.
+func IsTimestamp(field *descriptorpb.FieldDescriptorProto) bool {
+	if IsRepeated(field) || field.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+		return false
+	}
+	return strings.TrimPrefix(field.GetTypeName(), ".") == "google.protobuf.Timestamp"
+}
+
+// JSONElementType returns the Go type of the elements of the repeated scalar field stored as a JSON array.
+// The elements of the repeated messages, bytes and enums are untyped.
+func JSONElementType(field *descriptorpb.FieldDescriptorProto) string {
+	if !IsRepeated(field) {
+		return "interface{}"
+	}
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP,
+		descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return "interface{}"
+	}
+	return strings.TrimPrefix(ClearPointer(ConvertType(field)), "[]")
+}
+
+// SumType returns the Go type of the sum of the numeric field, it is empty if the field is not a single number.
+// The sums of the signed integers are int64, of the unsigned integers are uint64 and of the floats are float64.
+func SumType(field *descriptorpb.FieldDescriptorProto) string {
+	if IsRepeated(field) {
+		return ""
+	}
+
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return "int64"
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		return "uint64"
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "float64"
+	}
+	return ""
+}
+
 // IsOptional returns true if the field is optional and not a string, bytes, int32, int64, float32, float64, bool, uint32, uint64 type or a Google Protobuf wrapper message.
 func IsOptional(field *descriptorpb.FieldDescriptorProto) bool {
 	if field.GetProto3Optional() {
@@ -465,6 +770,11 @@ func IsOptional(field *descriptorpb.FieldDescriptorProto) bool {
 // GoFmt formats the generated Go code.
 func GoFmt(resp *plugingo.CodeGeneratorResponse) error {
 	for i := 0; i < len(resp.File); i++ {
+		// only the go files are formatted, e.g. the sql migrations are kept as is.
+		if !strings.HasSuffix(resp.File[i].GetName(), ".go") {
+			continue
+		}
+
 		formatted, err := format.Source([]byte(resp.File[i].GetContent()))
 		if err != nil {
 			return fmt.Errorf("go format error: %v", err)
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 
 	"github.com/stretchr/testify/assert"
+
+	structify "github.com/cjp2600/protoc-gen-structify/plugin/options"
 )
 
 func TestGoTypeToPostgresType(t *testing.T) {
@@ -35,6 +38,32 @@ func TestGoTypeToPostgresType(t *testing.T) {
 	}
 }
 
+func TestClickHouseType(t *testing.T) {
+	tests := []struct {
+		goType         string
+		clickHouseType string
+	}{
+		{"string", "String"},
+		{"*string", "Nullable(String)"},
+		{"bool", "Bool"},
+		{"int32", "Int32"},
+		{"int64", "Int64"},
+		{"uint64", "UInt64"},
+		{"float64", "Float64"},
+		{"time.Time", "DateTime64(3)"},
+		{"*time.Time", "Nullable(DateTime64(3))"},
+		{"[]byte", "String"},
+		{"[]string", "Array(String)"},
+		{"CustomType", "String"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			assert.Equal(t, tt.clickHouseType, ClickHouseType(tt.goType, nil, false))
+		})
+	}
+}
+
 func TestExecuteTemplate(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		tmpl := "Hello, {{.Name}}!"
@@ -343,3 +372,337 @@ func TestSliceToString(t *testing.T) {
 		})
 	}
 }
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		schema   string
+		name     string
+		expected string
+	}{
+		{"", "users", `"users"`},
+		{"auth", "users", `"auth"."users"`},
+		{"billing", `in"voices`, `"billing"."in""voices"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, QuoteIdent(tt.schema, tt.name))
+		})
+	}
+}
+
+func TestMessageName(t *testing.T) {
+	pkg := "blog"
+	file := &descriptor.FileDescriptorProto{Package: &pkg}
+
+	tests := []struct {
+		typeName string
+		expected string
+	}{
+		{".blog.User", "User"},
+		{"User", "User"},
+		{".User", "User"},
+		{".other.User", "other.User"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MessageName(file, tt.typeName))
+		})
+	}
+}
+
+func TestSumType(t *testing.T) {
+	timestamp := ".google.protobuf.Timestamp"
+	tests := []struct {
+		name      string
+		field     *descriptor.FieldDescriptorProto
+		sumType   string
+		timestamp bool
+	}{
+		{
+			name:    "int32",
+			field:   &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_INT32.Enum()},
+			sumType: "int64",
+		},
+		{
+			name:    "uint32",
+			field:   &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_UINT32.Enum()},
+			sumType: "uint64",
+		},
+		{
+			name:    "float",
+			field:   &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_FLOAT.Enum()},
+			sumType: "float64",
+		},
+		{
+			name: "repeated int64",
+			field: &descriptor.FieldDescriptorProto{
+				Type:  descriptor.FieldDescriptorProto_TYPE_INT64.Enum(),
+				Label: descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+			},
+		},
+		{
+			name:  "string",
+			field: &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+		},
+		{
+			name: "timestamp",
+			field: &descriptor.FieldDescriptorProto{
+				Type:     descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: &timestamp,
+			},
+			timestamp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.sumType, SumType(tt.field))
+			assert.Equal(t, tt.timestamp, IsTimestamp(tt.field))
+		})
+	}
+}
+
+func TestJSONElementType(t *testing.T) {
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	comment := ".blog.User.Comment"
+	tests := []struct {
+		name     string
+		field    *descriptor.FieldDescriptorProto
+		expected string
+	}{
+		{
+			name:     "repeated string",
+			field:    &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum(), Label: repeated},
+			expected: "string",
+		},
+		{
+			name:     "repeated int32",
+			field:    &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_INT32.Enum(), Label: repeated},
+			expected: "int32",
+		},
+		{
+			name: "repeated message",
+			field: &descriptor.FieldDescriptorProto{
+				Type:     descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: &comment,
+				Label:    repeated,
+			},
+			expected: "interface{}",
+		},
+		{
+			name:     "repeated bytes",
+			field:    &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_BYTES.Enum(), Label: repeated},
+			expected: "interface{}",
+		},
+		{
+			name:     "message",
+			field:    &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: &comment},
+			expected: "interface{}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, JSONElementType(tt.field))
+		})
+	}
+}
+
+func TestSearchWeight(t *testing.T) {
+	field := func(typ descriptor.FieldDescriptorProto_Type, opts *structify.StructifyFieldOptions) *descriptor.FieldDescriptorProto {
+		f := &descriptor.FieldDescriptorProto{Type: typ.Enum(), Options: &descriptor.FieldOptions{}}
+		if opts != nil {
+			_ = proto.SetExtension(f.Options, structify.E_Field, opts)
+		}
+		return f
+	}
+	str := descriptor.FieldDescriptorProto_TYPE_STRING
+	tests := []struct {
+		name       string
+		field      *descriptor.FieldDescriptorProto
+		searchable bool
+		weight     string
+	}{
+		{
+			name:       "weight",
+			field:      field(str, &structify.StructifyFieldOptions{Search: &structify.Search{Weight: "a"}}),
+			searchable: true,
+			weight:     "A",
+		},
+		{
+			name:       "default weight",
+			field:      field(str, &structify.StructifyFieldOptions{Search: &structify.Search{}}),
+			searchable: true,
+			weight:     "D",
+		},
+		{
+			name:       "unknown weight",
+			field:      field(str, &structify.StructifyFieldOptions{Search: &structify.Search{Weight: "E"}}),
+			searchable: true,
+			weight:     "D",
+		},
+		{
+			name:  "no search",
+			field: field(str, &structify.StructifyFieldOptions{Index: true}),
+		},
+		{
+			name:  "encrypted",
+			field: field(str, &structify.StructifyFieldOptions{Search: &structify.Search{Weight: "A"}, Encrypted: true}),
+		},
+		{
+			name:  "not a string",
+			field: field(descriptor.FieldDescriptorProto_TYPE_INT32, &structify.StructifyFieldOptions{Search: &structify.Search{Weight: "A"}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.searchable, IsSearchable(tt.field))
+			if tt.searchable {
+				assert.Equal(t, tt.weight, SearchWeight(tt.field))
+			}
+		})
+	}
+}
+
+func TestFilterKinds(t *testing.T) {
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	timestamp := ".google.protobuf.Timestamp"
+	comment := ".blog.User.Comment"
+	tests := []struct {
+		name   string
+		field  *descriptor.FieldDescriptorProto
+		scalar bool
+		rng    bool
+		list   bool
+	}{
+		{
+			name:   "string",
+			field:  &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum()},
+			scalar: true,
+			list:   true,
+		},
+		{
+			name:   "int32",
+			field:  &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_INT32.Enum()},
+			scalar: true,
+			rng:    true,
+			list:   true,
+		},
+		{
+			name:   "bool",
+			field:  &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_BOOL.Enum()},
+			scalar: true,
+		},
+		{
+			name:   "enum",
+			field:  &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_ENUM.Enum()},
+			scalar: true,
+			list:   true,
+		},
+		{
+			name:   "timestamp",
+			field:  &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: &timestamp},
+			scalar: true,
+			rng:    true,
+			list:   true,
+		},
+		{
+			name:  "message",
+			field: &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: &comment},
+		},
+		{
+			name:  "bytes",
+			field: &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_BYTES.Enum()},
+		},
+		{
+			name:  "repeated int32",
+			field: &descriptor.FieldDescriptorProto{Type: descriptor.FieldDescriptorProto_TYPE_INT32.Enum(), Label: repeated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.scalar, IsScalarFilter(tt.field))
+			assert.Equal(t, tt.rng, IsRangeFilter(tt.field))
+			assert.Equal(t, tt.list, IsListFilter(tt.field))
+		})
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	field := func(name string, opts *structify.StructifyFieldOptions) *descriptor.FieldDescriptorProto {
+		f := &descriptor.FieldDescriptorProto{Name: proto.String(name), Type: descriptor.FieldDescriptorProto_TYPE_STRING.Enum(), Options: &descriptor.FieldOptions{}}
+		if opts != nil {
+			_ = proto.SetExtension(f.Options, structify.E_Field, opts)
+		}
+		return f
+	}
+	message := func(opts *structify.StructifyMessageOptions, fields ...*descriptor.FieldDescriptorProto) *descriptor.DescriptorProto {
+		m := &descriptor.DescriptorProto{Name: proto.String("User"), Field: fields, Options: &descriptor.MessageOptions{}}
+		if opts != nil {
+			_ = proto.SetExtension(m.Options, structify.E_Opts, opts)
+		}
+		return m
+	}
+	names := func(constraints [][]*descriptor.FieldDescriptorProto) [][]string {
+		var result [][]string
+		for _, constraint := range constraints {
+			var columns []string
+			for _, f := range constraint {
+				columns = append(columns, f.GetName())
+			}
+			result = append(result, columns)
+		}
+		return result
+	}
+	tests := []struct {
+		name        string
+		message     *descriptor.DescriptorProto
+		primaryKey  []string
+		constraints [][]string
+	}{
+		{
+			name: "inserted primary key, unique field and unique index",
+			message: message(&structify.StructifyMessageOptions{UniqueIndex: []*structify.UniqueIndex{{Fields: []string{"name", "email", "unknown"}}}},
+				field("id", &structify.StructifyFieldOptions{PrimaryKey: true, Unique: true}),
+				field("name", nil),
+				field("email", &structify.StructifyFieldOptions{Unique: true}),
+			),
+			primaryKey:  []string{"id"},
+			constraints: [][]string{{"email"}, {"name", "email"}},
+		},
+		{
+			name: "auto increment primary key",
+			message: message(nil,
+				field("id", &structify.StructifyFieldOptions{PrimaryKey: true, AutoIncrement: true}),
+				field("email", &structify.StructifyFieldOptions{Unique: true}),
+			),
+			constraints: [][]string{{"email"}},
+		},
+		{
+			name: "generated uuid primary key",
+			message: message(nil,
+				field("id", &structify.StructifyFieldOptions{PrimaryKey: true, Uuid: true, Default: "uuid_generate_v4()"}),
+			),
+		},
+		{
+			name: "composite primary key",
+			message: message(nil,
+				field("tenant_id", &structify.StructifyFieldOptions{PrimaryKey: true}),
+				field("id", &structify.StructifyFieldOptions{PrimaryKey: true}),
+			),
+			primaryKey: []string{"tenant_id", "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pk := names([][]*descriptor.FieldDescriptorProto{InsertedPrimaryKey(tt.message)})
+			assert.Equal(t, tt.primaryKey, pk[0])
+			assert.Equal(t, tt.constraints, names(UniqueConstraints(tt.message)))
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	generatorpkg "github.com/cjp2600/protoc-gen-structify/plugin/generator"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	"github.com/cjp2600/protoc-gen-structify/plugin/provider"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -74,6 +75,7 @@ func (p *Plugin) Run() {
 		// set additional state parameters
 		p.state.IncludeConnection = p.parseIncludeConnectionParam()
 		p.state.CRUDSchemas = p.parseCRUDSchemasParam()
+		p.state.EmitSchema = p.parseEmitSchemaParam()
 	}
 
 	// get provider template builder based on command line parameter
@@ -100,6 +102,15 @@ func (p *Plugin) Run() {
 
 	p.res.File = append(p.res.File, files...)
 
+	// generate the migrations of the schema changes
+	if dir := p.param["migrations"]; dir != "" {
+		migrations, err := p.migrationFiles(dir)
+		if err != nil {
+			log.Fatalf("Failed to generate migrations: %v", err)
+		}
+		p.res.File = append(p.res.File, migrations...)
+	}
+
 	// set supported features
 	p.res.SupportedFeatures = proto.Uint64(uint64(plugingo.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL))
 
@@ -143,6 +154,22 @@ func (p *Plugin) parseCRUDSchemasParam() bool {
 	return p.param["create_crud_table_schemas"] == "true"
 }
 
+func (p *Plugin) parseEmitSchemaParam() bool {
+	return p.param["emit_schema"] == "true"
+}
+
+// migrationFiles returns the migration files of the schema changes since the snapshot in the directory.
+// The migration name can be set by the migration_name parameter.
+func (p *Plugin) migrationFiles(dir string) ([]*plugingo.CodeGeneratorResponse_File, error) {
+	dialect, err := schemapkg.NewDialect(provider.ParseFromString(p.state.Provider).String())
+	if err != nil {
+		return nil, err
+	}
+
+	current := schemapkg.Build(p.state, dialect)
+	return schemapkg.Migrations(dialect, current, dir, p.param["migration_name"], p.state.FileToGenerate)
+}
+
 // parsePathType parses the path type from the parameters.
 func (p *Plugin) parsePathType() {
 	switch p.param["paths"] {
@@ -1,7 +1,13 @@
 package clickhouse
 
 import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	templaterpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/clickhouse/templater"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
@@ -11,6 +17,10 @@ type Clickhouse struct{}
 
 // GetInitStatement returns the initialization statement.
 func (p *Clickhouse) GetInitStatement(s *statepkg.State) (statepkg.Templater, error) {
+	if err := checkOptions(s); err != nil {
+		return nil, err
+	}
+
 	templater := templaterpkg.NewInitTemplater(s)
 	s.ImportsFromTable([]statepkg.Templater{templater})
 
@@ -43,3 +53,41 @@ func (p *Clickhouse) GetFinalizeStatement(s *statepkg.State) (statepkg.Templater
 	s.ImportsFromTable([]statepkg.Templater{table})
 	return table, nil
 }
+
+// checkOptions returns an error if the proto file uses the options which the clickhouse storages don't support.
+func checkOptions(state *statepkg.State) error {
+	if schemas := state.SchemaNames(); len(schemas) > 0 {
+		return fmt.Errorf("the schema option is not supported by clickhouse: %s", strings.Join(schemas, ", "))
+	}
+	if fields := state.FieldsWith(isEncrypted); len(fields) > 0 {
+		return fmt.Errorf("the encrypted and blind_index options are not supported by clickhouse: %s", strings.Join(fields, ", "))
+	}
+	if fields := state.FieldsWith(hasValidation); len(fields) > 0 {
+		return fmt.Errorf("the validation options are not supported by clickhouse: %s", strings.Join(fields, ", "))
+	}
+	if len(state.Methods) > 0 {
+		methods := make([]string, 0, len(state.Methods))
+		for _, method := range state.Methods {
+			methods = append(methods, method.Name)
+		}
+		return fmt.Errorf("the service methods are not supported by clickhouse: %s", strings.Join(methods, ", "))
+	}
+	return nil
+}
+
+// isEncrypted returns true if the field has the encrypted or the blind_index option.
+func isEncrypted(f *descriptorpb.FieldDescriptorProto) bool {
+	if opts := helperpkg.GetFieldOptions(f); opts != nil {
+		return opts.GetEncrypted() || opts.GetBlindIndex()
+	}
+	return false
+}
+
+// hasValidation returns true if the field has the validation options.
+func hasValidation(f *descriptorpb.FieldDescriptorProto) bool {
+	if opts := helperpkg.GetFieldOptions(f); opts != nil {
+		return opts.Min != nil || opts.Max != nil || opts.MinLen != nil || opts.MaxLen != nil ||
+			opts.GetPattern() != "" || opts.GetEmail() || opts.GetNotEmpty() || opts.GetUuidFormat()
+	}
+	return false
+}
@@ -73,10 +73,30 @@ func (i *initTemplater) BuildTemplate() string {
 			Name: "options",
 			Body: tmplpkg.OptionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert",
+			Body: tmplpkg.UpsertTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "conditions",
 			Body: tmplpkg.TableConditionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "filters",
+			Body: tmplpkg.FiltersTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate",
+			Body: tmplpkg.AggregateTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "preload",
+			Body: tmplpkg.PreloadTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "upgrade",
+			Body: tmplpkg.UpgradeTemplate,
+		},
 	)
 	if err != nil {
 		log.Fatalf("failed to execute template: %v", err)
@@ -97,6 +117,12 @@ func (i *initTemplater) Imports() importpkg.ImportSet {
 		importpkg.ImportContext,
 		importpkg.ImportSquirrel,
 		importpkg.ImportClickhouseDriver,
+		importpkg.ImportReflect,
+		importpkg.ImportSort,
+		importpkg.ImportStrconv,
+		importpkg.ImportTime,
+		importpkg.ImportMath,
+		importpkg.ImportURL,
 	)
 
 	if i.IncludeConnection {
@@ -11,6 +11,7 @@ import (
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	tmplpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/clickhouse/tmpl"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -60,6 +61,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "batch_create_method",
 			Body: tmplpkg.TableBatchCreateMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert_method",
+			Body: tmplpkg.TableUpsertMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "update_method",
 			Body: tmplpkg.TableUpdateMethodTemplate,
@@ -88,6 +93,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "count_method",
 			Body: tmplpkg.TableCountMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate_method",
+			Body: tmplpkg.TableAggregateMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "find_with_pagination",
 			Body: tmplpkg.TableFindWithPaginationMethodTemplate,
@@ -132,9 +141,15 @@ func (t *tableTemplater) Imports() importpkg.ImportSet {
 	if strings.Contains(tmp, "time.Time") {
 		is.Add(importpkg.ImportTime)
 	}
+	if strings.Contains(tmp, "url.Values") {
+		is.Add(importpkg.ImportURL)
+	}
 	if strings.Contains(tmp, "null.") {
 		is.Add(importpkg.ImportNull)
 	}
+	if strings.Contains(tmp, "fmt.") {
+		is.Add(importpkg.ImportFMT)
+	}
 
 	return is
 }
@@ -277,6 +292,14 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return statepkg.Messages{newMess}
 		},
 
+		// jsonElementType returns the Go type of the elements of the repeated JSON field.
+		"jsonElementType": helperpkg.JSONElementType,
+		// isScalarFilter returns true if the filter of the field can be parsed from a query string value.
+		"isScalarFilter": helperpkg.IsScalarFilter,
+		// isRangeFilter returns true if the field can be filtered by a range of values.
+		"isRangeFilter": helperpkg.IsRangeFilter,
+		// isListFilter returns true if the field can be filtered by a list of the allowed values.
+		"isListFilter": helperpkg.IsListFilter,
 		// isJSON returns the field type.
 		"isJSON": func(f *descriptorpb.FieldDescriptorProto) bool {
 			return t.state.NestedMessages.IsJSON(f)
@@ -312,6 +335,23 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// sumType returns the Go type of the sum of the numeric field, it is empty if the field can't be summed.
+		"sumType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) {
+				return ""
+			}
+			return helperpkg.SumType(f)
+		},
+
+		// minMaxType returns the Go type of the smallest and the largest values of the numeric or timestamp field,
+		// it is empty for the other fields.
+		"minMaxType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) || (helperpkg.SumType(f) == "" && !helperpkg.IsTimestamp(f)) {
+				return ""
+			}
+			return helperpkg.ClearPointer(helperpkg.ConvertType(f))
+		},
+
 		"isValidGT": func(f *descriptorpb.FieldDescriptorProto) bool {
 			if f == nil {
 				return false
@@ -630,6 +670,17 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// conflictTargets returns the primary key if it is inserted, the inserted rows replace the rows
+		// with the same sorting key of the ReplacingMergeTree table. The unique fields are not the targets,
+		// the ReplacingMergeTree replaces the rows by the sorting key only, and the tables with the generated
+		// primary key have no Upsert, the inserted row never has the primary key of the existing one.
+		"conflictTargets": func() [][]*descriptorpb.FieldDescriptorProto {
+			if pk := helperpkg.InsertedPrimaryKey(t.message); len(pk) > 0 {
+				return [][]*descriptorpb.FieldDescriptorProto{pk}
+			}
+			return nil
+		},
+
 		"getStructureUniqueIndexes": func() map[int][]*descriptorpb.FieldDescriptorProto {
 			var indexes = make(map[int][]*descriptorpb.FieldDescriptorProto)
 			if opts := helperpkg.GetMessageOptions(t.message); opts != nil {
@@ -860,6 +911,26 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return helperpkg.Plural(t.message.GetName())
 		},
 
+		// quoteIdent returns the quoted identifier.
+		"quoteIdent": func(name string) string {
+			return helperpkg.QuoteIdent("", name)
+		},
+
+		// createTableStatements returns the statements which create the table.
+		"createTableStatements": func() []string {
+			return schemapkg.CreateStatements(schemapkg.ClickHouse, schemapkg.BuildTable(t.state, schemapkg.ClickHouse, t.message))
+		},
+
+		// upgradeColumns returns the columns which the table upgrade compares with the live table.
+		"upgradeColumns": func() []*schemapkg.UpgradeColumn {
+			return schemapkg.UpgradeColumns(schemapkg.ClickHouse, schemapkg.BuildTable(t.state, schemapkg.ClickHouse, t.message))
+		},
+
+		// upgradeIndexes returns the data skipping indexes which the table upgrade adds.
+		"upgradeIndexes": func() []*schemapkg.UpgradeIndex {
+			return schemapkg.UpgradeIndexes(schemapkg.ClickHouse, schemapkg.BuildTable(t.state, schemapkg.ClickHouse, t.message))
+		},
+
 		"pluralFieldName": func(f *descriptorpb.FieldDescriptorProto) string {
 			if helperpkg.IsRepeated(f) {
 				return helperpkg.UpperCamelCase(helperpkg.Plural(f.GetName()))
@@ -31,18 +31,55 @@ func toInterface[T any](s []T) []interface{} {
 	return result
 }
 
+// ToSql returns no WHERE expression, the condition adds the JOIN clause.
+func (c JoinCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
+// Apply applies the condition to the query, the ON expression is rendered by the condition tree.
 func (c JoinCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	onQuery := c.On.Apply(sq.Select("*"))
-	onClause, args, _ := onQuery.ToSql()
-	onClause = strings.TrimPrefix(onClause, "SELECT * WHERE ")
-	joinExpr := fmt.Sprintf("%s JOIN %s ON %s", c.Type, c.Table.TableName(), onClause)
-	return query.JoinClause(sq.Expr(joinExpr, args...))
+	joinExpr := fmt.Sprintf("%s JOIN %s ON ?", c.Type, c.Table.TableName())
+	return query.JoinClause(sq.Expr(joinExpr, c.On))
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JoinCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
 }
 
 func (c JoinCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
 	return query
 }
 
+// applyClauses adds the JOIN clause of the condition nested in And, Or or Not.
+func (c JoinCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clauseApplier is implemented by the conditions which change the clauses of the query other than WHERE,
+// e.g. ORDER BY, and by the combinators which may nest them.
+type clauseApplier interface {
+	applyClauses(query sq.SelectBuilder) sq.SelectBuilder
+}
+
+// applyClauses applies the clauses other than WHERE of the conditions to the query.
+func applyClauses(query sq.SelectBuilder, conditions ...FilterApplier) sq.SelectBuilder {
+	for _, condition := range conditions {
+		if c, ok := condition.(clauseApplier); ok {
+			query = c.applyClauses(query)
+		}
+	}
+	return query
+}
+
+// hasExpr returns true if the condition has the WHERE expression, the combinators of the ORDER BY and JOIN
+// conditions have none and must not be added to the WHERE clause. The conditions failing to render have it,
+// so the error is returned by the query.
+func hasExpr(condition sq.Sqlizer) bool {
+	sql, _, err := condition.ToSql()
+	return err != nil || sql != ""
+}
+
 // And returns a condition that combines the given conditions with AND.
 type AndCondition struct {
 	Where []FilterApplier
@@ -53,20 +90,43 @@ func And(conditions ...FilterApplier) FilterApplier {
 	return AndCondition{Where: conditions}
 }
 
-// And returns a condition that combines the given conditions with AND.
-func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+// ToSql returns the WHERE expressions of the conditions combined with AND.
+func (c AndCondition) ToSql() (string, []interface{}, error) {
+	and := make(sq.And, 0, len(c.Where))
 	for _, condition := range c.Where {
-		query = condition.Apply(query)
+		and = append(and, condition)
 	}
-	return query
+	return and.ToSql()
 }
 
-// And returns a condition that combines the given conditions with AND.
+// Apply applies the condition to the query.
+func (c AndCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c AndCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c AndCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	for _, condition := range c.Where {
-		query = condition.ApplyDelete(query)
+	if !hasExpr(c) {
+		return query
 	}
-	return query
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c AndCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Where...)
 }
 
 //
@@ -82,32 +142,92 @@ func Or(conditions ...FilterApplier) FilterApplier {
 	return OrCondition{Conditions: conditions}
 }
 
-// Apply applies the condition to the query.
-func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	or := sq.Or{}
+// ToSql returns the WHERE expressions of the conditions combined with OR.
+func (c OrCondition) ToSql() (string, []interface{}, error) {
+	or := make(sq.Or, 0, len(c.Conditions))
 	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+		or = append(or, condition)
 	}
-	return query.Where(or)
+	return or.ToSql()
 }
 
 // Apply applies the condition to the query.
+func (c OrCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c OrCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c OrCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	or := sq.Or{}
-	for _, condition := range c.Conditions {
-		subQuery := condition.Apply(sq.Select("*"))
-		// Extract WHERE clause from the subquery
-		whereParts, args, _ := subQuery.ToSql()
-		whereParts = strings.TrimPrefix(whereParts, "SELECT * WHERE ")
-		// Append the WHERE clause to the OR condition
-		or = append(or, sq.Expr(whereParts, args...))
+	if !hasExpr(c) {
+		return query
 	}
-	return query.Where(or)
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested conditions.
+func (c OrCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Conditions...)
+}
+
+// NotCondition negates the condition.
+type NotCondition struct {
+	Condition FilterApplier
+}
+
+// Not returns a condition that checks if the condition is false, e.g. Not(Or(Eq("a", 1), Eq("b", 2))).
+func Not(condition FilterApplier) FilterApplier {
+	return NotCondition{Condition: condition}
+}
+
+// ToSql returns the negated WHERE expression of the condition.
+func (c NotCondition) ToSql() (string, []interface{}, error) {
+	sql, args, err := c.Condition.ToSql()
+	if err != nil || sql == "" {
+		return sql, args, err
+	}
+	return "NOT (" + sql + ")", args, nil
+}
+
+// Apply applies the condition to the query.
+func (c NotCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	query = c.applyClauses(query)
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c NotCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	if !hasExpr(c) {
+		return query
+	}
+	return query.Where(c)
+}
+
+// applyClauses applies the clauses other than WHERE of the nested condition.
+func (c NotCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return applyClauses(query, c.Condition)
 }
 
 // EqualsCondition equals condition.
@@ -116,13 +236,24 @@ type EqualsCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c EqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c EqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+	return query.Where(c)
 }
 
+// ApplyUpdate applies the condition to the query.
+func (c EqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
 func (c EqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // Eq returns a condition that checks if the field equals the value.
@@ -137,14 +268,24 @@ type BetweenCondition struct {
 	Max   interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c BetweenCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max).ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c BetweenCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c BetweenCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c BetweenCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(fmt.Sprintf("%s BETWEEN ? AND ?", c.Field), c.Min, c.Max))
+	return query.Where(c)
 }
 
 // Between returns a condition that checks if the field is between the min and max values.
@@ -158,14 +299,24 @@ type NotEqualsCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotEqualsCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotEq returns a condition that checks if the field equals the value.
@@ -179,14 +330,24 @@ type GreaterThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Gt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Gt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThan returns a condition that checks if the field equals the value.
@@ -200,14 +361,24 @@ type LessThanCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanCondition) ToSql() (string, []interface{}, error) {
+	return sq.Lt{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Lt{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // LessThan returns a condition that checks if the field equals the value.
@@ -221,14 +392,24 @@ type GreaterThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c GreaterThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.GtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c GreaterThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c GreaterThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c GreaterThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.GtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // GreaterThanOrEqual returns a condition that checks if the field equals the value.
@@ -242,14 +423,24 @@ type LessThanOrEqualCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LessThanOrEqualCondition) ToSql() (string, []interface{}, error) {
+	return sq.LtOrEq{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LessThanOrEqualCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LessThanOrEqualCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LessThanOrEqualCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.LtOrEq{c.Field: c.Value})
+	return query.Where(c)
 }
 
 func LessThanOrEq(field string, value interface{}) FilterApplier {
@@ -262,14 +453,24 @@ type ILikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c ILikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.ILike{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c ILikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.ILike{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c ILikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c ILikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.ILike{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // ILike returns a condition that checks if the field equals the value.
@@ -283,14 +484,24 @@ type LikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c LikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.Like{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c LikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c LikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c LikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Like{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // Like returns a condition that checks if the field equals the value.
@@ -304,14 +515,24 @@ type NotLikeCondition struct {
 	Value interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotLikeCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotLike{c.Field: c.Value}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotLikeCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotLikeCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotLikeCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotLike{c.Field: c.Value})
+	return query.Where(c)
 }
 
 // NotLike returns a condition that checks if the field equals the value.
@@ -324,14 +545,24 @@ type IsNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NULL"))
+	return query.Where(c)
 }
 
 // IsNull returns a condition that checks if the field is null.
@@ -344,14 +575,24 @@ type IsNotNullCondition struct {
 	Field string
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c IsNotNullCondition) ToSql() (string, []interface{}, error) {
+	return sq.Expr(c.Field + " IS NOT NULL").ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c IsNotNullCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c IsNotNullCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c IsNotNullCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Expr(c.Field + " IS NOT NULL"))
+	return query.Where(c)
 }
 
 // IsNotNull returns a condition that checks if the field is not null.
@@ -365,14 +606,24 @@ type InCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c InCondition) ToSql() (string, []interface{}, error) {
+	return sq.Eq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c InCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c InCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.Eq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // In returns a condition that checks if the field is in the given values.
@@ -386,14 +637,24 @@ type NotInCondition struct {
 	Values []interface{}
 }
 
+// ToSql returns the WHERE expression of the condition.
+func (c NotInCondition) ToSql() (string, []interface{}, error) {
+	return sq.NotEq{c.Field: c.Values}.ToSql()
+}
+
 // Apply applies the condition to the query.
 func (c NotInCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c NotInCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
 func (c NotInCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query.Where(sq.NotEq{c.Field: c.Values})
+	return query.Where(c)
 }
 
 // NotIn returns a condition that checks if the field is not in the given values.
@@ -412,19 +673,270 @@ func OrderBy(column string, asc bool) FilterApplier {
 	return OrderCondition{Column: column, Asc: asc}
 }
 
+// ToSql returns no WHERE expression, the condition adds the ORDER BY clause.
+func (c OrderCondition) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
 // Apply applies the condition to the query.
 func (c OrderCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.OrderBy(c.clause())
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c OrderCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query
+}
+
+// ApplyDelete applies the condition to the query.
+func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query
+}
+
+// applyClauses adds the ORDER BY clause of the condition nested in And, Or or Not.
+func (c OrderCondition) applyClauses(query sq.SelectBuilder) sq.SelectBuilder {
+	return c.Apply(query)
+}
+
+// clause returns the ORDER BY clause of the column.
+func (c OrderCondition) clause() string {
 	if c.Asc {
-		return query.OrderBy(c.Column + " ASC")
+		return c.Column + " ASC"
 	}
 
 	// default to descending.
-	return query.OrderBy(c.Column + " DESC")
+	return c.Column + " DESC"
+}
+
+// InSubqueryCondition checks if the field is in the values selected by the subquery.
+type InSubqueryCondition struct {
+	Field string
+	Query sq.SelectBuilder
+	// Conditions are applied to the subquery when the condition is built.
+	Conditions []FilterApplier
+}
+
+// InSubquery returns a condition that checks if the field is in the values selected by the query,
+// e.g. InSubquery("id", sq.Select("user_id").From("bots").Where(sq.Eq{"published": true})).
+func InSubquery(field string, query sq.SelectBuilder) FilterApplier {
+	return InSubqueryCondition{Field: field, Query: query}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c InSubqueryCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c InSubqueryCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c InSubqueryCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
 }
 
 // ApplyDelete applies the condition to the query.
-func (c OrderCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
-	return query
+func (c InSubqueryCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the IN expression of the subquery, the placeholders of the subquery are numbered by the outer query.
+func (c InSubqueryCondition) expr() sq.Sqlizer {
+	query := c.Query
+	for _, condition := range c.Conditions {
+		query = condition.Apply(query)
+	}
+	sql, args, err := query.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		return errorExpr{err: errors.Wrap(err, "failed to build subquery")}
+	}
+	return sq.Expr(c.Field+" IN ("+sql+")", args...)
+}
+
+// errorExpr is the expression which failed to build, ToSql returns the build error.
+type errorExpr struct {
+	err error
 }
 
+// ToSql returns the build error of the expression.
+func (e errorExpr) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
+
+// JSONContainsCondition checks if the JSON field contains the value: the keys and the values of an object
+// or the elements of an array.
+type JSONContainsCondition struct {
+	Field string
+	Value interface{}
+}
+
+// JSONContains returns a condition that checks if the JSON field contains the value,
+// e.g. JSONContains("settings", map[string]interface{}{"theme": "dark"}) or JSONContains("phones", []string{"+1"}).
+func JSONContains(field string, value interface{}) FilterApplier {
+	return JSONContainsCondition{Field: field, Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONContainsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONContainsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONContainsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONContainsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONHasKeyCondition checks if the JSON object has the top-level key.
+type JSONHasKeyCondition struct {
+	Field string
+	Key   string
+}
+
+// JSONHasKey returns a condition that checks if the JSON object has the top-level key.
+func JSONHasKey(field, key string) FilterApplier {
+	return JSONHasKeyCondition{Field: field, Key: key}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONHasKeyCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONHasKeyCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONHasKeyCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// JSONPathEqualsCondition checks if the value at the path of the JSON object equals the value.
+type JSONPathEqualsCondition struct {
+	Field string
+	Path  []string
+	Value interface{}
+}
+
+// JSONPathEq returns a condition that checks if the value at the dot-separated path of the JSON object
+// equals the value, e.g. JSONPathEq("meta", "device.os", "ios"). The numeric keys index the arrays from 0.
+func JSONPathEq(field, path string, value interface{}) FilterApplier {
+	return JSONPathEqualsCondition{Field: field, Path: strings.Split(path, "."), Value: value}
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c JSONPathEqualsCondition) ToSql() (string, []interface{}, error) {
+	return c.expr().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c JSONPathEqualsCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c JSONPathEqualsCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// expr returns the JSONExtract expressions of the keys and the elements of the value.
+func (c JSONContainsCondition) expr() sq.Sqlizer {
+	raw, _ := json.Marshal(c.Value)
+	var value interface{}
+	_ = json.Unmarshal(raw, &value)
+	sql, args := jsonContains(c.Field, value, 0)
+	return sq.Expr(sql, args...)
+}
+
+// expr returns the JSONHas expression of the key.
+func (c JSONHasKeyCondition) expr() sq.Sqlizer {
+	return sq.Expr("JSONHas("+c.Field+", ?)", c.Key)
+}
+
+// expr returns the JSONExtract expression of the path, the function is chosen by the type of the value.
+func (c JSONPathEqualsCondition) expr() sq.Sqlizer {
+	args := make([]interface{}, 0, len(c.Path)+1)
+	for _, key := range c.Path {
+		// the arrays are indexed from 1
+		if index, err := strconv.Atoi(key); err == nil {
+			args = append(args, index+1)
+			continue
+		}
+		args = append(args, key)
+	}
+	keys := strings.TrimSuffix(strings.Repeat("?, ", len(c.Path)), ", ")
+
+	switch v := c.Value.(type) {
+	case string:
+		return sq.Expr("JSONExtractString("+c.Field+", "+keys+") = ?", append(args, v)...)
+	case bool:
+		return sq.Expr("JSONExtractBool("+c.Field+", "+keys+") = ?", append(args, v)...)
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		return sq.Expr("JSONExtractFloat("+c.Field+", "+keys+") = ?", append(args, v)...)
+	default:
+		raw, _ := json.Marshal(v)
+		return sq.Expr("JSONExtractRaw("+c.Field+", "+keys+") = ?", append(args, string(raw))...)
+	}
+}
+
+// jsonContains returns the condition that the raw JSON document doc contains the value,
+// the objects are matched by their keys and the arrays by their elements.
+func jsonContains(doc string, value interface{}, depth int) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sql, a := jsonContains("JSONExtractRaw("+doc+", ?)", v[key], depth)
+			parts = append(parts, sql)
+			args = append(args, key)
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "JSONType(" + doc + ") = 'Object'", nil
+		}
+	case []interface{}:
+		alias := fmt.Sprintf("j%d", depth)
+		for _, element := range v {
+			sql, a := jsonContains(alias, element, depth+1)
+			parts = append(parts, "arrayExists("+alias+" -> "+sql+", JSONExtractArrayRaw("+doc+"))")
+			args = append(args, a...)
+		}
+		if len(parts) == 0 {
+			return "JSONType(" + doc + ") = 'Array'", nil
+		}
+	default:
+		raw, _ := json.Marshal(v)
+		return doc + " = ?", []interface{}{string(raw)}
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", args
+}
 `
@@ -44,11 +44,31 @@ const InitStatementTemplate = `
 // Options.
 // 
 {{ template "options" . }}
+//
+// Upsert.
+//
+{{ template "upsert" . }}
 
 // 
 // Conditions for query builder.
 // 
 {{ template "conditions" . }}
+//
+// Filters from query strings.
+//
+{{ template "filters" . }}
+//
+// Aggregations.
+//
+{{ template "aggregate" . }}
+//
+// Eager loading.
+//
+{{ template "preload" . }}
+//
+// Table upgrades.
+//
+{{ template "upgrade" . }}
 `
 
 const OptionsTemplate = `
@@ -61,6 +81,8 @@ type Options struct {
 	relations bool
 	// uniqField is the unique field.
 	uniqField string
+	// preloads are the relations loaded by the Preload option.
+	preloads []preload
 }
 
 // WithRelations sets the relations flag.
@@ -79,8 +101,11 @@ func WithUniqField(field string) Option {
 }
 
 // FilterApplier is a condition filters.
+// The condition is the SQL expression, so the conditions nest with And, Or and Not.
 type FilterApplier interface {
+	sq.Sqlizer
 	Apply(query sq.SelectBuilder) sq.SelectBuilder
+	ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder
 	ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder
 }
 
@@ -106,8 +131,16 @@ type QueryBuilder struct {
 	}
 	// customTableName is the custom table name.
 	customTableName string
+	// columns are the selected columns, all the columns are selected if it is empty.
+	columns []Column
+	// preloads are the relations loaded with the found models.
+	preloads []preload
 }
 
+// Column is the column name of a table.
+// The columns of every model are generated as constants, e.g. UserColumnName.
+type Column string
+
 // NewQueryBuilder returns a new query builder.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{}
@@ -179,6 +212,12 @@ func (b *QueryBuilder) WithPagination(pagination *Pagination) *QueryBuilder {
 	return b
 }
 
+// WithColumns selects only the given columns, the other fields of the found models are left empty.
+func (b *QueryBuilder) WithColumns(columns ...Column) *QueryBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
 // Filter is a helper function to create a new query builder with filter options.
 func FilterBuilder(filterOptions ...FilterApplier) *QueryBuilder {
 	return NewQueryBuilder().WithFilter(filterOptions...)
@@ -208,6 +247,102 @@ func PaginateBuilder(limit, offset uint64) *QueryBuilder {
 	return NewQueryBuilder().WithPagination(NewPagination(limit, offset))
 }
 
+// ColumnsBuilder is a helper function to create a new query builder which selects the given columns.
+func ColumnsBuilder(columns ...Column) *QueryBuilder {
+	return NewQueryBuilder().WithColumns(columns...)
+}
+
+// ColumnQuerier queries the rows of the given columns, it is implemented by every storage.
+type ColumnQuerier interface {
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+}
+
+// FindManyInto selects the columns of the db tags of T and scans the found rows into T.
+// It lets the list queries skip the heavy columns, e.g.
+//
+//	type userItem struct {
+//		Id   string ` + "`" + `db:"id"` + "`" + `
+//		Name string ` + "`" + `db:"name"` + "`" + `
+//	}
+//
+//	items, err := FindManyInto[userItem](ctx, storages.GetUserStorage(), builder)
+func FindManyInto[T any](ctx context.Context, querier ColumnQuerier, builders ...*QueryBuilder) ([]*T, error) {
+	fields, err := dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+
+	rows, err := querier.QueryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		value := reflect.ValueOf(result).Elem()
+		dest := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			dest = append(dest, value.FieldByIndex(field.index).Addr().Interface())
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// dbField is the struct field which is scanned from the column of its db tag.
+type dbField struct {
+	column Column
+	index  []int
+}
+
+// dbFields returns the fields of the struct with the db tags.
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%s is not a struct", typ)
+	}
+
+	fields := collectDBFields(typ)
+	if len(fields) == 0 {
+		return nil, errors.Errorf("%s has no fields with the db tag", typ)
+	}
+	return fields, nil
+}
+
+// collectDBFields collects the fields with the db tags, the embedded structs without the tag are included.
+func collectDBFields(typ reflect.Type) []dbField {
+	var fields []dbField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, f := range collectDBFields(field.Type) {
+				fields = append(fields, dbField{column: f.column, index: append([]int{i}, f.index...)})
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, dbField{column: Column(tag), index: field.Index})
+	}
+	return fields
+}
+
 // Pagination is the pagination.
 type Pagination struct {
 	// limit is the limit.
@@ -289,6 +424,8 @@ type {{ storageName }} interface {
 	// Get{{ $value.Value }} returns the {{ $value.Value }} store.
 	Get{{ $value.Value }}() {{ $value.Value }}
 	{{- end }}
+	// VerifySchema compares the live tables with the models.
+	VerifySchema(ctx context.Context) (*SchemaReport, error)
 
 {{ if .CRUDSchemas }}
 	// CreateTables creates the tables for all the stores.
@@ -333,6 +470,24 @@ func (c *{{ storageName | lowerCamelCase }}) Get{{ $value.Value }}() {{ $value.V
 }
 {{ end }}
 
+// VerifySchema compares the live tables of all the stores with the models.
+// The differences are listed in the report, the error is returned only if the schema can not be read.
+// Use report.Err() to fail fast at startup when the tables are behind the models.
+func (c *{{ storageName | lowerCamelCase }}) VerifySchema(ctx context.Context) (*SchemaReport, error) {
+	var err error
+	var drifts []SchemaDrift
+	report := &SchemaReport{}
+{{ range $value := storages }}
+	// verify the {{ $value.Value }} table.
+	drifts, err = c.{{ $value.Key }}.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+{{ end }}
+	return report, nil
+}
+
 {{ if .CRUDSchemas }}
 // CreateTables creates the tables for all the stores.
 // This is idempotent and safe to run multiple times.
@@ -377,16 +532,25 @@ func (c *{{ storageName | lowerCamelCase }}) TruncateTables(ctx context.Context)
 }
 
 // UpgradeTables runs the database upgrades for all the stores.
+// The drifts of all the tables are collected into one *SchemaDriftError.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) UpgradeTables(ctx context.Context) error {
 	var err error
+	var drifts []SchemaDrift
 {{ range $value := storages }}
 	// run the {{ $value.Value }} upgrade.
 	err = c.{{ $value.Key }}.UpgradeTable(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to upgrade table")
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return errors.Wrap(err, "failed to upgrade table")
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 {{ end }}
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 {{ end }}
@@ -0,0 +1,84 @@
+package tmpl
+
+// UpsertTemplate is the template for the conflict handling of the upsert.
+// This is included in the init template.
+const UpsertTemplate = `
+// Conflict is the conflict handling of the upsert, e.g. OnConflict(UserColumnId).DoUpdate().
+// The table with the primary key is the ReplacingMergeTree ordered by it, the inserted row replaces
+// the whole row with the same primary key when the parts are merged, so the conflict target is the primary key
+// and all the columns are updated.
+type Conflict struct {
+	// target are the columns of the unique constraint.
+	target []Column
+	// update are the updated columns, all the inserted columns are updated if it is empty.
+	update []Column
+	// nothing keeps the existing row.
+	nothing bool
+}
+
+// OnConflict returns the conflict on the primary key of the table.
+func OnConflict(columns ...Column) *Conflict {
+	return &Conflict{target: columns}
+}
+
+// DoUpdate updates the existing row with the inserted row, the columns must be empty, the whole row is replaced.
+func (c *Conflict) DoUpdate(columns ...Column) *Conflict {
+	c.update = columns
+	c.nothing = false
+	return c
+}
+
+// DoNothing keeps the existing row, it is not supported by the ReplacingMergeTree tables.
+func (c *Conflict) DoNothing() *Conflict {
+	c.update = nil
+	c.nothing = true
+	return c
+}
+
+// isConflictTarget returns true if the columns are the columns of one of the unique constraints in any order.
+func isConflictTarget(targets [][]Column, columns []Column) bool {
+	for _, target := range targets {
+		if len(target) != len(columns) {
+			continue
+		}
+		matched := true
+		for i, column := range columns {
+			if !containsColumn(target, column) || containsColumn(columns[:i], column) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsColumn returns true if the column is one of the columns.
+func containsColumn(columns []Column, column Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// replace checks that the conflict is handled by the replacing of the row with the same primary key.
+func (c *Conflict) replace(table string, targets [][]Column) error {
+	if c == nil {
+		return errors.New("conflict is nil")
+	}
+	if !isConflictTarget(targets, c.target) {
+		return errors.Errorf("the columns %v are not the primary key of the %s table", c.target, table)
+	}
+	if c.nothing {
+		return errors.Errorf("the rows of the %s table are replaced, the existing row can't be kept", table)
+	}
+	if len(c.update) > 0 {
+		return errors.Errorf("the rows of the %s table are replaced, the updated columns can't be chosen", table)
+	}
+	return nil
+}
+`
@@ -7,6 +7,7 @@ const TableTemplate = `
 {{ template "async_create_method" . }}
 {{ template "create_method" . }}
 {{ template "batch_create_method" . }}
+{{ template "upsert_method" . }}
 {{ template "update_method" . }}
 {{ template "delete_method" . }}
 {{- if (hasPrimaryKey) }}
@@ -15,6 +16,7 @@ const TableTemplate = `
 {{ template "find_many_method" . }}
 {{ template "find_one_method" . }}
 {{ template "count_method" . }}
+{{ template "aggregate_method" . }}
 {{ template "find_with_pagination" . }}
 {{ template "lock_method" . }}
 {{ template "raw_method" . }}
@@ -24,6 +26,7 @@ const TableConditionFilters = `
 {{ range $key, $fieldMess := messages_for_filter }}
 	{{- if len $fieldMess.GetField }}
 		// {{ $fieldMess.GetName | camelCase }}Filters is a struct that holds filters for {{ $fieldMess.GetName }}.
+		// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 		type {{structureName}}Filters struct {
 			{{ range $field := $fieldMess.GetField }}
 			{{- if not ($field | isRelation) }}
@@ -32,8 +35,83 @@ const TableConditionFilters = `
 				{{- else }}
 					{{ $field | fieldName }} *{{ $field | fieldType }}
 				{{- end }}
+				{{- if ($field | isRangeFilter) }}
+					{{ $field | fieldName }}From *{{ $field | fieldTypeWP }}
+					{{ $field | fieldName }}To *{{ $field | fieldTypeWP }}
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+					{{ $field | fieldName }}In []{{ $field | fieldTypeWP }}
+				{{- end }}
+			{{- end }}
+			{{- end }}
+		}
+
+		// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+		func (f *{{structureName}}Filters) Apply() *QueryBuilder {
+			if f == nil {
+				return NewQueryBuilder()
+			}
+			var filters []FilterApplier
+			{{- range $field := $fieldMess.GetField }}
+			{{- if not ($field | isRelation) }}
+			if f.{{ $field | fieldName }} != nil {
+				{{- if ($field | isJSON) }}
+				filters = append(filters, JSONContains("{{ $field.GetName }}", f.{{ $field | fieldName }}))
+				{{- else }}
+				filters = append(filters, EqualsCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}})
+				{{- end }}
+			}
+			{{- if ($field | isRangeFilter) }}
+			if f.{{ $field | fieldName }}From != nil {
+				filters = append(filters, GreaterThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}From})
+			}
+			if f.{{ $field | fieldName }}To != nil {
+				filters = append(filters, LessThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}To})
+			}
+			{{- end }}
+			{{- if ($field | isListFilter) }}
+			if f.{{ $field | fieldName }}In != nil {
+				filters = append(filters, InCondition{Field: "{{ $field.GetName }}", Values: toInterface(f.{{ $field | fieldName }}In)})
+			}
 			{{- end }}
 			{{- end }}
+			{{- end }}
+			return FilterBuilder(filters...)
+		}
+
+		// Parse{{structureName}}Filters parses the filters from the query string values, e.g. of an HTTP list request.
+		// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+		// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+		// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+		func Parse{{structureName}}Filters(values url.Values) (*{{structureName}}Filters, error) {
+			f := &{{structureName}}Filters{}
+			for _, key := range filterKeys(values) {
+				var err error
+				switch key {
+				{{- range $field := $fieldMess.GetField }}
+				{{- if and (not ($field | isRelation)) ($field | isScalarFilter) }}
+				case "{{ $field.GetName }}":
+					f.{{ $field | fieldName }}, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- if ($field | isRangeFilter) }}
+				case "{{ $field.GetName }}_from":
+					f.{{ $field | fieldName }}From, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				case "{{ $field.GetName }}_to":
+					f.{{ $field | fieldName }}To, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+				case "{{ $field.GetName }}_in":
+					f.{{ $field | fieldName }}In, err = parseFilterValues[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- end }}
+				{{- end }}
+				default:
+					return nil, errors.Errorf("unknown filter %q", key)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			return f, nil
 		}
 	{{- end }}
 {{ end }}
@@ -263,6 +341,41 @@ const TableConditionFilters = `
   {{ end }}
   {{ end }}
 {{ end }}
+{{ range $field := fields }}
+   {{- if ($field | isJSON) }}
+   {{- if ($field | isRepeated) }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON array contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value {{ $field | jsonElementType }}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", []{{ $field | jsonElementType }}{value})
+    }
+   {{- else }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON object contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value interface{}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", value)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}HasKey returns a condition that checks if the JSON object has the key.
+    func {{ structureName }}{{ $field.GetName | camelCase }}HasKey(key string) FilterApplier {
+      return JSONHasKey("{{ $field.GetName }}", key)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}PathEq returns a condition that checks if the value at the dot-separated path of the JSON object equals the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}PathEq(path string, value interface{}) FilterApplier {
+      return JSONPathEq("{{ $field.GetName }}", path, value)
+    }
+   {{- end }}
+  {{ end }}
+{{ end }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+
+// {{ structureName }}Has{{ $field | fieldName }} checks if the {{ structureName }} has the {{ $field | fieldName }} relation matching the filters.
+func {{ structureName }}Has{{ $field | fieldName }}(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string({{ $field | relationStructureName }}Column{{ $field | getRefID }})).From((&{{ $field | relationStructureName }}{}).TableName())
+	return InSubqueryCondition{Field: string({{ structureName }}Column{{ $field | getFieldID }}), Query: query, Conditions: filters}
+}
+{{- end }}
+{{- end }}
 `
 
 const TableFindWithPaginationMethodTemplate = ``
@@ -272,6 +385,251 @@ const TableLockMethodTemplate = ``
 const TableCountMethodTemplate = `
 `
 
+const TableAggregateMethodTemplate = `
+// {{ structureName }}AggregateRow is a row of the {{ structureName }} Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type {{ structureName }}AggregateRow struct {
+	{{ structureName }}
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *{{ structureName }}AggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*uint64); ok {
+		return int64(*v)
+	}
+	return 0
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
+
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values, it is set by Sum({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Sum{{ $field | fieldName }}() {{ $field | sumType }} {
+	if v, ok := r.values[Sum({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | sumType }}); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values, it is set by Avg({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Avg{{ $field | fieldName }}() *float64 {
+	if v, ok := r.values[Avg({{ structureName }}Column{{ $field | fieldName }})].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+{{- end }}
+{{- if ($field | minMaxType) }}
+
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value, it is set by Min({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Min{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Min({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
+
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value, it is set by Max({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Max{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Max({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
+
+// Aggregate groups the {{ structureName }} rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy({{ structureName }}ColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *{{ storageName | lowerCamelCase }}) Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{ structureName }}AggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of {{ structureName }}")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply custom table name
+		query = builder.ApplyCustomTableName(query)
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB().Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*{{ structureName }}AggregateRow
+	for rows.Next() {
+		row := &{{ structureName }}AggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.{{ structureName }}.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }} aggregate")
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*{{ structureName }}AggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &{{ structureName }}AggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
+
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error) {
+	row, err := t.aggregate(ctx, Sum({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.Sum{{ $field | fieldName }}(), nil
+}
+
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Avg{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- if ($field | minMaxType) }}
+
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Min({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Min{{ $field | fieldName }}(), nil
+}
+
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Max({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Max{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- end }}
+
+// aggregateDest returns the scan destination of the aggregation of the {{ structureName }} rows.
+func (t *{{ storageName | lowerCamelCase }}) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(uint64), nil
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	case Sum({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | sumType }}), nil
+	case Avg({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*float64), nil
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	case Min({{ structureName }}Column{{ $field | fieldName }}), Max({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | minMaxType }}), nil
+	{{- end }}
+	{{- end }}
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of {{ structureName }}", aggregation)
+}
+`
+
 const TableFindOneMethodTemplate = `
 // FindOne finds a single {{ structureName }} based on the provided options.
 func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error) {
@@ -292,9 +650,131 @@ func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builde
 
 const TableFindManyMethodTemplate = `
 // FindMany finds multiple {{ structureName }} based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
 func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*{{structureName}}
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+	
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the {{ structureName }} rows found by the builders to fn without loading them all into memory,
+// the rows are read from the driver.Rows stream. It stops at the first error of fn and returns it.
+func (t *{{ storageName | lowerCamelCase }}) FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *{{ storageName | lowerCamelCase }}) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *{{ storageName | lowerCamelCase }}) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *{{ storageName | lowerCamelCase }}) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *{{ storageName | lowerCamelCase }}) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (driver.Rows, error) {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -348,26 +828,8 @@ func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, build
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			t.logError(ctx, err, "failed to close rows")
-		}
-	}()
-
-	var results []*{{structureName}}
-	for rows.Next() {
-		model := &{{structureName}}{}
-		if err := model.ScanRow(rows); err != nil { // Используем ScanRow вместо ScanRows
-			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
-		}
-		results = append(results, model)
-	}
 
-	if err := rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "failed to iterate over rows")
-	}
-	
-	return results, nil
+	return rows, nil
 }
 `
 
@@ -420,12 +882,50 @@ func (t *{{ structureName }}) TableName() string {
 func (t *{{ structureName }}) ScanRow(row driver.Row) error {
 	return row.Scan(
 		{{- range $field := fields }}
+
 		{{- if not ($field | isRelation) }}
 		&t.{{ $field | fieldName }},
 		{{- end }}
 		{{- end }}
 	)
-}`
+}
+
+// ScanColumns scans the given columns of the row into the {{ structureName }}, the other fields are left empty.
+func (t *{{ structureName }}) ScanColumns(row driver.Row, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *{{ structureName }}) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		{{- range $field := fields }}
+		{{- if not ($field | isRelation) }}
+		case {{ $field | sourceName | printf "%q" }}:
+			dest = append(dest, &t.{{ $field | fieldName }})
+		{{- end }}
+		{{- end }}
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// {{ structureName }} columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	{{- range $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{ structureName }}Column{{ $field | fieldName }} Column = {{ $field | sourceName | printf "%q" }}
+	{{- end }}
+	{{- end }}
+)
+`
 
 const TableBatchCreateMethodTemplate = `
 // BatchCreate creates multiple {{ structureName }} records in a single batch.
@@ -711,16 +1211,39 @@ type {{ storageName | lowerCamelCase }} struct {
 	queryBuilder sq.StatementBuilderType
 }
 
+{{ if .CRUDSchemas }}
+// {{structureName}}TableManager is an interface for managing the {{ tableName }} table.
+type {{structureName}}TableManager interface {
+	CreateTable(ctx context.Context) error
+	DropTable(ctx context.Context) error
+	TruncateTable(ctx context.Context) error
+	UpgradeTable(ctx context.Context) error
+}
+{{ end }}
+
+// {{structureName}}SchemaVerification is an interface for verifying the {{ tableName }} table.
+type {{structureName}}SchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // {{structureName}}CRUDOperations is an interface for managing the {{ tableName }} table.
 type {{structureName}}CRUDOperations interface {
 	Create(ctx context.Context, model *{{structureName}}, opts ...Option) error
 	AsyncCreate(ctx context.Context, model *{{structureName}}, opts ...Option) error
 	BatchCreate(ctx context.Context, models []*{{structureName}}, opts ...Option) error
+	{{- if (conflictTargets) }}
+	Upsert(ctx context.Context, model *{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{IDType}}, {{ end }}error)
+	BatchUpsert(ctx context.Context, models []*{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error)
+	{{- end }}
 }
 
 // {{structureName}}SearchOperations is an interface for searching the {{ tableName }} table.
 type {{structureName}}SearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*{{structureName}}, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (driver.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error)
 }
 
@@ -730,6 +1253,21 @@ type {{structureName}}Settings interface {
 	SetQueryBuilder(builder sq.StatementBuilderType) {{ storageName }}
 }
 
+// {{structureName}}AggregateOperations is an interface for the aggregate queries.
+type {{structureName}}AggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{structureName}}AggregateRow, error)
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error)
+	Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	{{- end }}
+	{{- end }}
+}
+
 // {{structureName}}RelationLoading is an interface for loading relations.
 type {{structureName}}RelationLoading interface {
 	{{- range $index, $field := fields }}
@@ -754,8 +1292,13 @@ type {{structureName}}RawQueryOperations interface {
 
 // {{ storageName }} is a struct for the "{{ tableName }}" table.
 type {{ storageName }} interface {
+	{{- if .CRUDSchemas }}
+	{{structureName}}TableManager
+	{{- end }}
+	{{structureName}}SchemaVerification
 	{{structureName}}CRUDOperations
 	{{structureName}}SearchOperations
+	{{structureName}}AggregateOperations
 	{{structureName}}RelationLoading
 	{{structureName}}RawQueryOperations
 	{{structureName}}Settings
@@ -817,6 +1360,98 @@ func (t *{{ storageName | lowerCamelCase }}) SetQueryBuilder(builder sq.Statemen
 	return t
 }
 
+{{ if .CRUDSchemas }}
+// CreateTable creates the table.
+func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) error {
+	for _, statement := range []string{
+		{{- range $statement := createTableStatements }}
+		{{ $statement | printf "%q" }},
+		{{- end }}
+	} {
+		if err := t.DB().Exec(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropTable drops the table.
+func (t *{{ storageName | lowerCamelCase }}) DropTable(ctx context.Context) error {
+	return t.DB().Exec(ctx, {{ printf "DROP TABLE IF EXISTS %s" (tableName | quoteIdent) | printf "%q" }})
+}
+
+// TruncateTable truncates the table.
+func (t *{{ storageName | lowerCamelCase }}) TruncateTable(ctx context.Context) error {
+	return t.DB().Exec(ctx, {{ printf "TRUNCATE TABLE IF EXISTS %s" (tableName | quoteIdent) | printf "%q" }})
+}
+
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
+func (t *{{ storageName | lowerCamelCase }}) UpgradeTable(ctx context.Context) error {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		err = t.DB().Exec(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		err = t.DB().Exec(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to add index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+{{ end }}
+
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *{{ storageName | lowerCamelCase }}) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	live, err := introspectColumns(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, t.DB(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *{{ storageName | lowerCamelCase }}) tableColumns() []tableColumn {
+	return []tableColumn{
+		{{- range $column := upgradeColumns }}
+		{Name: {{ $column.Name | printf "%q" }}, Type: {{ $column.Type | printf "%q" }}, NotNull: {{ $column.NotNull }}, Statement: {{ $column.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
+// tableIndexes returns the data skipping indexes which the upgrade adds to the table.
+func (t *{{ storageName | lowerCamelCase }}) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{{- range $index := upgradeIndexes }}
+		{Name: {{ $index.Name | printf "%q" }}, Columns: {{ $index.Columns | printf "%#v" }}, Unique: {{ $index.Unique }}, Statement: {{ $index.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
 {{- range $index, $field := fields }}
 {{- if and ($field | isRelation) }}
 // Load{{ $field | pluralFieldName }} loads the {{ $field | pluralFieldName }} relation.
@@ -941,4 +1576,101 @@ func (t *{{ storageName | lowerCamelCase }}) LoadBatch{{ $field | pluralFieldNam
 }
 {{- end }}
 {{- end }}
+{{- $hasRelations := false }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+{{- $hasRelations = true }}
+{{- end }}
+{{- end }}
+
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *{{ storageName | lowerCamelCase }}) preload(ctx context.Context, items []*{{structureName}}, builders ...*QueryBuilder) error {
+	{{- if $hasRelations }}
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*{{structureName}}, ...*QueryBuilder) error
+		switch relation {
+		{{- range $field := fields }}
+		{{- if ($field | isRelation) }}
+		case {{ $field | fieldName | printf "%q" }}:
+			load = t.LoadBatch{{ $field | pluralFieldName }}
+		{{- end }}
+		{{- end }}
+		default:
+			return errors.Errorf("unknown relation %s of {{ structureName }}", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+	{{- else }}
+	if relations, _ := preloadRelations(builders); len(relations) > 0 {
+		return errors.Errorf("unknown relation %s of {{ structureName }}", relations[0])
+	}
+	{{- end }}
+
+	return nil
+}
+`
+
+const TableUpsertMethodTemplate = `
+{{- if (conflictTargets) }}
+// {{ structureName | lowerCamelCase }}ConflictTargets is the primary key of the {{ tableName }} table, the inserted row replaces the row with the same primary key.
+var {{ structureName | lowerCamelCase }}ConflictTargets = [][]Column{
+	{{- range $target := conflictTargets }}
+	{ {{- range $i, $field := $target }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end -}} },
+	{{- end }}
+}
+
+// Upsert inserts the {{ structureName }} which replaces the row with the same primary key,
+// e.g. Upsert(ctx, model, OnConflict({{ range $i, $field := index conflictTargets 0 }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end }}).DoUpdate()).
+// The table is the ReplacingMergeTree, the rows are replaced when the parts are merged, so the replaced row
+// can be found until then, use SELECT ... FINAL to find the latest rows only.
+// Only the primary key is the conflict target, the ReplacingMergeTree does not replace the rows by the unique fields.
+{{- if (hasID) }}
+// It returns the primary key of the inserted row.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) Upsert(ctx context.Context, model *{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{ IDType }}, {{ end }}error) {
+	if err := conflict.replace(t.TableName(), {{ structureName | lowerCamelCase }}ConflictTargets); err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+	{{- if (hasID) }}
+	if err := t.Create(ctx, model, opts...); err != nil {
+		return nil, err
+	}
+
+	id := model.Id
+	return &id, nil
+	{{- else }}
+	return t.Create(ctx, model, opts...)
+	{{- end }}
+}
+
+// BatchUpsert inserts multiple {{ structureName }} records in a single batch, they replace the rows with the same primary key.
+{{- if (hasID) }}
+// It returns the primary keys of the inserted rows.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) BatchUpsert(ctx context.Context, models []*{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error) {
+	if err := conflict.replace(t.TableName(), {{ structureName | lowerCamelCase }}ConflictTargets); err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+	{{- if (hasID) }}
+	if err := t.BatchCreate(ctx, models, opts...); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(models))
+	for _, model := range models {
+		ids = append(ids, fmt.Sprint(model.Id))
+	}
+	return ids, nil
+	{{- else }}
+	return t.BatchCreate(ctx, models, opts...)
+	{{- end }}
+}
+{{- end }}
 `
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"log"
+	"strings"
 	"text/template"
 
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	tmplpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/postgres/tmpl"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -23,6 +25,9 @@ type initTemplater struct {
 
 	// initMethods bool
 	CRUDSchemas bool
+
+	// is any field encrypted
+	EncryptedFields bool
 }
 
 // NewInitTemplater returns a new initTemplater.
@@ -32,6 +37,7 @@ func NewInitTemplater(state *statepkg.State) statepkg.Templater {
 
 		IncludeConnection: state.IncludeConnection,
 		CRUDSchemas:       state.CRUDSchemas,
+		EncryptedFields:   state.HasEncryptedFields(),
 	}
 }
 
@@ -73,10 +79,58 @@ func (i *initTemplater) BuildTemplate() string {
 			Name: "options",
 			Body: tmplpkg.OptionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert",
+			Body: tmplpkg.UpsertTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "update_expression",
+			Body: tmplpkg.UpdateExpressionTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "conditions",
 			Body: tmplpkg.TableConditionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "filters",
+			Body: tmplpkg.FiltersTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "cipher",
+			Body: tmplpkg.CipherTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "validation",
+			Body: tmplpkg.ValidationTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate",
+			Body: tmplpkg.AggregateTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "cursor",
+			Body: tmplpkg.CursorTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "join",
+			Body: tmplpkg.JoinTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "preload",
+			Body: tmplpkg.PreloadTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "search",
+			Body: tmplpkg.SearchTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "upgrade",
+			Body: tmplpkg.UpgradeTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "fixtures",
+			Body: tmplpkg.FixturesTemplate,
+		},
 	)
 	if err != nil {
 		log.Fatalf("failed to execute template: %v", err)
@@ -100,6 +154,16 @@ func (i *initTemplater) Imports() importpkg.ImportSet {
 		importpkg.ImportStrings,
 		importpkg.ImportContext,
 		importpkg.ImportSquirrel,
+		importpkg.ImportRegexp,
+		importpkg.ImportUTF8,
+		importpkg.ImportReflect,
+		importpkg.ImportBase64,
+		importpkg.ImportAtomic,
+		importpkg.ImportStrconv,
+		importpkg.ImportSort,
+		importpkg.ImportTime,
+		importpkg.ImportMath,
+		importpkg.ImportURL,
 	)
 
 	if i.IncludeConnection {
@@ -107,6 +171,27 @@ func (i *initTemplater) Imports() importpkg.ImportSet {
 		is.Add(importpkg.ImportStrconv)
 	}
 
+	if i.EncryptedFields {
+		is.Add(
+			importpkg.ImportCryptoAES,
+			importpkg.ImportCryptoCipher,
+			importpkg.ImportCryptoHMAC,
+			importpkg.ImportCryptoRand,
+			importpkg.ImportCryptoSHA256,
+			importpkg.ImportBase64,
+			importpkg.ImportHex,
+		)
+	}
+
+	if i.CRUDSchemas {
+		is.Add(
+			importpkg.ImportBytes,
+			importpkg.ImportIO,
+			importpkg.ImportIOFS,
+			importpkg.ImportYAML,
+		)
+	}
+
 	/*	tmp := i.BuildTemplate()
 		if strings.Contains(tmp, "time.Time") {
 			is.Add(importpkg.ImportTime)
@@ -218,7 +303,156 @@ func (i *initTemplater) Funcs() map[string]interface{} {
 		"sourceName": func(f *descriptorpb.FieldDescriptorProto) string {
 			return f.GetName()
 		},
+
+		// schemas returns the unique database schemas of the tables.
+		"schemas": func() []string {
+			return i.state.SchemaNames()
+		},
+
+		// quoteIdent returns the quoted identifier.
+		"quoteIdent": func(name string) string {
+			return helperpkg.QuoteIdent("", name)
+		},
+
+		// sortedStorages returns the storages in the dependency order, the referenced tables go first.
+		"sortedStorages": func() []KeyValuePair {
+			tables, _ := i.sortedTables()
+			return i.tableStorages(tables)
+		},
+
+		// reversedStorages returns the storages in the reverse dependency order to drop the tables.
+		"reversedStorages": func() []KeyValuePair {
+			tables, _ := i.sortedTables()
+			storages := i.tableStorages(tables)
+			for l, r := 0, len(storages)-1; l < r; l, r = l+1, r-1 {
+				storages[l], storages[r] = storages[r], storages[l]
+			}
+			return storages
+		},
+
+		// deferredForeignKeys returns the idempotent statements which add the foreign keys closing the reference cycles.
+		"deferredForeignKeys": func() []string {
+			tables, deferred := i.sortedTables()
+			var statements []string
+			for _, t := range tables {
+				var fks []*schemapkg.ForeignKey
+				for _, fk := range t.ForeignKeys {
+					if deferred[fk] {
+						fks = append(fks, fk)
+					}
+				}
+				statements = append(statements, idempotentStatements(schemapkg.ForeignKeyStatements(schemapkg.Postgres, t, fks))...)
+			}
+			return statements
+		},
+
+		// dropDeferredForeignKeys returns the statements which drop the foreign keys closing the reference cycles,
+		// so the tables of the cycle can be dropped one by one.
+		"dropDeferredForeignKeys": func() []string {
+			tables, deferred := i.sortedTables()
+			var statements []string
+			for _, t := range tables {
+				for _, fk := range t.ForeignKeys {
+					if deferred[fk] {
+						statements = append(statements, fmt.Sprintf("ALTER TABLE IF EXISTS %s DROP CONSTRAINT IF EXISTS %s;",
+							helperpkg.QuoteIdent(t.Schema, t.Name), helperpkg.QuoteIdent("", fk.Name)))
+					}
+				}
+			}
+			return statements
+		},
+
+		// truncateStatement returns the statement which truncates all the tables at once.
+		"truncateStatement": func() string {
+			tables, _ := i.sortedTables()
+			idents := make([]string, 0, len(tables))
+			for _, t := range tables {
+				idents = append(idents, helperpkg.QuoteIdent(t.Schema, t.Name))
+			}
+			return fmt.Sprintf("TRUNCATE TABLE %s CASCADE;", strings.Join(idents, ", "))
+		},
+
+		// fixtureTables returns the tables of the fixtures loader in the dependency order.
+		"fixtureTables": func() []fixtureTable {
+			tables, _ := i.sortedTables()
+			return i.fixtureTables(tables)
+		},
+	}
+}
+
+// fixtureTable is the table of the fixtures loader.
+type fixtureTable struct {
+	Key        string
+	Name       string
+	PrimaryKey string
+	Columns    []string
+	Relations  []fixtureRelation
+}
+
+// fixtureRelation is the relation field which sets the referencing column of the fixture row.
+type fixtureRelation struct {
+	Field     string
+	Column    string
+	Reference string
+}
+
+// fixtureTables returns the fixture tables of the schema tables.
+// Only the relations which hold the referencing column can be set by the fixtures.
+func (i *initTemplater) fixtureTables(tables []*schemapkg.Table) []fixtureTable {
+	result := make([]fixtureTable, 0, len(tables))
+	for _, t := range tables {
+		ft := fixtureTable{Key: t.Key(), Name: helperpkg.QuoteIdent(t.Schema, t.Name)}
+		if pk := t.PrimaryKey(); len(pk) == 1 {
+			ft.PrimaryKey = pk[0]
+		}
+		for _, c := range t.Columns {
+			ft.Columns = append(ft.Columns, c.Name)
+		}
+
+		for _, m := range i.state.Messages {
+			if schemapkg.TableKey(i.state, m) != t.Key() {
+				continue
+			}
+			for _, f := range m.GetField() {
+				opts := helperpkg.GetFieldOptions(f)
+				if opts == nil || opts.GetRelation() == nil {
+					continue
+				}
+				relation := opts.GetRelation()
+				if c := t.Column(relation.GetField()); c == nil || c.PrimaryKey {
+					continue
+				}
+				ft.Relations = append(ft.Relations, fixtureRelation{
+					Field:     f.GetName(),
+					Column:    relation.GetField(),
+					Reference: relation.GetReference(),
+				})
+			}
+		}
+		result = append(result, ft)
+	}
+	return result
+}
+
+// sortedTables returns the tables in the dependency order and the foreign keys closing the reference cycles.
+func (i *initTemplater) sortedTables() ([]*schemapkg.Table, map[*schemapkg.ForeignKey]bool) {
+	return schemapkg.SortTables(schemapkg.Build(i.state, schemapkg.Postgres).Tables)
+}
+
+// tableStorages returns the storages of the tables.
+func (i *initTemplater) tableStorages(tables []*schemapkg.Table) []KeyValuePair {
+	storages := make([]KeyValuePair, 0, len(tables))
+	for _, t := range tables {
+		for _, m := range i.state.Messages {
+			if schemapkg.TableKey(i.state, m) == t.Key() {
+				storages = append(storages, KeyValuePair{
+					Key:   helperpkg.LowerCamelCase(m.GetName()) + StoragePostfix,
+					Value: helperpkg.UpperCamelCase(m.GetName()) + StoragePostfix,
+				})
+			}
+		}
 	}
+	return storages
 }
 
 // DBClientPostfix is the postfix for the client name.
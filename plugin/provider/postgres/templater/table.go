@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"log"
+	"regexp"
 	"strings"
 	"text/template"
 
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	tmplpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/postgres/tmpl"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -21,6 +23,9 @@ type tableTemplater struct {
 
 	// initMethods bool
 	CRUDSchemas bool
+
+	// is any field of the file encrypted
+	EncryptedFields bool
 }
 
 // NewTableTemplater returns a new initTemplater.
@@ -29,7 +34,8 @@ func NewTableTemplater(message *descriptorpb.DescriptorProto, state *statepkg.St
 		state:   state,
 		message: message,
 
-		CRUDSchemas: state.CRUDSchemas,
+		CRUDSchemas:     state.CRUDSchemas,
+		EncryptedFields: state.HasEncryptedFields(),
 	}
 }
 
@@ -55,6 +61,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "batch_create_method",
 			Body: tmplpkg.TableBatchCreateMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert_method",
+			Body: tmplpkg.TableUpsertMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "update_method",
 			Body: tmplpkg.TableUpdateMethodTemplate,
@@ -75,6 +85,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "find_many_method",
 			Body: tmplpkg.TableFindManyMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "join_method",
+			Body: tmplpkg.TableJoinMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "find_one_method",
 			Body: tmplpkg.TableFindOneMethodTemplate,
@@ -83,6 +97,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "count_method",
 			Body: tmplpkg.TableCountMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate_method",
+			Body: tmplpkg.TableAggregateMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "find_with_pagination",
 			Body: tmplpkg.TableFindWithPaginationMethodTemplate,
@@ -91,10 +109,18 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "table_conditions",
 			Body: tmplpkg.TableConditionFilters,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "search_method",
+			Body: tmplpkg.TableSearchMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "lock_method",
 			Body: tmplpkg.TableLockMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "service_methods",
+			Body: tmplpkg.TableServiceMethodsTemplate,
+		},
 	)
 	if err != nil {
 		log.Fatalf("failed to execute template: %v", err)
@@ -129,9 +155,15 @@ func (t *tableTemplater) Imports() importpkg.ImportSet {
 	if strings.Contains(tmp, "time.Time") {
 		is.Add(importpkg.ImportTime)
 	}
+	if strings.Contains(tmp, "url.Values") {
+		is.Add(importpkg.ImportURL)
+	}
 	if strings.Contains(tmp, "null.") {
 		is.Add(importpkg.ImportNull)
 	}
+	if strings.Contains(tmp, "regexp.") {
+		is.Add(importpkg.ImportRegexp)
+	}
 
 	return is
 }
@@ -262,6 +294,10 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			for _, f := range newMess.GetField() {
 				opts := helperpkg.GetFieldOptions(f)
 				if opts != nil {
+					// encrypted fields can be searched only by the blind index.
+					if opts.GetEncrypted() {
+						continue
+					}
 					if opts.GetPrimaryKey() ||
 						opts.GetInFilter() ||
 						t.state.Relations.FindBy(f) ||
@@ -274,11 +310,105 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return statepkg.Messages{newMess}
 		},
 
+		// jsonElementType returns the Go type of the elements of the repeated JSON field.
+		"jsonElementType": helperpkg.JSONElementType,
+		// isScalarFilter returns true if the filter of the field can be parsed from a query string value.
+		"isScalarFilter": helperpkg.IsScalarFilter,
+		// isRangeFilter returns true if the field can be filtered by a range of values.
+		"isRangeFilter": helperpkg.IsRangeFilter,
+		// isListFilter returns true if the field can be filtered by a list of the allowed values.
+		"isListFilter": helperpkg.IsListFilter,
 		// isJSON returns the field type.
 		"isJSON": func(f *descriptorpb.FieldDescriptorProto) bool {
 			return t.state.NestedMessages.IsJSON(f)
 		},
 
+		// isEncrypted returns true if the field is encrypted.
+		"isEncrypted": func(f *descriptorpb.FieldDescriptorProto) bool {
+			if !helperpkg.IsEncrypted(f) {
+				return false
+			}
+			if f.GetType() != descriptorpb.FieldDescriptorProto_TYPE_STRING || helperpkg.IsRepeated(f) {
+				log.Fatalf("field %s.%s: only string fields can be encrypted", t.message.GetName(), f.GetName())
+			}
+			return true
+		},
+
+		// hasEncrypted returns true if the message has encrypted fields.
+		"hasEncrypted": func() bool {
+			for _, f := range t.message.GetField() {
+				if helperpkg.IsEncrypted(f) {
+					return true
+				}
+			}
+			return false
+		},
+
+		// hasBlindIndex returns true if the encrypted field has a blind index column.
+		"hasBlindIndex": helperpkg.HasBlindIndex,
+
+		// blindIndexColumn returns the blind index column name.
+		"blindIndexColumn": helperpkg.BlindIndexColumn,
+
+		// indexColumn returns the column to build the indexes on.
+		"indexColumn": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.HasBlindIndex(f) {
+				return helperpkg.BlindIndexColumn(f)
+			}
+			return f.GetName()
+		},
+
+		// hasValidation returns true if the field has validation rules.
+		"hasValidation": func(f *descriptorpb.FieldDescriptorProto) bool {
+			return len(validationChecks(t.message, f, "")) > 0
+		},
+
+		// validationChecks returns the validator calls for the field value.
+		"validationChecks": func(f *descriptorpb.FieldDescriptorProto, value string) []string {
+			return validationChecks(t.message, f, value)
+		},
+
+		// validationPattern returns the pattern of the field.
+		"validationPattern": func(f *descriptorpb.FieldDescriptorProto) string {
+			if !isValidationString(f) {
+				return ""
+			}
+			if opts := helperpkg.GetFieldOptions(f); opts != nil {
+				return opts.GetPattern()
+			}
+			return ""
+		},
+
+		// validationPatternVar returns the name of the compiled pattern variable.
+		"validationPatternVar": func(f *descriptorpb.FieldDescriptorProto) string {
+			return validationPatternVar(t.message, f)
+		},
+
+		// methods returns the service methods of the message.
+		"methods": func() statepkg.Methods {
+			return t.state.Methods.ByObject(t.message)
+		},
+
+		// methodRequests returns the request messages of the service methods declared with the message.
+		"methodRequests": func() statepkg.Messages {
+			return t.state.Methods.Requests(t.message)
+		},
+
+		// requestFieldType returns the go type of the request field.
+		"requestFieldType": func(f *descriptorpb.FieldDescriptorProto) string {
+			typ := helperpkg.ConvertType(f)
+			if f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && !strings.Contains(typ, "time.Time") {
+				log.Fatalf("field %s: only scalar and timestamp fields are supported in the method requests", f.GetName())
+			}
+			return typ
+		},
+
+		// methodConditions returns the statements which build the filters of the method.
+		"methodConditions": methodConditions,
+
+		// methodPagination returns the statements which build the pagination of the method.
+		"methodPagination": methodPagination,
+
 		// isLastField returns true if the field is the last field.
 		"isLastField": func(f *descriptorpb.FieldDescriptorProto) bool {
 			var fields []*descriptorpb.FieldDescriptorProto
@@ -309,6 +439,38 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// isCursorColumn returns true if the field can be a sort column of the keyset pagination:
+		// a non-null scalar or timestamp column.
+		"isCursorColumn": func(f *descriptorpb.FieldDescriptorProto) bool {
+			if helperpkg.IsRepeated(f) || helperpkg.IsOptional(f) || helperpkg.IsEncrypted(f) || t.state.NestedMessages.IsJSON(f) {
+				return false
+			}
+			switch f.GetType() {
+			case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+				return false
+			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+				return helperpkg.IsTimestamp(f)
+			}
+			return true
+		},
+
+		// sumType returns the Go type of the sum of the numeric field, it is empty if the field can't be summed.
+		"sumType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) {
+				return ""
+			}
+			return helperpkg.SumType(f)
+		},
+
+		// minMaxType returns the Go type of the smallest and the largest values of the numeric or timestamp field,
+		// it is empty for the other fields.
+		"minMaxType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) || (helperpkg.SumType(f) == "" && !helperpkg.IsTimestamp(f)) {
+				return ""
+			}
+			return helperpkg.ClearPointer(helperpkg.ConvertType(f))
+		},
+
 		"isValidGT": func(f *descriptorpb.FieldDescriptorProto) bool {
 			if f == nil {
 				return false
@@ -454,6 +616,46 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return helperpkg.PostgresType(helperpkg.ConvertType(f), helperpkg.GetFieldOptions(f), t.state.NestedMessages.IsJSON(f))
 		},
 
+		// foreignKeyStatements returns the idempotent statements which add the foreign keys of the table.
+		// The keys which close the reference cycles are added by CreateTables after all the tables.
+		"foreignKeyStatements": func() []string {
+			s := schemapkg.Build(t.state, schemapkg.Postgres)
+			_, deferred := schemapkg.SortTables(s.Tables)
+			table := s.Table(schemapkg.TableKey(t.state, t.message))
+			return idempotentStatements(schemapkg.ForeignKeyStatements(schemapkg.Postgres, table, schemapkg.ImmediateForeignKeys(table, deferred)))
+		},
+
+		// searchFields returns the fields of the full-text search.
+		"searchFields": func() []*descriptorpb.FieldDescriptorProto {
+			var fields []*descriptorpb.FieldDescriptorProto
+			for _, f := range t.message.GetField() {
+				if !t.state.IsRelation(f) && helperpkg.IsSearchable(f) {
+					fields = append(fields, f)
+				}
+			}
+			return fields
+		},
+
+		// searchColumn returns the generated column which keeps the full-text search document, it is nil without the search.
+		"searchColumn": func() *schemapkg.Column {
+			return schemapkg.Postgres.SearchColumn(schemapkg.BuildTable(t.state, schemapkg.Postgres, t.message))
+		},
+
+		// searchConfig returns the text search configuration of the full-text search.
+		"searchConfig": func() string {
+			return helperpkg.SearchConfig
+		},
+
+		// upgradeColumns returns the columns which the table upgrade compares with the live table.
+		"upgradeColumns": func() []*schemapkg.UpgradeColumn {
+			return schemapkg.UpgradeColumns(schemapkg.Postgres, schemapkg.BuildTable(t.state, schemapkg.Postgres, t.message))
+		},
+
+		// upgradeIndexes returns the indexes which the table upgrade creates.
+		"upgradeIndexes": func() []*schemapkg.UpgradeIndex {
+			return schemapkg.UpgradeIndexes(schemapkg.Postgres, schemapkg.BuildTable(t.state, schemapkg.Postgres, t.message))
+		},
+
 		// storageName returns the upper camel case storage name.
 		"storageName": func() string {
 			return fmt.Sprintf("%sStorage", helperpkg.UpperCamelCase(t.message.GetName()))
@@ -618,6 +820,16 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// conflictTargets returns the unique constraints which the upsert can conflict on,
+		// the primary key is one of them if it is not generated by the database.
+		"conflictTargets": func() [][]*descriptorpb.FieldDescriptorProto {
+			var targets [][]*descriptorpb.FieldDescriptorProto
+			if pk := helperpkg.InsertedPrimaryKey(t.message); len(pk) > 0 {
+				targets = append(targets, pk)
+			}
+			return append(targets, helperpkg.UniqueConstraints(t.message)...)
+		},
+
 		"getStructureUniqueIndexes": func() map[int][]*descriptorpb.FieldDescriptorProto {
 			var indexes = make(map[int][]*descriptorpb.FieldDescriptorProto)
 			if opts := helperpkg.GetMessageOptions(t.message); opts != nil {
@@ -719,85 +931,6 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return ""
 		},
 
-		"getRefSource": func(fl *descriptorpb.FieldDescriptorProto) string {
-			relName := t.message.GetName() + "::" + helperpkg.ClearPointer(helperpkg.ConvertType(fl))
-			relation, ok := t.state.Relations.Get(relName)
-
-			if ok {
-				rd := relation.RelationDescriptor
-				pd := relation.ParentDescriptor
-
-				if relation.UseTag {
-					return helperpkg.SnakeCase(relation.Reference)
-				}
-
-				// todo: check
-
-				if helperpkg.DetermineRelationDirection(rd, pd) == "child-to-parent" {
-					for _, f := range rd.GetField() {
-						if f.GetName() == strings.ToLower(pd.GetName())+"_id" {
-							return helperpkg.SnakeCase(f.GetName())
-						}
-					}
-				} else {
-					for _, f := range rd.GetField() {
-						if f.GetName() == "id" {
-							return helperpkg.SnakeCase(f.GetName())
-						}
-					}
-				}
-			}
-			return ""
-		},
-
-		"isForeign": func(fl *descriptorpb.FieldDescriptorProto) bool {
-			if opts := helperpkg.GetFieldOptions(fl); opts != nil {
-				if opts.GetRelation() != nil {
-					return opts.GetRelation().Foreign != nil
-				}
-			}
-			return false
-		},
-
-		"isCascade": func(fl *descriptorpb.FieldDescriptorProto) bool {
-			if opts := helperpkg.GetFieldOptions(fl); opts != nil {
-				if opts.GetRelation() != nil && opts.GetRelation().Foreign != nil {
-					return opts.GetRelation().Foreign.Cascade
-				}
-			}
-			return false
-		},
-
-		"getFieldSource": func(fl *descriptorpb.FieldDescriptorProto) string {
-			relName := t.message.GetName() + "::" + helperpkg.ClearPointer(helperpkg.ConvertType(fl))
-			relation, ok := t.state.Relations.Get(relName)
-
-			if ok {
-				rd := relation.RelationDescriptor
-				pd := relation.ParentDescriptor
-
-				if relation.UseTag {
-					return helperpkg.SnakeCase(relation.Field)
-				}
-
-				var currentPrimaryKey string
-				for _, f := range pd.GetField() {
-					if opts := helperpkg.GetFieldOptions(f); opts != nil {
-						if opts.GetPrimaryKey() {
-							currentPrimaryKey = helperpkg.SnakeCase(f.GetName())
-						}
-					}
-				}
-
-				if helperpkg.DetermineRelationDirection(rd, pd) == "child-to-parent" {
-					return currentPrimaryKey
-				} else {
-					return helperpkg.SnakeCase(strings.ToLower(rd.GetName()) + "_id")
-				}
-			}
-			return ""
-		},
-
 		// relationName returns the relation name.
 		"hasID": func() bool {
 			for _, f := range t.message.GetField() {
@@ -848,6 +981,27 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return helperpkg.Plural(t.message.GetName())
 		},
 
+		// schemaName returns the database schema of the table.
+		"schemaName": func() string {
+			return t.state.SchemaName(t.message)
+		},
+
+		// tableIdent returns the schema-qualified, quoted table name.
+		"tableIdent": func() string {
+			tableName := helperpkg.Plural(t.message.GetName())
+			if opts := helperpkg.GetMessageOptions(t.message); opts != nil {
+				if opts.Table != "" {
+					tableName = opts.Table
+				}
+			}
+			return helperpkg.QuoteIdent(t.state.SchemaName(t.message), tableName)
+		},
+
+		// quoteIdent returns the quoted identifier.
+		"quoteIdent": func(name string) string {
+			return helperpkg.QuoteIdent("", name)
+		},
+
 		"pluralFieldName": func(f *descriptorpb.FieldDescriptorProto) string {
 			if helperpkg.IsRepeated(f) {
 				return helperpkg.UpperCamelCase(helperpkg.Plural(f.GetName()))
@@ -885,3 +1039,348 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 		"lowerCamelCase": helperpkg.LowerCamelCase,
 	}
 }
+
+// isValidationString returns true if the string validation rules can be applied to the field.
+func isValidationString(f *descriptorpb.FieldDescriptorProto) bool {
+	return f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_STRING && !helperpkg.IsRepeated(f)
+}
+
+// isValidationNumber returns true if the numeric validation rules can be applied to the field.
+func isValidationNumber(f *descriptorpb.FieldDescriptorProto) bool {
+	if helperpkg.IsRepeated(f) {
+		return false
+	}
+
+	switch f.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return true
+	}
+	return false
+}
+
+// validationPatternVar returns the name of the compiled pattern variable of the field.
+func validationPatternVar(message *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto) string {
+	return helperpkg.LowerCamelCase(message.GetName()) + helperpkg.UpperCamelCase(f.GetName()) + "Pattern"
+}
+
+// validationChecks returns the validator calls for the rules of the field.
+// value is the expression of the field value, it must not be a pointer.
+func validationChecks(message *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto, value string) []string {
+	opts := helperpkg.GetFieldOptions(f)
+	if opts == nil {
+		return nil
+	}
+
+	name := f.GetName()
+	var checks []string
+
+	if isValidationString(f) {
+		if opts.GetNotEmpty() {
+			checks = append(checks, fmt.Sprintf("v.notEmpty(%q, %s)", name, value))
+		}
+		if opts.MinLen != nil {
+			checks = append(checks, fmt.Sprintf("v.minLen(%q, %s, %d)", name, value, opts.GetMinLen()))
+		}
+		if opts.MaxLen != nil {
+			checks = append(checks, fmt.Sprintf("v.maxLen(%q, %s, %d)", name, value, opts.GetMaxLen()))
+		}
+		if opts.GetPattern() != "" {
+			if _, err := regexp.Compile(opts.GetPattern()); err != nil {
+				log.Fatalf("field %s.%s: invalid pattern: %v", message.GetName(), name, err)
+			}
+			checks = append(checks, fmt.Sprintf("v.pattern(%q, %s, %s)", name, value, validationPatternVar(message, f)))
+		}
+		if opts.GetEmail() {
+			checks = append(checks, fmt.Sprintf("v.email(%q, %s)", name, value))
+		}
+		if opts.GetUuidFormat() {
+			checks = append(checks, fmt.Sprintf("v.uuid(%q, %s)", name, value))
+		}
+	}
+
+	if isValidationNumber(f) {
+		if opts.Min != nil {
+			checks = append(checks, fmt.Sprintf("v.min(%q, float64(%s), %v)", name, value, opts.GetMin()))
+		}
+		if opts.Max != nil {
+			checks = append(checks, fmt.Sprintf("v.max(%q, float64(%s), %v)", name, value, opts.GetMax()))
+		}
+	}
+
+	return checks
+}
+
+// paginationFields are the request fields which are used for the pagination of the service methods.
+var paginationFields = map[string]bool{
+	"limit":     true,
+	"offset":    true,
+	"page":      true,
+	"page_size": true,
+}
+
+// requestValue returns the check of the request field presence and the expression of its value.
+// The check is empty if the field is always applied.
+func requestValue(f *descriptorpb.FieldDescriptorProto) (string, string) {
+	access := "req." + helperpkg.UpperCamelCase(f.GetName())
+
+	switch {
+	case helperpkg.IsRepeated(f):
+		return fmt.Sprintf("len(%s) > 0", access), access
+	case helperpkg.IsOptional(f):
+		return fmt.Sprintf("%s != nil", access), "*" + access
+	case strings.Contains(helperpkg.ConvertType(f), "time.Time"):
+		return fmt.Sprintf("!%s.IsZero()", access), access
+	}
+	return "", access
+}
+
+// appendFilter returns the statement which appends the condition to the filters if the check passes.
+func appendFilter(check, condition string) string {
+	if check == "" {
+		return fmt.Sprintf("filters = append(filters, %s)", condition)
+	}
+	return fmt.Sprintf("if %s {\nfilters = append(filters, %s)\n}", check, condition)
+}
+
+// methodColumns returns the columns of the method object which can be filtered by.
+func methodColumns(method *statepkg.Method) map[string]*descriptorpb.FieldDescriptorProto {
+	columns := make(map[string]*descriptorpb.FieldDescriptorProto)
+	for _, f := range method.Object.GetField() {
+		if f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && !strings.Contains(helperpkg.ConvertType(f), "time.Time") {
+			// relations and json fields
+			continue
+		}
+		columns[f.GetName()] = f
+	}
+	return columns
+}
+
+// methodConditions returns the statements which append the conditions of the method request to the filters.
+// The request fields are matched with the object columns by name:
+// "<column>" is Eq (In if the request field is repeated), "<column>_in" is In
+// and "<column>_from", "<column>_to" are Between or its open-ended variants.
+// Optional fields are applied when they are set, repeated fields when they are not empty.
+func methodConditions(method *statepkg.Method) []string {
+	columns := methodColumns(method)
+	requestFields := make(map[string]*descriptorpb.FieldDescriptorProto)
+	for _, f := range method.Request.GetField() {
+		requestFields[f.GetName()] = f
+	}
+
+	fatalf := func(format string, args ...interface{}) {
+		log.Fatalf("method %s: %s", method.Name, fmt.Sprintf(format, args...))
+	}
+	column := func(f *descriptorpb.FieldDescriptorProto, name string) *descriptorpb.FieldDescriptorProto {
+		col, ok := columns[name]
+		if !ok {
+			fatalf("request field %q does not match any column of %s", f.GetName(), method.Object.GetName())
+		}
+		if f.GetType() != col.GetType() || f.GetTypeName() != col.GetTypeName() {
+			fatalf("request field %q type does not match the column %q type", f.GetName(), name)
+		}
+		return col
+	}
+
+	var statements []string
+	ranges := make(map[string]bool)
+	for _, f := range method.Request.GetField() {
+		name := f.GetName()
+		if paginationFields[name] {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, "_from") || strings.HasSuffix(name, "_to"):
+			colName := strings.TrimSuffix(strings.TrimSuffix(name, "_from"), "_to")
+			if _, ok := columns[colName]; !ok {
+				// the column itself can be named with the suffix.
+				if col, ok := columns[name]; ok {
+					statements = append(statements, methodEq(method, f, col))
+					continue
+				}
+			}
+			if ranges[colName] {
+				continue
+			}
+			ranges[colName] = true
+
+			var from, to *descriptorpb.FieldDescriptorProto
+			if rf, ok := requestFields[colName+"_from"]; ok {
+				from = rf
+				column(rf, colName)
+			}
+			if rf, ok := requestFields[colName+"_to"]; ok {
+				to = rf
+				column(rf, colName)
+			}
+			if helperpkg.IsEncrypted(columns[colName]) {
+				fatalf("encrypted column %q can not be filtered by range", colName)
+			}
+			if (from != nil && helperpkg.IsRepeated(from)) || (to != nil && helperpkg.IsRepeated(to)) {
+				fatalf("range field of the column %q must not be repeated", colName)
+			}
+			statements = append(statements, methodRange(colName, from, to))
+		case strings.HasSuffix(name, "_in") && columns[name] == nil:
+			col := column(f, strings.TrimSuffix(name, "_in"))
+			if !helperpkg.IsRepeated(f) {
+				fatalf("request field %q must be repeated", name)
+			}
+			if helperpkg.IsEncrypted(col) {
+				fatalf("encrypted column %q can be filtered only by equality", col.GetName())
+			}
+			statements = append(statements, methodIn(f, col))
+		default:
+			statements = append(statements, methodEq(method, f, column(f, name)))
+		}
+	}
+
+	return statements
+}
+
+// methodEq returns the statement which appends the equality condition of the request field.
+func methodEq(method *statepkg.Method, f, col *descriptorpb.FieldDescriptorProto) string {
+	if helperpkg.IsRepeated(f) && !helperpkg.IsRepeated(col) {
+		if helperpkg.IsEncrypted(col) {
+			log.Fatalf("method %s: encrypted column %q can be filtered only by a single value", method.Name, col.GetName())
+		}
+		return methodIn(f, col)
+	}
+
+	check, value := requestValue(f)
+	if helperpkg.IsEncrypted(col) {
+		if !helperpkg.HasBlindIndex(col) {
+			log.Fatalf("method %s: encrypted column %q can be filtered only with a blind index", method.Name, col.GetName())
+		}
+		return appendFilter(check, fmt.Sprintf("BlindIndexCondition{Field: %q, Value: %s}", helperpkg.BlindIndexColumn(col), value))
+	}
+	return appendFilter(check, fmt.Sprintf("Eq(%q, %s)", col.GetName(), value))
+}
+
+// methodIn returns the statement which appends the In condition of the repeated request field.
+func methodIn(f, col *descriptorpb.FieldDescriptorProto) string {
+	access := "req." + helperpkg.UpperCamelCase(f.GetName())
+	return fmt.Sprintf(`if len(%[1]s) > 0 {
+		values := make([]interface{}, 0, len(%[1]s))
+		for _, v := range %[1]s {
+			values = append(values, v)
+		}
+		filters = append(filters, In(%[2]q, values...))
+	}`, access, col.GetName())
+}
+
+// methodRange returns the statement which appends the range condition of the column.
+func methodRange(column string, from, to *descriptorpb.FieldDescriptorProto) string {
+	if to == nil {
+		check, value := requestValue(from)
+		return appendFilter(check, fmt.Sprintf("GreaterThanOrEq(%q, %s)", column, value))
+	}
+	if from == nil {
+		check, value := requestValue(to)
+		return appendFilter(check, fmt.Sprintf("LessThanOrEq(%q, %s)", column, value))
+	}
+
+	fromCheck, fromValue := requestValue(from)
+	toCheck, toValue := requestValue(to)
+	between := fmt.Sprintf("filters = append(filters, Between(%q, %s, %s))", column, fromValue, toValue)
+	gte := fmt.Sprintf("filters = append(filters, GreaterThanOrEq(%q, %s))", column, fromValue)
+	lte := fmt.Sprintf("filters = append(filters, LessThanOrEq(%q, %s))", column, toValue)
+
+	var checks []string
+	for _, check := range []string{fromCheck, toCheck} {
+		if check != "" {
+			checks = append(checks, check)
+		}
+	}
+
+	switch {
+	case len(checks) == 0:
+		return between
+	case fromCheck == "":
+		return fmt.Sprintf("if %s {\n%s\n} else {\n%s\n}", toCheck, between, gte)
+	case toCheck == "":
+		return fmt.Sprintf("if %s {\n%s\n} else {\n%s\n}", fromCheck, between, lte)
+	}
+	return fmt.Sprintf("switch {\ncase %s:\n%s\ncase %s:\n%s\ncase %s:\n%s\n}",
+		strings.Join(checks, " && "), between, fromCheck, gte, toCheck, lte)
+}
+
+// methodPagination returns the statements which append the pagination of the method request to the builders.
+// The pagination is set by the "limit" and "offset" or the "page" and "page_size" request fields.
+func methodPagination(method *statepkg.Method) []string {
+	fields := make(map[string]*descriptorpb.FieldDescriptorProto)
+	for _, f := range method.Request.GetField() {
+		if !paginationFields[f.GetName()] {
+			continue
+		}
+		if helperpkg.IsRepeated(f) || !isValidationNumber(f) ||
+			f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_FLOAT ||
+			f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_DOUBLE {
+			log.Fatalf("method %s: pagination field %q must be an integer", method.Name, f.GetName())
+		}
+		fields[f.GetName()] = f
+	}
+
+	// positive returns the check that the field is set and positive.
+	positive := func(f *descriptorpb.FieldDescriptorProto) (string, string) {
+		check, value := requestValue(f)
+		if check == "" {
+			return fmt.Sprintf("%s > 0", value), value
+		}
+		return fmt.Sprintf("%s && %s > 0", check, value), value
+	}
+
+	var statements []string
+	if page, ok := fields["page"]; ok {
+		size, ok := fields["page_size"]
+		if !ok {
+			log.Fatalf("method %s: the page field requires the page_size field", method.Name)
+		}
+		if _, ok := fields["limit"]; ok {
+			log.Fatalf("method %s: the limit field can not be used with the page field", method.Name)
+		}
+		if _, ok := fields["offset"]; ok {
+			log.Fatalf("method %s: the offset field can not be used with the page field", method.Name)
+		}
+
+		pageCheck, pageValue := positive(page)
+		sizeCheck, sizeValue := positive(size)
+		statements = append(statements, fmt.Sprintf("if %s && %s {\nbuilders = append(builders, PaginateBuilder(uint64(%s), uint64(%s-1)*uint64(%s)))\n}",
+			pageCheck, sizeCheck, sizeValue, pageValue, sizeValue))
+		return statements
+	}
+	if _, ok := fields["page_size"]; ok {
+		log.Fatalf("method %s: the page_size field requires the page field", method.Name)
+	}
+
+	if limit, ok := fields["limit"]; ok {
+		check, value := positive(limit)
+		statements = append(statements, fmt.Sprintf("if %s {\nbuilders = append(builders, LimitBuilder(uint64(%s)))\n}", check, value))
+	}
+	if offset, ok := fields["offset"]; ok {
+		check, value := positive(offset)
+		statements = append(statements, fmt.Sprintf("if %s {\nbuilders = append(builders, OffsetBuilder(uint64(%s)))\n}", check, value))
+	}
+
+	return statements
+}
+
+// idempotentStatements wraps the statements which fail if the object already exists,
+// e.g. ADD CONSTRAINT, into the DO blocks which ignore the duplicate.
+func idempotentStatements(statements []string) []string {
+	wrapped := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		wrapped = append(wrapped, fmt.Sprintf("DO $$ BEGIN %s EXCEPTION WHEN duplicate_object THEN NULL; END $$;", statement))
+	}
+	return wrapped
+}
@@ -0,0 +1,151 @@
+package tmpl
+
+// AggregateTemplate is the template for the aggregate queries.
+// This is included in the init template.
+const AggregateTemplate = `
+// Aggregation is an aggregate function of the Aggregate query, e.g. Count() or Sum(UserColumnAge).
+type Aggregation struct {
+	function string
+	column   Column
+}
+
+// Count counts the rows.
+func Count() Aggregation {
+	return Aggregation{function: "COUNT"}
+}
+
+// Sum sums the values of the numeric column.
+func Sum(column Column) Aggregation {
+	return Aggregation{function: "SUM", column: column}
+}
+
+// Avg returns the average value of the numeric column.
+func Avg(column Column) Aggregation {
+	return Aggregation{function: "AVG", column: column}
+}
+
+// Min returns the smallest value of the numeric or timestamp column.
+func Min(column Column) Aggregation {
+	return Aggregation{function: "MIN", column: column}
+}
+
+// Max returns the largest value of the numeric or timestamp column.
+func Max(column Column) Aggregation {
+	return Aggregation{function: "MAX", column: column}
+}
+
+// Column returns the aggregated column, it is empty for Count.
+func (a Aggregation) Column() Column {
+	return a.column
+}
+
+// String returns the SQL expression of the aggregation.
+func (a Aggregation) String() string {
+	if a.column == "" {
+		return a.function + "(*)"
+	}
+	return a.function + "(" + string(a.column) + ")"
+}
+
+// Eq returns the Having condition: aggregation = value.
+func (a Aggregation) Eq(value interface{}) sq.Sqlizer {
+	return sq.Eq{a.String(): value}
+}
+
+// NotEq returns the Having condition: aggregation <> value.
+func (a Aggregation) NotEq(value interface{}) sq.Sqlizer {
+	return sq.NotEq{a.String(): value}
+}
+
+// GT returns the Having condition: aggregation > value.
+func (a Aggregation) GT(value interface{}) sq.Sqlizer {
+	return sq.Gt{a.String(): value}
+}
+
+// GTE returns the Having condition: aggregation >= value.
+func (a Aggregation) GTE(value interface{}) sq.Sqlizer {
+	return sq.GtOrEq{a.String(): value}
+}
+
+// LT returns the Having condition: aggregation < value.
+func (a Aggregation) LT(value interface{}) sq.Sqlizer {
+	return sq.Lt{a.String(): value}
+}
+
+// LTE returns the Having condition: aggregation <= value.
+func (a Aggregation) LTE(value interface{}) sq.Sqlizer {
+	return sq.LtOrEq{a.String(): value}
+}
+
+// AggregateOption is an option of the Aggregate query: an Aggregation, GroupBy, Having,
+// or a QueryBuilder which filters the aggregated rows.
+type AggregateOption interface {
+	applyAggregate(query *aggregateQuery)
+}
+
+// aggregateQuery is the Aggregate query built from the options.
+type aggregateQuery struct {
+	groupBy      []Column
+	aggregations []Aggregation
+	having       []sq.Sqlizer
+	builders     []*QueryBuilder
+}
+
+// newAggregateQuery builds the Aggregate query from the options.
+func newAggregateQuery(options []AggregateOption) *aggregateQuery {
+	query := &aggregateQuery{}
+	for _, option := range options {
+		if option != nil {
+			option.applyAggregate(query)
+		}
+	}
+	return query
+}
+
+// applyAggregate adds the aggregation to the Aggregate query.
+func (a Aggregation) applyAggregate(query *aggregateQuery) {
+	query.aggregations = append(query.aggregations, a)
+}
+
+// applyAggregate adds the filters of the builder to the Aggregate query,
+// the sorting and the pagination are applied to the groups.
+func (b *QueryBuilder) applyAggregate(query *aggregateQuery) {
+	query.builders = append(query.builders, b)
+}
+
+// groupBy is the GroupBy option.
+type groupBy []Column
+
+// GroupBy groups the aggregated rows by the columns, the rows of the Aggregate query hold the values of the columns.
+func GroupBy(columns ...Column) AggregateOption {
+	return groupBy(columns)
+}
+
+// applyAggregate adds the grouped columns to the Aggregate query.
+func (g groupBy) applyAggregate(query *aggregateQuery) {
+	query.groupBy = append(query.groupBy, g...)
+}
+
+// having is the Having option.
+type having []sq.Sqlizer
+
+// Having filters the groups by the conditions of the aggregations, e.g. Having(Count().GT(1)).
+func Having(conditions ...sq.Sqlizer) AggregateOption {
+	return having(conditions)
+}
+
+// applyAggregate adds the conditions to the Aggregate query.
+func (h having) applyAggregate(query *aggregateQuery) {
+	query.having = append(query.having, h...)
+}
+
+// aggregateOptions returns the options of the single aggregation of the rows found by the builders.
+func aggregateOptions(aggregation Aggregation, builders []*QueryBuilder) []AggregateOption {
+	options := make([]AggregateOption, 0, len(builders)+1)
+	options = append(options, aggregation)
+	for _, builder := range builders {
+		options = append(options, builder)
+	}
+	return options
+}
+`
@@ -0,0 +1,232 @@
+package tmpl
+
+const CipherTemplate = `
+// Cipher encrypts and decrypts the values of the encrypted fields.
+type Cipher interface {
+	// Encrypt encrypts the plaintext value.
+	Encrypt(plaintext string) (string, error)
+	// Decrypt decrypts the value returned by Encrypt.
+	Decrypt(ciphertext string) (string, error)
+	// BlindIndex returns a deterministic keyed hash of the value.
+	// It is stored next to the encrypted value to search by equality.
+	BlindIndex(value string) string
+}
+
+// AESCipher is the default Cipher based on AES-GCM.
+// Values are prefixed with the id of the key they are encrypted with,
+// so the keys can be rotated without re-encrypting the stored data at once.
+type AESCipher struct {
+	primaryKeyID string
+	keys         map[string]cipher.AEAD
+	indexKey     []byte
+}
+
+// NewAESCipher returns a new AESCipher.
+// keys maps key ids to 16, 24 or 32 byte AES keys, the primary key is used for encryption
+// and all the keys are used for decryption. indexKey is the HMAC key of the blind indexes,
+// it must not be changed when the encryption keys are rotated.
+func NewAESCipher(primaryKeyID string, keys map[string][]byte, indexKey []byte) (*AESCipher, error) {
+	if _, ok := keys[primaryKeyID]; !ok {
+		return nil, errors.New("primary key is not found")
+	}
+	if len(indexKey) == 0 {
+		return nil, errors.New("index key is empty")
+	}
+
+	c := &AESCipher{
+		primaryKeyID: primaryKeyID,
+		keys:         make(map[string]cipher.AEAD, len(keys)),
+		indexKey:     indexKey,
+	}
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, errors.Errorf("key id %q must not contain ':'", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %q", id)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %q", id)
+		}
+		c.keys[id] = aead
+	}
+
+	return c, nil
+}
+
+// Encrypt encrypts the plaintext with the primary key.
+func (c *AESCipher) Encrypt(plaintext string) (string, error) {
+	aead := c.keys[c.primaryKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return c.primaryKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts the ciphertext with the key it was encrypted with.
+func (c *AESCipher) Decrypt(ciphertext string) (string, error) {
+	keyID, payload, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return "", errors.New("invalid ciphertext format")
+	}
+
+	aead, ok := c.keys[keyID]
+	if !ok {
+		return "", errors.Errorf("unknown key %q", keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode ciphertext")
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt")
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation returns true if the ciphertext is not encrypted with the primary key.
+func (c *AESCipher) NeedsRotation(ciphertext string) bool {
+	keyID, _, _ := strings.Cut(ciphertext, ":")
+	return keyID != c.primaryKeyID
+}
+
+// BlindIndex returns the hex encoded HMAC-SHA256 of the value.
+func (c *AESCipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// encryptValue encrypts the value of the encrypted field, nil values are kept as NULL.
+func encryptValue(cp Cipher, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return cp.Encrypt(v)
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		return cp.Encrypt(*v)
+	}
+	return nil, errors.Errorf("unsupported encrypted value type %T", value)
+}
+
+// blindIndexValue returns the blind index of the value, nil values are kept as NULL.
+func blindIndexValue(cp Cipher, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return cp.BlindIndex(v)
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return cp.BlindIndex(*v)
+	}
+	return nil
+}
+
+// BlindIndexCondition checks if the encrypted field equals the value using its blind index column.
+type BlindIndexCondition struct {
+	Field  string
+	Value  string
+	Cipher Cipher
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c BlindIndexCondition) ToSql() (string, []interface{}, error) {
+	if c.Cipher == nil {
+		// the cipher is bound by the storage, nothing can match without it.
+		return "1 = 0", nil, nil
+	}
+	return sq.Eq{c.Field: c.Cipher.BlindIndex(c.Value)}.ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c BlindIndexCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c BlindIndexCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the delete query.
+func (c BlindIndexCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// cipherBinder is implemented by the conditions which need the Cipher.
+type cipherBinder interface {
+	bindCipher(cp Cipher) FilterApplier
+}
+
+// bindCipher binds the cipher to the condition and all its nested conditions.
+func bindCipher(condition FilterApplier, cp Cipher) FilterApplier {
+	if b, ok := condition.(cipherBinder); ok {
+		return b.bindCipher(cp)
+	}
+	return condition
+}
+
+func (c BlindIndexCondition) bindCipher(cp Cipher) FilterApplier {
+	c.Cipher = cp
+	return c
+}
+
+func (c AndCondition) bindCipher(cp Cipher) FilterApplier {
+	where := make([]FilterApplier, 0, len(c.Where))
+	for _, condition := range c.Where {
+		where = append(where, bindCipher(condition, cp))
+	}
+	return AndCondition{Where: where}
+}
+
+func (c OrCondition) bindCipher(cp Cipher) FilterApplier {
+	conditions := make([]FilterApplier, 0, len(c.Conditions))
+	for _, condition := range c.Conditions {
+		conditions = append(conditions, bindCipher(condition, cp))
+	}
+	return OrCondition{Conditions: conditions}
+}
+
+func (c NotCondition) bindCipher(cp Cipher) FilterApplier {
+	return NotCondition{Condition: bindCipher(c.Condition, cp)}
+}
+
+func (c JoinCondition) bindCipher(cp Cipher) FilterApplier {
+	c.On = bindCipher(c.On, cp)
+	return c
+}
+
+func (c ExistsCondition) bindCipher(cp Cipher) FilterApplier {
+	conditions := make([]FilterApplier, 0, len(c.Conditions))
+	for _, condition := range c.Conditions {
+		conditions = append(conditions, bindCipher(condition, cp))
+	}
+	c.Conditions = conditions
+	return c
+}
+
+func (c InSubqueryCondition) bindCipher(cp Cipher) FilterApplier {
+	conditions := make([]FilterApplier, 0, len(c.Conditions))
+	for _, condition := range c.Conditions {
+		conditions = append(conditions, bindCipher(condition, cp))
+	}
+	c.Conditions = conditions
+	return c
+}
+`
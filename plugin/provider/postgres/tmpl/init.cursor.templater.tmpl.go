@@ -0,0 +1,150 @@
+package tmpl
+
+// CursorTemplate is the template for the keyset pagination.
+// This is included in the init template.
+const CursorTemplate = `
+// Cursor is the position of the keyset pagination returned by FindManyAfter.
+// It holds the values of the sort columns and the primary key of the first or the last row of a page,
+// use String to pass it to the clients as an opaque token and ParseCursor to read it back.
+type Cursor struct {
+	sort     string
+	values   []json.RawMessage
+	backward bool
+}
+
+// cursorData is the encoded form of the Cursor.
+type cursorData struct {
+	Sort     string            ` + "`" + `json:"s"` + "`" + `
+	Values   []json.RawMessage ` + "`" + `json:"v"` + "`" + `
+	Backward bool              ` + "`" + `json:"b,omitempty"` + "`" + `
+}
+
+// CursorPaginator holds the cursors of the neighbour pages, a cursor is nil if there is no such page.
+type CursorPaginator struct {
+	Next *Cursor
+	Prev *Cursor
+}
+
+// ParseCursor decodes the cursor returned by Cursor.String.
+func ParseCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	var data cursorData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	return &Cursor{sort: data.Sort, values: data.Values, backward: data.Backward}, nil
+}
+
+// String encodes the cursor as a base64 string.
+func (c *Cursor) String() string {
+	raw, _ := json.Marshal(cursorData{Sort: c.sort, Values: c.values, Backward: c.backward})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsBackward returns true if the cursor points to the previous page.
+func (c *Cursor) IsBackward() bool {
+	return c.backward
+}
+
+// newCursor returns the cursor of the row, dest are the pointers to the fields of the sort columns.
+func newCursor(orders []OrderCondition, dest []interface{}, backward bool) (*Cursor, error) {
+	values := make([]json.RawMessage, 0, len(dest))
+	for _, value := range dest {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode cursor")
+		}
+		values = append(values, raw)
+	}
+	return &Cursor{sort: cursorSort(orders), values: values, backward: backward}, nil
+}
+
+// decode decodes the values of the cursor into dest, the pointers to the fields of the sort columns,
+// and returns the values. The cursor must be created with the same sorting.
+func (c *Cursor) decode(orders []OrderCondition, dest []interface{}) ([]interface{}, error) {
+	if c.sort != cursorSort(orders) {
+		return nil, errors.Wrapf(ErrInvalidCursor, "the cursor is sorted by %q, the query is sorted by %q", c.sort, cursorSort(orders))
+	}
+	if len(c.values) != len(dest) {
+		return nil, errors.Wrap(ErrInvalidCursor, "wrong number of values")
+	}
+
+	values := make([]interface{}, 0, len(dest))
+	for i, value := range dest {
+		if err := json.Unmarshal(c.values[i], value); err != nil {
+			return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+		}
+		values = append(values, reflect.ValueOf(value).Elem().Interface())
+	}
+	return values, nil
+}
+
+// cursorSort returns the signature of the sorting of the cursor.
+func cursorSort(orders []OrderCondition) string {
+	parts := make([]string, 0, len(orders))
+	for _, order := range orders {
+		parts = append(parts, order.clause())
+	}
+	return strings.Join(parts, ",")
+}
+
+// cursorColumns returns the sort columns.
+func cursorColumns(orders []OrderCondition) []string {
+	columns := make([]string, 0, len(orders))
+	for _, order := range orders {
+		columns = append(columns, order.Column)
+	}
+	return columns
+}
+
+// cursorCondition is the keyset condition of the rows after the cursor in the sorting order,
+// or before the cursor if it is backward.
+type cursorCondition struct {
+	orders   []OrderCondition
+	values   []interface{}
+	backward bool
+}
+
+// ToSql returns the WHERE expression of the condition.
+func (c cursorCondition) ToSql() (string, []interface{}, error) {
+	return c.condition().ToSql()
+}
+
+// Apply applies the condition to the query.
+func (c cursorCondition) Apply(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(c)
+}
+
+// ApplyUpdate applies the condition to the query.
+func (c cursorCondition) ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder {
+	return query.Where(c)
+}
+
+// ApplyDelete applies the condition to the query.
+func (c cursorCondition) ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder {
+	return query.Where(c)
+}
+
+// condition returns (a > x) OR (a = x AND b > y) ... for the sort columns a, b and the values x, y.
+func (c cursorCondition) condition() sq.Sqlizer {
+	or := sq.Or{}
+	for i, order := range c.orders {
+		and := sq.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{c.orders[j].Column: c.values[j]})
+		}
+		if order.Asc != c.backward {
+			and = append(and, sq.Gt{order.Column: c.values[i]})
+		} else {
+			and = append(and, sq.Lt{order.Column: c.values[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+`
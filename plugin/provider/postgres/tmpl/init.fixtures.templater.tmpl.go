@@ -0,0 +1,361 @@
+package tmpl
+
+// FixturesTemplate is the template for the fixtures loader.
+// This is included in the init template.
+const FixturesTemplate = `
+// LoadFixtures inserts the rows of the fixture files into the tables in one transaction.
+// The paths are the glob patterns of the YAML or JSON files in fsys, every document maps
+// the table names to the rows. The rows are a list, or a map of the named rows which can be referenced:
+//
+//	users:
+//	  alice:
+//	    name: Alice
+//	posts:
+//	  - title: Hello
+//	    author: $users.alice
+//
+// The string "$table.row" references the named row inserted before, it resolves to the referenced column
+// of the relation field or to the primary key of the row, "$table.row.column" resolves to the given column.
+// A leading "$$" escapes the dollar sign. The tables are filled in the dependency order, the values are inserted as is.
+func (c *{{ storageName | lowerCamelCase }}) LoadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error {
+	return c.loadFixtures(ctx, fsys, paths, false)
+}
+
+// ReloadFixtures truncates all the tables and loads the fixtures in one transaction.
+func (c *{{ storageName | lowerCamelCase }}) ReloadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error {
+	return c.loadFixtures(ctx, fsys, paths, true)
+}
+
+// loadFixtures reads the fixture files and inserts the rows, the tables are truncated first if truncate is set.
+func (c *{{ storageName | lowerCamelCase }}) loadFixtures(ctx context.Context, fsys fs.FS, paths []string, truncate bool) error {
+	rows, err := readFixtures(fsys, paths)
+	if err != nil {
+		return err
+	}
+
+	return c.tx.ExecFuncWithTx(ctx, func(ctx context.Context) error {
+		tx, _ := TxFromContext(ctx)
+		if truncate {
+			if _, err := tx.ExecContext(ctx, {{ truncateStatement | printf "%q" }}); err != nil {
+				return errors.Wrap(err, "failed to truncate tables")
+			}
+		}
+
+		// the named rows are kept to resolve the references.
+		loaded := make(map[string]map[string]interface{})
+		for _, key := range fixtureTableOrder {
+			for _, row := range rows[key] {
+				values, err := insertFixture(ctx, tx, fixtureTables[key], row, loaded)
+				if err != nil {
+					return errors.Wrapf(err, "failed to load fixture %s", row.source)
+				}
+				if row.name != "" {
+					loaded[key+"."+row.name] = values
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// fixtureTable is the table which can be filled by the fixtures.
+type fixtureTable struct {
+	name       string                     // name is the qualified table name.
+	primaryKey string                     // primaryKey is the column the references resolve to by default.
+	columns    map[string]bool            // columns are the columns of the table.
+	relations  map[string]fixtureRelation // relations are the relation fields which set the referencing columns.
+}
+
+// fixtureRelation is the relation field which sets the referencing column.
+type fixtureRelation struct {
+	column    string // column is the referencing column.
+	reference string // reference is the referenced column.
+}
+
+// fixtureRow is the row of the fixtures.
+type fixtureRow struct {
+	name    string        // name is the name of the row, it is empty for the rows of a list.
+	source  string        // source is the file and the line of the row.
+	keys    []string      // keys are the columns and the relation fields in the document order.
+	values  []interface{} // values are the values of the keys.
+}
+
+// fixtureTableOrder is the dependency order of the tables, the referenced tables go first.
+var fixtureTableOrder = []string{
+{{- range $table := fixtureTables }}
+	{{ $table.Key | printf "%q" }},
+{{- end }}
+}
+
+// fixtureTables are the tables by their names.
+var fixtureTables = map[string]fixtureTable{
+{{- range $table := fixtureTables }}
+	{{ $table.Key | printf "%q" }}: {
+		name:       {{ $table.Name | printf "%q" }},
+		primaryKey: {{ $table.PrimaryKey | printf "%q" }},
+		columns: map[string]bool{
+		{{- range $column := $table.Columns }}
+			{{ $column | printf "%q" }}: true,
+		{{- end }}
+		},
+		{{- if $table.Relations }}
+		relations: map[string]fixtureRelation{
+		{{- range $relation := $table.Relations }}
+			{{ $relation.Field | printf "%q" }}: {column: {{ $relation.Column | printf "%q" }}, reference: {{ $relation.Reference | printf "%q" }}},
+		{{- end }}
+		},
+		{{- end }}
+	},
+{{- end }}
+}
+
+// readFixtures reads the rows of the fixture files matching the patterns by the table names.
+func readFixtures(fsys fs.FS, patterns []string) (map[string][]*fixtureRow, error) {
+	rows := make(map[string][]*fixtureRow)
+	names := make(map[string]bool)
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fixture pattern %q", pattern)
+		}
+		if len(files) == 0 {
+			return nil, errors.Errorf("no fixture files match %q", pattern)
+		}
+
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read fixture %s", file)
+			}
+
+			// json is a subset of yaml, so both are read by the yaml decoder.
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			for {
+				var document yaml.Node
+				if err := decoder.Decode(&document); err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return nil, errors.Wrapf(err, "failed to parse fixture %s", file)
+				}
+				if err := parseFixtureDocument(file, &document, rows, names); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// parseFixtureDocument parses the rows of the document by the table names.
+func parseFixtureDocument(file string, document *yaml.Node, rows map[string][]*fixtureRow, names map[string]bool) error {
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	root := document.Content[0]
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return errors.Errorf("%s:%d: the fixture must map the table names to the rows", file, root.Line)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if _, ok := fixtureTables[key.Value]; !ok {
+			return errors.Errorf("%s:%d: unknown table %q", file, key.Line, key.Value)
+		}
+
+		switch value.Kind {
+		case yaml.SequenceNode:
+			for _, node := range value.Content {
+				row, err := parseFixtureRow(file, "", node)
+				if err != nil {
+					return err
+				}
+				rows[key.Value] = append(rows[key.Value], row)
+			}
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := value.Content[j].Value
+				if names[key.Value+"."+name] {
+					return errors.Errorf("%s:%d: duplicate row %s.%s", file, value.Content[j].Line, key.Value, name)
+				}
+				names[key.Value+"."+name] = true
+
+				row, err := parseFixtureRow(file, name, value.Content[j+1])
+				if err != nil {
+					return err
+				}
+				rows[key.Value] = append(rows[key.Value], row)
+			}
+		default:
+			return errors.Errorf("%s:%d: the rows of %s must be a list or a map", file, value.Line, key.Value)
+		}
+	}
+
+	return nil
+}
+
+// parseFixtureRow parses the values of the row, the nested maps and lists are stored as json.
+func parseFixtureRow(file string, name string, node *yaml.Node) (*fixtureRow, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.Errorf("%s:%d: the row must map the columns to the values", file, node.Line)
+	}
+
+	row := &fixtureRow{name: name, source: fmt.Sprintf("%s:%d", file, node.Line)}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value interface{}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return nil, errors.Wrapf(err, "%s:%d: failed to parse value", file, node.Content[i+1].Line)
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s:%d: failed to marshal value", file, node.Content[i+1].Line)
+			}
+			value = string(data)
+		}
+
+		row.keys = append(row.keys, node.Content[i].Value)
+		row.values = append(row.values, value)
+	}
+
+	return row, nil
+}
+
+// insertFixture inserts the row and returns the values of the inserted row.
+func insertFixture(ctx context.Context, db QueryExecer, table fixtureTable, row *fixtureRow, loaded map[string]map[string]interface{}) (map[string]interface{}, error) {
+	var columns []string
+	var values []interface{}
+	for i, key := range row.keys {
+		column, reference := key, ""
+		if relation, ok := table.relations[key]; ok {
+			column, reference = relation.column, relation.reference
+		} else if !table.columns[key] {
+			return nil, errors.Errorf("unknown column %q", key)
+		}
+
+		value, err := resolveFixtureValue(row.values[i], reference, loaded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve %s", key)
+		}
+		columns = append(columns, quoteFixtureIdent(column))
+		values = append(values, value)
+	}
+
+	sqlQuery, args := "INSERT INTO "+table.name+" DEFAULT VALUES RETURNING *", []interface{}(nil)
+	if len(columns) > 0 {
+		var err error
+		sqlQuery, args, err = sq.Insert(table.name).
+			Columns(columns...).
+			Values(values...).
+			Suffix("RETURNING *").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build query")
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert row")
+	}
+	defer rows.Close()
+
+	return scanFixtureRow(rows)
+}
+
+// scanFixtureRow scans the returned row into the map of the column values.
+func scanFixtureRow(rows *sql.Rows) (map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get columns")
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to insert row")
+		}
+		return nil, errors.New("no row is returned")
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan row")
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		// the text values are returned as bytes by the driver.
+		if b, ok := values[i].([]byte); ok {
+			row[column] = string(b)
+			continue
+		}
+		row[column] = values[i]
+	}
+
+	return row, rows.Err()
+}
+
+// resolveFixtureValue resolves the reference to the row loaded before, the other values are returned as is.
+// The reference resolves to the given column, to the referenced column of the relation or to the primary key.
+func resolveFixtureValue(value interface{}, reference string, loaded map[string]map[string]interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, "$") {
+		return value, nil
+	}
+	if strings.HasPrefix(s, "$$") {
+		return s[1:], nil
+	}
+
+	// the table names may contain the dots of the schema, so the longest one wins.
+	var table, rest string
+	for _, key := range fixtureTableOrder {
+		if strings.HasPrefix(s[1:], key+".") && len(key) > len(table) {
+			table, rest = key, s[len(key)+2:]
+		}
+	}
+	if table == "" {
+		return nil, errors.Errorf("unknown table of the reference %s", s)
+	}
+
+	name, column, _ := strings.Cut(rest, ".")
+	row, ok := loaded[table+"."+name]
+	if !ok {
+		return nil, errors.Errorf("row %s is not loaded, the referenced rows must be named", s)
+	}
+	if column == "" {
+		column = reference
+	}
+	if column == "" {
+		column = fixtureTables[table].primaryKey
+	}
+	if column == "" {
+		return nil, errors.Errorf("%s has no primary key, the column must be referenced", table)
+	}
+
+	v, ok := row[column]
+	if !ok {
+		return nil, errors.Errorf("unknown column of the reference %s", s)
+	}
+	return v, nil
+}
+
+// quoteFixtureIdent returns the quoted column name.
+func quoteFixtureIdent(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+}
+`
@@ -0,0 +1,132 @@
+package tmpl
+
+// JoinTemplate is the template for the typed joins of the relations.
+// This is included in the init template.
+const JoinTemplate = `
+// RelationJoin is the typed join of a relation, FindMany scans the joined rows into the relation field
+// of the found models in the same query. It is created by the generated join builders, e.g. UserJoinPosts.
+type RelationJoin struct {
+	table    string
+	relation string
+	joinType JoinType
+	filters  []FilterApplier
+}
+
+// WithJoins joins the relations, see RelationJoin.
+func (b *QueryBuilder) WithJoins(joins ...RelationJoin) *QueryBuilder {
+	b.joins = append(b.joins, joins...)
+	return b
+}
+
+// joinRowColumn is the column of the row numbers which identify the base and the joined rows.
+const joinRowColumn = "structify_row"
+
+// joinedRelation is the typed join of a relation resolved by the storage.
+type joinedRelation struct {
+	join RelationJoin
+	// query selects the joined rows.
+	query sq.SelectBuilder
+	// exists selects the joined rows of a base row, the base rows of InnerJoin are filtered by it.
+	// It keeps the question placeholders to be nested into the base query.
+	exists sq.SelectBuilder
+	// column is the column of the joined rows which references the column ref of the base rows.
+	column string
+	ref    string
+	// columns are the selected columns of the joined rows.
+	columns []string
+	// newRow returns the joined model, the scan destinations of its columns
+	// and the function which attaches the model to the base model.
+	newRow func() (interface{}, []interface{}, func(parent interface{}))
+}
+
+// joinedRow holds the scanned values of a joined row, the values are NULL if the base row has no joined row.
+type joinedRow struct {
+	model    interface{}
+	dest     []interface{}
+	nullable []interface{}
+	attach   func(parent interface{})
+	row      sql.NullInt64
+}
+
+// newJoinedRow returns the scan destinations of a joined row.
+func (r *joinedRelation) newJoinedRow() *joinedRow {
+	model, dest, attach := r.newRow()
+	nullable := make([]interface{}, 0, len(dest))
+	for _, d := range dest {
+		nullable = append(nullable, reflect.New(reflect.TypeOf(d)).Interface())
+	}
+	return &joinedRow{model: model, dest: dest, nullable: nullable, attach: attach}
+}
+
+// scanDest returns the scan destinations of the joined columns and the row number.
+func (r *joinedRow) scanDest() []interface{} {
+	return append(append([]interface{}{}, r.nullable...), &r.row)
+}
+
+// assign assigns the scanned non-NULL values to the joined model.
+func (r *joinedRow) assign() {
+	for i := range r.dest {
+		value := reflect.ValueOf(r.nullable[i]).Elem()
+		if !value.IsNil() {
+			reflect.ValueOf(r.dest[i]).Elem().Set(value.Elem())
+		}
+	}
+}
+
+// joinBuilders returns the typed joins of the builders.
+func joinBuilders(builders []*QueryBuilder) []RelationJoin {
+	var joins []RelationJoin
+	for _, builder := range builders {
+		if builder != nil {
+			joins = append(joins, builder.joins...)
+		}
+	}
+	return joins
+}
+
+// rowNumberColumn returns the column which numbers the rows in the sorting order of the builders.
+func rowNumberColumn(builders []*QueryBuilder) string {
+	var clauses []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			if order, ok := option.(OrderCondition); ok {
+				clauses = append(clauses, order.clause())
+			}
+		}
+	}
+	if len(clauses) == 0 {
+		return "ROW_NUMBER() OVER () AS " + joinRowColumn
+	}
+	return "ROW_NUMBER() OVER (ORDER BY " + strings.Join(clauses, ", ") + ") AS " + joinRowColumn
+}
+
+// joinQuery selects the base rows of the query base joined with the relations, the base rows are selected
+// as "t0" and the rows of the relations as "t1", "t2"... The joined columns are aliased as "t1_column".
+func joinQuery(builder sq.StatementBuilderType, base sq.SelectBuilder, columns []string, relations []*joinedRelation) sq.SelectBuilder {
+	selects := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		selects = append(selects, "t0."+column)
+	}
+	selects = append(selects, "t0."+joinRowColumn)
+	for i, relation := range relations {
+		alias := fmt.Sprintf("t%d", i+1)
+		for _, column := range relation.columns {
+			selects = append(selects, alias+"."+column+" AS "+alias+"_"+column)
+		}
+		selects = append(selects, alias+"."+joinRowColumn+" AS "+alias+"_"+joinRowColumn)
+	}
+
+	// the subqueries keep the question placeholders, they are numbered by the outer query
+	query := builder.Select(selects...).FromSelect(base.PlaceholderFormat(sq.Question), "t0")
+	for i, relation := range relations {
+		alias := fmt.Sprintf("t%d", i+1)
+		on := alias + "." + relation.column + " = t0." + relation.ref
+		subquery := relation.query.PlaceholderFormat(sq.Question)
+		query = query.JoinClause(sq.Expr(string(relation.join.joinType)+" JOIN (?) AS "+alias+" ON "+on, subquery))
+	}
+	return query.OrderBy("t0." + joinRowColumn)
+}
+`
@@ -0,0 +1,75 @@
+package tmpl
+
+// PreloadTemplate is the template for the eager loading of the relations.
+// This is included in the init template.
+const PreloadTemplate = `
+// preload is a relation path of WithPreload, e.g. "Posts.Author", with the builders of the relation.
+type preload struct {
+	path     string
+	builders []*QueryBuilder
+}
+
+// WithPreload loads the relations of the found models with the generated LoadBatch functions.
+// The nested relations are separated by dots, e.g. WithPreload("Posts", "Settings", "Posts.Author").
+func (b *QueryBuilder) WithPreload(paths ...string) *QueryBuilder {
+	for _, path := range paths {
+		b.preloads = append(b.preloads, preload{path: path})
+	}
+	return b
+}
+
+// WithPreloadBuilders loads the relation of the path like WithPreload, the builders filter and sort the loaded rows,
+// e.g. WithPreloadBuilders("Posts", SortBuilder(PostIdOrderBy(false))).
+func (b *QueryBuilder) WithPreloadBuilders(path string, builders ...*QueryBuilder) *QueryBuilder {
+	b.preloads = append(b.preloads, preload{path: path, builders: builders})
+	return b
+}
+
+// PreloadBuilder is a helper function to create a new query builder which loads the relations.
+func PreloadBuilder(paths ...string) *QueryBuilder {
+	return NewQueryBuilder().WithPreload(paths...)
+}
+
+// Preload is the option which loads the relations like QueryBuilder.WithPreload, e.g. in FindById.
+func Preload(paths ...string) Option {
+	return func(o *Options) {
+		for _, path := range paths {
+			o.preloads = append(o.preloads, preload{path: path})
+		}
+	}
+}
+
+// preloadRelations groups the preload paths of the builders by their first relation in the order of the paths.
+// The nested paths are preloaded by the builders of the relation.
+func preloadRelations(builders []*QueryBuilder) ([]string, map[string][]*QueryBuilder) {
+	var preloads []preload
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		preloads = append(preloads, builder.preloads...)
+
+		options := &Options{}
+		for _, o := range builder.options {
+			o(options)
+		}
+		preloads = append(preloads, options.preloads...)
+	}
+
+	var relations []string
+	grouped := make(map[string][]*QueryBuilder)
+	for _, p := range preloads {
+		relation, nested, ok := strings.Cut(p.path, ".")
+		if _, found := grouped[relation]; !found {
+			relations = append(relations, relation)
+			grouped[relation] = []*QueryBuilder{}
+		}
+		if ok {
+			grouped[relation] = append(grouped[relation], NewQueryBuilder().WithPreloadBuilders(nested, p.builders...))
+		} else {
+			grouped[relation] = append(grouped[relation], p.builders...)
+		}
+	}
+	return relations, grouped
+}
+`
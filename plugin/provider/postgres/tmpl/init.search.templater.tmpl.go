@@ -0,0 +1,41 @@
+package tmpl
+
+// SearchTemplate is the template for the full-text search.
+// This is included in the init template.
+const SearchTemplate = `
+// searchRankColumn is the column of the relevance of the rows found by Search.
+const searchRankColumn = "structify_rank"
+
+// searchHighlight are the marks of the matches in the snippets returned by Search.
+type searchHighlight struct {
+	start string
+	stop  string
+}
+
+// WithSearchHighlight is the option which makes Search return the snippets of the searchable fields,
+// the matches are wrapped in the start and the stop marks, e.g. WithSearchHighlight("<b>", "</b>").
+func WithSearchHighlight(start, stop string) Option {
+	return func(o *Options) {
+		o.searchHighlight = &searchHighlight{start: start, stop: stop}
+	}
+}
+
+// headlineOptions returns the ts_headline options which wrap the matches in the marks.
+func (h *searchHighlight) headlineOptions() string {
+	return fmt.Sprintf("StartSel=%q, StopSel=%q, MaxFragments=3", h.start, h.stop)
+}
+
+// builderHighlight returns the highlight marks set by the WithSearchHighlight option of the builders.
+func builderHighlight(builders []*QueryBuilder) *searchHighlight {
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	return options.searchHighlight
+}
+`
@@ -34,6 +34,12 @@ const InitStatementTemplate = `
 
 {{ template "errors" . }}
 
+//
+// Validation.
+//
+
+{{ template "validation" . }}
+
 //
 // Transaction manager.
 //
@@ -44,11 +50,60 @@ const InitStatementTemplate = `
 // Options.
 // 
 {{ template "options" . }}
+//
+// Upsert.
+//
+{{ template "upsert" . }}
+
+// 
+// Update expressions.
+// 
+{{ template "update_expression" . }}
 
 // 
 // Conditions for query builder.
 // 
 {{ template "conditions" . }}
+//
+// Filters from query strings.
+//
+{{ template "filters" . }}
+//
+// Aggregations.
+//
+{{ template "aggregate" . }}
+//
+// Keyset pagination.
+//
+{{ template "cursor" . }}
+//
+// Typed joins.
+//
+{{ template "join" . }}
+//
+// Eager loading.
+//
+{{ template "preload" . }}
+//
+// Full-text search.
+//
+{{ template "search" . }}
+//
+// Table upgrades.
+//
+{{ template "upgrade" . }}
+{{- if .CRUDSchemas }}
+//
+// Fixtures.
+//
+{{ template "fixtures" . }}
+{{- end }}
+{{- if .EncryptedFields }}
+//
+// Field encryption.
+//
+{{ template "cipher" . }}
+{{ end }}
 `
 
 const OptionsTemplate = `
@@ -63,6 +118,14 @@ type Options struct {
 	ignoreConflictField string
 	// uniqField is the unique field.
 	uniqField string
+	// skipValidation disables the model validation.
+	skipValidation bool
+	// serverCursorBatch is the batch size of the server-side cursor of FindEach.
+	serverCursorBatch int
+	// preloads are the relations loaded by the Preload option.
+	preloads []preload
+	// searchHighlight are the marks of the matches in the snippets of Search.
+	searchHighlight *searchHighlight
 }
 
 // WithRelations sets the relations flag.
@@ -87,9 +150,47 @@ func WithIgnoreConflictField(field string) Option {
 	}
 }
 
+// SkipValidation disables the validation of the models on create and update.
+func SkipValidation() Option {
+	return func(o *Options) {
+		o.skipValidation = true
+	}
+}
+
+// WithServerCursor makes FindEach fetch the rows from a server-side cursor in batches of the given size,
+// the cursor is declared in the transaction of the context or in a new read-only transaction.
+func WithServerCursor(batchSize int) Option {
+	return func(o *Options) {
+		o.serverCursorBatch = batchSize
+	}
+}
+
+// serverCursorSeq numbers the server-side cursors of FindEach.
+var serverCursorSeq uint64
+
+// serverCursor holds the statements of the server-side cursor of FindEach.
+type serverCursor struct {
+	declare string
+	fetch   string
+	close   string
+}
+
+// newServerCursor returns the statements of the uniquely named server-side cursor of the query.
+func newServerCursor(query string, batchSize int) serverCursor {
+	name := fmt.Sprintf("structify_cursor_%d", atomic.AddUint64(&serverCursorSeq, 1))
+	return serverCursor{
+		declare: "DECLARE " + name + " NO SCROLL CURSOR FOR " + query,
+		fetch:   fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, name),
+		close:   "CLOSE " + name,
+	}
+}
+
 // FilterApplier is a condition filters.
+// The condition is the SQL expression, so the conditions nest with And, Or and Not.
 type FilterApplier interface {
+	sq.Sqlizer
 	Apply(query sq.SelectBuilder) sq.SelectBuilder
+	ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder
 	ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder
 }
 
@@ -113,8 +214,18 @@ type QueryBuilder struct {
 		filter CustomFilter
 		params any
 	}
+	// columns are the selected columns, all the columns are selected if it is empty.
+	columns []Column
+	// joins are the typed joins of the relations.
+	joins []RelationJoin
+	// preloads are the relations loaded with the found models.
+	preloads []preload
 }
 
+// Column is the column name of a table.
+// The columns of every model are generated as constants, e.g. UserColumnName.
+type Column string
+
 // NewQueryBuilder returns a new query builder.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{}
@@ -172,6 +283,12 @@ func (b *QueryBuilder) WithPagination(pagination *Pagination) *QueryBuilder {
 	return b
 }
 
+// WithColumns selects only the given columns, the other fields of the found models are left empty.
+func (b *QueryBuilder) WithColumns(columns ...Column) *QueryBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
 // Filter is a helper function to create a new query builder with filter options.
 func FilterBuilder(filterOptions ...FilterApplier) *QueryBuilder {
 	return NewQueryBuilder().WithFilter(filterOptions...)
@@ -201,6 +318,102 @@ func PaginateBuilder(limit, offset uint64) *QueryBuilder {
 	return NewQueryBuilder().WithPagination(NewPagination(limit, offset))
 }
 
+// ColumnsBuilder is a helper function to create a new query builder which selects the given columns.
+func ColumnsBuilder(columns ...Column) *QueryBuilder {
+	return NewQueryBuilder().WithColumns(columns...)
+}
+
+// ColumnQuerier queries the rows of the given columns, it is implemented by every storage.
+type ColumnQuerier interface {
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+}
+
+// FindManyInto selects the columns of the db tags of T and scans the found rows into T.
+// It lets the list queries skip the heavy columns, e.g.
+//
+//	type userItem struct {
+//		Id   string ` + "`" + `db:"id"` + "`" + `
+//		Name string ` + "`" + `db:"name"` + "`" + `
+//	}
+//
+//	items, err := FindManyInto[userItem](ctx, storages.GetUserStorage(), builder)
+func FindManyInto[T any](ctx context.Context, querier ColumnQuerier, builders ...*QueryBuilder) ([]*T, error) {
+	fields, err := dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+
+	rows, err := querier.QueryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		value := reflect.ValueOf(result).Elem()
+		dest := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			dest = append(dest, value.FieldByIndex(field.index).Addr().Interface())
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// dbField is the struct field which is scanned from the column of its db tag.
+type dbField struct {
+	column Column
+	index  []int
+}
+
+// dbFields returns the fields of the struct with the db tags.
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%s is not a struct", typ)
+	}
+
+	fields := collectDBFields(typ)
+	if len(fields) == 0 {
+		return nil, errors.Errorf("%s has no fields with the db tag", typ)
+	}
+	return fields, nil
+}
+
+// collectDBFields collects the fields with the db tags, the embedded structs without the tag are included.
+func collectDBFields(typ reflect.Type) []dbField {
+	var fields []dbField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, f := range collectDBFields(field.Type) {
+				fields = append(fields, dbField{column: f.column, index: append([]int{i}, f.index...)})
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, dbField{column: Column(tag), index: field.Index})
+	}
+	return fields
+}
+
 // Pagination is the pagination.
 type Pagination struct {
 	// limit is the limit.
@@ -358,6 +571,11 @@ type Config struct {
 
 	QueryLogMethod    func(ctx context.Context, table string, query string, args ...interface{})
 	ErrorLogMethod    func(ctx context.Context, err error, message string)
+{{- if .EncryptedFields }}
+
+	// Cipher encrypts and decrypts the encrypted fields.
+	Cipher Cipher
+{{- end }}
 }
 
 type DB struct {
@@ -373,6 +591,8 @@ type {{ storageName }} interface {
 	{{- end }}
 	// TxManager returns the transaction manager.
 	TxManager() *TxManager
+	// VerifySchema compares the live tables with the models.
+	VerifySchema(ctx context.Context) (*SchemaReport, error)
 
 {{ if .CRUDSchemas }}
 	// CreateTables creates the tables for all the stores.
@@ -383,6 +603,10 @@ type {{ storageName }} interface {
 	TruncateTables(ctx context.Context) error
 	// UpgradeTables upgrades the tables for all the stores.
 	UpgradeTables(ctx context.Context) error
+	// LoadFixtures inserts the rows of the fixture files into the tables.
+	LoadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
+	// ReloadFixtures truncates the tables and loads the fixture files.
+	ReloadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
 {{ end }}
 }
 
@@ -431,26 +655,84 @@ func (c *{{ storageName | lowerCamelCase }}) Get{{ $value.Value }}() {{ $value.V
 }
 {{ end }}
 
+// VerifySchema compares the live tables of all the stores with the models.
+// The differences are listed in the report, the error is returned only if the schema can not be read.
+// Use report.Err() to fail fast at startup when the tables are behind the models.
+func (c *{{ storageName | lowerCamelCase }}) VerifySchema(ctx context.Context) (*SchemaReport, error) {
+	var err error
+	var drifts []SchemaDrift
+	report := &SchemaReport{}
+{{ range $value := storages }}
+	// verify the {{ $value.Value }} table.
+	drifts, err = c.{{ $value.Key }}.VerifyTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify table")
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+{{ end }}
+	return report, nil
+}
+
 {{ if .CRUDSchemas }}
 // CreateTables creates the tables for all the stores.
+// The tables are created in the dependency order, the referenced tables go first,
+// and the foreign keys which close the reference cycles are added after all the tables.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) CreateTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+{{- if or schemas deferredForeignKeys }}
+
+	// use the transaction from the context if there is one.
+	var db QueryExecer = c.config.DB.DBWrite
+	if tx, ok := TxFromContext(ctx); ok && tx != nil {
+		db = tx
+	}
+{{- end }}
+{{ range $schema := schemas }}
+	// create the {{ $schema }} schema.
+	_, err = db.ExecContext(ctx, {{ printf "CREATE SCHEMA IF NOT EXISTS %s" ($schema | quoteIdent) | printf "%q" }})
+	if err != nil {
+		return errors.Wrap(err, "failed to create schema")
+	}
+{{- end }}
+{{ range $value := sortedStorages }}
 	// create the {{ $value.Value }} table.
 	err = c.{{ $value.Key }}.CreateTable(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to create table")
 	}
+{{ end }}
+{{- range $statement := deferredForeignKeys }}
+	// add the foreign key which closes the reference cycle.
+	_, err = db.ExecContext(ctx, {{ $statement | printf "%q" }})
+	if err != nil {
+		return errors.Wrap(err, "failed to add foreign key")
+	}
 {{ end }}
 	return nil
 }
 
 // DropTables drops the tables for all the stores.
+// The tables are dropped in the reverse dependency order, the referencing tables go first.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) DropTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+{{- if dropDeferredForeignKeys }}
+
+	// use the transaction from the context if there is one.
+	var db QueryExecer = c.config.DB.DBWrite
+	if tx, ok := TxFromContext(ctx); ok && tx != nil {
+		db = tx
+	}
+{{ range $statement := dropDeferredForeignKeys }}
+	// drop the foreign key which closes the reference cycle.
+	_, err = db.ExecContext(ctx, {{ $statement | printf "%q" }})
+	if err != nil {
+		return errors.Wrap(err, "failed to drop foreign key")
+	}
+{{- end }}
+{{- end }}
+{{ range $value := reversedStorages }}
 	// drop the {{ $value.Value }} table.
 	err = c.{{ $value.Key }}.DropTable(ctx)
 	if err != nil {
@@ -460,31 +742,67 @@ func (c *{{ storageName | lowerCamelCase }}) DropTables(ctx context.Context) err
 	return nil
 }
 
-// TruncateTables truncates the tables for all the stores.
+// TruncateTables truncates the tables for all the stores in one statement.
+// The tables which reference them are truncated as well (CASCADE), this is meant for the test teardown.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) TruncateTables(ctx context.Context) error {
-	var err error
-{{ range  $value := storages }}
-	// truncate the {{ $value.Value }} table.
-	err = c.{{ $value.Key }}.TruncateTable(ctx)
+	// use the transaction from the context if there is one.
+	var db QueryExecer = c.config.DB.DBWrite
+	if tx, ok := TxFromContext(ctx); ok && tx != nil {
+		db = tx
+	}
+
+	_, err := db.ExecContext(ctx, {{ truncateStatement | printf "%q" }})
 	if err != nil {
-		return errors.Wrap(err, "failed to truncate table")
+		return errors.Wrap(err, "failed to truncate tables")
 	}
-{{ end }}
 	return nil
 }
 
 // UpgradeTables runs the database upgrades for all the stores.
+// The tables are upgraded in the dependency order like CreateTables, so the missing tables are created
+// after the tables they reference, and the foreign keys which close the reference cycles are added after all the tables.
+// The drifts of all the tables are collected into one *SchemaDriftError.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) UpgradeTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+	var drifts []SchemaDrift
+{{- if or schemas deferredForeignKeys }}
+
+	// use the transaction from the context if there is one.
+	var db QueryExecer = c.config.DB.DBWrite
+	if tx, ok := TxFromContext(ctx); ok && tx != nil {
+		db = tx
+	}
+{{- end }}
+{{ range $schema := schemas }}
+	// create the {{ $schema }} schema.
+	_, err = db.ExecContext(ctx, {{ printf "CREATE SCHEMA IF NOT EXISTS %s" ($schema | quoteIdent) | printf "%q" }})
+	if err != nil {
+		return errors.Wrap(err, "failed to create schema")
+	}
+{{- end }}
+{{ range $value := sortedStorages }}
 	// run the {{ $value.Value }} upgrade.
 	err = c.{{ $value.Key }}.UpgradeTable(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to upgrade table")
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return errors.Wrap(err, "failed to upgrade table")
+		}
+		drifts = append(drifts, driftErr.Drifts...)
+	}
+{{ end }}
+{{- range $statement := deferredForeignKeys }}
+	// add the foreign key which closes the reference cycle.
+	_, err = db.ExecContext(ctx, {{ $statement | printf "%q" }})
+	if err != nil {
+		return errors.Wrap(err, "failed to add foreign key")
 	}
 {{ end }}
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 {{ end }}
@@ -765,5 +1083,22 @@ var (
 	ErrRowAlreadyExist    = errors.New("row already exist")
 	// ErrModelIsNil is returned when a relation model is nil.
 	ErrModelIsNil = errors.New("model is nil")
+	// ErrInvalidCursor is returned when a cursor can't be decoded or doesn't match the sorting of the query.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrValidation is returned when a model does not pass the validation.
+	ErrValidation = errors.New("validation failed")
 )
+
+// rowsAffected returns the number of the rows affected by the statement of the single row,
+// it returns ErrRowNotFound if no row is affected.
+func rowsAffected(result sql.Result) (int64, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	if affected == 0 {
+		return 0, ErrRowNotFound
+	}
+	return affected, nil
+}
 `
@@ -0,0 +1,318 @@
+package tmpl
+
+// UpgradeTemplate is the template for the table upgrades.
+// This is included in the init template.
+const UpgradeTemplate = `
+// SchemaDriftKind is the kind of the difference between the live table and the model.
+type SchemaDriftKind string
+
+const (
+	// SchemaDriftMissingTable is a table of the model which does not exist in the database.
+	SchemaDriftMissingTable SchemaDriftKind = "missing_table"
+	// SchemaDriftMissingColumn is a column of the model which does not exist in the table.
+	SchemaDriftMissingColumn SchemaDriftKind = "missing_column"
+	// SchemaDriftExtraColumn is a column of the table which is not declared in the model.
+	SchemaDriftExtraColumn SchemaDriftKind = "extra_column"
+	// SchemaDriftTypeMismatch is a column which type differs from the model.
+	SchemaDriftTypeMismatch SchemaDriftKind = "type_mismatch"
+	// SchemaDriftNullabilityMismatch is a column which nullability differs from the model.
+	SchemaDriftNullabilityMismatch SchemaDriftKind = "nullability_mismatch"
+	// SchemaDriftMissingIndex is an index of the model which does not exist in the table.
+	SchemaDriftMissingIndex SchemaDriftKind = "missing_index"
+)
+
+// SchemaDrift is a difference between the live table and the model which is not applied by the upgrade.
+type SchemaDrift struct {
+	// Table is the name of the table.
+	Table string
+	// Column is the name of the column or the index, it is empty for the missing table.
+	Column string
+	// Kind is the kind of the difference.
+	Kind SchemaDriftKind
+	// Expected is the definition declared by the model.
+	Expected string
+	// Actual is the definition of the live table.
+	Actual string
+}
+
+// String returns the description of the drift.
+func (d SchemaDrift) String() string {
+	name := d.Table
+	if d.Column != "" {
+		name += "." + d.Column
+	}
+	switch {
+	case d.Expected == "" && d.Actual == "":
+		return fmt.Sprintf("%s: %s", name, d.Kind)
+	case d.Actual == "":
+		return fmt.Sprintf("%s: %s (expected %q)", name, d.Kind, d.Expected)
+	case d.Expected == "":
+		return fmt.Sprintf("%s: %s (actual %q)", name, d.Kind, d.Actual)
+	}
+	return fmt.Sprintf("%s: %s (expected %q, actual %q)", name, d.Kind, d.Expected, d.Actual)
+}
+
+// Breaking reports whether the drift breaks the queries of the store.
+// The extra columns and the missing indexes do not break the queries.
+func (d SchemaDrift) Breaking() bool {
+	return d.Kind != SchemaDriftExtraColumn && d.Kind != SchemaDriftMissingIndex
+}
+
+// SchemaDriftError is returned by the upgrade when the live schema has the destructive differences.
+type SchemaDriftError struct {
+	Drifts []SchemaDrift
+}
+
+// Error returns the error message.
+func (e *SchemaDriftError) Error() string {
+	drifts := make([]string, 0, len(e.Drifts))
+	for _, drift := range e.Drifts {
+		drifts = append(drifts, drift.String())
+	}
+	return "schema drift: " + strings.Join(drifts, "; ")
+}
+
+// SchemaReport is the result of the schema verification.
+type SchemaReport struct {
+	// Drifts are the differences between the live tables and the models.
+	Drifts []SchemaDrift
+}
+
+// OK reports whether the live tables match the models.
+func (r *SchemaReport) OK() bool {
+	return len(r.Drifts) == 0
+}
+
+// Err returns the *SchemaDriftError of the breaking drifts, nil if the stores can query the tables.
+func (r *SchemaReport) Err() error {
+	var drifts []SchemaDrift
+	for _, drift := range r.Drifts {
+		if drift.Breaking() {
+			drifts = append(drifts, drift)
+		}
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{Drifts: drifts}
+}
+
+// String returns the readable report, one drift per line.
+func (r *SchemaReport) String() string {
+	if r.OK() {
+		return "schema is up to date"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema has %d drift(s):", len(r.Drifts))
+	for _, drift := range r.Drifts {
+		b.WriteString("\n  ")
+		b.WriteString(drift.String())
+	}
+	return b.String()
+}
+
+// tableColumn is the column of the model which the upgrade compares with the live table.
+type tableColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+	// Statement adds the missing column, it is empty if the column can not be added safely.
+	Statement string
+}
+
+// tableIndex is the index of the model which the upgrade creates.
+type tableIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Statement creates the index if it does not exist.
+	Statement string
+}
+
+// liveColumn is the column of the live table.
+type liveColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+}
+
+// liveIndex is the index of the live table.
+type liveIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// introspectColumns returns the columns of the live table from information_schema.
+// No columns are returned if the table does not exist.
+func introspectColumns(ctx context.Context, db QueryExecer, schema string, table string) ([]liveColumn, error) {
+	rows, err := db.QueryContext(ctx, ` + "`" + `
+		SELECT column_name, data_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2
+		ORDER BY ordinal_position
+	` + "`" + `, schema, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query columns")
+	}
+	defer rows.Close()
+
+	var columns []liveColumn
+	for rows.Next() {
+		var column liveColumn
+		var isNullable string
+		if err := rows.Scan(&column.Name, &column.Type, &isNullable); err != nil {
+			return nil, errors.Wrap(err, "failed to scan column")
+		}
+		column.NotNull = isNullable == "NO"
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate columns")
+	}
+
+	return columns, nil
+}
+
+// introspectIndexes returns the indexes of the live table from pg_index.
+func introspectIndexes(ctx context.Context, db QueryExecer, schema string, table string) ([]liveIndex, error) {
+	rows, err := db.QueryContext(ctx, ` + "`" + `
+		SELECT i.relname, ix.indisunique, COALESCE(array_to_string(array(
+			SELECT a.attname
+			FROM unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+			ORDER BY k.ord
+		), ','), '')
+		FROM pg_index ix
+		JOIN pg_class c ON c.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+	` + "`" + `, schema, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query indexes")
+	}
+	defer rows.Close()
+
+	var indexes []liveIndex
+	for rows.Next() {
+		var index liveIndex
+		var columns string
+		if err := rows.Scan(&index.Name, &index.Unique, &columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan index")
+		}
+		if columns != "" {
+			index.Columns = strings.Split(columns, ",")
+		}
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate indexes")
+	}
+
+	return indexes, nil
+}
+
+// compareColumns returns the columns of the model which are missing in the live table and can be added,
+// and the differences which can not be applied safely.
+func compareColumns(table string, columns []tableColumn, live []liveColumn) ([]tableColumn, []SchemaDrift) {
+	actual := make(map[string]liveColumn, len(live))
+	for _, column := range live {
+		actual[column.Name] = column
+	}
+
+	var missing []tableColumn
+	var drifts []SchemaDrift
+	declared := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		declared[column.Name] = true
+
+		current, ok := actual[column.Name]
+		switch {
+		case !ok && column.Statement != "":
+			missing = append(missing, column)
+		case !ok:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+		case !strings.EqualFold(current.Type, column.Type):
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftTypeMismatch, Expected: column.Type, Actual: current.Type})
+		case current.NotNull != column.NotNull:
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftNullabilityMismatch, Expected: nullability(column.NotNull), Actual: nullability(current.NotNull)})
+		}
+	}
+
+	for _, column := range live {
+		if !declared[column.Name] {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftExtraColumn, Actual: column.Type})
+		}
+	}
+
+	return missing, drifts
+}
+
+// indexStatements returns the statements of the indexes which columns exist in the table.
+func indexStatements(indexes []tableIndex, live []liveColumn, added []tableColumn) []string {
+	existing := make(map[string]bool, len(live)+len(added))
+	for _, column := range live {
+		existing[column.Name] = true
+	}
+	for _, column := range added {
+		existing[column.Name] = true
+	}
+
+	var statements []string
+	for _, index := range indexes {
+		complete := true
+		for _, column := range index.Columns {
+			complete = complete && existing[column]
+		}
+		if complete {
+			statements = append(statements, index.Statement)
+		}
+	}
+	return statements
+}
+
+// verifyTable returns the differences between the live table and the model.
+// Unlike the upgrade, the columns which can be added are reported as missing too.
+func verifyTable(table string, columns []tableColumn, indexes []tableIndex, live []liveColumn, liveIndexes []liveIndex) []SchemaDrift {
+	if len(live) == 0 {
+		return []SchemaDrift{
+			{Table: table, Kind: SchemaDriftMissingTable},
+		}
+	}
+
+	missing, drifts := compareColumns(table, columns, live)
+	for _, column := range missing {
+		drifts = append(drifts, SchemaDrift{Table: table, Column: column.Name, Kind: SchemaDriftMissingColumn, Expected: column.Type})
+	}
+	for _, index := range indexes {
+		if !hasIndex(index, liveIndexes) {
+			drifts = append(drifts, SchemaDrift{Table: table, Column: index.Name, Kind: SchemaDriftMissingIndex, Expected: strings.Join(index.Columns, ", ")})
+		}
+	}
+	return drifts
+}
+
+// hasIndex reports whether the live table has the index with the same name or on the same columns.
+// The unique index is satisfied only by the live unique index.
+func hasIndex(index tableIndex, live []liveIndex) bool {
+	for _, current := range live {
+		if current.Name == index.Name {
+			return true
+		}
+		if len(current.Columns) > 0 && strings.Join(current.Columns, ",") == strings.Join(index.Columns, ",") && (current.Unique || !index.Unique) {
+			return true
+		}
+	}
+	return false
+}
+
+// nullability returns the nullability of the column in the SQL notation.
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+`
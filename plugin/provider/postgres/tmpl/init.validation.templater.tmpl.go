@@ -0,0 +1,120 @@
+package tmpl
+
+// ValidationTemplate is the template for the model validation.
+// This is included in the init template.
+const ValidationTemplate = `
+// FieldError describes a field which does not pass the validation.
+type FieldError struct {
+	// Field is the name of the field.
+	Field string
+	// Rule is the name of the failed validation rule.
+	Rule string
+	// Message is the human readable description of the error.
+	Message string
+}
+
+// Error returns the error message.
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned when a model does not pass the validation.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns ErrValidation, so the error can be checked with errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+var (
+	// emailRegexp is a simplified email address format.
+	emailRegexp = regexp.MustCompile(` + "`" + `^[^@\s]+@[^@\s]+\.[^@\s]+$` + "`" + `)
+	// uuidRegexp is the canonical UUID format.
+	uuidRegexp = regexp.MustCompile(` + "`" + `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$` + "`" + `)
+)
+
+// validator collects the field errors.
+type validator struct {
+	errors []FieldError
+}
+
+// add adds the field error.
+func (v *validator) add(field, rule, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// err returns the ValidationError if there are field errors.
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// notEmpty checks that the value is not empty.
+func (v *validator) notEmpty(field string, value string) {
+	if value == "" {
+		v.add(field, "not_empty", "must not be empty")
+	}
+}
+
+// min checks that the value is greater than or equal to min.
+func (v *validator) min(field string, value float64, min float64) {
+	if value < min {
+		v.add(field, "min", fmt.Sprintf("must be greater than or equal to %v", min))
+	}
+}
+
+// max checks that the value is less than or equal to max.
+func (v *validator) max(field string, value float64, max float64) {
+	if value > max {
+		v.add(field, "max", fmt.Sprintf("must be less than or equal to %v", max))
+	}
+}
+
+// minLen checks that the value has at least min characters.
+func (v *validator) minLen(field string, value string, min int) {
+	if utf8.RuneCountInString(value) < min {
+		v.add(field, "min_len", fmt.Sprintf("must be at least %d characters long", min))
+	}
+}
+
+// maxLen checks that the value has at most max characters.
+func (v *validator) maxLen(field string, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, "max_len", fmt.Sprintf("must be at most %d characters long", max))
+	}
+}
+
+// pattern checks that the value matches the regular expression.
+func (v *validator) pattern(field string, value string, re *regexp.Regexp) {
+	if !re.MatchString(value) {
+		v.add(field, "pattern", fmt.Sprintf("must match the pattern %q", re.String()))
+	}
+}
+
+// email checks that the value is an email address.
+func (v *validator) email(field string, value string) {
+	if !emailRegexp.MatchString(value) {
+		v.add(field, "email", "must be a valid email address")
+	}
+}
+
+// uuid checks that the value is a UUID.
+func (v *validator) uuid(field string, value string) {
+	if !uuidRegexp.MatchString(value) {
+		v.add(field, "uuid", "must be a valid UUID")
+	}
+}
+`
@@ -6,23 +6,29 @@ const TableTemplate = `
 {{ template "table_conditions" . }}
 {{ template "create_method" . }}
 {{ template "batch_create_method" . }}
+{{ template "upsert_method" . }}
 {{ template "update_method" . }}
 {{ template "delete_method" . }}
 {{- if (hasPrimaryKey) }}
 {{ template "get_by_id_method" . }}
 {{- end }}
 {{ template "find_many_method" . }}
+{{ template "join_method" . }}
 {{ template "find_one_method" . }}
 {{ template "count_method" . }}
+{{ template "aggregate_method" . }}
 {{ template "find_with_pagination" . }}
+{{ template "search_method" . }}
 {{ template "lock_method" . }}
 {{ template "raw_method" . }}
+{{ template "service_methods" . }}
 `
 
 const TableConditionFilters = `
 {{ range $key, $fieldMess := messages_for_filter }}
 	{{- if len $fieldMess.GetField }}
 		// {{ $fieldMess.GetName | camelCase }}Filters is a struct that holds filters for {{ $fieldMess.GetName }}.
+		// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 		type {{structureName}}Filters struct {
 			{{ range $field := $fieldMess.GetField }}
 			{{- if not ($field | isRelation) }}
@@ -31,8 +37,83 @@ const TableConditionFilters = `
 				{{- else }}
 					{{ $field | fieldName }} *{{ $field | fieldType }}
 				{{- end }}
+				{{- if ($field | isRangeFilter) }}
+					{{ $field | fieldName }}From *{{ $field | fieldTypeWP }}
+					{{ $field | fieldName }}To *{{ $field | fieldTypeWP }}
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+					{{ $field | fieldName }}In []{{ $field | fieldTypeWP }}
+				{{- end }}
+			{{- end }}
+			{{- end }}
+		}
+
+		// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+		func (f *{{structureName}}Filters) Apply() *QueryBuilder {
+			if f == nil {
+				return NewQueryBuilder()
+			}
+			var filters []FilterApplier
+			{{- range $field := $fieldMess.GetField }}
+			{{- if not ($field | isRelation) }}
+			if f.{{ $field | fieldName }} != nil {
+				{{- if ($field | isJSON) }}
+				filters = append(filters, JSONContains("{{ $field.GetName }}", f.{{ $field | fieldName }}))
+				{{- else }}
+				filters = append(filters, EqualsCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}})
+				{{- end }}
+			}
+			{{- if ($field | isRangeFilter) }}
+			if f.{{ $field | fieldName }}From != nil {
+				filters = append(filters, GreaterThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}From})
+			}
+			if f.{{ $field | fieldName }}To != nil {
+				filters = append(filters, LessThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}To})
+			}
+			{{- end }}
+			{{- if ($field | isListFilter) }}
+			if f.{{ $field | fieldName }}In != nil {
+				filters = append(filters, InCondition{Field: "{{ $field.GetName }}", Values: toInterface(f.{{ $field | fieldName }}In)})
+			}
+			{{- end }}
 			{{- end }}
 			{{- end }}
+			return FilterBuilder(filters...)
+		}
+
+		// Parse{{structureName}}Filters parses the filters from the query string values, e.g. of an HTTP list request.
+		// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+		// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+		// The times are in RFC 3339. The unknown keys, the repeated keys of the single values and the invalid values are errors.
+		func Parse{{structureName}}Filters(values url.Values) (*{{structureName}}Filters, error) {
+			f := &{{structureName}}Filters{}
+			for _, key := range filterKeys(values) {
+				var err error
+				switch key {
+				{{- range $field := $fieldMess.GetField }}
+				{{- if and (not ($field | isRelation)) ($field | isScalarFilter) }}
+				case "{{ $field.GetName }}":
+					f.{{ $field | fieldName }}, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- if ($field | isRangeFilter) }}
+				case "{{ $field.GetName }}_from":
+					f.{{ $field | fieldName }}From, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				case "{{ $field.GetName }}_to":
+					f.{{ $field | fieldName }}To, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+				case "{{ $field.GetName }}_in":
+					f.{{ $field | fieldName }}In, err = parseFilterValues[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- end }}
+				{{- end }}
+				default:
+					return nil, errors.Errorf("unknown filter %q", key)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			return f, nil
 		}
 	{{- end }}
 {{ end }}
@@ -51,6 +132,15 @@ const TableConditionFilters = `
   {{ end }}
 {{ end }}
 
+{{ range $field := fields }}
+  {{- if ($field | hasBlindIndex) }}
+	// {{ messageName }}{{ $field.GetName | camelCase }}Eq returns a condition that checks if the encrypted field equals the value.
+	func {{ messageName }}{{ $field.GetName | camelCase }}Eq(value {{ $field | fieldTypeWP }}) FilterApplier {
+		return BlindIndexCondition{Field: "{{ $field | blindIndexColumn }}", Value: value}
+	}
+  {{ end }}
+{{- end }}
+
 {{ range $key, $fieldMess := messages_for_filter }}
   {{ range $field := $fieldMess.GetField }}
    {{- if not ($field | isRelation) }}
@@ -262,6 +352,41 @@ const TableConditionFilters = `
   {{ end }}
   {{ end }}
 {{ end }}
+{{ range $field := fields }}
+   {{- if ($field | isJSON) }}
+   {{- if ($field | isRepeated) }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON array contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value {{ $field | jsonElementType }}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", []{{ $field | jsonElementType }}{value})
+    }
+   {{- else }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON object contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value interface{}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", value)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}HasKey returns a condition that checks if the JSON object has the key.
+    func {{ structureName }}{{ $field.GetName | camelCase }}HasKey(key string) FilterApplier {
+      return JSONHasKey("{{ $field.GetName }}", key)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}PathEq returns a condition that checks if the value at the dot-separated path of the JSON object equals the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}PathEq(path string, value interface{}) FilterApplier {
+      return JSONPathEq("{{ $field.GetName }}", path, value)
+    }
+   {{- end }}
+  {{ end }}
+{{ end }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+
+// {{ structureName }}Has{{ $field | fieldName }} checks if the {{ structureName }} has the {{ $field | fieldName }} relation matching the filters.
+func {{ structureName }}Has{{ $field | fieldName }}(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string({{ $field | relationStructureName }}Column{{ $field | getRefID }})).From((&{{ $field | relationStructureName }}{}).QualifiedTableName())
+	return InSubqueryCondition{Field: string({{ structureName }}Column{{ $field | getFieldID }}), Query: query, Conditions: filters}
+}
+{{- end }}
+{{- end }}
 `
 
 const TableFindWithPaginationMethodTemplate = `
@@ -295,12 +420,247 @@ func (t *{{ storageName | lowerCamelCase }}) FindManyWithPagination(ctx context.
 
 	return records, paginator, nil
 }
+{{- if (hasPrimaryKey) }}
+
+// FindManyAfter finds a page of {{ structureName }} after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *{{ storageName | lowerCamelCase }}) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*{{structureName}}, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&{{structureName}}{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find {{ structureName }}")
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the {{ structureName }} row.
+func (t *{{ storageName | lowerCamelCase }}) cursor(model *{{structureName}}, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *{{ storageName | lowerCamelCase }}) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, errors.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, errors.Errorf("the column %q of {{ structureName }} can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == {{ getPrimaryKey | sourceName | printf "%q" }} {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: {{ getPrimaryKey | sourceName | printf "%q" }}, Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *{{ storageName | lowerCamelCase }}) isCursorColumn(column string) bool {
+	switch column {
+	{{- range $field := fields }}
+	{{- if ($field | isCursorColumn) }}
+	case {{ $field | sourceName | printf "%q" }}:
+		return true
+	{{- end }}
+	{{- end }}
+	}
+	return false
+}
+{{- end }}
+`
+
+const TableSearchMethodTemplate = `
+{{- if searchFields }}
+// {{ structureName }}SearchResult is the {{ structureName }} found by Search with its relevance.
+type {{ structureName }}SearchResult struct {
+	{{ structureName }}
+	// Rank is the relevance of the row to the query, the higher the better.
+	Rank float64
+	// Snippets are the fragments of the searchable fields with the highlighted matches by their columns,
+	// they are set with the WithSearchHighlight option.
+	Snippets map[Column]string
+}
+
+// Search finds the {{ structureName }} rows matching the full-text search query, the most relevant first.
+// The query is parsed by websearch_to_tsquery, so it supports the quoted phrases, "or" and "-" before the excluded words.
+// The builders filter, sort and paginate the found rows like in FindMany.
+func (t *{{ storageName | lowerCamelCase }}) Search(ctx context.Context, query string, builders ...*QueryBuilder) ([]*{{ structureName }}SearchResult, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rows are sorted by the relevance before the sorting of the builders
+	tsQuery := "websearch_to_tsquery('{{ searchConfig }}', ?)"
+	selectQuery := t.selectQuery(columns, append([]*QueryBuilder{SortBuilder(OrderBy(searchRankColumn, false))}, builders...)...).
+		Column(sq.Expr("ts_rank({{ searchColumn.Name }}, "+tsQuery+") AS "+searchRankColumn, query)).
+		Where(sq.Expr("{{ searchColumn.Name }} @@ "+tsQuery, query))
+
+	highlight := builderHighlight(builders)
+	if highlight != nil {
+		{{- range $field := searchFields }}
+		selectQuery = selectQuery.Column(sq.Expr("ts_headline('{{ searchConfig }}', coalesce({{ $field | sourceName }}, ''), "+tsQuery+", ?)", query, highlight.headlineOptions()))
+		{{- end }}
+	}
+
+	sqlQuery, args, err := selectQuery.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*{{ structureName }}SearchResult
+	for rows.Next() {
+		result := &{{ structureName }}SearchResult{}
+		dest, err := result.{{ structureName }}.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		dest = append(dest, &result.Rank)
+		snippets := make([]string, {{ len searchFields }})
+		if highlight != nil {
+			for i := range snippets {
+				dest = append(dest, &snippets[i])
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		{{- if (hasEncrypted) }}
+		if err := result.{{ structureName }}.Decrypt(t.config.Cipher); err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+		}
+		{{- end }}
+		if highlight != nil {
+			result.Snippets = map[Column]string{
+				{{- range $i, $field := searchFields }}
+				{{ structureName }}Column{{ $field | fieldName }}: snippets[{{ $i }}],
+				{{- end }}
+			}
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	models := make([]*{{ structureName }}, 0, len(results))
+	for _, result := range results {
+		models = append(models, &result.{{ structureName }})
+	}
+	if err := t.preload(ctx, models, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+{{- end }}
 `
 
 const TableLockMethodTemplate = `
 // SelectForUpdate lock locks the {{ structureName }} for the given ID.
 func (t *{{ storageName | lowerCamelCase }}) SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error) {
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName()).Suffix("FOR UPDATE")
+	query := t.queryBuilder.Select(t.Columns()...).From(t.QualifiedTableName()).Suffix("FOR UPDATE")
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -310,6 +670,9 @@ func (t *{{ storageName | lowerCamelCase }}) SelectForUpdate(ctx context.Context
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
 			query = option.Apply(query)
 		}
 
@@ -332,6 +695,11 @@ func (t *{{ storageName | lowerCamelCase }}) SelectForUpdate(ctx context.Context
         }
         return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
     }
+	{{- if (hasEncrypted) }}
+	if err := model.Decrypt(t.config.Cipher); err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+	}
+	{{- end }}
 
 	return &model, nil
 }
@@ -341,7 +709,7 @@ const TableCountMethodTemplate = `
 // Count counts {{ structureName }} based on the provided options.
 func (t *{{ storageName | lowerCamelCase }}) Count(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Select("COUNT(*)").From(t.TableName())
+	query := t.queryBuilder.Select("COUNT(*)").From(t.QualifiedTableName())
 
 	// apply options from builder
 	for _, builder := range builders {
@@ -351,6 +719,9 @@ func (t *{{ storageName | lowerCamelCase }}) Count(ctx context.Context, builders
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
 			query = option.Apply(query)
 		}
 
@@ -375,47 +746,114 @@ func (t *{{ storageName | lowerCamelCase }}) Count(ctx context.Context, builders
 }
 `
 
-const TableFindOneMethodTemplate = `
-// FindOne finds a single {{ structureName }} based on the provided options.
-func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error) {
-	// Use findMany but limit the results to 1
-	builders = append(builders, LimitBuilder(1))
-	results, err := t.FindMany(ctx, builders...)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to findOne {{ structureName }}")
+const TableAggregateMethodTemplate = `
+// {{ structureName }}AggregateRow is a row of the {{ structureName }} Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type {{ structureName }}AggregateRow struct {
+	{{ structureName }}
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *{{ structureName }}AggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
 	}
+	return 0
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
 
-	if len(results) == 0 {
-		return nil, ErrRowNotFound
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values, it is set by Sum({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Sum{{ $field | fieldName }}() {{ $field | sumType }} {
+	if v, ok := r.values[Sum({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | sumType }}); ok && *v != nil {
+		return **v
 	}
+	return 0
+}
 
-	return results[0], nil
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values, it is set by Avg({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Avg{{ $field | fieldName }}() *float64 {
+	if v, ok := r.values[Avg({{ structureName }}Column{{ $field | fieldName }})].(**float64); ok {
+		return *v
+	}
+	return nil
 }
-`
+{{- end }}
+{{- if ($field | minMaxType) }}
 
-const TableFindManyMethodTemplate = `
-// FindMany finds multiple {{ structureName }} based on the provided options.
-func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
-	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value, it is set by Min({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Min{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Min({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
 
-	// set default options
-	options := &Options{}
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value, it is set by Max({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Max{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Max({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
 
- 	// apply options from builder
-	for _, builder := range builders {
+// Aggregate groups the {{ structureName }} rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy({{ structureName }}ColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *{{ storageName | lowerCamelCase }}) Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{ structureName }}AggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, errors.New("no aggregations of {{ structureName }}")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.QualifiedTableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
 		if builder == nil {
 			continue
 		}
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
 			query = option.Apply(query)
 		}
 
 		// apply custom filters
 		query = builder.ApplyCustomFilters(query)
 
+		if len(columns) == 0 {
+			continue
+		}
+
 		// apply pagination
 		if builder.pagination != nil {
 			if builder.pagination.limit != nil {
@@ -430,11 +868,6 @@ func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, build
 		for _, option := range builder.sortOptions {
 			query = option.Apply(query)
 		}
-
-	    // apply options
-		for _, o := range builder.options {
-			o(options)
-		}
 	}
 
 	// execute query
@@ -453,48 +886,612 @@ func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, build
 			t.logError(ctx, err, "failed to close rows")
 		}
 	}()
-	
-	var results []*{{structureName}}
+
+	var results []*{{ structureName }}AggregateRow
 	for rows.Next() {
-		model := &{{structureName}}{}
-		if err := model.ScanRows(rows); err != nil {
-			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		row := &{{ structureName }}AggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.{{ structureName }}.columnDest(columns)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, model)
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }} aggregate")
+		}
+		{{- if (hasEncrypted) }}
+		if err := row.{{ structureName }}.Decrypt(t.config.Cipher); err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+		}
+		{{- end }}
+		results = append(results, row)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, errors.Wrap(err, "failed to iterate over rows")
 	}
-	
+
 	return results, nil
 }
-`
 
-const TableGetByIDMethodTemplate = `
-// FindBy{{ getPrimaryKey.GetName | camelCase }} retrieves a {{ structureName }} by its {{ getPrimaryKey.GetName }}.
-func (t *{{ storageName | lowerCamelCase }}) FindBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, id {{IDType}}, opts ...Option) (*{{ structureName }}, error) {
-	builder := NewQueryBuilder()
-	{
-		builder.WithFilter({{ messageName }}{{ getPrimaryKey.GetName | camelCase }}Eq(id))
-		builder.WithOptions(opts...)
-	}
-	
-	// Use FindOne to get a single result
-	model, err := t.FindOne(ctx, builder)
+// aggregate returns the single aggregation of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*{{ structureName }}AggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
 	if err != nil {
-		return nil, errors.Wrap(err, "find one {{ structureName }}: ")
+		return nil, err
 	}
+	if len(rows) == 0 {
+		return &{{ structureName }}AggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
 
-	return model, nil
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error) {
+	row, err := t.aggregate(ctx, Sum({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.Sum{{ $field | fieldName }}(), nil
 }
-`
 
-const TableRawQueryMethodTemplate = `
-// Query executes a raw query and returns the result.
-// isWrite is used to determine if the query is a write operation.
-func (t *{{ storageName | lowerCamelCase }}) Query(ctx context.Context, isWrite bool, query string, args ...interface{}) (sql.Result, error) {
-	return t.DB(ctx, isWrite).ExecContext(ctx, query, args...)
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Avg{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- if ($field | minMaxType) }}
+
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Min({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Min{{ $field | fieldName }}(), nil
+}
+
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Max({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Max{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- end }}
+
+// aggregateDest returns the scan destination of the aggregation of the {{ structureName }} rows.
+func (t *{{ storageName | lowerCamelCase }}) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	case Sum({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | sumType }}), nil
+	case Avg({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*float64), nil
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	case Min({{ structureName }}Column{{ $field | fieldName }}), Max({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | minMaxType }}), nil
+	{{- end }}
+	{{- end }}
+	}
+	return nil, errors.Errorf("unsupported aggregation %s of {{ structureName }}", aggregation)
+}
+`
+
+const TableFindOneMethodTemplate = `
+// FindOne finds a single {{ structureName }} based on the provided options.
+func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error) {
+	// Use findMany but limit the results to 1
+	builders = append(builders, LimitBuilder(1))
+	results, err := t.FindMany(ctx, builders...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to findOne {{ structureName }}")
+	}
+
+	if len(results) == 0 {
+		return nil, ErrRowNotFound
+	}
+
+	return results[0], nil
+}
+`
+
+const TableFindManyMethodTemplate = `
+// FindMany finds multiple {{ structureName }} based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. {{ structureName }}Join<Relation>, are filled in the same query.
+func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+	
+	var results []*{{structureName}}
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		{{- if (hasEncrypted) }}
+		if err := model.Decrypt(t.config.Cipher); err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+		}
+		{{- end }}
+		results = append(results, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+	
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the {{ structureName }} rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it. With the WithServerCursor option the rows are fetched
+// in batches from a server-side cursor which is declared in the transaction of the context or in a new read-only transaction.
+func (t *{{ storageName | lowerCamelCase }}) FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	if options.serverCursorBatch > 0 {
+		return t.findEachWithCursor(ctx, fn, columns, options.serverCursorBatch, builders...)
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	_, err = t.scanEach(rows, columns, fn)
+	return err
+}
+
+// findEachWithCursor streams the rows to fn from a server-side cursor which fetches the rows in batches.
+func (t *{{ storageName | lowerCamelCase }}) findEachWithCursor(ctx context.Context, fn func(*{{structureName}}) error, columns []string, batchSize int, builders ...*QueryBuilder) error {
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	// the server-side cursor lives until the end of the transaction
+	if _, ok := TxFromContext(ctx); !ok {
+		tx, err := t.config.DB.DBRead.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				t.logError(ctx, err, "failed to rollback transaction")
+			}
+		}()
+		ctx = context.WithValue(ctx, txKey{}, tx)
+	}
+	db := t.DB(ctx, false)
+
+	cursor := newServerCursor(sqlQuery, batchSize)
+	t.logQuery(ctx, cursor.declare, args...)
+	if _, err := db.ExecContext(ctx, cursor.declare, args...); err != nil {
+		return errors.Wrap(err, "failed to declare cursor")
+	}
+	defer func() {
+		if _, err := db.ExecContext(ctx, cursor.close); err != nil {
+			t.logError(ctx, err, "failed to close cursor")
+		}
+	}()
+
+	for {
+		t.logQuery(ctx, cursor.fetch)
+		rows, err := db.QueryContext(ctx, cursor.fetch)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch cursor")
+		}
+		n, err := t.scanEach(rows, columns, fn)
+		if closeErr := rows.Close(); closeErr != nil {
+			t.logError(ctx, closeErr, "failed to close rows")
+		}
+		if err != nil || n < batchSize {
+			return err
+		}
+	}
+}
+
+// scanEach scans the rows and passes the models to fn, it returns the number of the scanned rows.
+func (t *{{ storageName | lowerCamelCase }}) scanEach(rows *sql.Rows, columns []string, fn func(*{{structureName}}) error) (int, error) {
+	var n int
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return n, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		{{- if (hasEncrypted) }}
+		if err := model.Decrypt(t.config.Cipher); err != nil {
+			return n, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+		}
+		{{- end }}
+		n++
+		if err := fn(model); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return n, nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *{{ storageName | lowerCamelCase }}) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *{{ storageName | lowerCamelCase }}) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, errors.Errorf("unknown column %q of {{ structureName }}", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *{{ storageName | lowerCamelCase }}) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *{{ storageName | lowerCamelCase }}) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *{{ storageName | lowerCamelCase }}) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
+	// build query
+	query := t.queryBuilder.Select(columns...).From(t.QualifiedTableName())
+
+	// set default options
+	options := &Options{}
+
+ 	// apply options from builder
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
+			query = option.Apply(query)
+		}
+
+		// apply custom filters
+		query = builder.ApplyCustomFilters(query)
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+
+	    // apply options
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	return query
+}
+`
+
+const TableJoinMethodTemplate = `
+{{- range $index, $field := fields }}
+{{- if ($field | isRelation) }}
+// {{ structureName }}Join{{ $field | fieldName }} joins the {{ $field | fieldName }} relation filtered by the filters,
+// FindMany fills {{ structureName }}.{{ $field | fieldName }} with the joined rows in the same query.
+// The {{ structureName }} rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func {{ structureName }}Join{{ $field | fieldName }}(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    {{ tableName | printf "%q" }},
+		relation: {{ $field | fieldName | printf "%q" }},
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+{{- end }}
+{{- end }}
+
+// findManyJoined finds the {{ structureName }} rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated {{ structureName }} rows are merged.
+func (t *{{ storageName | lowerCamelCase }}) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, false).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	var results []*{{structureName}}
+	found := make(map[int64]*{{structureName}})
+	attached := make(map[[3]int64]bool)
+	for rows.Next() {
+		model := &{{structureName}}{}
+		dest, err := model.columnDest(columns)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+		}
+
+		// the {{ structureName }} row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			{{- if (hasEncrypted) }}
+			if err := model.Decrypt(t.config.Cipher); err != nil {
+				return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+			}
+			{{- end }}
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			{{- if .EncryptedFields }}
+			if m, ok := joinedRow.model.(interface{ Decrypt(Cipher) error }); ok {
+				if err := m.Decrypt(t.config.Cipher); err != nil {
+					return nil, errors.Wrapf(err, "failed to decrypt the %s relation", relations[i].join.relation)
+				}
+			}
+			{{- end }}
+			joinedRow.attach(model)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate over rows")
+	}
+
+	return results, nil
+}
+
+// hasSelected returns true if the column is in the selected columns.
+func (t *{{ storageName | lowerCamelCase }}) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a {{ structureName }} relation.
+func (t *{{ storageName | lowerCamelCase }}) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, errors.Errorf("the join of the %s relation of %s can't be used with {{ structureName }}", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, errors.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	{{- range $index, $field := fields }}
+	{{- if ($field | isRelation) }}
+	case {{ $field | fieldName | printf "%q" }}:
+		s := &{{ $field | relationStorageName | lowerCamelCase }}{config: t.config, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string({{ $field | relationStructureName }}Column{{ $field | getRefID }})
+		ref := string({{ structureName }}Column{{ $field | getFieldID }})
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.QualifiedTableName() + "." + column + " = " + t.QualifiedTableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &{{ $field | relationStructureName }}{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*{{ structureName }})
+					{{- if ($field | isRepeated) }}
+					p.{{ $field | fieldName }} = append(p.{{ $field | fieldName }}, model)
+					{{- else }}
+					p.{{ $field | fieldName }} = model
+					{{- end }}
+				}
+			},
+		}, nil
+	{{- end }}
+	{{- end }}
+	}
+
+	return nil, errors.Errorf("unknown relation %s of {{ structureName }}", join.relation)
+}
+`
+
+const TableGetByIDMethodTemplate = `
+// FindBy{{ getPrimaryKey.GetName | camelCase }} retrieves a {{ structureName }} by its {{ getPrimaryKey.GetName }}.
+func (t *{{ storageName | lowerCamelCase }}) FindBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, id {{IDType}}, opts ...Option) (*{{ structureName }}, error) {
+	builder := NewQueryBuilder()
+	{
+		builder.WithFilter({{ messageName }}{{ getPrimaryKey.GetName | camelCase }}Eq(id))
+		builder.WithOptions(opts...)
+	}
+	
+	// Use FindOne to get a single result
+	model, err := t.FindOne(ctx, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "find one {{ structureName }}: ")
+	}
+
+	return model, nil
+}
+`
+
+const TableRawQueryMethodTemplate = `
+// Query executes a raw query and returns the result.
+// isWrite is used to determine if the query is a write operation.
+func (t *{{ storageName | lowerCamelCase }}) Query(ctx context.Context, isWrite bool, query string, args ...interface{}) (sql.Result, error) {
+	return t.DB(ctx, isWrite).ExecContext(ctx, query, args...)
 }
 
 // QueryRow executes a raw query and returns the result.
@@ -513,34 +1510,36 @@ func (t *{{ storageName | lowerCamelCase }}) QueryRows(ctx context.Context, isWr
 const TableDeleteMethodTemplate = `
 {{- if (hasPrimaryKey) }}
 // DeleteBy{{ getPrimaryKey.GetName | camelCase }} - deletes a {{ structureName }} by its {{ getPrimaryKey.GetName }}.
-func (t *{{ storageName | lowerCamelCase }}) DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) error {
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the {{ getPrimaryKey.GetName }}.
+func (t *{{ storageName | lowerCamelCase }}) DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) (int64, error) {
 	// set default options
 	options := &Options{}
 	for _, o := range opts {
 		o(options)
 	}
 
-	query := t.queryBuilder.Delete("{{ tableName }}").Where("id = ?", id)
+	query := t.queryBuilder.Delete(t.QualifiedTableName()).Where("id = ?", id)
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx,sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx,sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete {{ structureName }}")
+		return 0, errors.Wrap(err, "failed to delete {{ structureName }}")
 	}
 
-	return nil
+	return rowsAffected(result)
 }
 {{- end }}
 
-// DeleteMany removes entries from the {{ tableName }} table using the provided filters
-func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
+// DeleteMany removes entries from the {{ tableName }} table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
 	// build query
-	query := t.queryBuilder.Delete("{{ tableName }}")
+	query := t.queryBuilder.Delete(t.QualifiedTableName())
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -550,27 +1549,34 @@ func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, bui
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
 			query = option.ApplyDelete(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for delete operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+		return 0, errors.Wrap(err, "failed to build query")
 	}
 	t.logQuery(ctx, sqlQuery, args...)
 
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete {{ tableName }}")
+		return 0, errors.Wrap(err, "failed to delete {{ tableName }}")
 	}
-	
-	return nil
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
 }
 `
 
@@ -592,21 +1598,232 @@ type {{ structureName }}Update struct {
 	{{- end }}
 	{{- end }}
 	{{- end }}
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
+}
+
+// Validate validates the fields of the {{ structureName }}Update which are set.
+func (t *{{ structureName }}Update) Validate() error {
+	v := &validator{}
+	{{- range $index, $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{- if not ($field | isAutoIncrement) }}
+	{{- if not ($field | isPrimary) }}
+	{{- if ($field | hasValidation) }}
+	{{- if ($field | isCurrentOptional) }}
+	if t.{{ $field | fieldName }}.Valid{{ if (eq ($field | fieldTypeToNullType) "null.String") }} && t.{{ $field | fieldName }}.String != ""{{ end }} {
+		{{- range $check := validationChecks $field (printf "t.%s.ValueOrZero()" ($field | fieldName)) }}
+		{{ $check }}
+		{{- end }}
+	}
+	{{- else }}
+	if t.{{ $field | fieldName }} != nil {
+		{{- range $check := validationChecks $field (printf "*t.%s" ($field | fieldName)) }}
+		{{ $check }}
+		{{- end }}
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+
+	return v.err()
+}
+
+// Update updates an existing {{ structureName }} based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
+	query = query.Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update {{ structureName }}")
+	}
+
+	return rowsAffected(result)
+}
+{{- if (hasPrimaryKey) }}
+
+// UpdateReturning updates an existing {{ structureName }} based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *{{ storageName | lowerCamelCase }}) UpdateReturning(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update, opts ...Option) (*{{structureName}}, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update {{ structureName }}")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to update {{ structureName }}")
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &{{structureName}}{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, errors.Wrap(err, "failed to scan {{ structureName }}")
+	}
+	{{- if (hasEncrypted) }}
+	if err := model.Decrypt(t.config.Cipher); err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt {{ structureName }}")
+	}
+	{{- end }}
+
+	return model, nil
+}
+{{- end }}
+
+// UpdateMany updates the {{ tableName }} rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *{{ storageName | lowerCamelCase }}) UpdateMany(ctx context.Context, updateData *{{structureName}}Update, builders ...*QueryBuilder) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+
+	query, err := t.updateQuery(updateData, options)
+	if err != nil {
+		return 0, err
+	}
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			{{- if .EncryptedFields }}
+			option = bindCipher(option, t.config.Cipher)
+			{{- end }}
+			query = option.ApplyUpdate(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for update operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build query")
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	result, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update {{ tableName }}")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+	return affected, nil
 }
 
-// Update updates an existing {{ structureName }} based on non-nil fields.
-func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) error {
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *{{ storageName | lowerCamelCase }}) updateQuery(updateData *{{structureName}}Update, options *Options) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update(t.QualifiedTableName())
 	if updateData == nil {
-		return errors.New("update data is nil")
+		return query, errors.New("update data is nil")
 	}
 
-	query := t.queryBuilder.Update("{{ tableName }}")
+	if !options.skipValidation {
+		if err := updateData.Validate(); err != nil {
+			return query, errors.Wrap(err, "failed to validate {{ structureName }}")
+		}
+	}
 
 	{{- range $index, $field := fields }}
 	{{- if not ($field | isRelation) }}
 	{{- if not ($field | isAutoIncrement) }}
 	{{- if not ($field | isPrimary) }}
+	{{- if ($field | isEncrypted) }}
 	{{- if ($field | isCurrentOptional) }}
+		// Handle encrypted fields that are optional and can be explicitly set to NULL
+		if updateData.{{ $field | fieldName }}.Valid {
+			if updateData.{{ $field | fieldName }}.String == "" {
+				query = query.Set("{{ $field | sourceName }}", nil) // Explicitly set NULL for empty string
+				{{- if ($field | hasBlindIndex) }}
+				query = query.Set("{{ $field | blindIndexColumn }}", nil)
+				{{- end }}
+			} else {
+				value, err := encryptValue(t.config.Cipher, updateData.{{ $field | fieldName }}.String)
+				if err != nil {
+					return query, errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}")
+				}
+				query = query.Set("{{ $field | sourceName }}", value)
+				{{- if ($field | hasBlindIndex) }}
+				query = query.Set("{{ $field | blindIndexColumn }}", blindIndexValue(t.config.Cipher, updateData.{{ $field | fieldName }}.String))
+				{{- end }}
+			}
+		}
+	{{- else }}
+		// Handle encrypted fields using a nil check
+		if updateData.{{ $field | fieldName }} != nil {
+			value, err := encryptValue(t.config.Cipher, *updateData.{{ $field | fieldName }})
+			if err != nil {
+				return query, errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}")
+			}
+			query = query.Set("{{ $field | sourceName }}", value)
+			{{- if ($field | hasBlindIndex) }}
+			query = query.Set("{{ $field | blindIndexColumn }}", blindIndexValue(t.config.Cipher, *updateData.{{ $field | fieldName }}))
+			{{- end }}
+		}
+	{{- end }}
+	{{- else if ($field | isCurrentOptional) }}
 		// Handle fields that are optional and can be explicitly set to NULL
 		if updateData.{{ $field | fieldName }}.Valid {
 			{{- if (eq ($field | fieldTypeToNullType) "null.String") }}
@@ -654,20 +1871,7 @@ func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{ID
 	{{- end }}
 	{{- end }}
 
-	query = query.Where("id = ?", id)
-
-	sqlQuery, args, err := query.ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
-	}
-	t.logQuery(ctx, sqlQuery, args...)
-
-	_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
-	if err != nil {
-		return errors.Wrap(err, "failed to update {{ structureName }}")
-	}
-
-	return nil
+	return setExpressions(query, updateData.Expressions), nil
 }
 `
 
@@ -678,26 +1882,140 @@ type {{ structureName }} struct {
 	{{ $field | fieldName }} {{ $field | fieldType }}{{if not ($field | isRelation) }}` + " `db:\"{{ $field | sourceName }}\"`" + `{{end}}{{end}}
 }
 
-// TableName returns the table name.
-func (t *{{ structureName }}) TableName() string {
-	return "{{ tableName }}"
+// TableName returns the table name.
+func (t *{{ structureName }}) TableName() string {
+	return "{{ tableName }}"
+}
+
+// SchemaName returns the database schema of the table.
+func (t *{{ structureName }}) SchemaName() string {
+	return {{ schemaName | printf "%q" }}
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *{{ structureName }}) QualifiedTableName() string {
+	return {{ tableIdent | printf "%q" }}
+}
+
+// ScanRow scans a row into a {{ structureName }}.
+func (t *{{ structureName }}) ScanRow(r *sql.Row) error {
+	return r.Scan({{ range $field := fields }} {{if not ($field | isRelation) }} &t.{{ $field | fieldName }}, {{ end }}{{ end }})
+}
+
+// ScanRows scans a single row into the {{ structureName }}.
+func (t *{{ structureName }}) ScanRows(r *sql.Rows) error {
+	return r.Scan(
+		{{- range $index, $field := fields }}
+		{{- if not ($field | isRelation) }}
+		&t.{{ $field | fieldName }},
+		{{- end}}
+		{{- end }}
+	)
+}
+
+// ScanColumns scans the given columns of the row into the {{ structureName }}, the other fields are left empty.
+func (t *{{ structureName }}) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *{{ structureName }}) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		{{- range $field := fields }}
+		{{- if not ($field | isRelation) }}
+		case {{ $field | sourceName | printf "%q" }}:
+			dest = append(dest, &t.{{ $field | fieldName }})
+		{{- end }}
+		{{- end }}
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// {{ structureName }} columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	{{- range $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{ structureName }}Column{{ $field | fieldName }} Column = {{ $field | sourceName | printf "%q" }}
+	{{- end }}
+	{{- end }}
+)
+
+{{- range $field := fields }}
+{{- if ($field | validationPattern) }}
+
+// {{ $field | validationPatternVar }} is the pattern of the {{ $field | sourceName }} field.
+var {{ $field | validationPatternVar }} = regexp.MustCompile({{ $field | validationPattern | printf "%q" }})
+{{- end }}
+{{- end }}
+
+// Validate validates the {{ structureName }} fields.
+// The fields generated by the database are not validated.
+func (t *{{ structureName }}) Validate() error {
+	v := &validator{}
+	{{- range $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{- if not (or ($field | isAutoIncrement) ($field | isDefaultUUID)) }}
+	{{- if ($field | hasValidation) }}
+	{{- if ($field | findPointer) }}
+	if t.{{ $field | fieldName }} != nil {
+		{{- range $check := validationChecks $field (printf "*t.%s" ($field | fieldName)) }}
+		{{ $check }}
+		{{- end }}
+	}
+	{{- else }}
+	{{- range $check := validationChecks $field (printf "t.%s" ($field | fieldName)) }}
+	{{ $check }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+
+	return v.err()
 }
+{{- if (hasEncrypted) }}
 
-// ScanRow scans a row into a {{ structureName }}.
-func (t *{{ structureName }}) ScanRow(r *sql.Row) error {
-	return r.Scan({{ range $field := fields }} {{if not ($field | isRelation) }} &t.{{ $field | fieldName }}, {{ end }}{{ end }})
-}
+// Decrypt decrypts the encrypted fields of the scanned {{ structureName }}.
+func (t *{{ structureName }}) Decrypt(cp Cipher) error {
+	if cp == nil {
+		return errors.New("cipher is nil")
+	}
+	{{- range $index, $field := fields }}
+	{{- if ($field | isEncrypted) }}
+	{{- if ($field | findPointer) }}
+	if t.{{ $field | fieldName }} != nil {
+		{{ $field | fieldName | lowerCamelCase }}, err := cp.Decrypt(*t.{{ $field | fieldName }})
+		if err != nil {
+			return errors.Wrap(err, "failed to decrypt {{ $field | fieldName }}")
+		}
+		t.{{ $field | fieldName }} = &{{ $field | fieldName | lowerCamelCase }}
+	}
+	{{- else }}
+	// the field is empty if its column is not selected.
+	if t.{{ $field | fieldName }} != "" {
+		{{ $field | fieldName | lowerCamelCase }}, err := cp.Decrypt(t.{{ $field | fieldName }})
+		if err != nil {
+			return errors.Wrap(err, "failed to decrypt {{ $field | fieldName }}")
+		}
+		t.{{ $field | fieldName }} = {{ $field | fieldName | lowerCamelCase }}
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
 
-// ScanRows scans a single row into the {{ structureName }}.
-func (t *{{ structureName }}) ScanRows(r *sql.Rows) error {
-	return r.Scan(
-		{{- range $index, $field := fields }}
-		{{- if not ($field | isRelation) }}
-		&t.{{ $field | fieldName }},
-		{{- end}}
-		{{- end }}
-	)
+	return nil
 }
+{{- end }}
 `
 
 const TableBatchCreateMethodTemplate = `
@@ -716,13 +2034,27 @@ func (t *{{ storageName | lowerCamelCase }}) BatchCreate(ctx context.Context, mo
 		{{ if (hasID) }} return nil, errors.New("relations are not supported in batch create") {{ else }} return errors.New("relations are not supported in batch create") {{ end }}
 	}
 
-	query := t.queryBuilder.Insert(t.TableName()).
+	if !options.skipValidation {
+		for i, model := range models {
+			if model == nil {
+				continue
+			}
+			if err := model.Validate(); err != nil {
+				{{ if (hasID) }} return nil, errors.Wrapf(err, "failed to validate {{ structureName }} at index %d", i) {{ else }} return errors.Wrapf(err, "failed to validate {{ structureName }} at index %d", i) {{ end }}
+			}
+		}
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			{{- range $index, $field := fields }}
 			{{- if not ($field | isRelation) }}
 			{{- if not ($field | isAutoIncrement ) }}
 			{{- if not ($field | isDefaultUUID ) }}
 			"{{ $field | sourceName }}",
+			{{- if ($field | hasBlindIndex) }}
+			"{{ $field | blindIndexColumn }}",
+			{{- end}}
 			{{- end}}
 			{{- end}}
 			{{- end}}
@@ -733,6 +2065,22 @@ func (t *{{ storageName | lowerCamelCase }}) BatchCreate(ctx context.Context, mo
 		if model == nil {
 			{{ if (hasID) }} return nil, errors.New("one of the models is nil") {{ else }} return errors.New("one of the models is nil") {{ end }}
 		}
+		{{- range $index, $field := fields }}
+		{{- if and ($field | isRepeated) (not ($field | isRelation)) }}
+		// get value of {{ $field | fieldName | lowerCamelCase }}
+		{{ $field | fieldName | lowerCamelCase }}, err := model.{{ $field | fieldName }}.Value()
+		if err != nil {
+			{{ if (hasID) }} return nil, errors.Wrap(err, "failed to get value of {{ $field | fieldName }}") {{ else }} return errors.Wrap(err, "failed to get value of {{ $field | fieldName }}") {{ end }}
+		}
+		{{- end}}
+		{{- if ($field | isEncrypted) }}
+		// encrypt the value of {{ $field | fieldName }}
+		{{ $field | fieldName | lowerCamelCase }}Encrypted, err := encryptValue(t.config.Cipher, model.{{ $field | fieldName }})
+		if err != nil {
+			{{ if (hasID) }} return nil, errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}") {{ else }} return errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}") {{ end }}
+		}
+		{{- end}}
+		{{- end}}
 		query = query.Values(
 			{{- range $index, $field := fields }}
 			{{- if not ($field | isRelation) }}
@@ -741,6 +2089,11 @@ func (t *{{ storageName | lowerCamelCase }}) BatchCreate(ctx context.Context, mo
 
 			{{- if ($field | isRepeated) }}
 				{{ $field | fieldName | lowerCamelCase }},
+			{{- else if ($field | isEncrypted) }}
+				{{ $field | fieldName | lowerCamelCase }}Encrypted,
+				{{- if ($field | hasBlindIndex) }}
+				blindIndexValue(t.config.Cipher, model.{{ $field | fieldName }}),
+				{{- end }}
 			{{- else }}
 			
 				{{- if (findPointer $field) }}
@@ -820,6 +2173,12 @@ const TableCreateMethodTemplate = `
 		o(options)
 	}
 
+	if !options.skipValidation {
+		if err := model.Validate(); err != nil {
+			{{ if (hasID) }}return nil, errors.Wrap(err, "failed to validate {{ structureName }}") {{ else }}return errors.Wrap(err, "failed to validate {{ structureName }}") {{ end }}
+		}
+	}
+
 	{{- range $index, $field := fields }}
 	{{- if not ($field | isRelation) }}
 	{{- if ($field | isRepeated) }}
@@ -829,16 +2188,26 @@ const TableCreateMethodTemplate = `
 		return nil, errors.Wrap(err, "failed to get value of {{ $field | fieldName }}")
 	}
 	{{- end}}
+	{{- if ($field | isEncrypted) }}
+	// encrypt the value of {{ $field | fieldName }}
+	{{ $field | fieldName | lowerCamelCase }}Encrypted, err := encryptValue(t.config.Cipher, model.{{ $field | fieldName }})
+	if err != nil {
+		{{ if (hasID) }}return nil, errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}") {{ else }}return errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}") {{ end }}
+	}
+	{{- end}}
 	{{- end}}
 	{{- end}}
 
-	query := t.queryBuilder.Insert("{{ tableName }}").
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).
 		Columns(
 			{{- range $index, $field := fields }}
 			{{- if not ($field | isRelation) }}
 			{{- if not ($field | isAutoIncrement ) }}
 			{{- if not ($field | isDefaultUUID ) }}
 			"{{ $field | sourceName }}",
+			{{- if ($field | hasBlindIndex) }}
+			"{{ $field | blindIndexColumn }}",
+			{{- end}}
 			{{- end}}
 			{{- end}}
 			{{- end}}
@@ -852,6 +2221,11 @@ const TableCreateMethodTemplate = `
 			
 			{{- if ($field | isRepeated) }}
 				{{ $field | fieldName | lowerCamelCase }},
+			{{- else if ($field | isEncrypted) }}
+				{{ $field | fieldName | lowerCamelCase }}Encrypted,
+				{{- if ($field | hasBlindIndex) }}
+				blindIndexValue(t.config.Cipher, model.{{ $field | fieldName }}),
+				{{- end }}
 			{{- else }}
 			
 				{{- if (findPointer $field) }}
@@ -939,6 +2313,11 @@ type {{structureName}}TableManager interface {
 }
 {{ end }}
 
+// {{structureName}}SchemaVerification is an interface for verifying the {{ tableName }} table.
+type {{structureName}}SchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // {{structureName}}CRUDOperations is an interface for managing the {{ tableName }} table.
 type {{structureName}}CRUDOperations interface {
 	{{- if (hasID) }}
@@ -950,9 +2329,17 @@ type {{structureName}}CRUDOperations interface {
 	{{- else }}
 	BatchCreate(ctx context.Context, models []*{{structureName}}, opts ...Option) error
 	{{- end }}
-	Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) error
+	{{- if (conflictTargets) }}
+	Upsert(ctx context.Context, model *{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{IDType}}, {{ end }}error)
+	BatchUpsert(ctx context.Context, models []*{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error)
+	{{- end }}
+	Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update, opts ...Option) (int64, error)
+	{{- if (hasPrimaryKey) }}
+	UpdateReturning(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update, opts ...Option) (*{{structureName}}, error)
+	{{- end }}
+	UpdateMany(ctx context.Context, updateData *{{structureName}}Update, builders ...*QueryBuilder) (int64, error)
 	{{- if (hasPrimaryKey) }}
-	DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) error
+	DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) (int64, error)
 	{{- end }}
 	{{- if (hasPrimaryKey) }}
 	FindBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, id {{IDType}}, opts ...Option) (*{{ structureName }}, error)
@@ -962,14 +2349,40 @@ type {{structureName}}CRUDOperations interface {
 // {{structureName}}SearchOperations is an interface for searching the {{ tableName }} table.
 type {{structureName}}SearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*{{structureName}}, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error)
+	{{- if searchFields }}
+	// Search finds the rows matching the full-text search query, the most relevant first.
+	Search(ctx context.Context, query string, builders ...*QueryBuilder) ([]*{{structureName}}SearchResult, error)
+	{{- end }}
 }
 
 // {{structureName}}PaginationOperations is an interface for pagination operations.
 type {{structureName}}PaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*{{structureName}}, *Paginator, error)
+	{{- if (hasPrimaryKey) }}
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*{{structureName}}, *CursorPaginator, error)
+	{{- end }}
+}
+
+// {{structureName}}AggregateOperations is an interface for the aggregate queries.
+type {{structureName}}AggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{structureName}}AggregateRow, error)
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error)
+	Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	{{- end }}
+	{{- end }}
 }
 
 // {{structureName}}RelationLoading is an interface for loading relations.
@@ -988,7 +2401,7 @@ type {{structureName}}RelationLoading interface {
 
 // {{structureName}}AdvancedDeletion is an interface for advanced deletion operations.
 type {{structureName}}AdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // {{structureName}}RawQueryOperations is an interface for executing raw queries.
@@ -998,17 +2411,35 @@ type {{structureName}}RawQueryOperations interface {
 	QueryRows(ctx context.Context, isWrite bool, query string, args ...interface{}) (*sql.Rows, error)
 }
 
+{{- if methods }}
+// {{structureName}}ServiceMethods is an interface for the methods generated from the service definitions.
+type {{structureName}}ServiceMethods interface {
+	{{- range $method := methods }}
+	{{- if $method.Single }}
+	{{ $method.Name }}(ctx context.Context, req *{{ $method.Request.GetName | camelCase }}, builders ...*QueryBuilder) (*{{structureName}}, error)
+	{{- else }}
+	{{ $method.Name }}(ctx context.Context, req *{{ $method.Request.GetName | camelCase }}, builders ...*QueryBuilder) ([]*{{structureName}}, error)
+	{{- end }}
+	{{- end }}
+}
+{{- end }}
+
 // {{ storageName }} is a struct for the "{{ tableName }}" table.
 type {{ storageName }} interface {
 {{ if .CRUDSchemas }}
     {{structureName}}TableManager
 {{ end }}
+	{{structureName}}SchemaVerification
 	{{structureName}}CRUDOperations
 	{{structureName}}SearchOperations
 	{{structureName}}PaginationOperations
+	{{structureName}}AggregateOperations
 	{{structureName}}RelationLoading
 	{{structureName}}AdvancedDeletion
 	{{structureName}}RawQueryOperations
+{{- if methods }}
+	{{structureName}}ServiceMethods
+{{- end }}
 }
 
 // New{{ storageName }} returns a new {{ storageName | lowerCamelCase }}.
@@ -1025,6 +2456,11 @@ func New{{ storageName }}(config *Config) ({{ storageName }}, error) {
 	if config.DB.DBWrite == nil {
 		config.DB.DBWrite = config.DB.DBRead
 	}
+	{{- if (hasEncrypted) }}
+	if config.Cipher == nil {
+		return nil, errors.New("config.Cipher is nil")
+	}
+	{{- end }}
 
 	return &{{ storageName | lowerCamelCase }}{
 		config: config,
@@ -1051,6 +2487,16 @@ func (t *{{ storageName | lowerCamelCase }}) TableName() string {
 	return "{{ tableName }}"
 }
 
+// SchemaName returns the database schema of the table.
+func (t *{{ storageName | lowerCamelCase }}) SchemaName() string {
+	return {{ schemaName | printf "%q" }}
+}
+
+// QualifiedTableName returns the schema-qualified, quoted table name.
+func (t *{{ storageName | lowerCamelCase }}) QualifiedTableName() string {
+	return {{ tableIdent | printf "%q" }}
+}
+
 // Columns returns the columns for the table.
 func (t *{{ storageName | lowerCamelCase }}) Columns() []string {
 	return []string{
@@ -1084,7 +2530,8 @@ func (t *{{ storageName | lowerCamelCase }}) DB(ctx context.Context, isWrite boo
 }
 
 {{ if .CRUDSchemas }}
-// createTable creates the table.
+// CreateTable creates the table with its indexes and foreign keys.
+// The foreign keys which close the reference cycles are added by CreateTables.
 func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) error {
 	sqlQuery := ` + "`" + `
 		{{- range $index, $field := fields }}
@@ -1092,28 +2539,34 @@ func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) er
 		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 		{{- end}}
 		{{- end}}
-		-- Table: {{ tableName }}
-		CREATE TABLE IF NOT EXISTS {{ tableName }} (
+		-- Table: {{ tableIdent }}
+		CREATE TABLE IF NOT EXISTS {{ tableIdent }} (
 		{{- range $index, $field := fields }}
 		{{- if not ($field | isRelation) }}
-		{{ $field | sourceName }} {{if ($field | isAutoIncrement) }} SERIAL{{else}}{{ $field | postgresType }}{{end}}{{if $field | isPrimaryKey }} PRIMARY KEY{{end}}{{ if and (isNotNull $field) (not (isAutoIncrement $field)) }} NOT NULL{{ end }}{{if ($field | getDefaultValue) }} DEFAULT {{$field | getDefaultValue}}{{end}}{{if not ( $field | isLastField )}},{{end}}
+		{{ $field | sourceName }} {{if ($field | isAutoIncrement) }} SERIAL{{else}}{{ $field | postgresType }}{{end}}{{if $field | isPrimaryKey }} PRIMARY KEY{{end}}{{ if and (isNotNull $field) (not (isAutoIncrement $field)) }} NOT NULL{{ end }}{{if ($field | getDefaultValue) }} DEFAULT {{$field | getDefaultValue}}{{end}}
+		{{- if ($field | hasBlindIndex) }},
+		{{ $field | blindIndexColumn }} TEXT{{ if (isNotNull $field) }} NOT NULL{{ end }}
+		{{- end }}{{if not ( $field | isLastField )}},{{end}}
 		{{- end}}
-		{{- end}});
+		{{- end}}
+		{{- with searchColumn }},
+		{{ .Name }} {{ .Type }} GENERATED ALWAYS AS ({{ .Generated }}) STORED
+		{{- end }});
 		-- Other entities
 		{{- if (comment) }}
-		COMMENT ON TABLE {{ tableName }} IS '{{ comment }}';
+		COMMENT ON TABLE {{ tableIdent }} IS '{{ comment }}';
 		{{- end}}
 		{{- range $index, $field := fields }}
 		{{- if ($field | hasUnique) }}
-		CREATE UNIQUE INDEX IF NOT EXISTS {{ tableName }}_{{ $field | sourceName }}_unique_idx ON {{ tableName }} USING btree ({{ $field | sourceName }});
+		CREATE UNIQUE INDEX IF NOT EXISTS {{ printf "%s_%s_unique_idx" tableName ($field | sourceName) | quoteIdent }} ON {{ tableIdent }} USING btree ({{ $field | indexColumn }});
 		{{- end}}
 		{{- end}}
 
 		{{- range $index, $fields := getStructureUniqueIndexes }}
-		CREATE UNIQUE INDEX IF NOT EXISTS {{ tableName }}_unique_idx_{{ $fields | sliceToString }} ON {{ tableName }} USING btree (
+		CREATE UNIQUE INDEX IF NOT EXISTS {{ printf "%s_unique_idx_%s" tableName ($fields | sliceToString) | quoteIdent }} ON {{ tableIdent }} USING btree (
         {{- $length := sub (len $fields) 1 }}
         {{- range $i, $field := $fields }}
-            {{ $field | sourceName }}{{ if lt $i $length }}, {{ end }}
+            {{ $field | indexColumn }}{{ if lt $i $length }}, {{ end }}
         {{- end }}
     	);
 		{{- end }}
@@ -1121,21 +2574,17 @@ func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) er
 
 		{{- range $index, $field := fields }}
 		{{- if ($field | hasIndex) }}
-		CREATE INDEX IF NOT EXISTS {{ tableName }}_{{ $field | sourceName }}_idx ON {{ tableName }} USING btree ({{ $field | sourceName }});
-		{{- end}}
-		{{- end}}
-		{{- range $index, $field := fields }}
-		{{- if ($field | isRelation) }}
-		{{- if ($field | isForeign) }}
-		-- Foreign keys for {{ $field | relationTableName }}
-		ALTER TABLE {{ tableName }}
-		ADD FOREIGN KEY ({{ $field | getFieldSource }}) REFERENCES {{ $field | relationTableName }}({{ $field | getRefSource }})
-		{{- if ($field | isCascade) }}
-		ON DELETE CASCADE;
-		{{- else }}; 
-        {{- end}}
+		CREATE INDEX IF NOT EXISTS {{ printf "%s_%s_idx" tableName ($field | sourceName) | quoteIdent }} ON {{ tableIdent }} USING {{ if ($field | isJSON) }}gin{{ else }}btree{{ end }} ({{ $field | indexColumn }});
 		{{- end}}
 		{{- end}}
+		{{- with searchColumn }}
+		CREATE INDEX IF NOT EXISTS {{ printf "%s_%s_idx" tableName .Name | quoteIdent }} ON {{ tableIdent }} USING gin ({{ .Name }});
+		{{- end }}
+		{{- if foreignKeyStatements }}
+		-- Foreign keys
+		{{- range $statement := foreignKeyStatements }}
+		{{ $statement }}
+		{{- end }}
 		{{- end }}
 	` + "`" + `
 
@@ -1146,7 +2595,7 @@ func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) er
 // DropTable drops the table.
 func (t *{{ storageName | lowerCamelCase }}) DropTable(ctx context.Context) error {
 	sqlQuery := ` + "`" + `
-		DROP TABLE IF EXISTS {{ tableName }};
+		DROP TABLE IF EXISTS {{ tableIdent }};
 	` + "`" + `
 
 	_, err := t.DB(ctx, true).ExecContext(ctx,sqlQuery)
@@ -1156,20 +2605,84 @@ func (t *{{ storageName | lowerCamelCase }}) DropTable(ctx context.Context) erro
 // TruncateTable truncates the table.
 func (t *{{ storageName | lowerCamelCase }}) TruncateTable(ctx context.Context) error {
 	sqlQuery := ` + "`" + `
-		TRUNCATE TABLE {{ tableName }};
+		TRUNCATE TABLE {{ tableIdent }};
 	` + "`" + `
 
 	_, err := t.DB(ctx, true).ExecContext(ctx,sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method 
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *{{ storageName | lowerCamelCase }}) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx, true)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect table")
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add column %s", column.Name)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return errors.Wrap(err, "failed to create index")
+		}
+	}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
+
 {{ end }}
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *{{ storageName | lowerCamelCase }}) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx, false)
+
+	live, err := introspectColumns(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table")
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.SchemaName(), t.TableName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect indexes")
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *{{ storageName | lowerCamelCase }}) tableColumns() []tableColumn {
+	return []tableColumn{
+		{{- range $column := upgradeColumns }}
+		{Name: {{ $column.Name | printf "%q" }}, Type: {{ $column.Type | printf "%q" }}, NotNull: {{ $column.NotNull }}, Statement: {{ $column.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *{{ storageName | lowerCamelCase }}) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{{- range $index := upgradeIndexes }}
+		{Name: {{ $index.Name | printf "%q" }}, Columns: {{ $index.Columns | printf "%#v" }}, Unique: {{ $index.Unique }}, Statement: {{ $index.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
 {{- range $index, $field := fields }}
 {{- if and ($field | isRelation) }}
 // Load{{ $field | pluralFieldName }} loads the {{ $field | pluralFieldName }} relation.
@@ -1294,4 +2807,263 @@ func (t *{{ storageName | lowerCamelCase }}) LoadBatch{{ $field | pluralFieldNam
 }
 {{- end }}
 {{- end }}
+{{- $hasRelations := false }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+{{- $hasRelations = true }}
+{{- end }}
+{{- end }}
+
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *{{ storageName | lowerCamelCase }}) preload(ctx context.Context, items []*{{structureName}}, builders ...*QueryBuilder) error {
+	{{- if $hasRelations }}
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*{{structureName}}, ...*QueryBuilder) error
+		switch relation {
+		{{- range $field := fields }}
+		{{- if ($field | isRelation) }}
+		case {{ $field | fieldName | printf "%q" }}:
+			load = t.LoadBatch{{ $field | pluralFieldName }}
+		{{- end }}
+		{{- end }}
+		default:
+			return errors.Errorf("unknown relation %s of {{ structureName }}", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", relation)
+		}
+	}
+	{{- else }}
+	if relations, _ := preloadRelations(builders); len(relations) > 0 {
+		return errors.Errorf("unknown relation %s of {{ structureName }}", relations[0])
+	}
+	{{- end }}
+
+	return nil
+}
+`
+
+const TableServiceMethodsTemplate = `
+{{- range $request := methodRequests }}
+// {{ $request.GetName | camelCase }} is the request of the generated service methods.
+type {{ $request.GetName | camelCase }} struct {
+	{{- range $field := $request.GetField }}
+	{{ $field | fieldName }} {{ $field | requestFieldType }}
+	{{- end }}
+}
+{{ end }}
+
+{{- range $method := methods }}
+{{- if $method.Single }}
+// {{ $method.Name }} finds the {{ structureName }} matching the request.
+func (t *{{ storageName | lowerCamelCase }}) {{ $method.Name }}(ctx context.Context, req *{{ $method.Request.GetName | camelCase }}, builders ...*QueryBuilder) (*{{structureName}}, error) {
+{{- else }}
+// {{ $method.Name }} finds the {{ structureName }} list matching the request.
+func (t *{{ storageName | lowerCamelCase }}) {{ $method.Name }}(ctx context.Context, req *{{ $method.Request.GetName | camelCase }}, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+{{- end }}
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	var filters []FilterApplier
+	{{- range $statement := methodConditions $method }}
+	{{ $statement }}
+	{{- end }}
+	if len(filters) > 0 {
+		builders = append(builders, FilterBuilder(filters...))
+	}
+	{{- range $statement := methodPagination $method }}
+	{{ $statement }}
+	{{- end }}
+
+	{{- if $method.Single }}
+	return t.FindOne(ctx, builders...)
+	{{- else }}
+	return t.FindMany(ctx, builders...)
+	{{- end }}
+}
+{{ end }}
+`
+
+const TableUpsertMethodTemplate = `
+{{- if (conflictTargets) }}
+// {{ structureName | lowerCamelCase }}ConflictTargets are the unique constraints of the {{ tableName }} table which the upsert can conflict on.
+var {{ structureName | lowerCamelCase }}ConflictTargets = [][]Column{
+	{{- range $target := conflictTargets }}
+	{ {{- range $i, $field := $target }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end -}} },
+	{{- end }}
+}
+
+// {{ structureName | lowerCamelCase }}UpsertColumns are the columns inserted by the upsert.
+var {{ structureName | lowerCamelCase }}UpsertColumns = []upsertColumn{
+	{{- range $field := fields }}
+	{{- if not (or ($field | isRelation) ($field | isAutoIncrement) ($field | isDefaultUUID)) }}
+	{column: {{ structureName }}Column{{ $field | fieldName }}, written: []string{ {{- $field | sourceName | printf "%q" }}{{ if ($field | hasBlindIndex) }}, {{ $field | blindIndexColumn | printf "%q" }}{{ end -}} }, index: {{ $field | indexColumn | printf "%q" }}{{ if ($field | isPrimaryKey) }}, primaryKey: true{{ end }}},
+	{{- end }}
+	{{- end }}
+}
+
+// upsertValues returns the values of the model in the order of the {{ structureName | lowerCamelCase }}UpsertColumns.
+func (t *{{ storageName | lowerCamelCase }}) upsertValues(model *{{ structureName }}) ([]interface{}, error) {
+	values := make([]interface{}, 0, len({{ structureName | lowerCamelCase }}UpsertColumns))
+	{{- range $field := fields }}
+	{{- if not (or ($field | isRelation) ($field | isAutoIncrement) ($field | isDefaultUUID)) }}
+	{{- if ($field | isRepeated) }}
+	{{ $field | fieldName | lowerCamelCase }}Value, err := model.{{ $field | fieldName }}.Value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value of {{ $field | fieldName }}")
+	}
+	values = append(values, {{ $field | fieldName | lowerCamelCase }}Value)
+	{{- else if ($field | isEncrypted) }}
+	{{ $field | fieldName | lowerCamelCase }}Encrypted, err := encryptValue(t.config.Cipher, model.{{ $field | fieldName }})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt {{ $field | fieldName }}")
+	}
+	values = append(values, {{ $field | fieldName | lowerCamelCase }}Encrypted{{ if ($field | hasBlindIndex) }}, blindIndexValue(t.config.Cipher, model.{{ $field | fieldName }}){{ end }})
+	{{- else if ($field | findPointer) }}
+	values = append(values, nullValue(model.{{ $field | fieldName }}))
+	{{- else }}
+	values = append(values, model.{{ $field | fieldName }})
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *{{ storageName | lowerCamelCase }}) upsertQuery(models []*{{ structureName }}, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix(t.TableName(), {{ structureName | lowerCamelCase }}ConflictTargets, {{ structureName | lowerCamelCase }}UpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert(t.QualifiedTableName()).Columns(upsertColumnNames({{ structureName | lowerCamelCase }}UpsertColumns)...)
+	for i, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		if !options.skipValidation {
+			if err := model.Validate(); err != nil {
+				return "", nil, errors.Wrapf(err, "failed to validate {{ structureName }} at index %d", i)
+			}
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	{{- if (hasID) }}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+	{{- else }}
+	query = query.Suffix(suffix)
+	{{- end }}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to build query")
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the {{ structureName }} or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict({{ range $i, $field := index conflictTargets 0 }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end }}).DoUpdate()).
+{{- if (hasID) }}
+// It returns the primary key of the created or the updated row.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) Upsert(ctx context.Context, model *{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{ IDType }}, {{ end }}error) {
+	if model == nil {
+		return {{ if (hasID) }}nil, {{ end }}errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*{{ structureName }}{model}, conflict, opts...)
+	if err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+
+	{{ if (hasID) }}var id {{ IDType }}
+	err = t.DB(ctx, true).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	{{- else }}_, err = t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...)
+	{{- end }}
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return {{ if (hasID) }}nil, {{ end }}errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return {{ if (hasID) }}nil, {{ end }}errors.Wrap(err, "failed to upsert {{ structureName }}")
+	}
+
+	return {{ if (hasID) }}&id, {{ end }}nil
+}
+
+// BatchUpsert creates multiple {{ structureName }} records in a single batch or handles their conflicts on the unique constraint,
+// a row can't be upserted twice in the batch.
+{{- if (hasID) }}
+// It returns the primary keys of the created or the updated rows.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) BatchUpsert(ctx context.Context, models []*{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+	t.logQuery(ctx, sqlQuery, args...)
+	{{- if (hasID) }}
+
+	rows, err := t.DB(ctx, true).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if IsPgUniqueViolation(err) {
+			return nil, errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return nil, errors.Wrap(err, "failed to execute bulk upsert")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			t.logError(ctx, err, "failed to close rows")
+		}
+	}()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows iteration error")
+	}
+
+	return ids, nil
+	{{- else }}
+
+	if _, err := t.DB(ctx, true).ExecContext(ctx, sqlQuery, args...); err != nil {
+		if IsPgUniqueViolation(err) {
+			return errors.Wrap(ErrRowAlreadyExist, PgPrettyErr(err).Error())
+		}
+		return errors.Wrap(err, "failed to execute bulk upsert")
+	}
+
+	return nil
+	{{- end }}
+}
+{{- end }}
 `
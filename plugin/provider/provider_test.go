@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	plugingo "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	structify "github.com/cjp2600/protoc-gen-structify/plugin/options"
+	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
+)
+
+// request returns the request of the blog.proto file with the users table,
+// the field options are set on the email field.
+func request(db *structify.StructifyDBOptions, field *structify.StructifyFieldOptions) *plugingo.CodeGeneratorRequest {
+	options := &descriptorpb.FileOptions{}
+	proto.SetExtension(options, structify.E_Db, db)
+
+	emailOptions := &descriptorpb.FieldOptions{}
+	if field != nil {
+		proto.SetExtension(emailOptions, structify.E_Field, field)
+	}
+
+	return &plugingo.CodeGeneratorRequest{
+		FileToGenerate: []string{"blog.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("blog.proto"),
+				Package: proto.String("blog"),
+				Syntax:  proto.String("proto3"),
+				Options: options,
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("User"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{
+								Name:     proto.String("id"),
+								Number:   proto.Int32(1),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+								JsonName: proto.String("id"),
+							},
+							{
+								Name:     proto.String("phones"),
+								Number:   proto.Int32(2),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+								JsonName: proto.String("phones"),
+							},
+							{
+								Name:     proto.String("email"),
+								Number:   proto.Int32(3),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+								JsonName: proto.String("email"),
+								Options:  emailOptions,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// addMethod adds the FindUsers service method of the users storage to the request.
+func addMethod(req *plugingo.CodeGeneratorRequest) {
+	options := &descriptorpb.MethodOptions{}
+	proto.SetExtension(options, structify.E_Method, &structify.MethodOptions{ObjectType: "User"})
+
+	file := req.ProtoFile[0]
+	file.MessageType = append(file.MessageType, &descriptorpb.DescriptorProto{
+		Name: proto.String("FindUsersRequest"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:     proto.String("email"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				JsonName: proto.String("email"),
+			},
+		},
+	})
+	file.Service = append(file.Service, &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String("UserService"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			{
+				Name:       proto.String("FindUsers"),
+				InputType:  proto.String(".blog.FindUsersRequest"),
+				OutputType: proto.String(".blog.User"),
+				Options:    options,
+			},
+		},
+	})
+}
+
+func TestJSONFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+	}{
+		{name: "postgres", provider: "postgres"},
+		{name: "sqlite", provider: "sqlite"},
+		{name: "clickhouse", provider: "clickhouse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request(&structify.StructifyDBOptions{Provider: tt.provider}, nil)
+			builder, err := GetTemplateBuilder(req)
+			require.NoError(t, err)
+
+			entities, err := builder.GetEntities(statepkg.NewState(req))
+			require.NoError(t, err)
+			require.Len(t, entities, 1)
+
+			// the JSON filters don't require the in_filter option of the field.
+			assert.Contains(t, entities[0].BuildTemplate(), "func UserPhonesContains(value string) FilterApplier")
+		})
+	}
+}
+
+func TestUnsupportedOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       *structify.StructifyDBOptions
+		field    *structify.StructifyFieldOptions
+		methods  bool
+		expected string
+	}{
+		{
+			name:     "sqlite schema",
+			db:       &structify.StructifyDBOptions{Provider: "sqlite", Schema: "blog"},
+			expected: "the schema option is not supported by sqlite: blog",
+		},
+		{
+			name:     "clickhouse schema",
+			db:       &structify.StructifyDBOptions{Provider: "clickhouse", Schema: "blog"},
+			expected: "the schema option is not supported by clickhouse: blog",
+		},
+		{
+			name: "postgres schema",
+			db:   &structify.StructifyDBOptions{Provider: "postgres", Schema: "blog"},
+		},
+		{
+			name:     "sqlite encrypted",
+			db:       &structify.StructifyDBOptions{Provider: "sqlite"},
+			field:    &structify.StructifyFieldOptions{Encrypted: true, BlindIndex: true},
+			expected: "the encrypted and blind_index options are not supported by sqlite: users.email",
+		},
+		{
+			name:     "clickhouse encrypted",
+			db:       &structify.StructifyDBOptions{Provider: "clickhouse"},
+			field:    &structify.StructifyFieldOptions{Encrypted: true},
+			expected: "the encrypted and blind_index options are not supported by clickhouse: users.email",
+		},
+		{
+			name:     "sqlite validation",
+			db:       &structify.StructifyDBOptions{Provider: "sqlite"},
+			field:    &structify.StructifyFieldOptions{Email: true},
+			expected: "the validation options are not supported by sqlite: users.email",
+		},
+		{
+			name:     "clickhouse validation",
+			db:       &structify.StructifyDBOptions{Provider: "clickhouse"},
+			field:    &structify.StructifyFieldOptions{MaxLen: proto.Uint32(255)},
+			expected: "the validation options are not supported by clickhouse: users.email",
+		},
+		{
+			name:  "sqlite uuid",
+			db:    &structify.StructifyDBOptions{Provider: "sqlite"},
+			field: &structify.StructifyFieldOptions{Uuid: true},
+		},
+		{
+			name:     "sqlite methods",
+			db:       &structify.StructifyDBOptions{Provider: "sqlite"},
+			methods:  true,
+			expected: "the service methods are not supported by sqlite: FindUsers",
+		},
+		{
+			name:     "clickhouse methods",
+			db:       &structify.StructifyDBOptions{Provider: "clickhouse"},
+			methods:  true,
+			expected: "the service methods are not supported by clickhouse: FindUsers",
+		},
+		{
+			name:    "postgres methods",
+			db:      &structify.StructifyDBOptions{Provider: "postgres"},
+			methods: true,
+		},
+		{
+			name:  "postgres encrypted",
+			db:    &structify.StructifyDBOptions{Provider: "postgres"},
+			field: &structify.StructifyFieldOptions{Encrypted: true, BlindIndex: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request(tt.db, tt.field)
+			if tt.methods {
+				addMethod(req)
+			}
+			builder, err := GetTemplateBuilder(req)
+			require.NoError(t, err)
+
+			_, err = builder.GetInitStatement(statepkg.NewState(req))
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestUUIDValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    *structify.StructifyFieldOptions
+		expected bool
+	}{
+		{name: "uuid", field: &structify.StructifyFieldOptions{Uuid: true}, expected: false},
+		{name: "uuid_format", field: &structify.StructifyFieldOptions{UuidFormat: true}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request(&structify.StructifyDBOptions{Provider: "postgres"}, tt.field)
+			builder, err := GetTemplateBuilder(req)
+			require.NoError(t, err)
+
+			entities, err := builder.GetEntities(statepkg.NewState(req))
+			require.NoError(t, err)
+			require.Len(t, entities, 1)
+
+			assert.Equal(t, tt.expected, strings.Contains(entities[0].BuildTemplate(), `v.uuid("email", t.Email)`))
+		})
+	}
+}
+
+// ClickHouse replaces the rows by the sorting key only, so Upsert is generated for the inserted primary key only.
+func TestClickhouseUpsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       *structify.StructifyFieldOptions
+		email    *structify.StructifyFieldOptions
+		expected string
+	}{
+		{
+			name:     "inserted primary key",
+			id:       &structify.StructifyFieldOptions{PrimaryKey: true},
+			expected: "func (t *userStorage) Upsert(ctx context.Context, model *User, conflict *Conflict, opts ...Option) (*int32, error)",
+		},
+		{
+			name: "auto increment primary key",
+			id:   &structify.StructifyFieldOptions{PrimaryKey: true, AutoIncrement: true},
+		},
+		{
+			name:  "unique field",
+			id:    &structify.StructifyFieldOptions{PrimaryKey: true, AutoIncrement: true},
+			email: &structify.StructifyFieldOptions{Unique: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request(&structify.StructifyDBOptions{Provider: "clickhouse"}, tt.email)
+			idOptions := &descriptorpb.FieldOptions{}
+			proto.SetExtension(idOptions, structify.E_Field, tt.id)
+			req.ProtoFile[0].MessageType[0].Field[0].Options = idOptions
+
+			builder, err := GetTemplateBuilder(req)
+			require.NoError(t, err)
+
+			entities, err := builder.GetEntities(statepkg.NewState(req))
+			require.NoError(t, err)
+			require.Len(t, entities, 1)
+
+			tmpl := entities[0].BuildTemplate()
+			if tt.expected == "" {
+				assert.NotContains(t, tmpl, ") Upsert(")
+				return
+			}
+			assert.Contains(t, tmpl, tt.expected)
+		})
+	}
+}
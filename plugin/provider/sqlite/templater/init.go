@@ -10,6 +10,7 @@ import (
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	tmplpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/sqlite/tmpl"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -70,10 +71,50 @@ func (i *initTemplater) BuildTemplate() string {
 			Name: "options",
 			Body: tmplpkg.OptionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert",
+			Body: tmplpkg.UpsertTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "update_expression",
+			Body: tmplpkg.UpdateExpressionTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "conditions",
 			Body: tmplpkg.TableConditionsTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "filters",
+			Body: tmplpkg.FiltersTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate",
+			Body: tmplpkg.AggregateTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "cursor",
+			Body: tmplpkg.CursorTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "join",
+			Body: tmplpkg.JoinTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "preload",
+			Body: tmplpkg.PreloadTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "search",
+			Body: tmplpkg.SearchTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "upgrade",
+			Body: tmplpkg.UpgradeTemplate,
+		},
+		helperpkg.IncludeTemplate{
+			Name: "fixtures",
+			Body: tmplpkg.FixturesTemplate,
+		},
 	)
 	if err != nil {
 		log.Fatalf("failed to execute template: %v", err)
@@ -97,8 +138,24 @@ func (i *initTemplater) Imports() importpkg.ImportSet {
 		importpkg.ImportStrings,
 		importpkg.ImportContext,
 		importpkg.ImportSquirrel,
+		importpkg.ImportBytes,
+		importpkg.ImportIO,
+		importpkg.ImportIOFS,
+		importpkg.ImportYAML,
+		importpkg.ImportReflect,
+		importpkg.ImportSort,
+		importpkg.ImportBase64,
+		importpkg.ImportMath,
+		importpkg.ImportURL,
 	)
 
+	if i.IncludeConnection {
+		is.Add(importpkg.ImportTime)
+	}
+	if i.hasFixtureUUIDs() {
+		is.Add(importpkg.ImportGoogleUUID)
+	}
+
 	return is
 }
 
@@ -202,7 +259,126 @@ func (i *initTemplater) Funcs() map[string]interface{} {
 		"sourceName": func(f *descriptorpb.FieldDescriptorProto) string {
 			return f.GetName()
 		},
+
+		// sortedStorages returns the storages in the dependency order, the referenced tables go first.
+		"sortedStorages": func() []KeyValuePair {
+			return i.sortedStorages()
+		},
+
+		// fixtureTables returns the tables of the fixtures loader in the dependency order.
+		"fixtureTables": func() []fixtureTable {
+			return i.fixtureTables()
+		},
+
+		// hasFixtureUUIDs returns true if the fixtures loader generates the uuid primary keys.
+		"hasFixtureUUIDs": func() bool {
+			return i.hasFixtureUUIDs()
+		},
+
+		// reversedStorages returns the storages in the reverse dependency order,
+		// the referencing tables are dropped and cleared first.
+		"reversedStorages": func() []KeyValuePair {
+			storages := i.sortedStorages()
+			for l, r := 0, len(storages)-1; l < r; l, r = l+1, r-1 {
+				storages[l], storages[r] = storages[r], storages[l]
+			}
+			return storages
+		},
+	}
+}
+
+// sortedTables returns the tables in the dependency order.
+func (i *initTemplater) sortedTables() []*schemapkg.Table {
+	tables, _ := schemapkg.SortTables(schemapkg.Build(i.state, schemapkg.SQLite).Tables)
+	return tables
+}
+
+// sortedStorages returns the storages of the tables in the dependency order.
+func (i *initTemplater) sortedStorages() []KeyValuePair {
+	tables := i.sortedTables()
+	storages := make([]KeyValuePair, 0, len(tables))
+	for _, t := range tables {
+		for _, m := range i.state.Messages {
+			if schemapkg.TableKey(i.state, m) == t.Key() {
+				storages = append(storages, KeyValuePair{
+					Key:   helperpkg.LowerCamelCase(m.GetName()) + StoragePostfix,
+					Value: helperpkg.UpperCamelCase(m.GetName()) + StoragePostfix,
+				})
+			}
+		}
+	}
+	return storages
+}
+
+// fixtureTable is the table of the fixtures loader.
+type fixtureTable struct {
+	Key        string
+	Name       string
+	PrimaryKey string
+	Columns    []string
+	Relations  []fixtureRelation
+	// UUIDColumns are the uuid primary key columns which are generated by Create, not by the database.
+	UUIDColumns []string
+}
+
+// fixtureRelation is the relation field which sets the referencing column of the fixture row.
+type fixtureRelation struct {
+	Field     string
+	Column    string
+	Reference string
+}
+
+// fixtureTables returns the fixture tables in the dependency order.
+// Only the relations which hold the referencing column can be set by the fixtures.
+func (i *initTemplater) fixtureTables() []fixtureTable {
+	tables := i.sortedTables()
+	result := make([]fixtureTable, 0, len(tables))
+	for _, t := range tables {
+		// the documents are keyed by the bare table name, sqlite has no schemas.
+		ft := fixtureTable{Key: t.Name, Name: helperpkg.QuoteIdent("", t.Name)}
+		if pk := t.PrimaryKey(); len(pk) == 1 {
+			ft.PrimaryKey = pk[0]
+		}
+		for _, c := range t.Columns {
+			ft.Columns = append(ft.Columns, c.Name)
+		}
+
+		for _, m := range i.state.Messages {
+			if schemapkg.TableKey(i.state, m) != t.Key() {
+				continue
+			}
+			for _, f := range m.GetField() {
+				opts := helperpkg.GetFieldOptions(f)
+				if opts.GetUuid() && opts.GetPrimaryKey() && !opts.GetAutoIncrement() {
+					ft.UUIDColumns = append(ft.UUIDColumns, f.GetName())
+				}
+				if opts == nil || opts.GetRelation() == nil {
+					continue
+				}
+				relation := opts.GetRelation()
+				if c := t.Column(relation.GetField()); c == nil || c.PrimaryKey {
+					continue
+				}
+				ft.Relations = append(ft.Relations, fixtureRelation{
+					Field:     f.GetName(),
+					Column:    relation.GetField(),
+					Reference: relation.GetReference(),
+				})
+			}
+		}
+		result = append(result, ft)
+	}
+	return result
+}
+
+// hasFixtureUUIDs returns true if one of the fixture tables has the uuid primary key generated by Create.
+func (i *initTemplater) hasFixtureUUIDs() bool {
+	for _, t := range i.fixtureTables() {
+		if len(t.UUIDColumns) > 0 {
+			return true
+		}
 	}
+	return false
 }
 
 // DBClientPostfix is the postfix for the client name.
@@ -3,6 +3,7 @@ package templater
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -11,6 +12,7 @@ import (
 	importpkg "github.com/cjp2600/protoc-gen-structify/plugin/import"
 	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
 	tmplpkg "github.com/cjp2600/protoc-gen-structify/plugin/provider/sqlite/tmpl"
+	schemapkg "github.com/cjp2600/protoc-gen-structify/plugin/schema"
 	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
 )
 
@@ -47,6 +49,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "create_method",
 			Body: tmplpkg.TableCreateMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "upsert_method",
+			Body: tmplpkg.TableUpsertMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "update_method",
 			Body: tmplpkg.TableUpdateMethodTemplate,
@@ -67,6 +73,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "find_many_method",
 			Body: tmplpkg.TableFindManyMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "join_method",
+			Body: tmplpkg.TableJoinMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "find_one_method",
 			Body: tmplpkg.TableFindOneMethodTemplate,
@@ -75,6 +85,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "count_method",
 			Body: tmplpkg.TableCountMethodTemplate,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "aggregate_method",
+			Body: tmplpkg.TableAggregateMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "find_with_pagination",
 			Body: tmplpkg.TableFindWithPaginationMethodTemplate,
@@ -83,6 +97,10 @@ func (t *tableTemplater) BuildTemplate() string {
 			Name: "table_conditions",
 			Body: tmplpkg.TableConditionFilters,
 		},
+		helperpkg.IncludeTemplate{
+			Name: "search_method",
+			Body: tmplpkg.TableSearchMethodTemplate,
+		},
 		helperpkg.IncludeTemplate{
 			Name: "lock_method",
 			Body: tmplpkg.TableLockMethodTemplate,
@@ -122,6 +140,9 @@ func (t *tableTemplater) Imports() importpkg.ImportSet {
 	if strings.Contains(tmp, "time.Time") {
 		is.Add(importpkg.ImportTime)
 	}
+	if strings.Contains(tmp, "url.Values") {
+		is.Add(importpkg.ImportURL)
+	}
 
 	if strings.Contains(tmp, "uuid.NewUUID()") {
 		is.Add(importpkg.ImportGoogleUUID)
@@ -201,6 +222,18 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return statepkg.Messages{newMess}
 		},
 
+		// jsonElementType returns the Go type of the elements of the repeated JSON field.
+		"jsonElementType": helperpkg.JSONElementType,
+		// fieldTypeWP returns the field type without the pointer, it is used for the scalar filter fields.
+		"fieldTypeWP": func(f *descriptorpb.FieldDescriptorProto) string {
+			return helperpkg.ClearPointer(helperpkg.ConvertTypeSQLite(f))
+		},
+		// isScalarFilter returns true if the filter of the field can be parsed from a query string value.
+		"isScalarFilter": helperpkg.IsScalarFilter,
+		// isRangeFilter returns true if the field can be filtered by a range of values.
+		"isRangeFilter": helperpkg.IsRangeFilter,
+		// isListFilter returns true if the field can be filtered by a list of the allowed values.
+		"isListFilter": helperpkg.IsListFilter,
 		// isJSON returns the field type.
 		"isJSON": func(f *descriptorpb.FieldDescriptorProto) bool {
 			return t.state.NestedMessages.IsJSON(f)
@@ -236,6 +269,38 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// isCursorColumn returns true if the field can be a sort column of the keyset pagination:
+		// a non-null scalar or timestamp column.
+		"isCursorColumn": func(f *descriptorpb.FieldDescriptorProto) bool {
+			if helperpkg.IsRepeated(f) || helperpkg.IsOptional(f) || helperpkg.IsEncrypted(f) || t.state.NestedMessages.IsJSON(f) {
+				return false
+			}
+			switch f.GetType() {
+			case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+				return false
+			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+				return helperpkg.IsTimestamp(f)
+			}
+			return true
+		},
+
+		// sumType returns the Go type of the sum of the numeric field, it is empty if the field can't be summed.
+		"sumType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) {
+				return ""
+			}
+			return helperpkg.SumType(f)
+		},
+
+		// minMaxType returns the Go type of the smallest and the largest values of the numeric or timestamp field,
+		// it is empty for the other fields.
+		"minMaxType": func(f *descriptorpb.FieldDescriptorProto) string {
+			if helperpkg.IsEncrypted(f) || (helperpkg.SumType(f) == "" && !helperpkg.IsTimestamp(f)) {
+				return ""
+			}
+			return helperpkg.ClearPointer(helperpkg.ConvertTypeSQLite(f))
+		},
+
 		"isValidGT": func(f *descriptorpb.FieldDescriptorProto) bool {
 			if f == nil {
 				return false
@@ -402,6 +467,59 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return helperpkg.SQLiteType(helperpkg.ConvertTypeSQLite(f), helperpkg.GetFieldOptions(f), t.state.NestedMessages.IsJSON(f))
 		},
 
+		// upgradeColumns returns the columns which the table upgrade compares with the live table.
+		"upgradeColumns": func() []*schemapkg.UpgradeColumn {
+			return schemapkg.UpgradeColumns(schemapkg.SQLite, schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message))
+		},
+
+		// upgradeIndexes returns the indexes which the table upgrade creates.
+		"upgradeIndexes": func() []*schemapkg.UpgradeIndex {
+			return schemapkg.UpgradeIndexes(schemapkg.SQLite, schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message))
+		},
+
+		// searchFields returns the fields of the full-text search, it is empty if the table has no FTS5 table.
+		"searchFields": func() []*descriptorpb.FieldDescriptorProto {
+			if len(schemapkg.SQLite.CreateSearch(schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message))) == 0 {
+				return nil
+			}
+			var fields []*descriptorpb.FieldDescriptorProto
+			for _, f := range t.message.GetField() {
+				if !t.state.IsRelation(f) && helperpkg.IsSearchable(f) {
+					fields = append(fields, f)
+				}
+			}
+			return fields
+		},
+
+		// searchTable returns the name of the FTS5 table of the full-text search.
+		"searchTable": func() string {
+			return schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message).Name + helperpkg.SearchTablePostfix
+		},
+
+		// searchKey returns the primary key which matches the rows of the FTS5 table with the table.
+		"searchKey": func() string {
+			return schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message).PrimaryKey()[0]
+		},
+
+		// searchWeights returns the bm25 weights of the FTS5 table columns, the key column is not ranked.
+		"searchWeights": func() string {
+			weights := []string{"0"}
+			for _, c := range schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message).Search {
+				weights = append(weights, strconv.FormatFloat(helperpkg.SearchRank(c.Weight), 'f', -1, 64))
+			}
+			return strings.Join(weights, ", ")
+		},
+
+		// searchStatements returns the statements which create the FTS5 table of the full-text search.
+		"searchStatements": func() []string {
+			return schemapkg.SQLite.CreateSearch(schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message))
+		},
+
+		// searchDropStatements returns the statements which drop the FTS5 table of the full-text search.
+		"searchDropStatements": func() []string {
+			return schemapkg.SQLite.DropSearch(schemapkg.BuildTable(t.state, schemapkg.SQLite, t.message))
+		},
+
 		// storageName returns the upper camel case storage name.
 		"storageName": func() string {
 			return fmt.Sprintf("%sStorage", helperpkg.UpperCamelCase(t.message.GetName()))
@@ -562,6 +680,12 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return false
 		},
 
+		// conflictTargets returns the unique constraints which the upsert can conflict on, the primary key
+		// is the constraint of the sqlite table only if it is auto increment, so it is never inserted.
+		"conflictTargets": func() [][]*descriptorpb.FieldDescriptorProto {
+			return helperpkg.UniqueConstraints(t.message)
+		},
+
 		"getStructureUniqueIndexes": func() map[int][]*descriptorpb.FieldDescriptorProto {
 			var indexes = make(map[int][]*descriptorpb.FieldDescriptorProto)
 			if opts := helperpkg.GetMessageOptions(t.message); opts != nil {
@@ -588,6 +712,10 @@ func (t *tableTemplater) Funcs() map[string]interface{} {
 			return a - b
 		},
 
+		"add": func(a, b int) int {
+			return a + b
+		},
+
 		"sliceToString": func(fields []*descriptorpb.FieldDescriptorProto) string {
 			var slice []string
 			for _, f := range fields {
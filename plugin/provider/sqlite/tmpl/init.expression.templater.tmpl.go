@@ -0,0 +1,38 @@
+package tmpl
+
+// UpdateExpressionTemplate is the template for the expressions setting the columns on update
+// and the RETURNING clause of the updated row.
+// This is included in the init template.
+const UpdateExpressionTemplate = `
+// UpdateExpression sets the column to the SQL expression on update, e.g. Increment(UserColumnAge, 1).
+// The expression is evaluated by the database, so the counters are updated atomically.
+type UpdateExpression struct {
+	column Column
+	expr   sq.Sqlizer
+}
+
+// Increment adds the value to the column, the negative value decrements it.
+func Increment(column Column, value interface{}) UpdateExpression {
+	return UpdateExpression{column: column, expr: sq.Expr(string(column)+" + ?", value)}
+}
+
+// SetExpr sets the column to the SQL expression, e.g. SetExpr(UserColumnUpdatedAt, "now()").
+// The expression is not escaped, pass the values as the args of its placeholders.
+func SetExpr(column Column, expr string, args ...interface{}) UpdateExpression {
+	return UpdateExpression{column: column, expr: sq.Expr(expr, args...)}
+}
+
+// setExpressions sets the columns of the update query to the expressions.
+func setExpressions(query sq.UpdateBuilder, expressions []UpdateExpression) sq.UpdateBuilder {
+	for _, expression := range expressions {
+		query = query.Set(string(expression.column), expression.expr)
+	}
+	return query
+}
+
+// returning returns the RETURNING clause which reads the columns of the updated row.
+// The columns are listed instead of *, the columns of the table upgrades are added at the end of the table.
+func returning(columns []string) string {
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+`
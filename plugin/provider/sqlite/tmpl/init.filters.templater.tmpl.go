@@ -0,0 +1,88 @@
+package tmpl
+
+// FiltersTemplate is the template for parsing the filters from the query string values.
+// This is included in the init template.
+const FiltersTemplate = `
+// filterKeys returns the sorted keys of the query string values, so the filters are parsed in the same order.
+func filterKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseFilterValue parses the single query string value of the filter.
+func parseFilterValue[T any](key string, raw []string) (*T, error) {
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("filter %q must have a single value", key)
+	}
+	value, err := parseFilterScalar[T](key, raw[0])
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// parseFilterValues parses the list of the query string values of the filter.
+func parseFilterValues[T any](key string, raw []string) ([]T, error) {
+	values := make([]T, 0, len(raw))
+	for _, r := range raw {
+		value, err := parseFilterScalar[T](key, r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseFilterScalar parses the query string value into the type of the filter field.
+func parseFilterScalar[T any](key string, raw string) (T, error) {
+	var value T
+	var err error
+	switch v := any(&value).(type) {
+	case *string:
+		*v = raw
+	case *bool:
+		*v, err = strconv.ParseBool(raw)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(raw, 10, 32)
+		*v = int32(n)
+	case *int64:
+		*v, err = strconv.ParseInt(raw, 10, 64)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(raw, 10, 32)
+		*v = uint32(n)
+	case *uint64:
+		*v, err = strconv.ParseUint(raw, 10, 64)
+	case *float32:
+		var n float64
+		n, err = parseFilterFloat(raw, 32)
+		*v = float32(n)
+	case *float64:
+		*v, err = parseFilterFloat(raw, 64)
+	default:
+		return value, fmt.Errorf("filter %q has an unsupported type %T", key, value)
+	}
+	if err != nil {
+		return value, fmt.Errorf("invalid value %q of the filter %q", raw, key)
+	}
+	return value, nil
+}
+
+// parseFilterFloat parses the finite float, NaN and the infinities are not valid filter values.
+func parseFilterFloat(raw string, bitSize int) (float64, error) {
+	n, err := strconv.ParseFloat(raw, bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("the value is not finite")
+	}
+	return n, nil
+}
+`
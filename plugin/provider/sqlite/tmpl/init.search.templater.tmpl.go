@@ -0,0 +1,46 @@
+package tmpl
+
+// SearchTemplate is the template for the full-text search.
+// This is included in the init template.
+const SearchTemplate = `
+// searchRankColumn is the column of the relevance of the rows found by Search.
+const searchRankColumn = "structify_rank"
+
+// searchHighlight are the marks of the matches in the snippets returned by Search.
+type searchHighlight struct {
+	start string
+	stop  string
+}
+
+// WithSearchHighlight is the option which makes Search return the snippets of the searchable fields,
+// the matches are wrapped in the start and the stop marks, e.g. WithSearchHighlight("<b>", "</b>").
+func WithSearchHighlight(start, stop string) Option {
+	return func(o *Options) {
+		o.searchHighlight = &searchHighlight{start: start, stop: stop}
+	}
+}
+
+// builderHighlight returns the highlight marks set by the WithSearchHighlight option of the builders.
+func builderHighlight(builders []*QueryBuilder) *searchHighlight {
+	options := &Options{}
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, o := range builder.options {
+			o(options)
+		}
+	}
+	return options.searchHighlight
+}
+
+// searchMatch returns the FTS5 query which matches the rows containing all the words of the query.
+// The words are quoted, so the FTS5 query syntax is not interpreted.
+func searchMatch(query string) string {
+	words := strings.Fields(query)
+	for i, word := range words {
+		words[i] = "\"" + strings.ReplaceAll(word, "\"", "\"\"") + "\""
+	}
+	return strings.Join(words, " ")
+}
+`
@@ -44,11 +44,54 @@ const InitStatementTemplate = `
 // Options.
 // 
 {{ template "options" . }}
+//
+// Upsert.
+//
+{{ template "upsert" . }}
+
+// 
+// Update expressions.
+// 
+{{ template "update_expression" . }}
 
 // 
 // Conditions for query builder.
 // 
 {{ template "conditions" . }}
+//
+// Filters from query strings.
+//
+{{ template "filters" . }}
+
+//
+// Aggregations.
+//
+{{ template "aggregate" . }}
+//
+// Keyset pagination.
+//
+{{ template "cursor" . }}
+//
+// Typed joins.
+//
+{{ template "join" . }}
+//
+// Eager loading.
+//
+{{ template "preload" . }}
+//
+// Full-text search.
+//
+{{ template "search" . }}
+//
+// Table upgrades.
+//
+{{ template "upgrade" . }}
+
+//
+// Fixtures.
+//
+{{ template "fixtures" . }}
 `
 
 const OptionsTemplate = `
@@ -59,6 +102,10 @@ type Option func(*Options)
 type Options struct {
 	// if true, then method was create/update relations
 	relations bool
+	// preloads are the relations loaded by the Preload option.
+	preloads []preload
+	// searchHighlight are the marks of the matches in the snippets of Search.
+	searchHighlight *searchHighlight
 }
 
 // WithRelations sets the relations flag.
@@ -70,8 +117,11 @@ func WithRelations() Option {
 }
 
 // FilterApplier is a condition filters.
+// The condition is the SQL expression, so the conditions nest with And, Or and Not.
 type FilterApplier interface {
+	sq.Sqlizer
 	Apply(query sq.SelectBuilder) sq.SelectBuilder
+	ApplyUpdate(query sq.UpdateBuilder) sq.UpdateBuilder
 	ApplyDelete(query sq.DeleteBuilder) sq.DeleteBuilder
 }
 
@@ -85,8 +135,18 @@ type QueryBuilder struct {
 	sortOptions  []FilterApplier
 	// pagination is the pagination.
 	pagination    *Pagination
+	// columns are the selected columns, all the columns are selected if it is empty.
+	columns []Column
+	// joins are the typed joins of the relations.
+	joins []RelationJoin
+	// preloads are the relations loaded with the found models.
+	preloads []preload
 }
 
+// Column is the column name of a table.
+// The columns of every model are generated as constants, e.g. UserColumnName.
+type Column string
+
 // NewQueryBuilder returns a new query builder.
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{}
@@ -116,6 +176,12 @@ func (b *QueryBuilder) WithPagination(pagination *Pagination) *QueryBuilder {
 	return b
 }
 
+// WithColumns selects only the given columns, the other fields of the found models are left empty.
+func (b *QueryBuilder) WithColumns(columns ...Column) *QueryBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
 // Filter is a helper function to create a new query builder with filter options.
 func FilterBuilder(filterOptions ...FilterApplier) *QueryBuilder {
 	return NewQueryBuilder().WithFilter(filterOptions...)
@@ -145,6 +211,102 @@ func PaginateBuilder(limit, offset uint64) *QueryBuilder {
 	return NewQueryBuilder().WithPagination(NewPagination(limit, offset))
 }
 
+// ColumnsBuilder is a helper function to create a new query builder which selects the given columns.
+func ColumnsBuilder(columns ...Column) *QueryBuilder {
+	return NewQueryBuilder().WithColumns(columns...)
+}
+
+// ColumnQuerier queries the rows of the given columns, it is implemented by every storage.
+type ColumnQuerier interface {
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+}
+
+// FindManyInto selects the columns of the db tags of T and scans the found rows into T.
+// It lets the list queries skip the heavy columns, e.g.
+//
+//	type userItem struct {
+//		Id   string ` + "`" + `db:"id"` + "`" + `
+//		Name string ` + "`" + `db:"name"` + "`" + `
+//	}
+//
+//	items, err := FindManyInto[userItem](ctx, storages.GetUserStorage(), builder)
+func FindManyInto[T any](ctx context.Context, querier ColumnQuerier, builders ...*QueryBuilder) ([]*T, error) {
+	fields, err := dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+
+	rows, err := querier.QueryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		value := reflect.ValueOf(result).Elem()
+		dest := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			dest = append(dest, value.FieldByIndex(field.index).Addr().Interface())
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// dbField is the struct field which is scanned from the column of its db tag.
+type dbField struct {
+	column Column
+	index  []int
+}
+
+// dbFields returns the fields of the struct with the db tags.
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", typ)
+	}
+
+	fields := collectDBFields(typ)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no fields with the db tag", typ)
+	}
+	return fields, nil
+}
+
+// collectDBFields collects the fields with the db tags, the embedded structs without the tag are included.
+func collectDBFields(typ reflect.Type) []dbField {
+	var fields []dbField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, f := range collectDBFields(field.Type) {
+				fields = append(fields, dbField{column: f.column, index: append([]int{i}, f.index...)})
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, dbField{column: Column(tag), index: field.Index})
+	}
+	return fields
+}
+
 // Pagination is the pagination.
 type Pagination struct {
 	// limit is the limit.
@@ -304,6 +466,8 @@ type {{ storageName }} interface {
 	{{- end }}
 	// TxManager returns the transaction manager.
 	TxManager() *TxManager
+	// VerifySchema compares the live tables with the models.
+	VerifySchema(ctx context.Context) (*SchemaReport, error)
 	// CreateTables creates the tables for all the stores.
 	CreateTables(ctx context.Context) error
 	// DropTables drops the tables for all the stores.
@@ -312,6 +476,10 @@ type {{ storageName }} interface {
 	TruncateTables(ctx context.Context) error
 	// UpgradeTables upgrades the tables for all the stores.
 	UpgradeTables(ctx context.Context) error
+	// LoadFixtures inserts the rows of the fixture files into the tables.
+	LoadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
+	// ReloadFixtures truncates the tables and loads the fixture files.
+	ReloadFixtures(ctx context.Context, fsys fs.FS, paths ...string) error
 }
 
 // New{{ storageName }} returns a new {{ storageName }}.
@@ -336,11 +504,30 @@ func (c *{{ storageName | lowerCamelCase }}) Get{{ $value.Value }}() {{ $value.V
 }
 {{ end }}
 
+// VerifySchema compares the live tables of all the stores with the models.
+// The differences are listed in the report, the error is returned only if the schema can not be read.
+// Use report.Err() to fail fast at startup when the tables are behind the models.
+func (c *{{ storageName | lowerCamelCase }}) VerifySchema(ctx context.Context) (*SchemaReport, error) {
+	var err error
+	var drifts []SchemaDrift
+	report := &SchemaReport{}
+{{ range $value := storages }}
+	// verify the {{ $value.Value }} table.
+	drifts, err = c.{{ $value.Key }}.VerifyTable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify table: %w", err)
+	}
+	report.Drifts = append(report.Drifts, drifts...)
+{{ end }}
+	return report, nil
+}
+
 // CreateTables creates the tables for all the stores.
+// The referenced tables are created first.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) CreateTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+{{ range $value := sortedStorages }}
 	// create the {{ $value.Value }} table.
 	err = c.{{ $value.Key }}.CreateTable(ctx)
 	if err != nil {
@@ -351,10 +538,11 @@ func (c *{{ storageName | lowerCamelCase }}) CreateTables(ctx context.Context) e
 }
 
 // DropTables drops the tables for all the stores.
+// The referencing tables are dropped first.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) DropTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+{{ range $value := reversedStorages }}
 	// drop the {{ $value.Value }} table.
 	err = c.{{ $value.Key }}.DropTable(ctx)
 	if err != nil {
@@ -365,10 +553,11 @@ func (c *{{ storageName | lowerCamelCase }}) DropTables(ctx context.Context) err
 }
 
 // TruncateTables truncates the tables for all the stores.
+// The referencing tables are truncated first.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) TruncateTables(ctx context.Context) error {
 	var err error
-{{ range  $value := storages }}
+{{ range $value := reversedStorages }}
 	// truncate the {{ $value.Value }} table.
 	err = c.{{ $value.Key }}.TruncateTable(ctx)
 	if err != nil {
@@ -379,16 +568,26 @@ func (c *{{ storageName | lowerCamelCase }}) TruncateTables(ctx context.Context)
 }
 
 // UpgradeTables runs the database upgrades for all the stores.
+// The referenced tables are upgraded first like in CreateTables, so the missing tables are created in the dependency order.
+// The drifts of all the tables are collected into one *SchemaDriftError.
 // This is idempotent and safe to run multiple times.
 func (c *{{ storageName | lowerCamelCase }}) UpgradeTables(ctx context.Context) error {
 	var err error
-{{ range $value := storages }}
+	var drifts []SchemaDrift
+{{ range $value := sortedStorages }}
 	// run the {{ $value.Value }} upgrade.
 	err = c.{{ $value.Key }}.UpgradeTable(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to upgrade: %w", err)
+		var driftErr *SchemaDriftError
+		if !errors.As(err, &driftErr) {
+			return fmt.Errorf("failed to upgrade: %w", err)
+		}
+		drifts = append(drifts, driftErr.Drifts...)
 	}
 {{ end }}
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 `
@@ -579,5 +778,20 @@ var (
 	ErrRowAlreadyExist    = errors.New("row already exist")
 	// ErrModelIsNil is returned when a relation model is nil.
 	ErrModelIsNil = errors.New("model is nil")
+	// ErrInvalidCursor is returned when a cursor can't be decoded or doesn't match the sorting of the query.
+	ErrInvalidCursor = errors.New("invalid cursor")
 )
+
+// rowsAffected returns the number of the rows affected by the statement of the single row,
+// it returns ErrRowNotFound if no row is affected.
+func rowsAffected(result sql.Result) (int64, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return 0, ErrRowNotFound
+	}
+	return affected, nil
+}
 `
@@ -0,0 +1,129 @@
+package tmpl
+
+// UpsertTemplate is the template for the conflict handling of the upsert.
+// This is included in the init template.
+const UpsertTemplate = `
+// Conflict is the conflict handling of the upsert, e.g. OnConflict(UserColumnEmail).DoUpdate(UserColumnName, UserColumnAge).
+type Conflict struct {
+	// target are the columns of the unique constraint.
+	target []Column
+	// update are the updated columns, all the inserted columns are updated if it is empty.
+	update []Column
+	// nothing keeps the existing row.
+	nothing bool
+}
+
+// OnConflict returns the conflict on the unique constraint of the columns: the unique field or the unique_index
+// group of the table. The existing row is updated with all the inserted columns but the primary key by default.
+func OnConflict(columns ...Column) *Conflict {
+	return &Conflict{target: columns}
+}
+
+// DoUpdate updates the columns of the existing row with the inserted values.
+func (c *Conflict) DoUpdate(columns ...Column) *Conflict {
+	c.update = columns
+	c.nothing = false
+	return c
+}
+
+// DoNothing keeps the existing row, its primary key is still returned by the upsert.
+func (c *Conflict) DoNothing() *Conflict {
+	c.update = nil
+	c.nothing = true
+	return c
+}
+
+// upsertColumn is the column inserted by the upsert.
+type upsertColumn struct {
+	column Column
+	// primaryKey is true for the primary key, it is not updated unless it is one of the updated columns.
+	primaryKey bool
+}
+
+// upsertColumnNames returns the names of the inserted columns.
+func upsertColumnNames(columns []upsertColumn) []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, string(column.column))
+	}
+	return names
+}
+
+// isConflictTarget returns true if the columns are the columns of one of the unique constraints in any order.
+func isConflictTarget(targets [][]Column, columns []Column) bool {
+	for _, target := range targets {
+		if len(target) != len(columns) {
+			continue
+		}
+		matched := true
+		for i, column := range columns {
+			if !containsColumn(target, column) || containsColumn(columns[:i], column) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsColumn returns true if the column is one of the columns.
+func containsColumn(columns []Column, column Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// suffix returns the ON CONFLICT clause of the upsert into the table with the unique constraints targets
+// and the inserted columns.
+func (c *Conflict) suffix(table string, targets [][]Column, columns []upsertColumn) (string, error) {
+	if c == nil {
+		return "", errors.New("conflict is nil")
+	}
+	if !isConflictTarget(targets, c.target) {
+		return "", fmt.Errorf("the columns %v are not a unique constraint of the %s table", c.target, table)
+	}
+
+	inserted := make([]Column, 0, len(columns))
+	for _, column := range columns {
+		inserted = append(inserted, column.column)
+	}
+
+	var set []Column
+	switch {
+	case c.nothing:
+	case len(c.update) == 0:
+		for _, column := range columns {
+			if !column.primaryKey && !containsColumn(c.target, column.column) {
+				set = append(set, column.column)
+			}
+		}
+	default:
+		for _, column := range c.update {
+			if !containsColumn(inserted, column) {
+				return "", fmt.Errorf("the column %q of the %s table is not inserted by the upsert", column, table)
+			}
+			set = append(set, column)
+		}
+	}
+	if len(set) == 0 {
+		// the existing row is not changed, the update of the target returns its primary key.
+		set = c.target[:1]
+	}
+
+	assignments := make([]string, 0, len(set))
+	for _, column := range set {
+		assignments = append(assignments, string(column)+" = excluded."+string(column))
+	}
+	target := make([]string, 0, len(c.target))
+	for _, column := range c.target {
+		target = append(target, string(column))
+	}
+	return "ON CONFLICT (" + strings.Join(target, ", ") + ") DO UPDATE SET " + strings.Join(assignments, ", "), nil
+}
+`
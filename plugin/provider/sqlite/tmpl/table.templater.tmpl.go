@@ -5,15 +5,19 @@ const TableTemplate = `
 {{ template "structure" . }}
 {{ template "table_conditions" . }}
 {{ template "create_method" . }}
+{{ template "upsert_method" . }}
 {{ template "update_method" . }}
 {{ template "delete_method" . }}
 {{- if (hasPrimaryKey) }}
 {{ template "get_by_id_method" . }}
 {{- end }}
 {{ template "find_many_method" . }}
+{{ template "join_method" . }}
 {{ template "find_one_method" . }}
 {{ template "count_method" . }}
+{{ template "aggregate_method" . }}
 {{ template "find_with_pagination" . }}
+{{ template "search_method" . }}
 {{ template "lock_method" . }}
 {{ template "raw_method" . }}
 `
@@ -22,13 +26,93 @@ const TableConditionFilters = `
 {{ range $key, $fieldMess := messages_for_filter }}
 	{{- if len $fieldMess.GetField }}
 		// {{ $fieldMess.GetName | camelCase }}Filters is a struct that holds filters for {{ $fieldMess.GetName }}.
+		// The From and To fields are the inclusive bounds of the range, the In fields are the lists of the allowed values.
 		type {{structureName}}Filters struct {
 			{{ range $field := $fieldMess.GetField }}
 			{{- if not ($field | isRelation) }}
-			{{ $field | fieldName }} *{{ $field | fieldType }}
+				{{- if (findPointer $field) }}
+					{{ $field | fieldName }} {{ $field | fieldType }}
+				{{- else }}
+					{{ $field | fieldName }} *{{ $field | fieldType }}
+				{{- end }}
+				{{- if ($field | isRangeFilter) }}
+					{{ $field | fieldName }}From *{{ $field | fieldTypeWP }}
+					{{ $field | fieldName }}To *{{ $field | fieldTypeWP }}
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+					{{ $field | fieldName }}In []{{ $field | fieldTypeWP }}
+				{{- end }}
 			{{- end }}
 			{{- end }}
 		}
+
+		// Apply returns the query builder with the conditions of the set filters, the nil filters match all the rows.
+		func (f *{{structureName}}Filters) Apply() *QueryBuilder {
+			if f == nil {
+				return NewQueryBuilder()
+			}
+			var filters []FilterApplier
+			{{- range $field := $fieldMess.GetField }}
+			{{- if not ($field | isRelation) }}
+			if f.{{ $field | fieldName }} != nil {
+				{{- if ($field | isJSON) }}
+				filters = append(filters, JSONContains("{{ $field.GetName }}", f.{{ $field | fieldName }}))
+				{{- else }}
+				filters = append(filters, EqualsCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}})
+				{{- end }}
+			}
+			{{- if ($field | isRangeFilter) }}
+			if f.{{ $field | fieldName }}From != nil {
+				filters = append(filters, GreaterThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}From})
+			}
+			if f.{{ $field | fieldName }}To != nil {
+				filters = append(filters, LessThanOrEqualCondition{Field: "{{ $field.GetName }}", Value: *f.{{ $field | fieldName }}To})
+			}
+			{{- end }}
+			{{- if ($field | isListFilter) }}
+			if f.{{ $field | fieldName }}In != nil {
+				filters = append(filters, InCondition{Field: "{{ $field.GetName }}", Values: toInterface(f.{{ $field | fieldName }}In)})
+			}
+			{{- end }}
+			{{- end }}
+			{{- end }}
+			return FilterBuilder(filters...)
+		}
+
+		// Parse{{structureName}}Filters parses the filters from the query string values, e.g. of an HTTP list request.
+		// The keys are the column names, the inclusive range bounds have the _from and _to suffixes and the lists
+		// have the _in suffix, the values of the list are the repeated keys: ?<column>_in=a&<column>_in=b.
+		// The unknown keys, the repeated keys of the single values and the invalid values are errors.
+		func Parse{{structureName}}Filters(values url.Values) (*{{structureName}}Filters, error) {
+			f := &{{structureName}}Filters{}
+			for _, key := range filterKeys(values) {
+				var err error
+				switch key {
+				{{- range $field := $fieldMess.GetField }}
+				{{- if and (not ($field | isRelation)) ($field | isScalarFilter) }}
+				case "{{ $field.GetName }}":
+					f.{{ $field | fieldName }}, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- if ($field | isRangeFilter) }}
+				case "{{ $field.GetName }}_from":
+					f.{{ $field | fieldName }}From, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				case "{{ $field.GetName }}_to":
+					f.{{ $field | fieldName }}To, err = parseFilterValue[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- if ($field | isListFilter) }}
+				case "{{ $field.GetName }}_in":
+					f.{{ $field | fieldName }}In, err = parseFilterValues[{{ $field | fieldTypeWP }}](key, values[key])
+				{{- end }}
+				{{- end }}
+				{{- end }}
+				default:
+					return nil, fmt.Errorf("unknown filter %q", key)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			return f, nil
+		}
 	{{- end }}
 {{ end }}
 
@@ -221,6 +305,41 @@ const TableConditionFilters = `
   {{ end }}
   {{ end }}
 {{ end }}
+{{ range $field := fields }}
+   {{- if ($field | isJSON) }}
+   {{- if ($field | isRepeated) }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON array contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value {{ $field | jsonElementType }}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", []{{ $field | jsonElementType }}{value})
+    }
+   {{- else }}
+	// {{ structureName }}{{ $field.GetName | camelCase }}Contains returns a condition that checks if the JSON object contains the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}Contains(value interface{}) FilterApplier {
+      return JSONContains("{{ $field.GetName }}", value)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}HasKey returns a condition that checks if the JSON object has the key.
+    func {{ structureName }}{{ $field.GetName | camelCase }}HasKey(key string) FilterApplier {
+      return JSONHasKey("{{ $field.GetName }}", key)
+    }
+
+	// {{ structureName }}{{ $field.GetName | camelCase }}PathEq returns a condition that checks if the value at the dot-separated path of the JSON object equals the value.
+    func {{ structureName }}{{ $field.GetName | camelCase }}PathEq(path string, value interface{}) FilterApplier {
+      return JSONPathEq("{{ $field.GetName }}", path, value)
+    }
+   {{- end }}
+  {{ end }}
+{{ end }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+
+// {{ structureName }}Has{{ $field | fieldName }} checks if the {{ structureName }} has the {{ $field | fieldName }} relation matching the filters.
+func {{ structureName }}Has{{ $field | fieldName }}(filters ...FilterApplier) FilterApplier {
+	query := sq.Select(string({{ $field | relationStructureName }}Column{{ $field | getRefID }})).From((&{{ $field | relationStructureName }}{}).TableName())
+	return InSubqueryCondition{Field: string({{ structureName }}Column{{ $field | getFieldID }}), Query: query, Conditions: filters}
+}
+{{- end }}
+{{- end }}
 `
 
 const TableFindWithPaginationMethodTemplate = `
@@ -254,6 +373,244 @@ func (t *{{ storageName | lowerCamelCase }}) FindManyWithPagination(ctx context.
 
 	return records, paginator, nil
 }
+{{- if (hasPrimaryKey) }}
+
+// FindManyAfter finds a page of {{ structureName }} after the cursor with the keyset pagination, the first page is found if the cursor is nil.
+// The rows are sorted by the WithSort columns of the builders followed by the primary key, the pagination of the builders is ignored.
+// The cursor must be returned by a query with the same sorting, otherwise ErrInvalidCursor is returned.
+// The cursors of the paginator are nil if there are no next or previous rows, or if the page is empty.
+func (t *{{ storageName | lowerCamelCase }}) FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*{{structureName}}, *CursorPaginator, error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("limit must be positive")
+	}
+
+	orders, err := t.cursorOrders(builders...)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := cursorColumns(orders)
+	backward := cursor != nil && cursor.backward
+
+	// the keyset builder replaces the sorting and the pagination of the builders,
+	// one more row is found to check if there is the next page
+	keyset := LimitBuilder(uint64(limit) + 1)
+	for _, order := range orders {
+		keyset.sortOptions = append(keyset.sortOptions, OrderCondition{Column: order.Column, Asc: order.Asc != backward})
+	}
+	if cursor != nil {
+		dest, err := (&{{structureName}}{}).columnDest(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := cursor.decode(orders, dest)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyset.filterOptions = append(keyset.filterOptions, cursorCondition{orders: orders, values: values, backward: backward})
+	}
+
+	query := make([]*QueryBuilder, 0, len(builders)+1)
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		b := *builder
+		b.sortOptions = nil
+		b.pagination = nil
+		if len(b.columns) > 0 && len(keyset.columns) == 0 {
+			// the sort columns are needed to build the cursors
+			for _, column := range columns {
+				keyset.columns = append(keyset.columns, Column(column))
+			}
+		}
+		query = append(query, &b)
+	}
+
+	records, err := t.FindMany(ctx, append(query, keyset)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find {{ structureName }}: %w", err)
+	}
+
+	hasMore := len(records) > limit
+	if hasMore {
+		records = records[:limit]
+	}
+	if backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	paginator := &CursorPaginator{}
+	if len(records) == 0 {
+		return records, paginator, nil
+	}
+	if backward || hasMore {
+		if paginator.Next, err = t.cursor(records[len(records)-1], orders, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		if paginator.Prev, err = t.cursor(records[0], orders, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records, paginator, nil
+}
+
+// cursor returns the cursor of the {{ structureName }} row.
+func (t *{{ storageName | lowerCamelCase }}) cursor(model *{{structureName}}, orders []OrderCondition, backward bool) (*Cursor, error) {
+	dest, err := model.columnDest(cursorColumns(orders))
+	if err != nil {
+		return nil, err
+	}
+	return newCursor(orders, dest, backward)
+}
+
+// cursorOrders returns the sorting of the keyset pagination: the WithSort columns of the builders followed by the primary key.
+func (t *{{ storageName | lowerCamelCase }}) cursorOrders(builders ...*QueryBuilder) ([]OrderCondition, error) {
+	var orders []OrderCondition
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, option := range builder.sortOptions {
+			order, ok := option.(OrderCondition)
+			if !ok {
+				return nil, fmt.Errorf("the sort option %T can't be used with the cursor", option)
+			}
+			if !t.isCursorColumn(order.Column) {
+				return nil, fmt.Errorf("the column %q of {{ structureName }} can't be used with the cursor, it must be a non-null scalar column", order.Column)
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	for _, order := range orders {
+		if order.Column == {{ getPrimaryKey | sourceName | printf "%q" }} {
+			return orders, nil
+		}
+	}
+	asc := len(orders) == 0 || orders[len(orders)-1].Asc
+	return append(orders, OrderCondition{Column: {{ getPrimaryKey | sourceName | printf "%q" }}, Asc: asc}), nil
+}
+
+// isCursorColumn returns true if the column can be a sort column of the keyset pagination.
+func (t *{{ storageName | lowerCamelCase }}) isCursorColumn(column string) bool {
+	switch column {
+	{{- range $field := fields }}
+	{{- if ($field | isCursorColumn) }}
+	case {{ $field | sourceName | printf "%q" }}:
+		return true
+	{{- end }}
+	{{- end }}
+	}
+	return false
+}
+{{- end }}
+`
+
+const TableSearchMethodTemplate = `
+{{- if searchFields }}
+// {{ structureName }}SearchResult is the {{ structureName }} found by Search with its relevance.
+type {{ structureName }}SearchResult struct {
+	{{ structureName }}
+	// Rank is the relevance of the row to the query, the higher the better.
+	Rank float64
+	// Snippets are the fragments of the searchable fields with the highlighted matches by their columns,
+	// they are set with the WithSearchHighlight option.
+	Snippets map[Column]string
+}
+
+// Search finds the {{ structureName }} rows matching the full-text search query, the most relevant first.
+// The rows must contain all the words of the query, they are ranked by bm25 of the FTS5 table {{ searchTable }}.
+// The builders filter, sort and paginate the found rows like in FindMany.
+// FTS5 must be compiled into the driver, github.com/mattn/go-sqlite3 needs the sqlite_fts5 build tag.
+func (t *{{ storageName | lowerCamelCase }}) Search(ctx context.Context, query string, builders ...*QueryBuilder) ([]*{{ structureName }}SearchResult, error) {
+	match := searchMatch(query)
+	if match == "" {
+		return nil, nil
+	}
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	// bm25 is lower for the more relevant rows
+	search := sq.Select("{{ searchKey }} AS structify_search_key", "-bm25({{ searchTable }}, {{ searchWeights }}) AS "+searchRankColumn).
+		From("{{ searchTable }}").
+		Where("{{ searchTable }} MATCH ?", match)
+	highlight := builderHighlight(builders)
+	if highlight != nil {
+		{{- range $i, $field := searchFields }}
+		search = search.Column(sq.Expr("snippet({{ searchTable }}, {{ add $i 1 }}, ?, ?, '...', 16) AS structify_snippet_{{ $i }}", highlight.start, highlight.stop))
+		{{- end }}
+	}
+
+	// the rows are sorted by the relevance before the sorting of the builders
+	selectQuery := t.selectQuery(columns, append([]*QueryBuilder{SortBuilder(OrderBy(searchRankColumn, false))}, builders...)...).
+		JoinClause(sq.Expr("JOIN (?) AS structify_search ON structify_search.structify_search_key = "+t.TableName()+".{{ searchKey }}", search)).
+		Column("structify_search." + searchRankColumn)
+	if highlight != nil {
+		{{- range $i, $field := searchFields }}
+		selectQuery = selectQuery.Column("structify_search.structify_snippet_{{ $i }}")
+		{{- end }}
+	}
+
+	sqlQuery, args, err := selectQuery.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*{{ structureName }}SearchResult
+	for rows.Next() {
+		result := &{{ structureName }}SearchResult{}
+		dest, err := result.{{ structureName }}.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		dest = append(dest, &result.Rank)
+		snippets := make([]string, {{ len searchFields }})
+		if highlight != nil {
+			for i := range snippets {
+				dest = append(dest, &snippets[i])
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan {{ structureName }}: %w", err)
+		}
+		if highlight != nil {
+			result.Snippets = map[Column]string{
+				{{- range $i, $field := searchFields }}
+				{{ structureName }}Column{{ $field | fieldName }}: snippets[{{ $i }}],
+				{{- end }}
+			}
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	models := make([]*{{ structureName }}, 0, len(results))
+	for _, result := range results {
+		models = append(models, &result.{{ structureName }})
+	}
+	if err := t.preload(ctx, models, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+{{- end }}
 `
 
 const TableLockMethodTemplate = `
@@ -326,6 +683,240 @@ func (t *{{ storageName | lowerCamelCase }}) Count(ctx context.Context, builders
 }
 `
 
+const TableAggregateMethodTemplate = `
+// {{ structureName }}AggregateRow is a row of the {{ structureName }} Aggregate query.
+// The embedded model holds the values of the grouped columns, the methods return the values of the aggregations.
+type {{ structureName }}AggregateRow struct {
+	{{ structureName }}
+	values map[Aggregation]interface{}
+}
+
+// Count returns the number of the rows, it is set by Count().
+func (r *{{ structureName }}AggregateRow) Count() int64 {
+	if v, ok := r.values[Count()].(*int64); ok {
+		return *v
+	}
+	return 0
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
+
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values, it is set by Sum({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Sum{{ $field | fieldName }}() {{ $field | sumType }} {
+	if v, ok := r.values[Sum({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | sumType }}); ok && *v != nil {
+		return **v
+	}
+	return 0
+}
+
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values, it is set by Avg({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Avg{{ $field | fieldName }}() *float64 {
+	if v, ok := r.values[Avg({{ structureName }}Column{{ $field | fieldName }})].(**float64); ok {
+		return *v
+	}
+	return nil
+}
+{{- end }}
+{{- if ($field | minMaxType) }}
+
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value, it is set by Min({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Min{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Min({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
+
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value, it is set by Max({{ structureName }}Column{{ $field | fieldName }}).
+func (r *{{ structureName }}AggregateRow) Max{{ $field | fieldName }}() *{{ $field | minMaxType }} {
+	if v, ok := r.values[Max({{ structureName }}Column{{ $field | fieldName }})].(**{{ $field | minMaxType }}); ok {
+		return *v
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
+
+// Aggregate groups the {{ structureName }} rows and returns the values of the aggregations per group, e.g.
+//
+//	rows, err := storage.Aggregate(ctx, GroupBy({{ structureName }}ColumnX), Count(), Having(Count().GT(1)), FilterBuilder(...))
+//
+// It returns a single row if there is no GroupBy. The sorting and the pagination of the builders are applied to the groups only.
+func (t *{{ storageName | lowerCamelCase }}) Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{ structureName }}AggregateRow, error) {
+	aggregate := newAggregateQuery(options)
+	if len(aggregate.groupBy) == 0 && len(aggregate.aggregations) == 0 {
+		return nil, fmt.Errorf("no aggregations of {{ structureName }}")
+	}
+
+	columns := make([]string, 0, len(aggregate.groupBy))
+	for _, column := range aggregate.groupBy {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		columns = append(columns, string(column))
+	}
+
+	selects := append([]string{}, columns...)
+	for _, aggregation := range aggregate.aggregations {
+		if _, err := t.aggregateDest(aggregation); err != nil {
+			return nil, err
+		}
+		selects = append(selects, aggregation.String())
+	}
+
+	// build query
+	query := t.queryBuilder.Select(selects...).From(t.TableName()).GroupBy(columns...)
+	for _, condition := range aggregate.having {
+		query = query.Having(condition)
+	}
+
+	// apply options from builder
+	for _, builder := range aggregate.builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.Apply(query)
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		// apply pagination
+		if builder.pagination != nil {
+			if builder.pagination.limit != nil {
+				query = query.Limit(*builder.pagination.limit)
+			}
+			if builder.pagination.offset != nil {
+				query = query.Offset(*builder.pagination.offset)
+			}
+		}
+
+		// apply sorting
+		for _, option := range builder.sortOptions {
+			query = option.Apply(query)
+		}
+	}
+
+	// execute query
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate {{ structureName }}: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*{{ structureName }}AggregateRow
+	for rows.Next() {
+		row := &{{ structureName }}AggregateRow{values: make(map[Aggregation]interface{}, len(aggregate.aggregations))}
+		dest, err := row.{{ structureName }}.columnDest(columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, aggregation := range aggregate.aggregations {
+			value, _ := t.aggregateDest(aggregation)
+			row.values[aggregation] = value
+			dest = append(dest, value)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan {{ structureName }} aggregate: %w", err)
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// aggregate returns the single aggregation of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) aggregate(ctx context.Context, aggregation Aggregation, builders []*QueryBuilder) (*{{ structureName }}AggregateRow, error) {
+	rows, err := t.Aggregate(ctx, aggregateOptions(aggregation, builders)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &{{ structureName }}AggregateRow{}, nil
+	}
+	return rows[0], nil
+}
+{{- range $field := fields }}
+{{- if ($field | sumType) }}
+
+// Sum{{ $field | fieldName }} returns the sum of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders.
+func (t *{{ storageName | lowerCamelCase }}) Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error) {
+	row, err := t.aggregate(ctx, Sum({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return 0, err
+	}
+	return row.Sum{{ $field | fieldName }}(), nil
+}
+
+// Avg{{ $field | fieldName }} returns the average of the {{ $field | sourceName }} values of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error) {
+	row, err := t.aggregate(ctx, Avg({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Avg{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- if ($field | minMaxType) }}
+
+// Min{{ $field | fieldName }} returns the smallest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Min({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Min{{ $field | fieldName }}(), nil
+}
+
+// Max{{ $field | fieldName }} returns the largest {{ $field | sourceName }} value of the {{ structureName }} rows found by the builders,
+// it is nil if there are no rows.
+func (t *{{ storageName | lowerCamelCase }}) Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error) {
+	row, err := t.aggregate(ctx, Max({{ structureName }}Column{{ $field | fieldName }}), builders)
+	if err != nil {
+		return nil, err
+	}
+	return row.Max{{ $field | fieldName }}(), nil
+}
+{{- end }}
+{{- end }}
+
+// aggregateDest returns the scan destination of the aggregation of the {{ structureName }} rows.
+func (t *{{ storageName | lowerCamelCase }}) aggregateDest(aggregation Aggregation) (interface{}, error) {
+	switch aggregation {
+	case Count():
+		return new(int64), nil
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	case Sum({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | sumType }}), nil
+	case Avg({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*float64), nil
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	case Min({{ structureName }}Column{{ $field | fieldName }}), Max({{ structureName }}Column{{ $field | fieldName }}):
+		return new(*{{ $field | minMaxType }}), nil
+	{{- end }}
+	{{- end }}
+	}
+	return nil, fmt.Errorf("unsupported aggregation %s of {{ structureName }}", aggregation)
+}
+`
+
 const TableFindOneMethodTemplate = `
 // FindOne finds a single {{ structureName }} based on the provided options.
 func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error) {
@@ -346,9 +937,146 @@ func (t *{{ storageName | lowerCamelCase }}) FindOne(ctx context.Context, builde
 
 const TableFindManyMethodTemplate = `
 // FindMany finds multiple {{ structureName }} based on the provided options.
+// Only the columns selected by QueryBuilder.WithColumns are filled if there are any.
+// The relations of QueryBuilder.WithPreload are loaded into the found models.
+// The relations joined by the typed joins, e.g. {{ structureName }}Join<Relation>, are filled in the same query.
 func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return nil, err
+	}
+	if joins := joinBuilders(builders); len(joins) > 0 {
+		results, err := t.findManyJoined(ctx, columns, joins, builders...)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.preload(ctx, results, builders...); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var results []*{{structureName}}
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return nil, fmt.Errorf("failed to scan {{ structureName }}: %w", err)
+		}
+		results = append(results, model)
+	}
+	
+	if err := t.preload(ctx, results, builders...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FindEach streams the {{ structureName }} rows found by the builders to fn without loading them all into memory.
+// It stops at the first error of fn and returns it.
+func (t *{{ storageName | lowerCamelCase }}) FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error {
+	columns, err := t.selectColumns(builders...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := t.queryColumns(ctx, columns, builders...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		model := &{{structureName}}{}
+		if err := model.ScanColumns(rows, columns); err != nil {
+			return fmt.Errorf("failed to scan {{ structureName }}: %w", err)
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumns executes the query of the builders which selects the given columns, the columns of the builders are ignored.
+// The caller must close the rows. It is used by FindManyInto to scan the rows into the custom structs.
+func (t *{{ storageName | lowerCamelCase }}) QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !t.hasColumn(column) {
+			return nil, fmt.Errorf("unknown column %q of {{ structureName }}", column)
+		}
+		names = append(names, string(column))
+	}
+	if len(names) == 0 {
+		names = t.Columns()
+	}
+
+	return t.queryColumns(ctx, names, builders...)
+}
+
+// selectColumns returns the columns selected by the builders, all the columns are selected by default.
+func (t *{{ storageName | lowerCamelCase }}) selectColumns(builders ...*QueryBuilder) ([]string, error) {
+	var columns []string
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		for _, column := range builder.columns {
+			if !t.hasColumn(column) {
+				return nil, fmt.Errorf("unknown column %q of {{ structureName }}", column)
+			}
+			columns = append(columns, string(column))
+		}
+	}
+
+	if len(columns) == 0 {
+		return t.Columns(), nil
+	}
+	return columns, nil
+}
+
+// hasColumn returns true if the column belongs to the table.
+func (t *{{ storageName | lowerCamelCase }}) hasColumn(column Column) bool {
+	for _, c := range t.Columns() {
+		if c == string(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryColumns executes the query of the builders which selects the columns.
+func (t *{{ storageName | lowerCamelCase }}) queryColumns(ctx context.Context, columns []string, builders ...*QueryBuilder) (*sql.Rows, error) {
+	// execute query
+	sqlQuery, args, err := t.selectQuery(columns, builders...).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find {{ structureName }}: %w", err)
+	}
+
+	return rows, nil
+}
+
+// selectQuery builds the query of the builders which selects the columns.
+func (t *{{ storageName | lowerCamelCase }}) selectQuery(columns []string, builders ...*QueryBuilder) sq.SelectBuilder {
 	// build query
-	query := t.queryBuilder.Select(t.Columns()...).From(t.TableName())
+	query := t.queryBuilder.Select(columns...).From(t.TableName())
 
 	// set default options
 	options := &Options{}
@@ -384,8 +1112,52 @@ func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, build
 		}
 	}
 
-	// execute query
-	sqlQuery, args, err := query.ToSql()
+	return query
+}
+`
+
+const TableJoinMethodTemplate = `
+{{- range $index, $field := fields }}
+{{- if ($field | isRelation) }}
+// {{ structureName }}Join{{ $field | fieldName }} joins the {{ $field | fieldName }} relation filtered by the filters,
+// FindMany fills {{ structureName }}.{{ $field | fieldName }} with the joined rows in the same query.
+// The {{ structureName }} rows without the relation are found with LeftJoin and skipped with InnerJoin.
+func {{ structureName }}Join{{ $field | fieldName }}(joinType JoinType, filters ...FilterApplier) *QueryBuilder {
+	return NewQueryBuilder().WithJoins(RelationJoin{
+		table:    {{ tableName | printf "%q" }},
+		relation: {{ $field | fieldName | printf "%q" }},
+		joinType: joinType,
+		filters:  filters,
+	})
+}
+{{- end }}
+{{- end }}
+
+// findManyJoined finds the {{ structureName }} rows with the joined relations in one query,
+// the joined rows are scanned into the relation fields and the repeated {{ structureName }} rows are merged.
+func (t *{{ storageName | lowerCamelCase }}) findManyJoined(ctx context.Context, columns []string, joins []RelationJoin, builders ...*QueryBuilder) ([]*{{structureName}}, error) {
+	columns = append([]string{}, columns...)
+	relations := make([]*joinedRelation, 0, len(joins))
+	for _, join := range joins {
+		relation, err := t.joinRelation(join)
+		if err != nil {
+			return nil, err
+		}
+		// the joined rows reference the base column, it is selected even if it is not in the columns
+		if !t.hasSelected(columns, relation.ref) {
+			columns = append(columns, relation.ref)
+		}
+		relations = append(relations, relation)
+	}
+
+	base := t.selectQuery(append(append([]string{}, columns...), rowNumberColumn(builders)), builders...)
+	for _, relation := range relations {
+		if relation.join.joinType == InnerJoin {
+			base = base.Where(sq.Expr("EXISTS (?)", relation.exists))
+		}
+	}
+
+	sqlQuery, args, err := joinQuery(t.queryBuilder, base, columns, relations).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
@@ -395,18 +1167,108 @@ func (t *{{ storageName | lowerCamelCase }}) FindMany(ctx context.Context, build
 		return nil, fmt.Errorf("failed to find {{ structureName }}: %w", err)
 	}
 	defer rows.Close()
-	
+
 	var results []*{{structureName}}
+	found := make(map[int64]*{{structureName}})
+	attached := make(map[[3]int64]bool)
 	for rows.Next() {
 		model := &{{structureName}}{}
-		if err := model.ScanRows(rows); err != nil {
+		dest, err := model.columnDest(columns)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan {{ structureName }}: %w", err)
 		}
-		results = append(results, model)
+		var row int64
+		dest = append(dest, &row)
+		joined := make([]*joinedRow, 0, len(relations))
+		for _, relation := range relations {
+			joinedRow := relation.newJoinedRow()
+			dest = append(dest, joinedRow.scanDest()...)
+			joined = append(joined, joinedRow)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan {{ structureName }}: %w", err)
+		}
+
+		// the {{ structureName }} row is repeated for every joined row
+		if parent, ok := found[row]; ok {
+			model = parent
+		} else {
+			found[row] = model
+			results = append(results, model)
+		}
+
+		for i, joinedRow := range joined {
+			key := [3]int64{int64(i), row, joinedRow.row.Int64}
+			if !joinedRow.row.Valid || attached[key] {
+				continue
+			}
+			attached[key] = true
+			joinedRow.assign()
+			joinedRow.attach(model)
+		}
 	}
-	
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
 	return results, nil
 }
+
+// hasSelected returns true if the column is in the selected columns.
+func (t *{{ storageName | lowerCamelCase }}) hasSelected(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelation resolves the typed join of a {{ structureName }} relation.
+func (t *{{ storageName | lowerCamelCase }}) joinRelation(join RelationJoin) (*joinedRelation, error) {
+	if join.table != t.TableName() {
+		return nil, fmt.Errorf("the join of the %s relation of %s can't be used with {{ structureName }}", join.relation, join.table)
+	}
+	if join.joinType != LeftJoin && join.joinType != InnerJoin {
+		return nil, fmt.Errorf("unsupported join type %s of the %s relation", join.joinType, join.relation)
+	}
+
+	switch join.relation {
+	{{- range $index, $field := fields }}
+	{{- if ($field | isRelation) }}
+	case {{ $field | fieldName | printf "%q" }}:
+		s := &{{ $field | relationStorageName | lowerCamelCase }}{db: t.db, queryBuilder: t.queryBuilder}
+		columns := s.Columns()
+		filter := FilterBuilder(join.filters...)
+		column := string({{ $field | relationStructureName }}Column{{ $field | getRefID }})
+		ref := string({{ structureName }}Column{{ $field | getFieldID }})
+		return &joinedRelation{
+			join:    join,
+			query:   s.selectQuery(append(append([]string{}, columns...), rowNumberColumn(nil)), filter),
+			exists:  s.selectQuery([]string{"1"}, filter).Where(s.TableName() + "." + column + " = " + t.TableName() + "." + ref).PlaceholderFormat(sq.Question),
+			column:  column,
+			ref:     ref,
+			columns: columns,
+			newRow: func() (interface{}, []interface{}, func(parent interface{})) {
+				model := &{{ $field | relationStructureName }}{}
+				dest, _ := model.columnDest(columns)
+				return model, dest, func(parent interface{}) {
+					p := parent.(*{{ structureName }})
+					{{- if ($field | isRepeated) }}
+					p.{{ $field | fieldName }} = append(p.{{ $field | fieldName }}, model)
+					{{- else }}
+					p.{{ $field | fieldName }} = model
+					{{- end }}
+				}
+			},
+		}, nil
+	{{- end }}
+	{{- end }}
+	}
+
+	return nil, fmt.Errorf("unknown relation %s of {{ structureName }}", join.relation)
+}
 `
 
 const TableGetByIDMethodTemplate = `
@@ -434,47 +1296,169 @@ func (t *{{ storageName | lowerCamelCase }}) Query(ctx context.Context, query st
 	return t.DB(ctx).ExecContext(ctx, query, args...)
 }
 
-// QueryRow executes a raw query and returns the result.
-func (t *{{ storageName | lowerCamelCase }}) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return t.DB(ctx).QueryRowContext(ctx, query, args...)
-}
+// QueryRow executes a raw query and returns the result.
+func (t *{{ storageName | lowerCamelCase }}) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.DB(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// QueryRows executes a raw query and returns the result.
+func (t *{{ storageName | lowerCamelCase }}) QueryRows(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.DB(ctx).QueryContext(ctx, query, args...)
+}
+`
+
+const TableDeleteMethodTemplate = `
+{{- if (hasPrimaryKey) }}
+// DeleteBy{{ getPrimaryKey.GetName | camelCase }} - deletes a {{ structureName }} by its {{ getPrimaryKey.GetName }}.
+// It returns the number of the deleted rows or ErrRowNotFound if there is no row with the {{ getPrimaryKey.GetName }}.
+func (t *{{ storageName | lowerCamelCase }}) DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) (int64, error) {
+	// set default options
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	query := t.queryBuilder.Delete("{{ tableName }}").Where("id = ?", id)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := t.DB(ctx).ExecContext(ctx,sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete {{ structureName }}: %w", err)
+	}
+
+	return rowsAffected(result)
+}
+{{- end }}
+
+// DeleteMany removes entries from the {{ tableName }} table using the provided filters,
+// it returns the number of the deleted rows.
+func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error) {
+	// build query
+	query := t.queryBuilder.Delete("{{ tableName }}")
+
+	var withFilter bool
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+
+		// apply filter options
+		for _, option := range builder.filterOptions {
+			query = option.ApplyDelete(query)
+			withFilter = true
+		}
+	}
+
+	if !withFilter {
+		return 0, errors.New("filters are required for delete operation")
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete Address: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+`
+
+const TableUpdateMethodTemplate = `
+// {{ structureName }}Update is used to update an existing {{ structureName }}.
+type {{ structureName }}Update struct {
+	{{- range $index, $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{- if not ($field | isAutoIncrement) }}
+	{{- if not ($field | isPrimary) }}
+	{{ $field | fieldName }} {{- if not ($field | findPointer) }}*{{- end }}{{ $field | fieldType }}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	// Expressions set the columns to the SQL expressions, see Increment and SetExpr.
+	Expressions []UpdateExpression
+}
+
+// Update updates an existing {{ structureName }} based on non-nil fields and the expressions.
+// It returns the number of the updated rows or ErrRowNotFound if there is no row with the id.
+func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
+	query = query.Where("id = ?", id)
 
-// QueryRows executes a raw query and returns the result.
-func (t *{{ storageName | lowerCamelCase }}) QueryRows(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return t.DB(ctx).QueryContext(ctx, query, args...)
-}
-`
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
 
-const TableDeleteMethodTemplate = `
-{{- if (hasPrimaryKey) }}
-// DeleteBy{{ getPrimaryKey.GetName | camelCase }} - deletes a {{ structureName }} by its {{ getPrimaryKey.GetName }}.
-func (t *{{ storageName | lowerCamelCase }}) DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) error {
-	// set default options
-	options := &Options{}
-	for _, o := range opts {
-		o(options)
+	result, err := t.DB(ctx).ExecContext(ctx,sqlQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update {{ structureName }}: %w", err)
 	}
 
-	query := t.queryBuilder.Delete("{{ tableName }}").Where("id = ?", id)
+	return rowsAffected(result)
+}
+{{- if (hasPrimaryKey) }}
+
+// UpdateReturning updates an existing {{ structureName }} based on non-nil fields and the expressions and returns the updated row,
+// so the values set by the database, e.g. by the expressions and the triggers, are read without a second query.
+// It returns ErrRowNotFound if there is no row with the id.
+func (t *{{ storageName | lowerCamelCase }}) UpdateReturning(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) (*{{structureName}}, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return nil, err
+	}
+	columns := t.Columns()
+	query = query.Where("id = ?", id).Suffix(returning(columns))
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx,sqlQuery, args...)
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete {{ structureName }}: %w", err)
+		return nil, fmt.Errorf("failed to update {{ structureName }}: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to update {{ structureName }}: %w", err)
+		}
+		return nil, ErrRowNotFound
+	}
+
+	model := &{{structureName}}{}
+	if err := model.ScanColumns(rows, columns); err != nil {
+		return nil, fmt.Errorf("failed to scan {{ structureName }}: %w", err)
+	}
+
+	return model, nil
 }
 {{- end }}
 
-// DeleteMany removes entries from the {{ tableName }} table using the provided filters
-func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, builders ...*QueryBuilder) error {
-	// build query
-	query := t.queryBuilder.Delete("{{ tableName }}")
+// UpdateMany updates the {{ tableName }} rows matching the filters based on non-nil fields and the expressions,
+// it returns the number of the updated rows.
+func (t *{{ storageName | lowerCamelCase }}) UpdateMany(ctx context.Context, updateData *{{structureName}}Update, builders ...*QueryBuilder) (int64, error) {
+	query, err := t.updateQuery(updateData)
+	if err != nil {
+		return 0, err
+	}
 
 	var withFilter bool
 	for _, builder := range builders {
@@ -484,51 +1468,39 @@ func (t *{{ storageName | lowerCamelCase }}) DeleteMany(ctx context.Context, bui
 
 		// apply filter options
 		for _, option := range builder.filterOptions {
-			query = option.ApplyDelete(query)
+			query = option.ApplyUpdate(query)
 			withFilter = true
 		}
 	}
 
 	if !withFilter {
-		return errors.New("filters are required for delete operation")
+		return 0, errors.New("filters are required for update operation")
 	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	result, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete Address: %w", err)
+		return 0, fmt.Errorf("failed to update {{ tableName }}: %w", err)
 	}
-	
-	return nil
-}
-`
 
-const TableUpdateMethodTemplate = `
-// {{ structureName }}Update is used to update an existing {{ structureName }}.
-type {{ structureName }}Update struct {
-	{{- range $index, $field := fields }}
-	{{- if not ($field | isRelation) }}
-	{{- if not ($field | isAutoIncrement) }}
-	{{- if not ($field | isPrimary) }}
-	{{ $field | fieldName }} {{- if not ($field | findPointer) }}*{{- end }}{{ $field | fieldType }}
-	{{- end}}
-	{{- end}}
-	{{- end}}
-	{{- end}}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
 }
 
-// Update updates an existing {{ structureName }} based on non-nil fields.
-func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) error {
+// updateQuery returns the query which sets the non-nil fields and the expressions of the update data.
+func (t *{{ storageName | lowerCamelCase }}) updateQuery(updateData *{{structureName}}Update) (sq.UpdateBuilder, error) {
+	query := t.queryBuilder.Update("{{ tableName }}")
 	if updateData == nil {
-		return errors.New("update data is nil")
+		return query, errors.New("update data is nil")
 	}
 
-	query := t.queryBuilder.Update("{{ tableName }}")
-
 	{{- range $index, $field := fields }}
 	{{- if not ($field | isRelation) }}
 	{{- if not ($field | isAutoIncrement) }}
@@ -537,7 +1509,7 @@ func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{ID
 		{{- if ($field | isRepeated) }}
 		value, err := updateData.{{ $field | fieldName }}.Value()
 		if err != nil {
-			return fmt.Errorf("failed to get {{ $field | fieldName | lowerCamelCase }} value: %w", err)
+			return query, fmt.Errorf("failed to get {{ $field | fieldName | lowerCamelCase }} value: %w", err)
 		}
 		query = query.Set("{{ $field | sourceName }}", value)
 		{{- else }}
@@ -549,19 +1521,7 @@ func (t *{{ storageName | lowerCamelCase }}) Update(ctx context.Context, id {{ID
 	{{- end}}
 	{{- end}}
 
-	query = query.Where("id = ?", id)
-
-	sqlQuery, args, err := query.ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
-	}
-
-	_, err = t.DB(ctx).ExecContext(ctx,sqlQuery, args...)
-	if err != nil {
-		return fmt.Errorf("failed to update {{ structureName }}: %w", err)
-	}
-
-	return nil
+	return setExpressions(query, updateData.Expressions), nil
 }
 `
 
@@ -592,6 +1552,42 @@ func (t *{{ structureName }}) ScanRows(r *sql.Rows) error {
 		{{- end }}
 	)
 }
+
+// ScanColumns scans the given columns of the row into the {{ structureName }}, the other fields are left empty.
+func (t *{{ structureName }}) ScanColumns(r *sql.Rows, columns []string) error {
+	dest, err := t.columnDest(columns)
+	if err != nil {
+		return err
+	}
+	return r.Scan(dest...)
+}
+
+// columnDest returns the pointers to the fields of the given columns.
+func (t *{{ structureName }}) columnDest(columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		switch column {
+		{{- range $field := fields }}
+		{{- if not ($field | isRelation) }}
+		case {{ $field | sourceName | printf "%q" }}:
+			dest = append(dest, &t.{{ $field | fieldName }})
+		{{- end }}
+		{{- end }}
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return dest, nil
+}
+
+// {{ structureName }} columns, use them to select the columns with QueryBuilder.WithColumns.
+const (
+	{{- range $field := fields }}
+	{{- if not ($field | isRelation) }}
+	{{ structureName }}Column{{ $field | fieldName }} Column = {{ $field | sourceName | printf "%q" }}
+	{{- end }}
+	{{- end }}
+)
 `
 
 const TableCreateMethodTemplate = `
@@ -716,6 +1712,11 @@ type {{structureName}}TableManager interface {
 	UpgradeTable(ctx context.Context) error
 }
 
+// {{structureName}}SchemaVerification is an interface for verifying the {{ tableName }} table.
+type {{structureName}}SchemaVerification interface {
+	VerifyTable(ctx context.Context) ([]SchemaDrift, error)
+}
+
 // {{structureName}}CRUDOperations is an interface for managing the {{ tableName }} table.
 type {{structureName}}CRUDOperations interface {
 	{{- if (hasID) }}
@@ -723,9 +1724,17 @@ type {{structureName}}CRUDOperations interface {
 	{{- else }} 
 	Create(ctx context.Context, model *{{structureName}}, opts ...Option) error
 	{{- end }}
-	Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) error
+	{{- if (conflictTargets) }}
+	Upsert(ctx context.Context, model *{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{IDType}}, {{ end }}error)
+	BatchUpsert(ctx context.Context, models []*{{structureName}}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error)
+	{{- end }}
+	Update(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) (int64, error)
+	{{- if (hasPrimaryKey) }}
+	UpdateReturning(ctx context.Context, id {{IDType}}, updateData *{{structureName}}Update) (*{{structureName}}, error)
+	{{- end }}
+	UpdateMany(ctx context.Context, updateData *{{structureName}}Update, builders ...*QueryBuilder) (int64, error)
 	{{- if (hasPrimaryKey) }}
-	DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) error
+	DeleteBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, {{getPrimaryKey.GetName}} {{IDType}}, opts ...Option) (int64, error)
 	{{- end }}
 	{{- if (hasPrimaryKey) }}
 	FindBy{{ getPrimaryKey.GetName | camelCase }}(ctx context.Context, id {{IDType}}, opts ...Option) (*{{ structureName }}, error)
@@ -735,14 +1744,40 @@ type {{structureName}}CRUDOperations interface {
 // {{structureName}}SearchOperations is an interface for searching the {{ tableName }} table.
 type {{structureName}}SearchOperations interface {
 	FindMany(ctx context.Context, builder ...*QueryBuilder) ([]*{{structureName}}, error)
+	// QueryColumns executes the query which selects the given columns, it is used by FindManyInto.
+	QueryColumns(ctx context.Context, columns []Column, builders ...*QueryBuilder) (*sql.Rows, error)
+	// FindEach streams the found rows to fn, it is used to process the large result sets.
+	FindEach(ctx context.Context, fn func(*{{structureName}}) error, builders ...*QueryBuilder) error
 	FindOne(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error)
 	Count(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 	SelectForUpdate(ctx context.Context, builders ...*QueryBuilder) (*{{structureName}}, error)
+	{{- if searchFields }}
+	// Search finds the rows matching the full-text search query, the most relevant first.
+	Search(ctx context.Context, query string, builders ...*QueryBuilder) ([]*{{structureName}}SearchResult, error)
+	{{- end }}
 }
 
 // {{structureName}}PaginationOperations is an interface for pagination operations.
 type {{structureName}}PaginationOperations interface {
 	FindManyWithPagination(ctx context.Context, limit int, page int, builders ...*QueryBuilder) ([]*{{structureName}}, *Paginator, error)
+	{{- if (hasPrimaryKey) }}
+	FindManyAfter(ctx context.Context, cursor *Cursor, limit int, builders ...*QueryBuilder) ([]*{{structureName}}, *CursorPaginator, error)
+	{{- end }}
+}
+
+// {{structureName}}AggregateOperations is an interface for the aggregate queries.
+type {{structureName}}AggregateOperations interface {
+	Aggregate(ctx context.Context, options ...AggregateOption) ([]*{{structureName}}AggregateRow, error)
+	{{- range $field := fields }}
+	{{- if ($field | sumType) }}
+	Sum{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) ({{ $field | sumType }}, error)
+	Avg{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*float64, error)
+	{{- end }}
+	{{- if ($field | minMaxType) }}
+	Min{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	Max{{ $field | fieldName }}(ctx context.Context, builders ...*QueryBuilder) (*{{ $field | minMaxType }}, error)
+	{{- end }}
+	{{- end }}
 }
 
 // {{structureName}}RelationLoading is an interface for loading relations.
@@ -761,7 +1796,7 @@ type {{structureName}}RelationLoading interface {
 
 // {{structureName}}AdvancedDeletion is an interface for advanced deletion operations.
 type {{structureName}}AdvancedDeletion interface {
-	DeleteMany(ctx context.Context, builders ...*QueryBuilder) error
+	DeleteMany(ctx context.Context, builders ...*QueryBuilder) (int64, error)
 }
 
 // {{structureName}}RawQueryOperations is an interface for executing raw queries.
@@ -774,9 +1809,11 @@ type {{structureName}}RawQueryOperations interface {
 // {{ storageName }} is a struct for the "{{ tableName }}" table.
 type {{ storageName }} interface {
     {{structureName}}TableManager
+	{{structureName}}SchemaVerification
 	{{structureName}}CRUDOperations
 	{{structureName}}SearchOperations
 	{{structureName}}PaginationOperations
+	{{structureName}}AggregateOperations
 	{{structureName}}RelationLoading
 	{{structureName}}AdvancedDeletion
 	{{structureName}}RawQueryOperations
@@ -844,6 +1881,13 @@ func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) er
         CREATE INDEX IF NOT EXISTS {{ tableName }}_{{ $field | sourceName }}_idx ON {{ tableName }} ({{ $field | sourceName }});
         {{- end}}
         {{- end}}
+        {{- if searchStatements }}
+
+        -- Full-text search
+        {{- range $statement := searchStatements }}
+        {{ $statement }}
+        {{- end }}
+        {{- end }}
         
         -- SQLite handles foreign key constraints differently and should be part of table creation
     ` + "`" + `
@@ -855,6 +1899,9 @@ func (t *{{ storageName | lowerCamelCase }}) CreateTable(ctx context.Context) er
 // DropTable drops the table.
 func (t *{{ storageName | lowerCamelCase }}) DropTable(ctx context.Context) error {
 	sqlQuery := ` + "`" + `
+		{{- range $statement := searchDropStatements }}
+		{{ $statement }}
+		{{- end }}
 		DROP TABLE IF EXISTS {{ tableName }};
 	` + "`" + `
 
@@ -862,22 +1909,98 @@ func (t *{{ storageName | lowerCamelCase }}) DropTable(ctx context.Context) erro
 	return err
 }
 
-// TruncateTable truncates the table.
+// TruncateTable truncates the table, sqlite has no TRUNCATE statement so all the rows are deleted.
 func (t *{{ storageName | lowerCamelCase }}) TruncateTable(ctx context.Context) error {
 	sqlQuery := ` + "`" + `
-		TRUNCATE TABLE {{ tableName }};
+		DELETE FROM {{ tableName }};
 	` + "`" + `
 
 	_, err := t.db.ExecContext(ctx,sqlQuery)
 	return err
 }
 
-// UpgradeTable upgrades the table.
-// todo: delete this method 
+// UpgradeTable creates the table if it does not exist, adds the missing columns and indexes.
+// The differences which can not be applied safely are returned as *SchemaDriftError.
+// This is idempotent and safe to run multiple times.
 func (t *{{ storageName | lowerCamelCase }}) UpgradeTable(ctx context.Context) error {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return fmt.Errorf("failed to introspect table: %w", err)
+	}
+	if len(live) == 0 {
+		return t.CreateTable(ctx)
+	}
+
+	missing, drifts := compareColumns(t.TableName(), t.tableColumns(), live)
+	for _, column := range missing {
+		_, err = db.ExecContext(ctx, column.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.Name, err)
+		}
+	}
+	for _, statement := range indexStatements(t.tableIndexes(), live, missing) {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+	{{- if searchStatements }}
+	// the full-text search is created if it is missing and filled with the existing rows
+	for _, statement := range []string{
+		{{- range $statement := searchStatements }}
+		{{ $statement | printf "%q" }},
+		{{- end }}
+	} {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to create search: %w", err)
+		}
+	}
+	{{- end }}
+
+	if len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
 	return nil
 }
 
+// VerifyTable compares the live table with the model and returns the differences.
+// The error is returned only if the table can not be introspected.
+func (t *{{ storageName | lowerCamelCase }}) VerifyTable(ctx context.Context) ([]SchemaDrift, error) {
+	db := t.DB(ctx)
+
+	live, err := introspectColumns(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table: %w", err)
+	}
+	liveIndexes, err := introspectIndexes(ctx, db, t.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect indexes: %w", err)
+	}
+
+	return verifyTable(t.TableName(), t.tableColumns(), t.tableIndexes(), live, liveIndexes), nil
+}
+
+// tableColumns returns the columns of the model which the upgrade compares with the live table.
+func (t *{{ storageName | lowerCamelCase }}) tableColumns() []tableColumn {
+	return []tableColumn{
+		{{- range $column := upgradeColumns }}
+		{Name: {{ $column.Name | printf "%q" }}, Type: {{ $column.Type | printf "%q" }}, NotNull: {{ $column.NotNull }}, Statement: {{ $column.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
+// tableIndexes returns the indexes which the upgrade creates on the table.
+func (t *{{ storageName | lowerCamelCase }}) tableIndexes() []tableIndex {
+	return []tableIndex{
+		{{- range $index := upgradeIndexes }}
+		{Name: {{ $index.Name | printf "%q" }}, Columns: {{ $index.Columns | printf "%#v" }}, Unique: {{ $index.Unique }}, Statement: {{ $index.Statement | printf "%q" }}},
+		{{- end }}
+	}
+}
+
 {{- range $index, $field := fields }}
 {{- if and ($field | isRelation) }}
 // Load{{ $field | pluralFieldName }} loads the {{ $field | pluralFieldName }} relation.
@@ -996,4 +2119,205 @@ func (t *{{ storageName | lowerCamelCase }}) LoadBatch{{ $field | pluralFieldNam
 }
 {{- end }}
 {{- end }}
+{{- $hasRelations := false }}
+{{- range $field := fields }}
+{{- if ($field | isRelation) }}
+{{- $hasRelations = true }}
+{{- end }}
+{{- end }}
+
+// preload loads the relations of the items preloaded by the builders with the LoadBatch functions, see QueryBuilder.WithPreload.
+func (t *{{ storageName | lowerCamelCase }}) preload(ctx context.Context, items []*{{structureName}}, builders ...*QueryBuilder) error {
+	{{- if $hasRelations }}
+	relations, relationBuilders := preloadRelations(builders)
+	for _, relation := range relations {
+		var load func(context.Context, []*{{structureName}}, ...*QueryBuilder) error
+		switch relation {
+		{{- range $field := fields }}
+		{{- if ($field | isRelation) }}
+		case {{ $field | fieldName | printf "%q" }}:
+			load = t.LoadBatch{{ $field | pluralFieldName }}
+		{{- end }}
+		{{- end }}
+		default:
+			return fmt.Errorf("unknown relation %s of {{ structureName }}", relation)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+		if err := load(ctx, items, relationBuilders[relation]...); err != nil {
+			return fmt.Errorf("failed to preload %s: %w", relation, err)
+		}
+	}
+	{{- else }}
+	if relations, _ := preloadRelations(builders); len(relations) > 0 {
+		return fmt.Errorf("unknown relation %s of {{ structureName }}", relations[0])
+	}
+	{{- end }}
+
+	return nil
+}
+`
+
+const TableUpsertMethodTemplate = `
+{{- if (conflictTargets) }}
+// {{ structureName | lowerCamelCase }}ConflictTargets are the unique constraints of the {{ tableName }} table which the upsert can conflict on.
+var {{ structureName | lowerCamelCase }}ConflictTargets = [][]Column{
+	{{- range $target := conflictTargets }}
+	{ {{- range $i, $field := $target }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end -}} },
+	{{- end }}
+}
+
+// {{ structureName | lowerCamelCase }}UpsertColumns are the columns inserted by the upsert.
+var {{ structureName | lowerCamelCase }}UpsertColumns = []upsertColumn{
+	{{- range $field := fields }}
+	{{- if not (or ($field | isRelation) ($field | isAutoIncrement) ($field | isDefaultUUID)) }}
+	{column: {{ structureName }}Column{{ $field | fieldName }}{{ if ($field | isPrimaryKey) }}, primaryKey: true{{ end }}},
+	{{- end }}
+	{{- end }}
+}
+
+// upsertValues returns the values of the model in the order of the {{ structureName | lowerCamelCase }}UpsertColumns.
+func (t *{{ storageName | lowerCamelCase }}) upsertValues(model *{{ structureName }}) ([]interface{}, error) {
+	{{- range $field := fields }}
+	{{- if and ($field | isUUID) ($field | isPrimaryKey) (not ($field | isAutoIncrement)) }}
+	if model.{{ $field | fieldName }} == "" {
+		uuidStr, err := uuid.NewUUID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate uuid for {{ structureName }}: %w", err)
+		}
+
+		model.{{ $field | fieldName }} = uuidStr.String()
+	}
+	{{- end }}
+	{{- end }}
+
+	values := make([]interface{}, 0, len({{ structureName | lowerCamelCase }}UpsertColumns))
+	{{- range $field := fields }}
+	{{- if not (or ($field | isRelation) ($field | isAutoIncrement) ($field | isDefaultUUID)) }}
+	{{- if ($field | isRepeated) }}
+	{{ $field | fieldName | lowerCamelCase }}Value, err := model.{{ $field | fieldName }}.Value()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get {{ $field | fieldName | lowerCamelCase }} value: %w", err)
+	}
+	values = append(values, {{ $field | fieldName | lowerCamelCase }}Value)
+	{{- else }}
+	values = append(values, model.{{ $field | fieldName }})
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	return values, nil
+}
+
+// upsertQuery returns the query which inserts the models or handles the conflict on the unique constraint.
+func (t *{{ storageName | lowerCamelCase }}) upsertQuery(models []*{{ structureName }}, conflict *Conflict, opts ...Option) (string, []interface{}, error) {
+	if len(models) == 0 {
+		return "", nil, errors.New("no models to upsert")
+	}
+
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.relations {
+		return "", nil, errors.New("relations are not supported in upsert")
+	}
+
+	suffix, err := conflict.suffix("{{ tableName }}", {{ structureName | lowerCamelCase }}ConflictTargets, {{ structureName | lowerCamelCase }}UpsertColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := t.queryBuilder.Insert("{{ tableName }}").Columns(upsertColumnNames({{ structureName | lowerCamelCase }}UpsertColumns)...)
+	for _, model := range models {
+		if model == nil {
+			return "", nil, errors.New("one of the models is nil")
+		}
+		values, err := t.upsertValues(model)
+		if err != nil {
+			return "", nil, err
+		}
+		query = query.Values(values...)
+	}
+	{{- if (hasID) }}
+	query = query.Suffix(suffix + " RETURNING \"id\"")
+	{{- else }}
+	query = query.Suffix(suffix)
+	{{- end }}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	return sqlQuery, args, nil
+}
+
+// Upsert creates the {{ structureName }} or handles the conflict on the unique constraint,
+// e.g. Upsert(ctx, model, OnConflict({{ range $i, $field := index conflictTargets 0 }}{{ if $i }}, {{ end }}{{ structureName }}Column{{ $field | fieldName }}{{ end }}).DoUpdate()).
+{{- if (hasID) }}
+// It returns the primary key of the created or the updated row.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) Upsert(ctx context.Context, model *{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}*{{ IDType }}, {{ end }}error) {
+	if model == nil {
+		return {{ if (hasID) }}nil, {{ end }}errors.New("model is nil")
+	}
+
+	sqlQuery, args, err := t.upsertQuery([]*{{ structureName }}{model}, conflict, opts...)
+	if err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+
+	{{ if (hasID) }}var id {{ IDType }}
+	err = t.DB(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&id)
+	{{- else }}_, err = t.DB(ctx).ExecContext(ctx, sqlQuery, args...)
+	{{- end }}
+	if err != nil {
+		return {{ if (hasID) }}nil, {{ end }}fmt.Errorf("failed to upsert {{ structureName }}: %w", err)
+	}
+
+	return {{ if (hasID) }}&id, {{ end }}nil
+}
+
+// BatchUpsert creates multiple {{ structureName }} records in a single batch or handles their conflicts on the unique constraint.
+{{- if (hasID) }}
+// It returns the primary keys of the created or the updated rows.
+{{- end }}
+func (t *{{ storageName | lowerCamelCase }}) BatchUpsert(ctx context.Context, models []*{{ structureName }}, conflict *Conflict, opts ...Option) ({{ if (hasID) }}[]string, {{ end }}error) {
+	sqlQuery, args, err := t.upsertQuery(models, conflict, opts...)
+	if err != nil {
+		return {{ if (hasID) }}nil, {{ end }}err
+	}
+	{{- if (hasID) }}
+
+	rows, err := t.DB(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute bulk upsert: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, len(models))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return ids, nil
+	{{- else }}
+
+	if _, err := t.DB(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return fmt.Errorf("failed to execute bulk upsert: %w", err)
+	}
+
+	return nil
+	{{- end }}
+}
+{{- end }}
 `
@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+)
+
+// clickhouseDialect is the Dialect of clickhouse.
+// The indexes are the data skipping indexes, the uniqueness and the foreign keys are not enforced.
+type clickhouseDialect struct{}
+
+// Name returns the name of the provider.
+func (clickhouseDialect) Name() string {
+	return "clickhouse"
+}
+
+// ColumnType returns the column type of the field.
+func (clickhouseDialect) ColumnType(f *descriptorpb.FieldDescriptorProto, isJSON bool) string {
+	return helperpkg.ClickHouseType(helperpkg.ConvertType(f), helperpkg.GetFieldOptions(f), isJSON)
+}
+
+// Prepare returns nothing, the clickhouse storages query the tables of the connection database.
+func (clickhouseDialect) Prepare(*Diff) []string {
+	return nil
+}
+
+// CreateTable returns the statement which creates the table, the table with the primary key is
+// the ReplacingMergeTree ordered by it, so the inserted row replaces the row with the same primary key.
+func (d clickhouseDialect) CreateTable(t *Table) []string {
+	var definitions []string
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.column(c))
+	}
+	for _, i := range t.Indexes {
+		definitions = append(definitions, d.index(i))
+	}
+
+	engine, orderBy := "MergeTree()", "tuple()"
+	if pk := t.PrimaryKey(); len(pk) > 0 {
+		engine, orderBy = "ReplacingMergeTree()", "("+quoteList(pk)+")"
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n) ENGINE = %s\nORDER BY %s", d.table(t), strings.Join(definitions, ",\n\t"), engine, orderBy)
+	if t.Comment != "" {
+		statement += "\nCOMMENT " + quoteString(t.Comment)
+	}
+	return []string{statement + ";"}
+}
+
+// CreateForeignKeys returns nothing, clickhouse has no foreign keys.
+func (clickhouseDialect) CreateForeignKeys(*Table) []string {
+	return nil
+}
+
+// AlterTable returns the statements which apply the table changes.
+func (d clickhouseDialect) AlterTable(td *TableDiff) []string {
+	var statements []string
+	table := d.table(td.New)
+
+	for _, i := range td.DroppedIndexes {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP INDEX IF EXISTS %s;", table, quote(i.Name)))
+	}
+	for _, c := range td.AddedColumns {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", table, d.column(c)))
+	}
+	for _, c := range td.AlteredColumns {
+		if c.Old.PrimaryKey != c.New.PrimaryKey {
+			statements = append(statements, fmt.Sprintf("-- the sorting key of %s is changed, the table must be migrated manually.", table))
+		}
+		if c.Old.Type != c.New.Type || c.Old.Default != c.New.Default {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", table, d.column(c.New)))
+		}
+	}
+	for _, c := range td.DroppedColumns {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", table, quote(c.Name)))
+	}
+	for _, i := range td.AddedIndexes {
+		statements = append(statements, d.CreateIndex(td.New, i))
+	}
+
+	return statements
+}
+
+// DropTable returns the statements which drop the table.
+func (d clickhouseDialect) DropTable(t *Table) []string {
+	return []string{fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.table(t))}
+}
+
+// AddColumn returns the statement which adds the column, the sorting key can not be changed safely.
+func (d clickhouseDialect) AddColumn(t *Table, c *Column) string {
+	if c.PrimaryKey {
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", d.table(t), d.column(c))
+}
+
+// CatalogColumn returns the type of the column as system.columns reports it,
+// the nullability is the part of the type.
+func (clickhouseDialect) CatalogColumn(c *Column) (string, bool) {
+	return c.Type, !strings.HasPrefix(c.Type, "Nullable(")
+}
+
+// SearchColumn returns nil, the full-text search is not supported by clickhouse.
+func (clickhouseDialect) SearchColumn(*Table) *Column {
+	return nil
+}
+
+// CreateSearch returns nothing, the full-text search is not supported by clickhouse.
+func (clickhouseDialect) CreateSearch(*Table) []string {
+	return nil
+}
+
+// DropSearch returns nothing, the full-text search is not supported by clickhouse.
+func (clickhouseDialect) DropSearch(*Table) []string {
+	return nil
+}
+
+// BlindIndex returns false, the encrypted fields are not supported by clickhouse.
+func (clickhouseDialect) BlindIndex() bool {
+	return false
+}
+
+// column returns the column definition.
+func (clickhouseDialect) column(c *Column) string {
+	definition := quote(c.Name) + " " + c.Type
+	if value := clickhouseDefault(c.Default); value != "" {
+		definition += " DEFAULT " + value
+	}
+	return definition
+}
+
+// index returns the data skipping index definition.
+func (clickhouseDialect) index(i *Index) string {
+	return fmt.Sprintf("INDEX %s (%s) TYPE bloom_filter GRANULARITY 1", quote(i.Name), quoteList(i.Columns))
+}
+
+// CreateIndex returns the statement which adds the data skipping index to the existing table.
+func (d clickhouseDialect) CreateIndex(t *Table, i *Index) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD INDEX IF NOT EXISTS %s (%s) TYPE bloom_filter GRANULARITY 1;", d.table(t), quote(i.Name), quoteList(i.Columns))
+}
+
+// table returns the quoted table name.
+func (clickhouseDialect) table(t *Table) string {
+	return quote(t.Name)
+}
+
+// clickhouseDefault returns the clickhouse default value, the postgres functions are replaced.
+func clickhouseDefault(value string) string {
+	if strings.Contains(value, "uuid_generate") {
+		return "generateUUIDv4()"
+	}
+	return value
+}
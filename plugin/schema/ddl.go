@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaFileSuffix is the suffix of the schema DDL file name.
+const SchemaFileSuffix = ".schema.sql"
+
+// DDL returns the SQL script which creates the whole schema from scratch.
+// The tables go in the dependency order with their indexes and foreign keys,
+// the foreign keys which close the reference cycles are added at the end.
+func DDL(d Dialect, s *Schema, source string) string {
+	tables, deferred := SortTables(s.Tables)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Code generated by protoc-gen-structify. DO NOT EDIT.\n-- source: %s\n-- provider: %s\n", source, d.Name())
+	if prepare := d.Prepare(&Diff{Created: tables}); len(prepare) > 0 {
+		b.WriteString("\n" + renderStatements(prepare))
+	}
+
+	for _, t := range tables {
+		fmt.Fprintf(&b, "\n-- %s\n", t.Key())
+		if t.Comment != "" {
+			fmt.Fprintf(&b, "-- %s\n", strings.ReplaceAll(t.Comment, "\n", "\n-- "))
+		}
+		statements := d.CreateTable(t)
+		statements = append(statements, ForeignKeyStatements(d, t, ImmediateForeignKeys(t, deferred))...)
+		b.WriteString(renderStatements(statements))
+	}
+
+	var cycles []string
+	for _, t := range tables {
+		var fks []*ForeignKey
+		for _, fk := range t.ForeignKeys {
+			if deferred[fk] {
+				fks = append(fks, fk)
+			}
+		}
+		cycles = append(cycles, ForeignKeyStatements(d, t, fks)...)
+	}
+	if len(cycles) > 0 {
+		b.WriteString("\n-- the foreign keys closing the reference cycles\n")
+		b.WriteString(renderStatements(cycles))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDDL(t *testing.T) {
+	users := &Table{
+		Name:    "users",
+		Comment: "The registered users.",
+		Columns: []*Column{
+			{Name: "id", Type: "UUID", PrimaryKey: true, NotNull: true},
+			{Name: "pinned_post_id", Type: "INTEGER"},
+		},
+		ForeignKeys: []*ForeignKey{
+			{Name: "users_pinned_post_id_fkey", Column: "pinned_post_id", RefTable: "posts", RefColumn: "id"},
+		},
+	}
+	posts := &Table{
+		Name: "posts",
+		Columns: []*Column{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+			{Name: "author_id", Type: "UUID", NotNull: true},
+		},
+		Indexes: []*Index{
+			{Name: "posts_author_id_idx", Columns: []string{"author_id"}},
+		},
+		ForeignKeys: []*ForeignKey{
+			{Name: "posts_author_id_fkey", Column: "author_id", RefTable: "users", RefColumn: "id", Cascade: true},
+		},
+	}
+	s := &Schema{Provider: "postgres", Tables: []*Table{users, posts}}
+
+	expected := `-- Code generated by protoc-gen-structify. DO NOT EDIT.
+-- source: blog.proto
+-- provider: postgres
+
+-- users
+-- The registered users.
+CREATE TABLE IF NOT EXISTS "users" (
+	"id" UUID PRIMARY KEY NOT NULL,
+	"pinned_post_id" INTEGER
+);
+
+COMMENT ON TABLE "users" IS 'The registered users.';
+
+-- posts
+CREATE TABLE IF NOT EXISTS "posts" (
+	"id" INTEGER PRIMARY KEY NOT NULL,
+	"author_id" UUID NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "posts_author_id_idx" ON "posts" USING btree ("author_id");
+
+ALTER TABLE "posts" ADD CONSTRAINT "posts_author_id_fkey" FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- the foreign keys closing the reference cycles
+ALTER TABLE "users" ADD CONSTRAINT "users_pinned_post_id_fkey" FOREIGN KEY ("pinned_post_id") REFERENCES "posts" ("id");
+`
+	assert.Equal(t, expected, DDL(Postgres, s, "blog.proto"))
+}
+
+func TestPostgresCreateIndex(t *testing.T) {
+	users := &Table{Name: "users"}
+	tests := []struct {
+		name     string
+		index    *Index
+		expected string
+	}{
+		{
+			name:     "btree",
+			index:    &Index{Name: "users_name_idx", Columns: []string{"name"}},
+			expected: `CREATE INDEX IF NOT EXISTS "users_name_idx" ON "users" USING btree ("name");`,
+		},
+		{
+			name:     "unique",
+			index:    &Index{Name: "users_email_unique_idx", Columns: []string{"email"}, Unique: true},
+			expected: `CREATE UNIQUE INDEX IF NOT EXISTS "users_email_unique_idx" ON "users" USING btree ("email");`,
+		},
+		{
+			name:     "gin",
+			index:    &Index{Name: "users_settings_idx", Columns: []string{"settings"}, Method: "gin"},
+			expected: `CREATE INDEX IF NOT EXISTS "users_settings_idx" ON "users" USING gin ("settings");`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Postgres.CreateIndex(users, tt.index))
+		})
+	}
+}
+
+func TestPostgresSearchColumn(t *testing.T) {
+	posts := &Table{
+		Name:   "posts",
+		Search: []*SearchColumn{{Column: "title", Weight: "A"}, {Column: "body", Weight: "B"}},
+	}
+
+	c := Postgres.SearchColumn(posts)
+	assert.Equal(t, &Column{
+		Name:      "search_vector",
+		Type:      "TSVECTOR",
+		Generated: `setweight(to_tsvector('simple', coalesce("title", '')), 'A') || setweight(to_tsvector('simple', coalesce("body", '')), 'B')`,
+	}, c)
+	assert.Equal(t,
+		`ALTER TABLE "posts" ADD COLUMN IF NOT EXISTS "search_vector" TSVECTOR GENERATED ALWAYS AS (`+c.Generated+`) STORED;`,
+		Postgres.AddColumn(posts, c))
+	assert.Nil(t, Postgres.SearchColumn(&Table{Name: "users"}))
+}
+
+func TestSQLiteCreateSearch(t *testing.T) {
+	posts := &Table{
+		Name:    "posts",
+		Columns: []*Column{{Name: "id", Type: "INTEGER", PrimaryKey: true}, {Name: "title", Type: "TEXT"}},
+		Search:  []*SearchColumn{{Column: "title", Weight: "A"}},
+	}
+
+	assert.Equal(t, []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS "posts_search" USING fts5("id" UNINDEXED, "title");`,
+		`CREATE TRIGGER IF NOT EXISTS "posts_search_insert" AFTER INSERT ON "posts" BEGIN INSERT INTO "posts_search" ("id", "title") VALUES (new."id", new."title"); END;`,
+		`CREATE TRIGGER IF NOT EXISTS "posts_search_update" AFTER UPDATE ON "posts" BEGIN DELETE FROM "posts_search" WHERE "id" = old."id"; INSERT INTO "posts_search" ("id", "title") VALUES (new."id", new."title"); END;`,
+		`CREATE TRIGGER IF NOT EXISTS "posts_search_delete" AFTER DELETE ON "posts" BEGIN DELETE FROM "posts_search" WHERE "id" = old."id"; END;`,
+		`INSERT INTO "posts_search" ("id", "title") SELECT "id", "title" FROM "posts" WHERE NOT EXISTS (SELECT 1 FROM "posts_search");`,
+	}, SQLite.CreateSearch(posts))
+	assert.Equal(t, []string{
+		`DROP TRIGGER IF EXISTS "posts_search_insert";`,
+		`DROP TRIGGER IF EXISTS "posts_search_update";`,
+		`DROP TRIGGER IF EXISTS "posts_search_delete";`,
+		`DROP TABLE IF EXISTS "posts_search";`,
+		`DROP TABLE IF EXISTS "posts";`,
+	}, SQLite.DropTable(posts))
+}
+
+func TestClickHouseCreateTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    *Table
+		expected string
+	}{
+		{
+			name: "primary key",
+			table: &Table{
+				Name:    "users",
+				Columns: []*Column{{Name: "id", Type: "UUID", PrimaryKey: true, NotNull: true}, {Name: "name", Type: "String", NotNull: true}},
+			},
+			expected: "CREATE TABLE IF NOT EXISTS \"users\" (\n\t\"id\" UUID,\n\t\"name\" String\n) ENGINE = ReplacingMergeTree()\nORDER BY (\"id\");",
+		},
+		{
+			name: "no primary key",
+			table: &Table{
+				Name:    "events",
+				Columns: []*Column{{Name: "name", Type: "String", NotNull: true}},
+			},
+			expected: "CREATE TABLE IF NOT EXISTS \"events\" (\n\t\"name\" String\n) ENGINE = MergeTree()\nORDER BY tuple();",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, []string{tt.expected}, ClickHouse.CreateTable(tt.table))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+)
+
+// Dialect renders the schema changes for the database provider.
+type Dialect interface {
+	// Name returns the name of the provider.
+	Name() string
+	// ColumnType returns the column type of the field.
+	ColumnType(f *descriptorpb.FieldDescriptorProto, isJSON bool) string
+	// Prepare returns the statements which must precede the changes, e.g. the extensions.
+	Prepare(diff *Diff) []string
+	// CreateTable returns the statements which create the table and its indexes.
+	CreateTable(t *Table) []string
+	// CreateForeignKeys returns the statements which add the foreign keys of the created table.
+	// They are executed after all the tables are created.
+	CreateForeignKeys(t *Table) []string
+	// AlterTable returns the statements which apply the table changes.
+	AlterTable(td *TableDiff) []string
+	// DropTable returns the statements which drop the table.
+	DropTable(t *Table) []string
+	// AddColumn returns the statement which adds the column to the existing table,
+	// it is empty if the column can not be added safely.
+	AddColumn(t *Table, c *Column) string
+	// CreateIndex returns the idempotent statement which creates the index on the existing table.
+	CreateIndex(t *Table, i *Index) string
+	// CatalogColumn returns the type and the nullability of the column as the database catalog reports them.
+	CatalogColumn(c *Column) (string, bool)
+	// SearchColumn returns the column which keeps the full-text search document of the table,
+	// it is nil if the table has no search or the dialect keeps the document outside of the table.
+	SearchColumn(t *Table) *Column
+	// CreateSearch returns the idempotent statements which create the full-text search kept outside of the table.
+	CreateSearch(t *Table) []string
+	// DropSearch returns the statements which drop the full-text search kept outside of the table.
+	DropSearch(t *Table) []string
+	// BlindIndex returns true if the storages keep the blind index columns of the encrypted fields.
+	BlindIndex() bool
+}
+
+var (
+	// Postgres is the Dialect of postgres.
+	Postgres Dialect = postgresDialect{}
+	// SQLite is the Dialect of sqlite.
+	SQLite Dialect = sqliteDialect{}
+	// ClickHouse is the Dialect of clickhouse.
+	ClickHouse Dialect = clickhouseDialect{}
+)
+
+// NewDialect returns the Dialect of the given provider.
+func NewDialect(provider string) (Dialect, error) {
+	switch provider {
+	case "postgres":
+		return Postgres, nil
+	case "sqlite":
+		return SQLite, nil
+	case "clickhouse":
+		return ClickHouse, nil
+	}
+	return nil, fmt.Errorf("unsupported provider: %q", provider)
+}
+
+// Statements returns the statements which apply the diff.
+func Statements(d Dialect, diff *Diff) []string {
+	statements := d.Prepare(diff)
+	for _, t := range diff.Created {
+		statements = append(statements, d.CreateTable(t)...)
+	}
+	for _, td := range diff.Altered {
+		statements = append(statements, d.AlterTable(td)...)
+	}
+	for _, t := range diff.Created {
+		statements = append(statements, d.CreateForeignKeys(t)...)
+	}
+	for i := len(diff.Dropped) - 1; i >= 0; i-- {
+		statements = append(statements, d.DropTable(diff.Dropped[i])...)
+	}
+	return statements
+}
+
+// quote returns the quoted identifier.
+func quote(name string) string {
+	return helperpkg.QuoteIdent("", name)
+}
+
+// quoteList returns the comma separated quoted identifiers.
+func quoteList(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quote(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quoteString returns the quoted string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,166 @@
+package schema
+
+import (
+	"reflect"
+)
+
+// Diff is the difference between two schemas.
+type Diff struct {
+	Created []*Table     // Created are the tables which are added.
+	Dropped []*Table     // Dropped are the tables which are removed.
+	Altered []*TableDiff // Altered are the tables which are changed.
+}
+
+// TableDiff is the difference between two versions of the table.
+type TableDiff struct {
+	Old *Table
+	New *Table
+
+	AddedColumns   []*Column
+	DroppedColumns []*Column
+	AlteredColumns []*ColumnChange
+
+	AddedIndexes   []*Index
+	DroppedIndexes []*Index
+
+	AddedForeignKeys   []*ForeignKey
+	DroppedForeignKeys []*ForeignKey
+
+	// SearchChanged is true if the columns of the full-text search are changed.
+	SearchChanged bool
+}
+
+// ColumnChange is the change of the column definition.
+type ColumnChange struct {
+	Old *Column
+	New *Column
+}
+
+// Compare returns the changes which turn the old schema into the new one.
+// A nil old schema is empty.
+func Compare(old, new *Schema) *Diff {
+	if old == nil {
+		old = &Schema{}
+	}
+
+	diff := &Diff{}
+	for _, t := range new.Tables {
+		ot := old.Table(t.Key())
+		if ot == nil {
+			diff.Created = append(diff.Created, t)
+			continue
+		}
+		if td := compareTables(ot, t); !td.Empty() {
+			diff.Altered = append(diff.Altered, td)
+		}
+	}
+	for _, t := range old.Tables {
+		if new.Table(t.Key()) == nil {
+			diff.Dropped = append(diff.Dropped, t)
+		}
+	}
+
+	return diff
+}
+
+// compareTables returns the changes which turn the old table into the new one.
+func compareTables(old, new *Table) *TableDiff {
+	td := &TableDiff{Old: old, New: new}
+
+	for _, c := range new.Columns {
+		oc := old.Column(c.Name)
+		switch {
+		case oc == nil:
+			td.AddedColumns = append(td.AddedColumns, c)
+		case *oc != *c:
+			td.AlteredColumns = append(td.AlteredColumns, &ColumnChange{Old: oc, New: c})
+		}
+	}
+	for _, c := range old.Columns {
+		if new.Column(c.Name) == nil {
+			td.DroppedColumns = append(td.DroppedColumns, c)
+		}
+	}
+
+	// the changed indexes and foreign keys are dropped and created again.
+	oldIndexes := make(map[string]*Index)
+	for _, i := range old.Indexes {
+		oldIndexes[i.Name] = i
+	}
+	newIndexes := make(map[string]*Index)
+	for _, i := range new.Indexes {
+		newIndexes[i.Name] = i
+		if oi, ok := oldIndexes[i.Name]; !ok || !reflect.DeepEqual(oi, i) {
+			td.AddedIndexes = append(td.AddedIndexes, i)
+		}
+	}
+	for _, i := range old.Indexes {
+		if ni, ok := newIndexes[i.Name]; !ok || !reflect.DeepEqual(ni, i) {
+			td.DroppedIndexes = append(td.DroppedIndexes, i)
+		}
+	}
+
+	oldKeys := make(map[string]*ForeignKey)
+	for _, fk := range old.ForeignKeys {
+		oldKeys[fk.Name] = fk
+	}
+	newKeys := make(map[string]*ForeignKey)
+	for _, fk := range new.ForeignKeys {
+		newKeys[fk.Name] = fk
+		if ofk, ok := oldKeys[fk.Name]; !ok || *ofk != *fk {
+			td.AddedForeignKeys = append(td.AddedForeignKeys, fk)
+		}
+	}
+	for _, fk := range old.ForeignKeys {
+		if nfk, ok := newKeys[fk.Name]; !ok || *nfk != *fk {
+			td.DroppedForeignKeys = append(td.DroppedForeignKeys, fk)
+		}
+	}
+
+	td.SearchChanged = !reflect.DeepEqual(old.Search, new.Search)
+
+	return td
+}
+
+// Empty returns true if there are no changes.
+func (d *Diff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Dropped) == 0 && len(d.Altered) == 0
+}
+
+// Reverse returns the changes which undo the diff.
+func (d *Diff) Reverse() *Diff {
+	r := &Diff{
+		Created: d.Dropped,
+		Dropped: d.Created,
+	}
+	for _, td := range d.Altered {
+		r.Altered = append(r.Altered, td.Reverse())
+	}
+	return r
+}
+
+// Empty returns true if the table is not changed.
+func (td *TableDiff) Empty() bool {
+	return len(td.AddedColumns) == 0 && len(td.DroppedColumns) == 0 && len(td.AlteredColumns) == 0 &&
+		len(td.AddedIndexes) == 0 && len(td.DroppedIndexes) == 0 &&
+		len(td.AddedForeignKeys) == 0 && len(td.DroppedForeignKeys) == 0 && !td.SearchChanged
+}
+
+// Reverse returns the changes which undo the table diff.
+func (td *TableDiff) Reverse() *TableDiff {
+	r := &TableDiff{
+		Old:                td.New,
+		New:                td.Old,
+		AddedColumns:       td.DroppedColumns,
+		DroppedColumns:     td.AddedColumns,
+		AddedIndexes:       td.DroppedIndexes,
+		DroppedIndexes:     td.AddedIndexes,
+		AddedForeignKeys:   td.DroppedForeignKeys,
+		DroppedForeignKeys: td.AddedForeignKeys,
+		SearchChanged:      td.SearchChanged,
+	}
+	for _, c := range td.AlteredColumns {
+		r.AlteredColumns = append(r.AlteredColumns, &ColumnChange{Old: c.New, New: c.Old})
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	users := func(columns ...*Column) *Table {
+		return &Table{
+			Name:    "users",
+			Columns: append([]*Column{{Name: "id", Type: "UUID", PrimaryKey: true, NotNull: true}}, columns...),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		old      *Schema
+		new      *Schema
+		expected []string
+	}{
+		{
+			name:     "unchanged",
+			old:      &Schema{Provider: "postgres", Tables: []*Table{users()}},
+			new:      &Schema{Provider: "postgres", Tables: []*Table{users()}},
+			expected: nil,
+		},
+		{
+			name: "initial",
+			old:  nil,
+			new:  &Schema{Provider: "postgres", Tables: []*Table{users()}},
+			expected: []string{
+				"CREATE TABLE IF NOT EXISTS \"users\" (\n\t\"id\" UUID PRIMARY KEY NOT NULL\n);",
+			},
+		},
+		{
+			name: "added column",
+			old:  &Schema{Provider: "postgres", Tables: []*Table{users()}},
+			new:  &Schema{Provider: "postgres", Tables: []*Table{users(&Column{Name: "name", Type: "TEXT"})}},
+			expected: []string{
+				`ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "name" TEXT;`,
+			},
+		},
+		{
+			name: "altered column",
+			old:  &Schema{Provider: "postgres", Tables: []*Table{users(&Column{Name: "age", Type: "INTEGER"})}},
+			new:  &Schema{Provider: "postgres", Tables: []*Table{users(&Column{Name: "age", Type: "BIGINT", NotNull: true})}},
+			expected: []string{
+				`ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT USING "age"::BIGINT;`,
+				`ALTER TABLE "users" ALTER COLUMN "age" SET NOT NULL;`,
+			},
+		},
+		{
+			name: "altered generated column",
+			old: &Schema{Provider: "postgres", Tables: []*Table{{
+				Name:    "posts",
+				Columns: []*Column{{Name: "search_vector", Type: "TSVECTOR", Generated: "to_tsvector('simple', title)"}},
+				Indexes: []*Index{{Name: "posts_search_vector_idx", Columns: []string{"search_vector"}, Method: "gin"}},
+			}}},
+			new: &Schema{Provider: "postgres", Tables: []*Table{{
+				Name:    "posts",
+				Columns: []*Column{{Name: "search_vector", Type: "TSVECTOR", Generated: "to_tsvector('simple', body)"}},
+				Indexes: []*Index{{Name: "posts_search_vector_idx", Columns: []string{"search_vector"}, Method: "gin"}},
+			}}},
+			expected: []string{
+				`ALTER TABLE "posts" DROP COLUMN IF EXISTS "search_vector";`,
+				`ALTER TABLE "posts" ADD COLUMN IF NOT EXISTS "search_vector" TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED;`,
+				`CREATE INDEX IF NOT EXISTS "posts_search_vector_idx" ON "posts" USING gin ("search_vector");`,
+			},
+		},
+		{
+			name: "dropped table",
+			old:  &Schema{Provider: "postgres", Tables: []*Table{users()}},
+			new:  &Schema{Provider: "postgres"},
+			expected: []string{
+				`DROP TABLE IF EXISTS "users" CASCADE;`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Statements(postgresDialect{}, Compare(tt.old, tt.new)))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	plugingo "github.com/golang/protobuf/protoc-gen-go/plugin"
+)
+
+// SnapshotFileName is the name of the schema snapshot in the migrations directory.
+const SnapshotFileName = "schema.snapshot.json"
+
+// migrationFileRegexp matches the numbered migration files.
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_.+\.(up|down)\.sql$`)
+
+// migrationNameRegexp matches the characters which are replaced in the migration names.
+var migrationNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Migrations returns the migration files which turn the schema of the previous snapshot
+// in the directory into the current one, and the new snapshot.
+// No files are returned if the schema is not changed.
+//
+// The directory is read relative to the working directory and the files are written
+// to the same path relative to the output directory.
+func Migrations(d Dialect, current *Schema, dir, name, source string) ([]*plugingo.CodeGeneratorResponse_File, error) {
+	previous, err := readSnapshot(path.Join(dir, SnapshotFileName))
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.Provider != current.Provider {
+		return nil, fmt.Errorf("snapshot provider %q does not match %q", previous.Provider, current.Provider)
+	}
+
+	diff := Compare(previous, current)
+	if diff.Empty() {
+		return nil, nil
+	}
+
+	number, err := nextMigrationNumber(dir)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = migrationName(previous, diff)
+	}
+	baseName := fmt.Sprintf("%04d_%s", number, sanitizeMigrationName(name))
+
+	snapshot, err := current.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	header := fmt.Sprintf("-- Code generated by protoc-gen-structify.\n-- source: %s\n\n", source)
+	return []*plugingo.CodeGeneratorResponse_File{
+		{
+			Name:    proto.String(path.Join(dir, baseName+".up.sql")),
+			Content: proto.String(header + renderStatements(Statements(d, diff))),
+		},
+		{
+			Name:    proto.String(path.Join(dir, baseName+".down.sql")),
+			Content: proto.String(header + renderStatements(Statements(d, diff.Reverse()))),
+		},
+		{
+			Name:    proto.String(path.Join(dir, SnapshotFileName)),
+			Content: proto.String(string(snapshot)),
+		},
+	}, nil
+}
+
+// readSnapshot reads the schema snapshot, nil is returned if it does not exist.
+func readSnapshot(name string) (*Schema, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	s, err := Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// nextMigrationNumber returns the number of the next migration in the directory.
+func nextMigrationNumber(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 1, nil
+		}
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	last := 0
+	for _, entry := range entries {
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(matches[1]); err == nil && n > last {
+			last = n
+		}
+	}
+	return last + 1, nil
+}
+
+// migrationName returns the default name of the migration.
+func migrationName(previous *Schema, diff *Diff) string {
+	if previous == nil {
+		return "init"
+	}
+
+	switch {
+	case len(diff.Created) == 1 && len(diff.Dropped) == 0 && len(diff.Altered) == 0:
+		return "create_" + diff.Created[0].Name
+	case len(diff.Created) == 0 && len(diff.Dropped) == 1 && len(diff.Altered) == 0:
+		return "drop_" + diff.Dropped[0].Name
+	case len(diff.Created) == 0 && len(diff.Dropped) == 0 && len(diff.Altered) == 1:
+		return "alter_" + diff.Altered[0].New.Name
+	}
+	return "update_schema"
+}
+
+// sanitizeMigrationName returns the snake case name which is safe to use in the file name.
+func sanitizeMigrationName(name string) string {
+	name = strings.Trim(migrationNameRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "migration"
+	}
+	return name
+}
+
+// renderStatements returns the statements separated by the blank lines.
+func renderStatements(statements []string) string {
+	if len(statements) == 0 {
+		return ""
+	}
+	return strings.Join(statements, "\n\n") + "\n"
+}
@@ -0,0 +1,81 @@
+package schema
+
+// SortTables returns the tables in the dependency order, every table goes after the tables it references,
+// and the foreign keys which close the reference cycles. Such keys can only be added after all the tables
+// are created. The tables keep the declaration order where they do not depend on each other.
+func SortTables(tables []*Table) ([]*Table, map[*ForeignKey]bool) {
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t.Key()] = true
+	}
+
+	// the self references and the references to the unknown tables do not affect the order.
+	dependencies := make(map[string]map[string]bool, len(tables))
+	for _, t := range tables {
+		dependencies[t.Key()] = make(map[string]bool)
+		for _, fk := range t.ForeignKeys {
+			if ref := fk.RefKey(); ref != t.Key() && known[ref] {
+				dependencies[t.Key()][ref] = true
+			}
+		}
+	}
+
+	sorted := make([]*Table, 0, len(tables))
+	done := make(map[string]bool, len(tables))
+	deferred := make(map[*ForeignKey]bool)
+	ready := func(t *Table) bool {
+		for ref := range dependencies[t.Key()] {
+			if !done[ref] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(sorted) < len(tables) {
+		var next *Table
+		for _, t := range tables {
+			if !done[t.Key()] && ready(t) {
+				next = t
+				break
+			}
+		}
+
+		// every remaining table waits for another one, so the cycle is broken
+		// by deferring the pending foreign keys of the first declared table.
+		if next == nil {
+			for _, t := range tables {
+				if !done[t.Key()] {
+					next = t
+					break
+				}
+			}
+			for _, fk := range next.ForeignKeys {
+				if ref := fk.RefKey(); ref != next.Key() && known[ref] && !done[ref] {
+					deferred[fk] = true
+				}
+			}
+		}
+
+		sorted = append(sorted, next)
+		done[next.Key()] = true
+	}
+
+	return sorted, deferred
+}
+
+// ForeignKeyStatements returns the statements which add the given foreign keys of the table.
+func ForeignKeyStatements(d Dialect, t *Table, fks []*ForeignKey) []string {
+	return d.CreateForeignKeys(&Table{Schema: t.Schema, Name: t.Name, ForeignKeys: fks})
+}
+
+// ImmediateForeignKeys returns the foreign keys of the table which are not deferred.
+func ImmediateForeignKeys(t *Table, deferred map[*ForeignKey]bool) []*ForeignKey {
+	var fks []*ForeignKey
+	for _, fk := range t.ForeignKeys {
+		if !deferred[fk] {
+			fks = append(fks, fk)
+		}
+	}
+	return fks
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTables(t *testing.T) {
+	table := func(name string, refs ...string) *Table {
+		t := &Table{Name: name}
+		for _, ref := range refs {
+			t.ForeignKeys = append(t.ForeignKeys, &ForeignKey{
+				Name:      name + "_" + ref + "_id_fkey",
+				Column:    ref + "_id",
+				RefTable:  ref,
+				RefColumn: "id",
+			})
+		}
+		return t
+	}
+
+	tests := []struct {
+		name     string
+		tables   []*Table
+		expected []string
+		deferred []string
+	}{
+		{
+			name:     "no foreign keys",
+			tables:   []*Table{table("users"), table("posts"), table("comments")},
+			expected: []string{"users", "posts", "comments"},
+		},
+		{
+			name:     "referenced table first",
+			tables:   []*Table{table("comments", "posts", "users"), table("posts", "users"), table("users")},
+			expected: []string{"users", "posts", "comments"},
+		},
+		{
+			name:     "self reference",
+			tables:   []*Table{table("categories", "categories"), table("products", "categories")},
+			expected: []string{"categories", "products"},
+		},
+		{
+			name:     "unknown table",
+			tables:   []*Table{table("posts", "accounts"), table("users")},
+			expected: []string{"posts", "users"},
+		},
+		{
+			name:     "cycle",
+			tables:   []*Table{table("posts", "users"), table("users", "posts"), table("comments", "posts")},
+			expected: []string{"posts", "users", "comments"},
+			deferred: []string{"posts_users_id_fkey"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, deferred := SortTables(tt.tables)
+
+			var names []string
+			for _, table := range sorted {
+				names = append(names, table.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+
+			var deferredNames []string
+			for _, table := range sorted {
+				for _, fk := range table.ForeignKeys {
+					if deferred[fk] {
+						deferredNames = append(deferredNames, fk.Name)
+					}
+				}
+			}
+			assert.Equal(t, tt.deferred, deferredNames)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+)
+
+// postgresDialect is the Dialect of postgres.
+type postgresDialect struct{}
+
+// Name returns the name of the provider.
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+// ColumnType returns the column type of the field.
+func (postgresDialect) ColumnType(f *descriptorpb.FieldDescriptorProto, isJSON bool) string {
+	return helperpkg.PostgresType(helperpkg.ConvertType(f), helperpkg.GetFieldOptions(f), isJSON)
+}
+
+// Prepare returns the statements which create the schemas and the extensions used by the changes.
+func (postgresDialect) Prepare(diff *Diff) []string {
+	var statements []string
+
+	schemas := make(map[string]bool)
+	for _, t := range diff.Created {
+		if t.Schema != "" && !schemas[t.Schema] {
+			schemas[t.Schema] = true
+			statements = append(statements, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", quote(t.Schema)))
+		}
+	}
+
+	columns := make([]*Column, 0)
+	for _, t := range diff.Created {
+		columns = append(columns, t.Columns...)
+	}
+	for _, td := range diff.Altered {
+		columns = append(columns, td.AddedColumns...)
+		for _, c := range td.AlteredColumns {
+			columns = append(columns, c.New)
+		}
+	}
+	for _, c := range columns {
+		if strings.Contains(c.Default, "uuid_generate") {
+			statements = append(statements, `CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`)
+			break
+		}
+	}
+
+	return statements
+}
+
+// CreateTable returns the statements which create the table and its indexes.
+func (d postgresDialect) CreateTable(t *Table) []string {
+	var definitions []string
+	pk := t.PrimaryKey()
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.column(c, len(pk) == 1))
+	}
+	if len(pk) > 1 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(pk)))
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n);", d.table(t), strings.Join(definitions, ",\n\t")),
+	}
+	if t.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", d.table(t), quoteString(t.Comment)))
+	}
+	for _, i := range t.Indexes {
+		statements = append(statements, d.CreateIndex(t, i))
+	}
+
+	return statements
+}
+
+// CreateForeignKeys returns the statements which add the foreign keys of the table.
+func (d postgresDialect) CreateForeignKeys(t *Table) []string {
+	var statements []string
+	for _, fk := range t.ForeignKeys {
+		statements = append(statements, d.addForeignKey(t, fk))
+	}
+	return statements
+}
+
+// AlterTable returns the statements which apply the table changes.
+func (d postgresDialect) AlterTable(td *TableDiff) []string {
+	var statements []string
+	table := d.table(td.New)
+
+	for _, fk := range td.DroppedForeignKeys {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", table, quote(fk.Name)))
+	}
+	for _, i := range td.DroppedIndexes {
+		statements = append(statements, fmt.Sprintf("DROP INDEX IF EXISTS %s;", helperpkg.QuoteIdent(td.New.Schema, i.Name)))
+	}
+	for _, c := range td.AddedColumns {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", table, d.column(c, true)))
+	}
+	for _, c := range td.AlteredColumns {
+		statements = append(statements, d.alterColumn(table, c)...)
+		if c.Old.Generated == c.New.Generated {
+			continue
+		}
+		// the generated column is added again without its indexes
+		for _, i := range td.New.Indexes {
+			if containsString(i.Columns, c.New.Name) && !containsIndex(td.AddedIndexes, i) {
+				statements = append(statements, d.CreateIndex(td.New, i))
+			}
+		}
+	}
+	for _, c := range td.DroppedColumns {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", table, quote(c.Name)))
+	}
+	for _, i := range td.AddedIndexes {
+		statements = append(statements, d.CreateIndex(td.New, i))
+	}
+	for _, fk := range td.AddedForeignKeys {
+		statements = append(statements, d.addForeignKey(td.New, fk))
+	}
+
+	return statements
+}
+
+// DropTable returns the statements which drop the table.
+func (d postgresDialect) DropTable(t *Table) []string {
+	return []string{fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE;", d.table(t))}
+}
+
+// AddColumn returns the statement which adds the column, the primary key can not be added safely.
+func (d postgresDialect) AddColumn(t *Table, c *Column) string {
+	if c.PrimaryKey {
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", d.table(t), d.column(c, false))
+}
+
+// CatalogColumn returns the data type and the nullability of the column as information_schema reports them.
+func (postgresDialect) CatalogColumn(c *Column) (string, bool) {
+	notNull := c.NotNull || c.PrimaryKey || c.AutoIncrement
+	if c.AutoIncrement {
+		return "integer", notNull
+	}
+
+	switch c.Type {
+	case "TIMESTAMP":
+		return "timestamp without time zone", notNull
+	case "TIMESTAMPTZ":
+		return "timestamp with time zone", notNull
+	}
+	return strings.ToLower(c.Type), notNull
+}
+
+// SearchColumn returns the stored generated tsvector column of the weighted search columns.
+func (postgresDialect) SearchColumn(t *Table) *Column {
+	if len(t.Search) == 0 {
+		return nil
+	}
+
+	vectors := make([]string, 0, len(t.Search))
+	for _, c := range t.Search {
+		vectors = append(vectors, fmt.Sprintf("setweight(to_tsvector(%s, coalesce(%s, '')), %s)",
+			quoteString(helperpkg.SearchConfig), quote(c.Column), quoteString(c.Weight)))
+	}
+	return &Column{
+		Name:      helperpkg.SearchVectorColumn,
+		Type:      "TSVECTOR",
+		Generated: strings.Join(vectors, " || "),
+	}
+}
+
+// CreateSearch returns nothing, the search document is kept by the generated column.
+func (postgresDialect) CreateSearch(*Table) []string {
+	return nil
+}
+
+// DropSearch returns nothing, the search document is dropped with the generated column.
+func (postgresDialect) DropSearch(*Table) []string {
+	return nil
+}
+
+// BlindIndex returns true, the postgres storages keep the blind index columns of the encrypted fields.
+func (postgresDialect) BlindIndex() bool {
+	return true
+}
+
+// alterColumn returns the statements which change the column definition.
+// The generated column can not be altered, it is dropped and added again.
+func (d postgresDialect) alterColumn(table string, c *ColumnChange) []string {
+	column := quote(c.New.Name)
+	if c.Old.Generated != "" || c.New.Generated != "" {
+		return []string{
+			fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", table, column),
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", table, d.column(c.New, false)),
+		}
+	}
+
+	var statements []string
+
+	if c.Old.PrimaryKey != c.New.PrimaryKey || c.Old.AutoIncrement != c.New.AutoIncrement {
+		statements = append(statements, fmt.Sprintf("-- the primary key or the auto increment of %s.%s is changed, it must be migrated manually.", table, column))
+	}
+	if c.Old.Type != c.New.Type {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", table, column, c.New.Type, column, c.New.Type))
+	}
+	if c.Old.NotNull != c.New.NotNull && !c.New.AutoIncrement {
+		if c.New.NotNull {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", table, column))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", table, column))
+		}
+	}
+	if c.Old.Default != c.New.Default {
+		if c.New.Default != "" {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, column, c.New.Default))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", table, column))
+		}
+	}
+
+	return statements
+}
+
+// column returns the column definition.
+// primaryKey is false if the primary key is declared by the table constraint.
+func (postgresDialect) column(c *Column, primaryKey bool) string {
+	if c.Generated != "" {
+		return quote(c.Name) + " " + c.Type + " GENERATED ALWAYS AS (" + c.Generated + ") STORED"
+	}
+
+	definition := quote(c.Name) + " " + c.Type
+	if c.AutoIncrement {
+		definition = quote(c.Name) + " SERIAL"
+	}
+	if c.PrimaryKey && primaryKey {
+		definition += " PRIMARY KEY"
+	}
+	if c.AutoIncrement {
+		return definition
+	}
+	if c.NotNull {
+		definition += " NOT NULL"
+	}
+	if c.Default != "" {
+		definition += " DEFAULT " + c.Default
+	}
+	return definition
+}
+
+// CreateIndex returns the statement which creates the index.
+func (d postgresDialect) CreateIndex(t *Table, i *Index) string {
+	unique := ""
+	if i.Unique {
+		unique = "UNIQUE "
+	}
+	method := "btree"
+	if i.Method != "" {
+		method = i.Method
+	}
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s USING %s (%s);", unique, quote(i.Name), d.table(t), method, quoteList(i.Columns))
+}
+
+// addForeignKey returns the statement which adds the foreign key.
+func (d postgresDialect) addForeignKey(t *Table, fk *ForeignKey) string {
+	statement := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		d.table(t), quote(fk.Name), quote(fk.Column), helperpkg.QuoteIdent(fk.RefSchema, fk.RefTable), quote(fk.RefColumn))
+	if fk.Cascade {
+		statement += " ON DELETE CASCADE"
+	}
+	return statement + ";"
+}
+
+// table returns the qualified table name.
+func (postgresDialect) table(t *Table) string {
+	return helperpkg.QuoteIdent(t.Schema, t.Name)
+}
+
+// containsString returns true if the values contain the value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsIndex returns true if the indexes contain the index with the same name.
+func containsIndex(indexes []*Index, index *Index) bool {
+	for _, i := range indexes {
+		if i.Name == index.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,319 @@
+// Package schema describes the database schema of the generated storages.
+// It is used to keep the schema snapshot and to generate the migrations.
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+	statepkg "github.com/cjp2600/protoc-gen-structify/plugin/state"
+)
+
+// Schema is the database schema of the proto file.
+type Schema struct {
+	Provider string   `json:"provider"`
+	Tables   []*Table `json:"tables"`
+}
+
+// Table is the database table of the message.
+type Table struct {
+	Schema      string        `json:"schema,omitempty"`
+	Name        string        `json:"name"`
+	Comment     string        `json:"comment,omitempty"`
+	Columns     []*Column     `json:"columns"`
+	Indexes     []*Index      `json:"indexes,omitempty"`
+	ForeignKeys []*ForeignKey `json:"foreign_keys,omitempty"`
+	// Search are the columns of the full-text search of the table.
+	Search []*SearchColumn `json:"search,omitempty"`
+}
+
+// Column is the table column.
+type Column struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	PrimaryKey    bool   `json:"primary_key,omitempty"`
+	NotNull       bool   `json:"not_null,omitempty"`
+	AutoIncrement bool   `json:"auto_increment,omitempty"`
+	Default       string `json:"default,omitempty"`
+	// Generated is the expression of the stored generated column.
+	Generated string `json:"generated,omitempty"`
+}
+
+// Index is the table index.
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	// Method is the Postgres index method, e.g. gin for the JSON columns, it is btree if empty.
+	Method string `json:"method,omitempty"`
+}
+
+// SearchColumn is the column of the full-text search with its rank weight: A, B, C or D.
+type SearchColumn struct {
+	Column string `json:"column"`
+	Weight string `json:"weight"`
+}
+
+// ForeignKey is the foreign key constraint of the table.
+type ForeignKey struct {
+	Name      string `json:"name"`
+	Column    string `json:"column"`
+	RefSchema string `json:"ref_schema,omitempty"`
+	RefTable  string `json:"ref_table"`
+	RefColumn string `json:"ref_column"`
+	Cascade   bool   `json:"cascade,omitempty"`
+}
+
+// Key returns the unique key of the table.
+func (t *Table) Key() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// Column returns the column by the given name.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key columns.
+func (t *Table) PrimaryKey() []string {
+	var columns []string
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			columns = append(columns, c.Name)
+		}
+	}
+	return columns
+}
+
+// RefKey returns the key of the referenced table.
+func (fk *ForeignKey) RefKey() string {
+	t := &Table{Schema: fk.RefSchema, Name: fk.RefTable}
+	return t.Key()
+}
+
+// Table returns the table by the given key.
+func (s *Schema) Table(key string) *Table {
+	for _, t := range s.Tables {
+		if t.Key() == key {
+			return t
+		}
+	}
+	return nil
+}
+
+// Marshal returns the indented JSON snapshot of the schema.
+func (s *Schema) Marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Unmarshal parses the JSON snapshot of the schema.
+func Unmarshal(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Build builds the schema of the state messages with the column types of the dialect.
+func Build(state *statepkg.State, d Dialect) *Schema {
+	s := &Schema{Provider: d.Name()}
+	for _, m := range state.Messages {
+		s.Tables = append(s.Tables, buildTable(state, d, m))
+	}
+
+	// foreign keys are declared on the relation fields,
+	// the constraint belongs to the table which holds the referencing column.
+	for _, m := range state.Messages {
+		for _, f := range m.GetField() {
+			addForeignKey(state, s, m, f)
+		}
+	}
+
+	return s
+}
+
+// BuildTable builds the table of the message with the foreign keys of the whole schema.
+func BuildTable(state *statepkg.State, d Dialect, m *descriptorpb.DescriptorProto) *Table {
+	return Build(state, d).Table(TableKey(state, m))
+}
+
+// buildTable builds the table of the message.
+func buildTable(state *statepkg.State, d Dialect, m *descriptorpb.DescriptorProto) *Table {
+	t := &Table{
+		Schema: state.SchemaName(m),
+		Name:   state.TableName(m),
+	}
+	if opts := helperpkg.GetMessageOptions(m); opts != nil {
+		t.Comment = opts.GetComment()
+	}
+
+	for _, f := range m.GetField() {
+		if state.IsRelation(f) {
+			continue
+		}
+
+		column := &Column{
+			Name: f.GetName(),
+			Type: d.ColumnType(f, state.NestedMessages.IsJSON(f)),
+		}
+		if opts := helperpkg.GetFieldOptions(f); opts != nil {
+			column.PrimaryKey = opts.GetPrimaryKey()
+			column.NotNull = !opts.GetNullable()
+			column.AutoIncrement = opts.GetAutoIncrement()
+			column.Default = opts.GetDefault()
+		}
+		t.Columns = append(t.Columns, column)
+
+		if d.BlindIndex() && helperpkg.HasBlindIndex(f) {
+			t.Columns = append(t.Columns, &Column{
+				Name:    helperpkg.BlindIndexColumn(f),
+				Type:    column.Type,
+				NotNull: column.NotNull,
+			})
+		}
+	}
+
+	for _, f := range m.GetField() {
+		if state.IsRelation(f) || !helperpkg.IsSearchable(f) {
+			continue
+		}
+		t.Search = append(t.Search, &SearchColumn{Column: f.GetName(), Weight: helperpkg.SearchWeight(f)})
+	}
+	searchColumn := d.SearchColumn(t)
+	if searchColumn != nil {
+		t.Columns = append(t.Columns, searchColumn)
+	}
+
+	for _, f := range m.GetField() {
+		if state.IsRelation(f) {
+			continue
+		}
+		if helperpkg.HasUnique(f) {
+			t.Indexes = append(t.Indexes, &Index{
+				Name:    t.Name + "_" + f.GetName() + "_unique_idx",
+				Columns: []string{indexColumn(d, f)},
+				Unique:  true,
+			})
+		}
+	}
+
+	if opts := helperpkg.GetMessageOptions(m); opts != nil {
+		for _, uniqueIndex := range opts.GetUniqueIndex() {
+			var names, columns []string
+			for _, name := range uniqueIndex.GetFields() {
+				for _, f := range m.GetField() {
+					if f.GetName() == name {
+						names = append(names, helperpkg.SnakeCase(name))
+						columns = append(columns, indexColumn(d, f))
+					}
+				}
+			}
+			if len(columns) > 0 {
+				t.Indexes = append(t.Indexes, &Index{
+					Name:    t.Name + "_unique_idx_" + strings.Join(names, "_"),
+					Columns: columns,
+					Unique:  true,
+				})
+			}
+		}
+	}
+
+	for _, f := range m.GetField() {
+		if state.IsRelation(f) {
+			continue
+		}
+		if helperpkg.HasIndex(f) {
+			index := &Index{
+				Name:    t.Name + "_" + f.GetName() + "_idx",
+				Columns: []string{indexColumn(d, f)},
+			}
+			// the JSON filters are served by the gin index
+			if state.NestedMessages.IsJSON(f) {
+				index.Method = "gin"
+			}
+			t.Indexes = append(t.Indexes, index)
+		}
+	}
+
+	if searchColumn != nil {
+		t.Indexes = append(t.Indexes, &Index{
+			Name:    t.Name + "_" + searchColumn.Name + "_idx",
+			Columns: []string{searchColumn.Name},
+			Method:  "gin",
+		})
+	}
+
+	return t
+}
+
+// addForeignKey adds the foreign key of the relation field to the owning table.
+func addForeignKey(state *statepkg.State, s *Schema, m *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto) {
+	opts := helperpkg.GetFieldOptions(f)
+	if opts == nil || opts.GetRelation() == nil || opts.GetRelation().GetForeign() == nil {
+		return
+	}
+
+	related := state.Messages.FindByName(helperpkg.DetectStructName(helperpkg.ConvertType(f)))
+	if related == nil {
+		return
+	}
+
+	relation := opts.GetRelation()
+	table := s.Table(TableKey(state, m))
+	relatedTable := s.Table(TableKey(state, related))
+
+	// the parent to child relation is declared on the parent by its primary key,
+	// so the referencing column is in the related table.
+	owner, column, ref, refColumn := table, relation.GetField(), relatedTable, relation.GetReference()
+	if c := table.Column(relation.GetField()); c == nil || c.PrimaryKey {
+		owner, column, ref, refColumn = relatedTable, relation.GetReference(), table, relation.GetField()
+	}
+	if owner.Column(column) == nil {
+		return
+	}
+
+	fk := &ForeignKey{
+		Name:      owner.Name + "_" + column + "_fkey",
+		Column:    column,
+		RefSchema: ref.Schema,
+		RefTable:  ref.Name,
+		RefColumn: refColumn,
+		Cascade:   relation.GetForeign().GetCascade(),
+	}
+	for _, existing := range owner.ForeignKeys {
+		if existing.Name == fk.Name {
+			return
+		}
+	}
+	owner.ForeignKeys = append(owner.ForeignKeys, fk)
+}
+
+// TableKey returns the unique key of the message table.
+func TableKey(state *statepkg.State, m *descriptorpb.DescriptorProto) string {
+	t := &Table{Schema: state.SchemaName(m), Name: state.TableName(m)}
+	return t.Key()
+}
+
+// indexColumn returns the column to build the index on.
+func indexColumn(d Dialect, f *descriptorpb.FieldDescriptorProto) string {
+	if d.BlindIndex() && helperpkg.HasBlindIndex(f) {
+		return helperpkg.BlindIndexColumn(f)
+	}
+	return f.GetName()
+}
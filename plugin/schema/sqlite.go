@@ -0,0 +1,244 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	helperpkg "github.com/cjp2600/protoc-gen-structify/plugin/pkg/helper"
+)
+
+// sqliteDialect is the Dialect of sqlite.
+type sqliteDialect struct{}
+
+// Name returns the name of the provider.
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+// ColumnType returns the column type of the field.
+func (sqliteDialect) ColumnType(f *descriptorpb.FieldDescriptorProto, isJSON bool) string {
+	return helperpkg.SQLiteType(helperpkg.ConvertTypeSQLite(f), helperpkg.GetFieldOptions(f), isJSON)
+}
+
+// Prepare returns nothing, sqlite does not need any preparation.
+func (sqliteDialect) Prepare(*Diff) []string {
+	return nil
+}
+
+// CreateTable returns the statements which create the table and its indexes.
+// The foreign keys are the part of the table definition in sqlite.
+func (d sqliteDialect) CreateTable(t *Table) []string {
+	statements := []string{d.createTable(t, t.Name)}
+	for _, i := range t.Indexes {
+		statements = append(statements, d.CreateIndex(t, i))
+	}
+	return append(statements, d.CreateSearch(t)...)
+}
+
+// CreateForeignKeys returns nothing, the foreign keys are created with the table.
+func (sqliteDialect) CreateForeignKeys(*Table) []string {
+	return nil
+}
+
+// AlterTable returns the statements which apply the table changes.
+// sqlite can only add and drop the columns, so the table is rebuilt
+// if the columns are altered or the foreign keys are changed.
+func (d sqliteDialect) AlterTable(td *TableDiff) []string {
+	if len(td.AlteredColumns) > 0 || len(td.DroppedColumns) > 0 ||
+		len(td.AddedForeignKeys) > 0 || len(td.DroppedForeignKeys) > 0 {
+		return d.rebuildTable(td)
+	}
+
+	var statements []string
+	if td.SearchChanged {
+		statements = append(statements, d.DropSearch(td.Old)...)
+	}
+	for _, i := range td.DroppedIndexes {
+		statements = append(statements, fmt.Sprintf("DROP INDEX IF EXISTS %s;", quote(i.Name)))
+	}
+	for _, c := range td.AddedColumns {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", quote(td.New.Name), d.column(c, false)))
+	}
+	for _, i := range td.AddedIndexes {
+		statements = append(statements, d.CreateIndex(td.New, i))
+	}
+	if td.SearchChanged {
+		statements = append(statements, d.CreateSearch(td.New)...)
+	}
+	return statements
+}
+
+// DropTable returns the statements which drop the table with its full-text search.
+func (d sqliteDialect) DropTable(t *Table) []string {
+	return append(d.DropSearch(t), fmt.Sprintf("DROP TABLE IF EXISTS %s;", quote(t.Name)))
+}
+
+// AddColumn returns the statement which adds the column.
+// sqlite can not add the primary key and the not null column without the default value.
+func (d sqliteDialect) AddColumn(t *Table, c *Column) string {
+	if c.PrimaryKey || c.AutoIncrement || (c.NotNull && sqliteDefault(c.Default) == "") {
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", quote(t.Name), d.column(c, false))
+}
+
+// CatalogColumn returns the declared type and the nullability of the column as PRAGMA table_info reports them.
+func (sqliteDialect) CatalogColumn(c *Column) (string, bool) {
+	if c.AutoIncrement {
+		return "INTEGER", false
+	}
+	return c.Type, c.NotNull
+}
+
+// rebuildTable returns the statements which create the new version of the table,
+// copy the data of the kept columns and replace the old table.
+func (d sqliteDialect) rebuildTable(td *TableDiff) []string {
+	tmpName := td.New.Name + "__new"
+
+	var columns []string
+	for _, c := range td.New.Columns {
+		if td.Old.Column(c.Name) != nil {
+			columns = append(columns, c.Name)
+		}
+	}
+
+	statements := []string{
+		fmt.Sprintf("-- sqlite can not alter the columns and the foreign keys, the table %s is rebuilt.", quote(td.New.Name)),
+	}
+	if td.SearchChanged {
+		statements = append(statements, d.DropSearch(td.Old)...)
+	}
+	statements = append(statements,
+		"PRAGMA foreign_keys = OFF;",
+		d.createTable(td.New, tmpName),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;", quote(tmpName), quoteList(columns), quoteList(columns), quote(td.Old.Name)),
+		fmt.Sprintf("DROP TABLE %s;", quote(td.Old.Name)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", quote(tmpName), quote(td.New.Name)),
+	)
+	for _, i := range td.New.Indexes {
+		statements = append(statements, d.CreateIndex(td.New, i))
+	}
+	// the triggers of the search are dropped with the old table
+	statements = append(statements, d.CreateSearch(td.New)...)
+	return append(statements, "PRAGMA foreign_keys = ON;")
+}
+
+// createTable returns the statement which creates the table with the given name.
+func (d sqliteDialect) createTable(t *Table, name string) string {
+	var definitions []string
+	pk := t.PrimaryKey()
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.column(c, len(pk) == 1))
+	}
+	if len(pk) > 1 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(pk)))
+	}
+	for _, fk := range t.ForeignKeys {
+		definition := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", quote(fk.Column), quote(fk.RefTable), quote(fk.RefColumn))
+		if fk.Cascade {
+			definition += " ON DELETE CASCADE"
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n);", quote(name), strings.Join(definitions, ",\n\t"))
+}
+
+// column returns the column definition.
+func (sqliteDialect) column(c *Column, primaryKey bool) string {
+	if c.AutoIncrement {
+		return quote(c.Name) + " INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+
+	definition := quote(c.Name) + " " + c.Type
+	if c.PrimaryKey && primaryKey {
+		definition += " PRIMARY KEY"
+	}
+	if c.NotNull {
+		definition += " NOT NULL"
+	}
+	if value := sqliteDefault(c.Default); value != "" {
+		definition += " DEFAULT " + value
+	}
+	return definition
+}
+
+// CreateIndex returns the statement which creates the index.
+func (sqliteDialect) CreateIndex(t *Table, i *Index) string {
+	unique := ""
+	if i.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s);", unique, quote(i.Name), quote(t.Name), quoteList(i.Columns))
+}
+
+// SearchColumn returns nil, the search document is kept by the FTS5 table.
+func (sqliteDialect) SearchColumn(*Table) *Column {
+	return nil
+}
+
+// CreateSearch returns the statements which create the FTS5 table of the search columns, the triggers
+// which keep it in sync with the table and fill it with the existing rows if it is empty.
+// The rows of the FTS5 table are matched with the table by the primary key, so the table must have a single one.
+func (sqliteDialect) CreateSearch(t *Table) []string {
+	pk := t.PrimaryKey()
+	if len(t.Search) == 0 || len(pk) != 1 {
+		return nil
+	}
+
+	search := t.Name + helperpkg.SearchTablePostfix
+	columns := []string{pk[0]}
+	definitions := []string{quote(pk[0]) + " UNINDEXED"}
+	for _, c := range t.Search {
+		columns = append(columns, c.Column)
+		definitions = append(definitions, quote(c.Column))
+	}
+	newValues := make([]string, 0, len(columns))
+	for _, c := range columns {
+		newValues = append(newValues, "new."+quote(c))
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", quote(search), quoteList(columns), strings.Join(newValues, ", "))
+	remove := fmt.Sprintf("DELETE FROM %s WHERE %s = old.%s;", quote(search), quote(pk[0]), quote(pk[0]))
+	return []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s);", quote(search), strings.Join(definitions, ", ")),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN %s END;", quote(search+"_insert"), quote(t.Name), insert),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN %s %s END;", quote(search+"_update"), quote(t.Name), remove, insert),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN %s END;", quote(search+"_delete"), quote(t.Name), remove),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE NOT EXISTS (SELECT 1 FROM %s);",
+			quote(search), quoteList(columns), quoteList(columns), quote(t.Name), quote(search)),
+	}
+}
+
+// DropSearch returns the statements which drop the FTS5 table and its triggers.
+func (sqliteDialect) DropSearch(t *Table) []string {
+	if len(t.Search) == 0 {
+		return nil
+	}
+
+	search := t.Name + helperpkg.SearchTablePostfix
+	return []string{
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s;", quote(search+"_insert")),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s;", quote(search+"_update")),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s;", quote(search+"_delete")),
+		fmt.Sprintf("DROP TABLE IF EXISTS %s;", quote(search)),
+	}
+}
+
+// BlindIndex returns false, the encrypted fields are not supported by sqlite.
+func (sqliteDialect) BlindIndex() bool {
+	return false
+}
+
+// sqliteDefault returns the sqlite default value, the postgres functions are replaced.
+func sqliteDefault(value string) string {
+	if strings.Contains(value, "uuid") {
+		return ""
+	}
+	if strings.Contains(value, "now") {
+		return "CURRENT_TIMESTAMP"
+	}
+	return value
+}
@@ -0,0 +1,54 @@
+package schema
+
+// UpgradeColumn is the column which the table upgrade compares with the live table.
+type UpgradeColumn struct {
+	Name string
+	// Type is the column type as the database catalog reports it.
+	Type    string
+	NotNull bool
+	// Statement adds the missing column, it is empty if the column can not be added safely.
+	Statement string
+}
+
+// UpgradeColumns returns the columns of the table for the upgrade.
+func UpgradeColumns(d Dialect, t *Table) []*UpgradeColumn {
+	columns := make([]*UpgradeColumn, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		typ, notNull := d.CatalogColumn(c)
+		columns = append(columns, &UpgradeColumn{
+			Name:      c.Name,
+			Type:      typ,
+			NotNull:   notNull,
+			Statement: d.AddColumn(t, c),
+		})
+	}
+	return columns
+}
+
+// UpgradeIndex is the index which the table upgrade creates.
+type UpgradeIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Statement creates the index if it does not exist.
+	Statement string
+}
+
+// UpgradeIndexes returns the indexes of the table for the upgrade.
+func UpgradeIndexes(d Dialect, t *Table) []*UpgradeIndex {
+	indexes := make([]*UpgradeIndex, 0, len(t.Indexes))
+	for _, i := range t.Indexes {
+		indexes = append(indexes, &UpgradeIndex{
+			Name:      i.Name,
+			Columns:   i.Columns,
+			Unique:    i.Unique,
+			Statement: d.CreateIndex(t, i),
+		})
+	}
+	return indexes
+}
+
+// CreateStatements returns the statements which create the table with its foreign keys.
+func CreateStatements(d Dialect, t *Table) []string {
+	return Statements(d, &Diff{Created: []*Table{t}})
+}
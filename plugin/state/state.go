@@ -3,6 +3,7 @@ package state
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"path"
 	"strings"
 
@@ -25,11 +26,14 @@ type State struct {
 	FileToGenerate    string // FileToGenerate is the file to generate.
 	IncludeConnection bool   // IncludeConnection is the flag to include connection in the generated code.
 	CRUDSchemas       bool
+	EmitSchema        bool   // EmitSchema is the flag to generate the schema DDL file alongside the Go code.
+	Schema            string // Schema is the default database schema (namespace) of the tables.
 
 	Imports        importpkg.ImportSet // Imports is the set of Imports.
 	Relations      Relations           // Relations is the set of Relations Messages.
 	Messages       Messages            // Messages is the set of root Messages.
 	NestedMessages NestedMessages      // NestedMessages is the set of nested Messages.
+	Methods        Methods             // Methods is the set of the annotated service methods.
 
 	// SingleTypes is the set of single types. example: type UserNames []string
 	// used for generating json statements.
@@ -42,14 +46,17 @@ func NewState(
 ) *State {
 	protoFile := helperpkg.GetUserProtoFile(request)
 	nestedMessages := getNestedMessages(request)
+	messages := getMessages(request)
 	state := &State{
 		Provider:    getProvider(request),
+		Schema:      getSchema(request),
 		PackageName: protoFile.GetPackage(),
 		FileName:    parseFileName(request),
 
 		Imports:        defaultImports(request),
-		Messages:       getMessages(request),
+		Messages:       messages,
 		NestedMessages: nestedMessages,
+		Methods:        getMethods(request, messages),
 		Relations:      getRelations(request, nestedMessages),
 		ProtocVersion:  getProtocVersion(request),
 		Version:        version.GetPluginVersion(),
@@ -75,6 +82,77 @@ func getProvider(request *plugingo.CodeGeneratorRequest) string {
 	return ""
 }
 
+// getSchema returns the file-level database schema.
+func getSchema(request *plugingo.CodeGeneratorRequest) string {
+	protoFile := helperpkg.GetUserProtoFile(request)
+	opts := helperpkg.GetDBOptions(protoFile)
+	if opts != nil {
+		return opts.GetSchema()
+	}
+	return ""
+}
+
+// SchemaName returns the database schema of the given message.
+// The message-level schema overrides the file-level one.
+func (s *State) SchemaName(m *descriptorpb.DescriptorProto) string {
+	if opts := helperpkg.GetMessageOptions(m); opts != nil {
+		if opts.GetSchema() != "" {
+			return opts.GetSchema()
+		}
+	}
+	return s.Schema
+}
+
+// TableName returns the table name of the given message.
+func (s *State) TableName(m *descriptorpb.DescriptorProto) string {
+	if opts := helperpkg.GetMessageOptions(m); opts != nil {
+		if opts.GetTable() != "" {
+			return opts.GetTable()
+		}
+	}
+	return helperpkg.Plural(m.GetName())
+}
+
+// SchemaNames returns the unique database schemas used by the messages.
+func (s *State) SchemaNames() []string {
+	var schemas []string
+	seen := make(map[string]bool)
+	for _, m := range s.Messages {
+		schema := s.SchemaName(m)
+		if schema == "" || seen[schema] {
+			continue
+		}
+		seen[schema] = true
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+// HasEncryptedFields checks if any of the messages has encrypted fields.
+func (s *State) HasEncryptedFields() bool {
+	for _, m := range s.Messages {
+		for _, f := range m.GetField() {
+			if helperpkg.IsEncrypted(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FieldsWith returns the names of the message fields matching the given function, qualified by the table name, e.g. users.email.
+func (s *State) FieldsWith(match func(f *descriptorpb.FieldDescriptorProto) bool) []string {
+	var fields []string
+	for _, m := range s.Messages {
+		for _, f := range m.GetField() {
+			if match(f) {
+				fields = append(fields, s.TableName(m)+"."+f.GetName())
+			}
+		}
+	}
+	return fields
+}
+
 // defaultImports returns the default Imports.
 func defaultImports(request *plugingo.CodeGeneratorRequest) importpkg.ImportSet {
 	var imports = make(importpkg.ImportSet)
@@ -131,6 +209,10 @@ func getRelations(request *plugingo.CodeGeneratorRequest, nestSet NestedMessages
 	var respRelations = make(Relations)
 
 	for _, msg := range protoFile.GetMessageType() {
+		if helperpkg.IsServiceMessage(protoFile, msg) {
+			continue
+		}
+
 		var pk *descriptor.FieldDescriptorProto
 		for _, f := range msg.GetField() {
 			if opts := helperpkg.GetFieldOptions(f); opts != nil {
@@ -232,7 +314,7 @@ func getNestedMessages(request *plugingo.CodeGeneratorRequest) map[string]*Messa
 	file := helperpkg.GetUserProtoFile(request)
 
 	for _, msg := range file.GetMessageType() {
-		if len(msg.GetNestedType()) == 0 {
+		if len(msg.GetNestedType()) == 0 || helperpkg.IsServiceMessage(file, msg) {
 			continue
 		}
 
@@ -286,7 +368,7 @@ func getMessages(request *plugingo.CodeGeneratorRequest) []*descriptorpb.Descrip
 
 	f := helperpkg.GetUserProtoFile(request)
 	for _, m := range f.GetMessageType() {
-		if !helperpkg.IsUserMessage(f, m) {
+		if !helperpkg.IsUserMessage(f, m) || helperpkg.IsServiceMessage(f, m) {
 			continue
 		}
 		messages = append(messages, m)
@@ -295,6 +377,51 @@ func getMessages(request *plugingo.CodeGeneratorRequest) []*descriptorpb.Descrip
 	return messages
 }
 
+// getMethods returns the service methods annotated with the structify method options.
+func getMethods(request *plugingo.CodeGeneratorRequest, messages Messages) Methods {
+	var methods Methods
+
+	f := helperpkg.GetUserProtoFile(request)
+	for _, service := range f.GetService() {
+		for _, method := range service.GetMethod() {
+			opts := helperpkg.GetMethodOptions(method)
+			if opts == nil {
+				continue
+			}
+
+			name := service.GetName() + "." + method.GetName()
+			if method.GetClientStreaming() || method.GetServerStreaming() {
+				log.Fatalf("method %s: streaming methods are not supported", name)
+			}
+
+			object := messages.FindByName(helperpkg.MessageName(f, opts.GetObjectType()))
+			if object == nil {
+				log.Fatalf("method %s: object type %q is not found", name, opts.GetObjectType())
+			}
+
+			var request *descriptorpb.DescriptorProto
+			for _, m := range f.GetMessageType() {
+				if m.GetName() == helperpkg.MessageName(f, method.GetInputType()) {
+					request = m
+				}
+			}
+			if request == nil {
+				log.Fatalf("method %s: request %q must be declared in the same file", name, method.GetInputType())
+			}
+
+			methods = append(methods, &Method{
+				Name:       method.GetName(),
+				Descriptor: method,
+				Object:     object,
+				Request:    request,
+				Single:     helperpkg.MessageName(f, method.GetOutputType()) == object.GetName(),
+			})
+		}
+	}
+
+	return methods
+}
+
 // parseFileName parses the file name from the protobuf request.
 func parseFileName(request *plugingo.CodeGeneratorRequest) string {
 	fileBase := path.Base(request.GetFileToGenerate()[0])
@@ -522,6 +649,48 @@ func (t Messages) FindByName(name string) *descriptorpb.DescriptorProto {
 	return nil
 }
 
+// Method is a service method which is generated as a storage method of its object.
+type Method struct {
+	Name       string                              // Name is the name of the method.
+	Descriptor *descriptorpb.MethodDescriptorProto // Descriptor is the method descriptor.
+	Object     *descriptorpb.DescriptorProto       // Object is the message of the storage.
+	Request    *descriptorpb.DescriptorProto       // Request is the request message, its fields are the filters.
+	Single     bool                                // Single is true if the method returns the object itself.
+}
+
+// Methods is a type for the annotated service methods.
+type Methods []*Method
+
+// ByObject returns the methods of the given object message.
+func (m Methods) ByObject(object *descriptorpb.DescriptorProto) Methods {
+	var methods Methods
+	for _, method := range m {
+		if method.Object.GetName() == object.GetName() {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// Requests returns the request messages which are declared by the given object.
+// The request shared by several methods is declared by the object of the first one.
+func (m Methods) Requests(object *descriptorpb.DescriptorProto) Messages {
+	var requests Messages
+	seen := make(map[string]bool)
+	for _, method := range m {
+		name := method.Request.GetName()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if method.Object.GetName() == object.GetName() {
+			requests = append(requests, method.Request)
+		}
+	}
+	return requests
+}
+
 // NestedMessages is a type for how to generate json statements.
 type NestedMessages map[string]*MessageDescriptor
 
@@ -771,6 +940,10 @@ func getSingleTypes(request *plugingo.CodeGeneratorRequest, messages NestedMessa
 
 	// Get all the SingleTypes.
 	for _, m := range file.GetMessageType() {
+		if helperpkg.IsServiceMessage(file, m) {
+			continue
+		}
+
 		for _, field := range m.GetField() {
 			if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
 				repeated := helperpkg.IsRepeated(field)